@@ -155,6 +155,16 @@ func init() {
 			Name:   "services",
 			Usage:  "List services in the registry",
 			Action: util.Print(listServices),
+			Flags: []cli.Flag{
+				&cli.StringFlag{
+					Name:  "selector",
+					Usage: "Filter on version and node metadata e.g. version=~1.2,region=eu",
+				},
+				&cli.BoolFlag{
+					Name:  "all",
+					Usage: "Include services whose nodes failed their health check",
+				},
+			},
 		},
 	)
 }
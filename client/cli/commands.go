@@ -67,7 +67,7 @@ func QueryStats(c *cli.Context, args []string) ([]byte, error) {
 
 	req := client.NewRequest(service[0].Name, "Debug.Stats", &proto.StatsRequest{})
 
-	var output []string
+	var output, breakers []string
 
 	// print things
 	output = append(output, "service  "+service[0].Name)
@@ -99,8 +99,24 @@ func QueryStats(c *cli.Context, args []string) ([]byte, error) {
 				node.Id, node.Address, started, uptime, memory, rsp.Threads, gc)
 
 			output = append(output, line)
+
+			// the circuit breakers of the services called by the node
+			if len(rsp.Breakers) > 0 {
+				breakers = append(breakers, "\nbreakers "+node.Id)
+				breakers = append(breakers, "namespace\tservice\t\tnode\t\tstate\trequests\terrors\tslow\ttrips")
+			}
+			for _, b := range rsp.Breakers {
+				node := b.Node
+				if len(node) == 0 {
+					node = "-"
+				}
+				breakers = append(breakers, fmt.Sprintf("%s\t%s\t\t%s\t\t%s\t%d\t%d\t%d\t%d",
+					b.Namespace, b.Service, node, b.State, b.Requests, b.Errors, b.Slow, b.Trips))
+			}
 		}
 	}
 
+	output = append(output, breakers...)
+
 	return []byte(strings.Join(output, "\n")), nil
 }
@@ -23,6 +23,28 @@ var Flags = []cli.Flag{
 		Usage:   "The provider that will be used to communicate with Let's Encrypt. Valid options: autocert, certmagic",
 		EnvVars: []string{"MICRO_ACME_PROVIDER"},
 	},
+	&cli.StringFlag{
+		Name:    "acme_challenge_provider",
+		Usage:   "The DNS provider used by certmagic for the dns-01 challenge e.g. cloudflare, route53, rfc2136. It's configured using the env vars of the lego provider",
+		EnvVars: []string{"MICRO_ACME_CHALLENGE_PROVIDER"},
+		Value:   "cloudflare",
+	},
+	&cli.StringFlag{
+		Name:    "acme_ca",
+		Usage:   "The directory url of the ACME CA used by certmagic. Defaults to the Let's Encrypt production CA",
+		EnvVars: []string{"MICRO_ACME_CA"},
+	},
+	&cli.StringFlag{
+		Name:    "acme_storage",
+		Usage:   "The storage of the certificates issued by certmagic. Valid options: store, file",
+		EnvVars: []string{"MICRO_ACME_STORAGE"},
+		Value:   "store",
+	},
+	&cli.StringFlag{
+		Name:    "acme_storage_path",
+		Usage:   "The directory of the certificates when using the file storage",
+		EnvVars: []string{"MICRO_ACME_STORAGE_PATH"},
+	},
 	&cli.BoolFlag{
 		Name:    "enable_tls",
 		Usage:   "Enable TLS support. Expects cert and key file to be specified",
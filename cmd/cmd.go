@@ -38,6 +38,7 @@ import (
 	muclient "github.com/micro/micro/v3/service/client"
 	muconfig "github.com/micro/micro/v3/service/config"
 	muregistry "github.com/micro/micro/v3/service/registry"
+	"github.com/micro/micro/v3/service/router/policy"
 	muruntime "github.com/micro/micro/v3/service/runtime"
 	muserver "github.com/micro/micro/v3/service/server"
 	mustore "github.com/micro/micro/v3/service/store"
@@ -125,6 +126,27 @@ var (
 			EnvVars: []string{"MICRO_AUTH_PRIVATE_KEY"},
 			Usage:   "Private key for JWT auth (base64 encoded PEM)",
 		},
+		&cli.BoolFlag{
+			Name:    "enable_mtls",
+			EnvVars: []string{"MICRO_ENABLE_MTLS"},
+			Usage:   "Enable mutual TLS between services using certificates issued by the auth service",
+		},
+		&cli.StringFlag{
+			Name:    "mtls_ca_file",
+			EnvVars: []string{"MICRO_MTLS_CA_FILE"},
+			Usage:   "Certificate of the CA which issues the mutual TLS certificates, required when mutual TLS is enabled",
+		},
+		&cli.StringFlag{
+			Name:    "mtls_ca_key_file",
+			EnvVars: []string{"MICRO_MTLS_CA_KEY_FILE"},
+			Usage:   "Private key of the CA which issues the mutual TLS certificates, only used by the auth service",
+		},
+		&cli.DurationFlag{
+			Name:    "mtls_cert_expiry",
+			EnvVars: []string{"MICRO_MTLS_CERT_EXPIRY"},
+			Usage:   "How long the mutual TLS certificates are valid for, they're rotated after two thirds of it",
+			Value:   time.Hour * 24,
+		},
 		&cli.StringFlag{
 			Name:    "registry_address",
 			EnvVars: []string{"MICRO_REGISTRY_ADDRESS"},
@@ -339,7 +361,8 @@ func (c *command) Before(ctx *cli.Context) error {
 		client.Lookup(network.Lookup),
 	)
 
-	// wrap the client
+	// wrap the client, the traffic policies are applied when the node of a call is selected
+	muclient.DefaultClient = policy.Wrapper(muclient.DefaultClient)
 	muclient.DefaultClient = wrapper.AuthClient(muclient.DefaultClient)
 	muclient.DefaultClient = wrapper.CacheClient(muclient.DefaultClient)
 	muclient.DefaultClient = wrapper.TraceCall(muclient.DefaultClient)
@@ -471,6 +494,13 @@ func (c *command) Before(ctx *cli.Context) error {
 		server.Registry(muregistry.DefaultRegistry),
 	)
 
+	// setup mutual tls using the certificates issued by the auth service
+	if ctx.Bool("enable_mtls") {
+		if err := setupMTLS(ctx); err != nil {
+			logger.Fatalf("Error setting up mutual TLS: %v", err)
+		}
+	}
+
 	// setup auth credentials, use local credentials for the CLI and injected creds
 	// for the service.
 	var err error
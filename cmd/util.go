@@ -1,16 +1,27 @@
 package cmd
 
 import (
+	"fmt"
+	"io/ioutil"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/micro/cli/v2"
 	goauth "github.com/micro/go-micro/v3/auth"
+	"github.com/micro/go-micro/v3/client"
+	cgrpc "github.com/micro/go-micro/v3/client/grpc"
 	"github.com/micro/go-micro/v3/logger"
+	sgrpc "github.com/micro/go-micro/v3/server/grpc"
 	"github.com/micro/micro/v3/client/cli/namespace"
 	clitoken "github.com/micro/micro/v3/client/cli/token"
 	"github.com/micro/micro/v3/client/cli/util"
+	"github.com/micro/micro/v3/internal/mtls"
+	"github.com/micro/micro/v3/internal/network"
 	"github.com/micro/micro/v3/service/auth"
+	authClient "github.com/micro/micro/v3/service/auth/client"
+	muclient "github.com/micro/micro/v3/service/client"
+	muserver "github.com/micro/micro/v3/service/server"
+	"google.golang.org/grpc"
 )
 
 // setupAuthForCLI handles exchanging refresh tokens to access tokens
@@ -105,6 +116,47 @@ func setupAuthForService() error {
 	return nil
 }
 
+// setupMTLS configures the client and server to use mutual TLS. It's done before auth is setup
+// since auth is called using the client, the certificate is issued by the service once it has
+// been and it knows its name.
+func setupMTLS(ctx *cli.Context) error {
+	issuer, ok := auth.DefaultAuth.(authClient.CertIssuer)
+	if !ok {
+		return authClient.ErrCertsNotSupported
+	}
+
+	// the CA is required so it's never trusted on first use
+	f := ctx.String("mtls_ca_file")
+	if len(f) == 0 {
+		return fmt.Errorf("the mtls_ca_file flag is required")
+	}
+	ca, err := ioutil.ReadFile(f)
+	if err != nil {
+		return err
+	}
+
+	m, err := mtls.New(
+		mtls.WithIssuer(issuer),
+		mtls.Expiry(ctx.Duration("mtls_cert_expiry")),
+		mtls.CA(ca),
+	)
+	if err != nil {
+		return err
+	}
+	mtls.DefaultManager = m
+
+	// the client verifies the server is the service it looked up at the address
+	muclient.DefaultClient.Init(
+		cgrpc.AuthTLS(m.Config()),
+		client.Lookup(m.Lookup(network.Lookup)),
+		func(o *client.Options) {
+			cgrpc.DialOptions(grpc.WithTransportCredentials(m.Credentials()))(&o.CallOptions)
+		},
+	)
+	muserver.DefaultServer.Init(sgrpc.AuthTLS(m.Config()))
+	return nil
+}
+
 // refreshAuthToken if it is close to expiring
 func refreshAuthToken() {
 	// can't refresh a token we don't have
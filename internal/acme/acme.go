@@ -0,0 +1,104 @@
+// Package acme sets up the ACME provider used by the proxy and the gateway to issue certificates
+// using Let's Encrypt. The autocert provider uses the tls-alpn-01 challenge, the certmagic provider
+// uses the dns-01 challenge of any lego DNS provider, e.g.
+//
+//	MICRO_ACME_PROVIDER=certmagic MICRO_ACME_CHALLENGE_PROVIDER=rfc2136 RFC2136_NAMESERVER=127.0.0.1:53 micro api
+//
+// The DNS providers are configured using the env vars documented by lego. The certificates issued
+// by certmagic are kept in the micro store by default, so they're shared by every replica.
+package acme
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/caddyserver/certmagic"
+	"github.com/go-acme/lego/v3/challenge"
+	"github.com/go-acme/lego/v3/providers/dns"
+	"github.com/go-acme/lego/v3/providers/dns/cloudflare"
+	"github.com/micro/cli/v2"
+	goacme "github.com/micro/go-micro/v3/api/server/acme"
+	"github.com/micro/go-micro/v3/api/server/acme/autocert"
+	mcertmagic "github.com/micro/go-micro/v3/api/server/acme/certmagic"
+	"github.com/micro/go-micro/v3/sync/memory"
+	"github.com/micro/micro/v3/service/store"
+)
+
+// Storages create the storage of the certificates by name, the path is optional
+var Storages = map[string]func(path string) (certmagic.Storage, error){
+	"store": func(path string) (certmagic.Storage, error) {
+		return mcertmagic.NewStorage(memory.NewSync(), store.DefaultStore), nil
+	},
+	"file": func(path string) (certmagic.Storage, error) {
+		if len(path) == 0 {
+			return certmagic.Default.Storage, nil
+		}
+		return &certmagic.FileStorage{Path: path}, nil
+	},
+}
+
+// NewProvider returns the ACME provider set by the flags
+func NewProvider(ctx *cli.Context) (goacme.Provider, error) {
+	switch name := ctx.String("acme_provider"); name {
+	case "", "autocert":
+		return autocert.NewProvider(), nil
+	case "certmagic":
+		storage, err := Storage(ctx.String("acme_storage"), ctx.String("acme_storage_path"))
+		if err != nil {
+			return nil, err
+		}
+		cp, err := ChallengeProvider(ctx.String("acme_challenge_provider"))
+		if err != nil {
+			return nil, err
+		}
+
+		ca := ctx.String("acme_ca")
+		if len(ca) == 0 {
+			ca = goacme.LetsEncryptProductionCA
+		}
+
+		return mcertmagic.NewProvider(
+			goacme.AcceptToS(true),
+			goacme.CA(ca),
+			goacme.Cache(storage),
+			goacme.ChallengeProvider(cp),
+			goacme.OnDemand(false),
+		), nil
+	default:
+		return nil, fmt.Errorf("%s is not a valid ACME provider", name)
+	}
+}
+
+// Storage returns the storage of the certificates with the name, e.g. store or file
+func Storage(name, path string) (certmagic.Storage, error) {
+	if len(name) == 0 {
+		name = "store"
+	}
+	fn, ok := Storages[name]
+	if !ok {
+		return nil, fmt.Errorf("%s is not a valid ACME storage", name)
+	}
+	return fn(path)
+}
+
+// ChallengeProvider returns the lego DNS provider with the name, configured by its env vars
+func ChallengeProvider(name string) (challenge.Provider, error) {
+	if len(name) == 0 {
+		name = "cloudflare"
+	}
+
+	// the cloudflare provider was configured using CF_API_TOKEN before any lego provider could be
+	// used, so it's still supported
+	if token := os.Getenv("CF_API_TOKEN"); name == "cloudflare" && len(token) > 0 {
+		config := cloudflare.NewDefaultConfig()
+		config.AuthToken = token
+		config.ZoneToken = token
+		return cloudflare.NewDNSProviderConfig(config)
+	}
+
+	p, err := dns.NewDNSChallengeProviderByName(name)
+	if err != nil {
+		return nil, fmt.Errorf("error creating the %s DNS provider: %v", name, err)
+	}
+	return p, nil
+}
@@ -0,0 +1,179 @@
+package acme
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"net"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/caddyserver/certmagic"
+	"github.com/miekg/dns"
+)
+
+// testDNSServer is a DNS server for the example.com zone which accepts dynamic updates
+type testDNSServer struct {
+	sync.Mutex
+	records map[string]string
+}
+
+func (s *testDNSServer) ServeDNS(w dns.ResponseWriter, r *dns.Msg) {
+	m := new(dns.Msg)
+	m.SetReply(r)
+
+	s.Lock()
+	defer s.Unlock()
+
+	switch {
+	case r.Opcode == dns.OpcodeUpdate:
+		for _, rr := range r.Ns {
+			txt, ok := rr.(*dns.TXT)
+			if !ok {
+				continue
+			}
+			switch txt.Hdr.Class {
+			case dns.ClassINET:
+				s.records[txt.Hdr.Name] = txt.Txt[0]
+			case dns.ClassNONE:
+				delete(s.records, txt.Hdr.Name)
+			}
+		}
+	case len(r.Question) > 0 && r.Question[0].Qtype == dns.TypeSOA && r.Question[0].Name == "example.com.":
+		m.Answer = append(m.Answer, &dns.SOA{
+			Hdr:    dns.RR_Header{Name: "example.com.", Rrtype: dns.TypeSOA, Class: dns.ClassINET, Ttl: 60},
+			Ns:     "ns.example.com.",
+			Mbox:   "admin.example.com.",
+			Serial: 1,
+		})
+	default:
+		m.Rcode = dns.RcodeNameError
+	}
+
+	w.WriteMsg(m)
+}
+
+func TestChallengeProviderRFC2136(t *testing.T) {
+	pc, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ts := &testDNSServer{records: make(map[string]string)}
+	started := make(chan bool)
+	srv := &dns.Server{
+		PacketConn:        pc,
+		Handler:           ts,
+		NotifyStartedFunc: func() { close(started) },
+		// dynamic updates are rejected by default
+		MsgAcceptFunc: func(dns.Header) dns.MsgAcceptAction { return dns.MsgAccept },
+	}
+	go srv.ActivateAndServe()
+	defer srv.Shutdown()
+	<-started
+
+	os.Setenv("RFC2136_NAMESERVER", pc.LocalAddr().String())
+	defer os.Unsetenv("RFC2136_NAMESERVER")
+
+	p, err := ChallengeProvider("rfc2136")
+	if err != nil {
+		t.Fatalf("Error creating the provider: %v", err)
+	}
+
+	// the challenge record is added to the zone and removed once the challenge is complete
+	if err := p.Present("example.com", "token", "keyAuth"); err != nil {
+		t.Fatalf("Error presenting the challenge: %v", err)
+	}
+	ts.Lock()
+	val := ts.records["_acme-challenge.example.com."]
+	ts.Unlock()
+	if len(val) == 0 {
+		t.Fatalf("Expected the challenge record to be added")
+	}
+
+	if err := p.CleanUp("example.com", "token", "keyAuth"); err != nil {
+		t.Fatalf("Error cleaning up the challenge: %v", err)
+	}
+	ts.Lock()
+	_, ok := ts.records["_acme-challenge.example.com."]
+	ts.Unlock()
+	if ok {
+		t.Fatalf("Expected the challenge record to be removed")
+	}
+
+	if _, err := ChallengeProvider("unknown"); err == nil {
+		t.Fatalf("Expected an error for an unknown provider")
+	}
+}
+
+func TestCertificates(t *testing.T) {
+	dir, err := ioutil.TempDir("", "acme")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	storage, err := Storage("file", dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	now := time.Now()
+	for domain, expiry := range map[string]time.Time{
+		"foo.example.com": now.Add(time.Hour * 24 * 60),
+		"bar.example.com": now.Add(time.Hour * 24),
+	} {
+		key := certmagic.KeyBuilder{}.SiteCert("acme-v02.api.letsencrypt.org-directory", domain)
+		if err := storage.Store(key, testCertificate(t, domain, expiry)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	certs, err := Certificates(storage)
+	if err != nil {
+		t.Fatalf("Error listing certificates: %v", err)
+	}
+	if len(certs) != 2 {
+		t.Fatalf("Expected 2 certificates, got %v", len(certs))
+	}
+	if certs[0].Domain != "bar.example.com" || certs[0].Status(now) != "expiring" {
+		t.Errorf("Expected bar.example.com to be expiring, got %v %v", certs[0].Domain, certs[0].Status(now))
+	}
+	if certs[1].Domain != "foo.example.com" || certs[1].Status(now) != "valid" {
+		t.Errorf("Expected foo.example.com to be valid, got %v %v", certs[1].Domain, certs[1].Status(now))
+	}
+	if certs[1].Status(now.Add(time.Hour*24*90)) != "expired" {
+		t.Errorf("Expected foo.example.com to expire")
+	}
+
+	if _, err := Storage("unknown", ""); err == nil {
+		t.Errorf("Expected an error for an unknown storage")
+	}
+}
+
+func testCertificate(t *testing.T, domain string, expiry time.Time) []byte {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: domain},
+		Issuer:       pkix.Name{CommonName: "test"},
+		DNSNames:     []string{domain},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     expiry,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+}
@@ -0,0 +1,91 @@
+package acme
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/caddyserver/certmagic"
+)
+
+// RenewWindow is how long before it expires a certificate is expiring
+var RenewWindow = time.Hour * 24 * 30
+
+// Certificate is a certificate issued by the ACME provider
+type Certificate struct {
+	// Domain the certificate was issued for
+	Domain string
+	// Names of the certificate
+	Names []string
+	// Issuer of the certificate
+	Issuer string
+	// NotBefore is when the certificate becomes valid
+	NotBefore time.Time
+	// NotAfter is when the certificate expires
+	NotAfter time.Time
+}
+
+// Status of the certificate at the time, valid, expiring or expired
+func (c *Certificate) Status(t time.Time) string {
+	switch {
+	case t.After(c.NotAfter) || t.Before(c.NotBefore):
+		return "expired"
+	case c.NotAfter.Sub(t) < RenewWindow:
+		return "expiring"
+	default:
+		return "valid"
+	}
+}
+
+// Certificates returns the certificates in the storage ordered by domain
+func Certificates(s certmagic.Storage) ([]*Certificate, error) {
+	keys, err := s.List("certificates", true)
+	if err != nil {
+		return nil, err
+	}
+
+	var certs []*Certificate
+	for _, k := range keys {
+		if !strings.HasSuffix(k, ".crt") {
+			continue
+		}
+		b, err := s.Load(k)
+		if err != nil {
+			return nil, err
+		}
+		c, err := parseCertificate(b)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing %s: %v", k, err)
+		}
+		c.Domain = strings.TrimSuffix(path.Base(k), ".crt")
+		certs = append(certs, c)
+	}
+
+	sort.Slice(certs, func(i, j int) bool {
+		return certs[i].Domain < certs[j].Domain
+	})
+	return certs, nil
+}
+
+// parseCertificate parses the leaf of the PEM encoded certificate chain
+func parseCertificate(b []byte) (*Certificate, error) {
+	block, _ := pem.Decode(b)
+	if block == nil || block.Type != "CERTIFICATE" {
+		return nil, fmt.Errorf("no certificate found")
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Certificate{
+		Names:     cert.DNSNames,
+		Issuer:    cert.Issuer.CommonName,
+		NotBefore: cert.NotBefore,
+		NotAfter:  cert.NotAfter,
+	}, nil
+}
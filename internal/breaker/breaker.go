@@ -0,0 +1,224 @@
+// Package breaker implements circuit breakers for the services and nodes called by the proxy and
+// the gateway. A breaker opens when the rate of failed or slow calls exceeds a threshold. Calls to
+// a service with an open breaker fail fast, and nodes with an open breaker are ejected from the
+// selection pool. Once the cooldown has passed the breaker is half open, and probe calls decide
+// whether it closes or opens again.
+package breaker
+
+import (
+	"sync"
+	"time"
+
+	goerrors "github.com/micro/go-micro/v3/errors"
+)
+
+// State of a breaker
+type State int
+
+const (
+	// Closed breakers allow all calls
+	Closed State = iota
+	// Open breakers reject calls until the cooldown has passed
+	Open
+	// HalfOpen breakers allow probe calls to check the service or node has recovered
+	HalfOpen
+)
+
+func (s State) String() string {
+	switch s {
+	case Open:
+		return "open"
+	case HalfOpen:
+		return "half-open"
+	default:
+		return "closed"
+	}
+}
+
+// Stat is the state of a breaker
+type Stat struct {
+	// Namespace of the service the breaker applies to
+	Namespace string `json:"namespace"`
+	// Service the breaker applies to
+	Service string `json:"service"`
+	// Node the breaker applies to, blank for the breaker of the service
+	Node string `json:"node,omitempty"`
+	// State of the breaker, closed, open or half-open
+	State string `json:"state"`
+	// Requests in the current window
+	Requests int `json:"requests"`
+	// Errors in the current window
+	Errors int `json:"errors"`
+	// Slow calls in the current window
+	Slow int `json:"slow"`
+	// Opened is the unix timestamp of when the breaker last opened
+	Opened int64 `json:"opened,omitempty"`
+	// Trips is the number of times the breaker opened
+	Trips int `json:"trips"`
+}
+
+type breaker struct {
+	sync.Mutex
+
+	opts  *Options
+	state State
+
+	// counts of the current window
+	window   time.Time
+	requests int
+	errors   int
+	slow     int
+
+	// opened is when the breaker last opened
+	opened time.Time
+	trips  int
+
+	// probes in flight and the successful probes while half open
+	probes    int
+	successes int
+
+	// used is when the breaker last recorded a call
+	used time.Time
+}
+
+func newBreaker(opts *Options, now time.Time) *breaker {
+	return &breaker{opts: opts, window: now, used: now}
+}
+
+// ready returns true if the breaker would allow a call
+func (b *breaker) ready(now time.Time) bool {
+	b.Lock()
+	defer b.Unlock()
+	return b.readyLocked(now)
+}
+
+func (b *breaker) readyLocked(now time.Time) bool {
+	switch b.state {
+	case Open:
+		if now.Sub(b.opened) < b.opts.Cooldown {
+			return false
+		}
+		b.state = HalfOpen
+		b.probes = 0
+		b.successes = 0
+		return true
+	case HalfOpen:
+		return b.probes < b.opts.Probes
+	default:
+		return true
+	}
+}
+
+// allow returns true if the call is allowed, calls allowed while half open are probes
+func (b *breaker) allow(now time.Time) bool {
+	b.Lock()
+	defer b.Unlock()
+
+	if !b.readyLocked(now) {
+		return false
+	}
+	if b.state == HalfOpen {
+		b.probes++
+	}
+	return true
+}
+
+// record the result of a call
+func (b *breaker) record(now time.Time, err error, d time.Duration) {
+	failed := isFailure(err)
+	slow := !failed && b.opts.Latency > 0 && d > b.opts.Latency
+
+	b.Lock()
+	defer b.Unlock()
+
+	b.used = now
+
+	switch b.state {
+	case Open:
+		// the call started before the breaker opened
+		return
+	case HalfOpen:
+		if b.probes > 0 {
+			b.probes--
+		}
+		if failed || slow {
+			b.trip(now)
+			return
+		}
+		if b.successes++; b.successes >= b.opts.Probes {
+			b.reset(now)
+			b.state = Closed
+		}
+		return
+	}
+
+	if now.Sub(b.window) >= b.opts.Window {
+		b.reset(now)
+	}
+
+	b.requests++
+	if failed {
+		b.errors++
+	} else if slow {
+		b.slow++
+	}
+
+	if b.requests < b.opts.MinRequests {
+		return
+	}
+	if float64(b.errors+b.slow)/float64(b.requests) >= b.opts.ErrorRate {
+		b.trip(now)
+	}
+}
+
+func (b *breaker) trip(now time.Time) {
+	b.state = Open
+	b.opened = now
+	b.trips++
+	b.probes = 0
+	b.successes = 0
+}
+
+func (b *breaker) reset(now time.Time) {
+	b.window = now
+	b.requests = 0
+	b.errors = 0
+	b.slow = 0
+}
+
+func (b *breaker) stat(k key) Stat {
+	b.Lock()
+	defer b.Unlock()
+
+	s := Stat{
+		Namespace: k.namespace,
+		Service:   k.service,
+		Node:      k.node,
+		State:     b.state.String(),
+		Requests:  b.requests,
+		Errors:    b.errors,
+		Slow:      b.slow,
+		Trips:     b.trips,
+	}
+	if !b.opened.IsZero() {
+		s.Opened = b.opened.Unix()
+	}
+	return s
+}
+
+// idle returns true if the breaker is closed and hasn't recorded a call since the time
+func (b *breaker) idle(since time.Time) bool {
+	b.Lock()
+	defer b.Unlock()
+	return b.state == Closed && b.used.Before(since)
+}
+
+// isFailure returns true if the error counts against the breaker. Errors returned by the service
+// for bad requests don't, timeouts and server or transport errors do.
+func isFailure(err error) bool {
+	if err == nil {
+		return false
+	}
+	verr := goerrors.FromError(err)
+	return verr.Code == 0 || verr.Code == 408 || verr.Code >= 500
+}
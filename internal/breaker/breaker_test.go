@@ -0,0 +1,116 @@
+package breaker
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/micro/go-micro/v3/client"
+	"github.com/micro/go-micro/v3/metadata"
+	"github.com/micro/go-micro/v3/selector/roundrobin"
+	"github.com/micro/micro/v3/service/errors"
+)
+
+func TestBreaker(t *testing.T) {
+	opts := newOptions(MinRequests(4), Latency(time.Second), Cooldown(time.Minute))
+	now := time.Now()
+	b := newBreaker(&opts, now)
+
+	// errors returned for bad requests don't count
+	for i := 0; i < 4; i++ {
+		b.record(now, errors.BadRequest("test", "bad request"), 0)
+	}
+	if !b.allow(now) {
+		t.Fatalf("Expected the breaker to be closed")
+	}
+
+	// the breaker opens once the error rate is exceeded
+	b.record(now.Add(opts.Window), nil, 0)
+	b.record(now.Add(opts.Window), errors.InternalServerError("test", "error"), 0)
+	b.record(now.Add(opts.Window), errors.Timeout("test", "timeout"), 0)
+	if !b.allow(now.Add(opts.Window)) {
+		t.Fatalf("Expected the breaker to be closed before the min requests")
+	}
+	b.record(now.Add(opts.Window), nil, time.Second*2)
+	if b.allow(now.Add(opts.Window)) {
+		t.Fatalf("Expected the breaker to be open")
+	}
+
+	// a probe is allowed after the cooldown and the breaker opens again if it fails
+	now = now.Add(opts.Window + opts.Cooldown)
+	if !b.allow(now) {
+		t.Fatalf("Expected a probe to be allowed")
+	}
+	if b.allow(now) {
+		t.Fatalf("Expected a single probe to be allowed")
+	}
+	b.record(now, errors.InternalServerError("test", "error"), 0)
+	if b.allow(now) {
+		t.Fatalf("Expected the breaker to open after the probe failed")
+	}
+
+	// the breaker closes when the probe succeeds
+	now = now.Add(opts.Cooldown)
+	if !b.allow(now) {
+		t.Fatalf("Expected a probe to be allowed")
+	}
+	b.record(now, nil, 0)
+	if s := b.stat(key{service: "test"}); s.State != "closed" || s.Trips != 2 {
+		t.Fatalf("Expected the breaker to be closed after tripping twice, got %+v", s)
+	}
+}
+
+func TestSelect(t *testing.T) {
+	b := New(MinRequests(1))
+	sel := &nodeSelector{Selector: roundrobin.NewSelector(), namespace: "micro", service: "foo", breakers: b}
+
+	b.get(key{namespace: "micro", service: "foo", node: "10.0.0.1:8080"}).record(time.Now(), errors.InternalServerError("test", "error"), 0)
+
+	// the node with an open breaker is ejected
+	next, err := sel.Select([]string{"10.0.0.1:8080", "10.0.0.2:8080"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 3; i++ {
+		if n := next(); n != "10.0.0.2:8080" {
+			t.Fatalf("Expected the failing node to be ejected, got %v", n)
+		}
+	}
+
+	// nodes aren't ejected if no others are available
+	next, err = sel.Select([]string{"10.0.0.1:8080"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := next(); n != "10.0.0.1:8080" {
+		t.Fatalf("Expected the only node to be selected, got %v", n)
+	}
+
+	stats := b.Stats()
+	if len(stats) != 1 || stats[0].Node != "10.0.0.1:8080" || stats[0].State != "open" {
+		t.Fatalf("Unexpected stats: %+v", stats)
+	}
+}
+
+func TestNamespace(t *testing.T) {
+	c := &breakerClient{breakers: New(MinRequests(1))}
+	ctx := metadata.Set(context.Background(), "Micro-Namespace", "foo")
+
+	// the breaker of a service in one namespace doesn't affect those of others
+	k := key{namespace: callNamespace(ctx, client.CallOptions{}), service: "helloworld"}
+	c.breakers.get(k).record(time.Now(), errors.InternalServerError("test", "error"), 0)
+	if c.breakers.ready(k, time.Now()) {
+		t.Fatalf("Expected the breaker of the service in namespace foo to be open")
+	}
+	if !c.breakers.ready(key{namespace: "micro", service: "helloworld"}, time.Now()) {
+		t.Fatalf("Expected the breaker of the service in namespace micro to be closed")
+	}
+
+	// the network of the call takes precedence over the namespace of the request
+	if ns := callNamespace(ctx, client.CallOptions{Network: "bar"}); ns != "bar" {
+		t.Errorf("Expected namespace bar, got %v", ns)
+	}
+	if ns := callNamespace(context.Background(), client.CallOptions{}); ns != "micro" {
+		t.Errorf("Expected namespace micro, got %v", ns)
+	}
+}
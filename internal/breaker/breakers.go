@@ -0,0 +1,228 @@
+package breaker
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/micro/go-micro/v3/client"
+	"github.com/micro/go-micro/v3/metadata"
+	"github.com/micro/go-micro/v3/selector"
+	"github.com/micro/micro/v3/internal/namespace"
+	"github.com/micro/micro/v3/service/errors"
+)
+
+// idleTimeout is how long a closed breaker is kept without calls
+const idleTimeout = time.Minute * 10
+
+// DefaultBreakers are the breakers reported by the stats of the service
+var DefaultBreakers = New()
+
+// Breakers are the breakers of the services and nodes called
+type Breakers struct {
+	opts Options
+
+	sync.RWMutex
+	breakers map[key]*breaker
+	pruned   time.Time
+}
+
+// key of a breaker, the node is blank for the breaker of the service. Services with the same
+// name in different namespaces have their own breakers.
+type key struct {
+	namespace string
+	service   string
+	node      string
+}
+
+// New returns breakers with the options
+func New(opts ...Option) *Breakers {
+	return &Breakers{
+		opts:     newOptions(opts...),
+		breakers: make(map[key]*breaker),
+		pruned:   time.Now(),
+	}
+}
+
+// Stats returns the state of the breakers
+func (b *Breakers) Stats() []Stat {
+	b.RLock()
+	stats := make([]Stat, 0, len(b.breakers))
+	for k, br := range b.breakers {
+		stats = append(stats, br.stat(k))
+	}
+	b.RUnlock()
+
+	sort.Slice(stats, func(i, j int) bool {
+		if stats[i].Namespace != stats[j].Namespace {
+			return stats[i].Namespace < stats[j].Namespace
+		}
+		if stats[i].Service != stats[j].Service {
+			return stats[i].Service < stats[j].Service
+		}
+		return stats[i].Node < stats[j].Node
+	})
+	return stats
+}
+
+// Wrapper is a client.Wrapper which applies the breakers to the calls made by the client
+func (b *Breakers) Wrapper(c client.Client) client.Client {
+	return &breakerClient{Client: c, breakers: b}
+}
+
+// get returns the breaker for the service or node, creating it if it doesn't exist
+func (b *Breakers) get(k key) *breaker {
+	b.RLock()
+	br, ok := b.breakers[k]
+	b.RUnlock()
+	if ok {
+		return br
+	}
+
+	now := time.Now()
+
+	b.Lock()
+	defer b.Unlock()
+
+	if br, ok := b.breakers[k]; ok {
+		return br
+	}
+
+	// remove the breakers of nodes which are no longer called
+	if now.Sub(b.pruned) > b.opts.Window {
+		for k, br := range b.breakers {
+			if br.idle(now.Add(-idleTimeout)) {
+				delete(b.breakers, k)
+			}
+		}
+		b.pruned = now
+	}
+
+	br = newBreaker(&b.opts, now)
+	b.breakers[k] = br
+	return br
+}
+
+// ready returns true if the breaker of the node would allow a call
+func (b *Breakers) ready(k key, now time.Time) bool {
+	b.RLock()
+	br, ok := b.breakers[k]
+	b.RUnlock()
+	return !ok || br.ready(now)
+}
+
+type breakerClient struct {
+	client.Client
+	breakers *Breakers
+}
+
+func (c *breakerClient) Call(ctx context.Context, req client.Request, rsp interface{}, opts ...client.CallOption) error {
+	options := callOptions(opts)
+	br := c.breakers.get(key{namespace: callNamespace(ctx, options), service: req.Service()})
+	if !br.allow(time.Now()) {
+		return errors.ServiceUnavailable("go.micro.client", "circuit breaker for %v is open", req.Service())
+	}
+
+	start := time.Now()
+	err := c.Client.Call(ctx, req, rsp, append(opts, c.callOptions(ctx, req, options)...)...)
+	br.record(time.Now(), err, time.Since(start))
+	return err
+}
+
+func (c *breakerClient) Stream(ctx context.Context, req client.Request, opts ...client.CallOption) (client.Stream, error) {
+	options := callOptions(opts)
+	br := c.breakers.get(key{namespace: callNamespace(ctx, options), service: req.Service()})
+	if !br.allow(time.Now()) {
+		return nil, errors.ServiceUnavailable("go.micro.client", "circuit breaker for %v is open", req.Service())
+	}
+
+	// only errors creating the stream are recorded, streams are long lived so aren't slow
+	stream, err := c.Client.Stream(ctx, req, append(opts, c.callOptions(ctx, req, options)...)...)
+	br.record(time.Now(), err, 0)
+	return stream, err
+}
+
+// callOptions applies the options of a call
+func callOptions(opts []client.CallOption) client.CallOptions {
+	var options client.CallOptions
+	for _, o := range opts {
+		o(&options)
+	}
+	return options
+}
+
+// callNamespace returns the namespace of the service being called, the network of the call or
+// the namespace of the request if it isn't set
+func callNamespace(ctx context.Context, options client.CallOptions) string {
+	if len(options.Network) > 0 {
+		return options.Network
+	}
+	if ns, ok := metadata.Get(ctx, "Micro-Namespace"); ok && len(ns) > 0 {
+		return ns
+	}
+	return namespace.DefaultNamespace
+}
+
+// callOptions returns the options which eject the nodes with an open breaker from selection and
+// record the result of the calls made to each node
+func (c *breakerClient) callOptions(ctx context.Context, req client.Request, options client.CallOptions) []client.CallOption {
+	sel := options.Selector
+	if sel == nil {
+		sel = c.Client.Options().Selector
+	}
+
+	ns, service := callNamespace(ctx, options), req.Service()
+	wrapper := func(cf client.CallFunc) client.CallFunc {
+		return func(ctx context.Context, addr string, req client.Request, rsp interface{}, opts client.CallOptions) error {
+			br := c.breakers.get(key{namespace: ns, service: service, node: addr})
+			br.allow(time.Now())
+
+			start := time.Now()
+			err := cf(ctx, addr, req, rsp, opts)
+
+			var d time.Duration
+			if !req.Stream() {
+				d = time.Since(start)
+			}
+			br.record(time.Now(), err, d)
+			return err
+		}
+	}
+
+	return []client.CallOption{
+		client.WithSelector(&nodeSelector{Selector: sel, namespace: ns, service: service, breakers: c.breakers}),
+		func(o *client.CallOptions) {
+			// the wrappers are copied so the wrappers of the client's options aren't appended to
+			n := len(o.CallWrappers)
+			o.CallWrappers = append(o.CallWrappers[:n:n], wrapper)
+		},
+	}
+}
+
+// nodeSelector ejects the nodes with an open breaker from the routes selected from
+type nodeSelector struct {
+	selector.Selector
+	namespace string
+	service   string
+	breakers  *Breakers
+}
+
+func (s *nodeSelector) Select(routes []string, opts ...selector.SelectOption) (selector.Next, error) {
+	now := time.Now()
+
+	available := make([]string, 0, len(routes))
+	for _, r := range routes {
+		if s.breakers.ready(key{namespace: s.namespace, service: s.service, node: r}, now) {
+			available = append(available, r)
+		}
+	}
+
+	// when every node is ejected the calls are spread over all of them rather than failing, the
+	// breaker of the service fails fast if they're all down
+	if len(available) == 0 {
+		available = routes
+	}
+
+	return s.Selector.Select(available, opts...)
+}
@@ -0,0 +1,84 @@
+package breaker
+
+import "time"
+
+// Options of the breakers
+type Options struct {
+	// ErrorRate of the calls in a window which opens the breaker, slow calls count as errors
+	ErrorRate float64
+	// MinRequests in a window before the breaker can open
+	MinRequests int
+	// Latency above which calls are slow, slow calls aren't counted if zero
+	Latency time.Duration
+	// Window the error rate is calculated over
+	Window time.Duration
+	// Cooldown is how long the breaker stays open before probing
+	Cooldown time.Duration
+	// Probes which must succeed while half open for the breaker to close
+	Probes int
+}
+
+// Option sets an option
+type Option func(o *Options)
+
+// ErrorRate sets the error rate which opens the breaker, e.g. 0.5
+func ErrorRate(r float64) Option {
+	return func(o *Options) {
+		o.ErrorRate = r
+	}
+}
+
+// MinRequests sets the requests in a window before the breaker can open
+func MinRequests(n int) Option {
+	return func(o *Options) {
+		o.MinRequests = n
+	}
+}
+
+// Latency sets the latency above which calls are slow
+func Latency(d time.Duration) Option {
+	return func(o *Options) {
+		o.Latency = d
+	}
+}
+
+// Window sets the window the error rate is calculated over
+func Window(d time.Duration) Option {
+	return func(o *Options) {
+		o.Window = d
+	}
+}
+
+// Cooldown sets how long the breaker stays open before probing
+func Cooldown(d time.Duration) Option {
+	return func(o *Options) {
+		o.Cooldown = d
+	}
+}
+
+// Probes sets the probes which must succeed for the breaker to close
+func Probes(n int) Option {
+	return func(o *Options) {
+		o.Probes = n
+	}
+}
+
+func newOptions(opts ...Option) Options {
+	options := Options{
+		ErrorRate:   0.5,
+		MinRequests: 20,
+		Window:      time.Second * 10,
+		Cooldown:    time.Second * 30,
+		Probes:      1,
+	}
+	for _, o := range opts {
+		o(&options)
+	}
+	if options.Probes < 1 {
+		options.Probes = 1
+	}
+	if options.MinRequests < 1 {
+		options.MinRequests = 1
+	}
+	return options
+}
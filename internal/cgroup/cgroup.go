@@ -0,0 +1,54 @@
+// Package cgroup enforces the resource limits of services run by the local runtime
+package cgroup
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+
+	"github.com/micro/go-micro/v3/runtime"
+)
+
+const (
+	// joinScript moves the shell into the cgroup passed as $0 and then execs the command, so the
+	// process of the command is in the cgroup before any of its code runs
+	joinScript = `echo $$ > "$0/cgroup.procs" && exec "$@"`
+)
+
+var (
+	// Root is the cgroup the service cgroups are created under. The cpu and memory controllers
+	// must be enabled for it by its parent, e.g. by systemd delegating it to the runtime.
+	Root = "/sys/fs/cgroup/micro"
+	// ErrNotSupported is returned when cgroups v2 aren't available
+	ErrNotSupported = errors.New("cgroups v2 are not supported on this system")
+	// ErrNotDelegated is returned when the controllers aren't enabled for the root cgroup
+	ErrNotDelegated = errors.New("the cpu and memory controllers are not enabled for the cgroup")
+)
+
+// Command returns the command and args which run the command in the cgroup at the path
+func Command(path string, command, args []string) ([]string, []string) {
+	return []string{"/bin/sh"}, append(append([]string{"-c", joinScript, path}, command...), args...)
+}
+
+// name returns the cgroup name of a service, cgroup names can't contain slashes
+func name(namespace, service, version string) string {
+	r := strings.NewReplacer("/", "-", ":", "-")
+	return r.Replace(namespace + "." + service + "." + version)
+}
+
+// limits returns the cgroup v2 interface files to write for the resources. Disk space can't
+// be limited by cgroups so it's only enforced on kubernetes.
+func limits(r *runtime.Resources) map[string]string {
+	l := make(map[string]string)
+	if r == nil {
+		return l
+	}
+	// cpu.max is the quota in microseconds per period, e.g. 250 millicpu is 25ms every 100ms
+	if r.CPU > 0 {
+		l["cpu.max"] = strconv.Itoa(r.CPU*100) + " 100000"
+	}
+	if r.Mem > 0 {
+		l["memory.max"] = strconv.Itoa(r.Mem * 1024 * 1024)
+	}
+	return l
+}
@@ -0,0 +1,71 @@
+package cgroup
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/micro/go-micro/v3/runtime"
+)
+
+// supported returns true if the unified cgroup v2 hierarchy is mounted
+func supported() bool {
+	_, err := os.Stat(filepath.Join(filepath.Dir(Root), "cgroup.controllers"))
+	return err == nil
+}
+
+// delegated returns true if the cpu and memory controllers are available in the root cgroup
+func delegated() bool {
+	b, err := ioutil.ReadFile(filepath.Join(Root, "cgroup.controllers"))
+	if err != nil {
+		return false
+	}
+	var cpu, mem bool
+	for _, c := range strings.Fields(string(b)) {
+		cpu = cpu || c == "cpu"
+		mem = mem || c == "memory"
+	}
+	return cpu && mem
+}
+
+// Create the cgroup of a service and apply the resource limits, the path of the cgroup is
+// returned for the runtime to start the processes of the service in. Only the root cgroup is
+// modified, its parent has to enable the controllers for it.
+func Create(namespace, service, version string, r *runtime.Resources) (string, error) {
+	if !supported() {
+		return "", ErrNotSupported
+	}
+	if err := os.MkdirAll(Root, 0755); err != nil {
+		return "", err
+	}
+	if !delegated() {
+		return "", ErrNotDelegated
+	}
+
+	// enable the controllers for the cgroups of the services
+	if err := ioutil.WriteFile(filepath.Join(Root, "cgroup.subtree_control"), []byte("+cpu +memory"), 0644); err != nil {
+		return "", err
+	}
+
+	path := filepath.Join(Root, name(namespace, service, version))
+	if err := os.MkdirAll(path, 0755); err != nil {
+		return "", err
+	}
+	for file, value := range limits(r) {
+		if err := ioutil.WriteFile(filepath.Join(path, file), []byte(value), 0644); err != nil {
+			return "", err
+		}
+	}
+
+	return path, nil
+}
+
+// Delete the cgroup of a service, it can only be removed once its processes have exited
+func Delete(namespace, service, version string) error {
+	err := os.Remove(filepath.Join(Root, name(namespace, service, version)))
+	if os.IsNotExist(err) {
+		return nil
+	}
+	return err
+}
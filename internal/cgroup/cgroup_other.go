@@ -0,0 +1,18 @@
+//go:build !linux
+// +build !linux
+
+package cgroup
+
+import (
+	"github.com/micro/go-micro/v3/runtime"
+)
+
+// Create is not supported on this platform
+func Create(namespace, service, version string, r *runtime.Resources) (string, error) {
+	return "", ErrNotSupported
+}
+
+// Delete is not supported on this platform
+func Delete(namespace, service, version string) error {
+	return nil
+}
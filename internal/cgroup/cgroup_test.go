@@ -0,0 +1,51 @@
+package cgroup
+
+import (
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/micro/go-micro/v3/runtime"
+)
+
+func TestLimits(t *testing.T) {
+	l := limits(&runtime.Resources{CPU: 250, Mem: 128, Disk: 1024})
+	if l["cpu.max"] != "25000 100000" {
+		t.Fatalf("Expected cpu.max 25000 100000, got %q", l["cpu.max"])
+	}
+	if l["memory.max"] != "134217728" {
+		t.Fatalf("Expected memory.max 134217728, got %q", l["memory.max"])
+	}
+	if len(l) != 2 {
+		t.Fatalf("Expected only cpu and memory limits, got %v", l)
+	}
+
+	if n := name("micro", "github.com/micro/services/helloworld", "latest"); n != "micro.github.com-micro-services-helloworld.latest" {
+		t.Fatalf("Unexpected cgroup name %q", n)
+	}
+}
+
+func TestCommand(t *testing.T) {
+	dir, err := ioutil.TempDir("", "cgroup")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	cmd, args := Command(dir, []string{"/bin/sh"}, []string{"-c", "echo $$"})
+	out, err := exec.Command(cmd[0], args...).Output()
+	if err != nil {
+		t.Fatalf("Unexpected error running the command: %v", err)
+	}
+
+	// the process of the command is the one which was moved into the cgroup
+	procs, err := ioutil.ReadFile(filepath.Join(dir, "cgroup.procs"))
+	if err != nil {
+		t.Fatalf("Expected the process to be moved into the cgroup: %v", err)
+	}
+	if len(out) == 0 || string(procs) != string(out) {
+		t.Errorf("Expected process %q to be moved into the cgroup, got %q", out, procs)
+	}
+}
@@ -20,6 +20,7 @@ import (
 	"github.com/micro/micro/v3/service/client"
 	proto "github.com/micro/micro/v3/service/debug/proto"
 	"github.com/micro/micro/v3/service/registry"
+	regclient "github.com/micro/micro/v3/service/registry/client"
 
 	"github.com/serenize/snaker"
 )
@@ -159,7 +160,15 @@ func ListServices(c *cli.Context) ([]byte, error) {
 		return nil, err
 	}
 
-	rsp, err = registry.ListServices(goregistry.ListDomain(ns))
+	opts := []goregistry.ListOption{goregistry.ListDomain(ns)}
+	if sel := c.String("selector"); len(sel) > 0 {
+		opts = append(opts, regclient.ListSelector(sel))
+	}
+	if c.Bool("all") {
+		opts = append(opts, regclient.ListAll())
+	}
+
+	rsp, err = registry.ListServices(opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,222 @@
+// Package cron parses cron schedules and calculates when they're next due
+package cron
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	// ErrInvalidSchedule is returned when a schedule can't be parsed
+	ErrInvalidSchedule = errors.New("invalid schedule")
+
+	// descriptors are shorthands for common schedules
+	descriptors = map[string]string{
+		"@yearly":   "0 0 1 1 *",
+		"@annually": "0 0 1 1 *",
+		"@monthly":  "0 0 1 * *",
+		"@weekly":   "0 0 * * 0",
+		"@daily":    "0 0 * * *",
+		"@midnight": "0 0 * * *",
+		"@hourly":   "0 * * * *",
+	}
+
+	months = map[string]int{
+		"jan": 1, "feb": 2, "mar": 3, "apr": 4, "may": 5, "jun": 6,
+		"jul": 7, "aug": 8, "sep": 9, "oct": 10, "nov": 11, "dec": 12,
+	}
+	days = map[string]int{
+		"sun": 0, "mon": 1, "tue": 2, "wed": 3, "thu": 4, "fri": 5, "sat": 6,
+	}
+)
+
+// field is the range of values a field of the schedule accepts
+type field struct {
+	min, max int
+	names    map[string]int
+}
+
+var (
+	minuteField = field{0, 59, nil}
+	hourField   = field{0, 23, nil}
+	domField    = field{1, 31, nil}
+	monthField  = field{1, 12, months}
+	// 7 is accepted as sunday as well as 0
+	dowField = field{0, 7, days}
+)
+
+// Schedule is a parsed cron schedule
+type Schedule struct {
+	minute, hour, dom, month, dow uint64
+	// domAny and dowAny are true when the day of month or week is "*", a day matches if either
+	// day field matches when both are restricted
+	domAny, dowAny bool
+	// every is the interval of "@every" schedules
+	every time.Duration
+}
+
+// Parse a schedule in the standard five field format "minute hour day-of-month month day-of-week",
+// e.g. "30 2 * * 1-5", or a descriptor such as "@daily" or "@every 1h30m"
+func Parse(spec string) (*Schedule, error) {
+	spec = strings.TrimSpace(spec)
+
+	if strings.HasPrefix(spec, "@every ") {
+		d, err := time.ParseDuration(strings.TrimSpace(strings.TrimPrefix(spec, "@every ")))
+		if err != nil || d < time.Second {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidSchedule, spec)
+		}
+		return &Schedule{every: d}, nil
+	}
+	if d, ok := descriptors[spec]; ok {
+		spec = d
+	}
+
+	fields := strings.Fields(spec)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("%w: expected 5 fields, got %d", ErrInvalidSchedule, len(fields))
+	}
+
+	s := &Schedule{
+		domAny: fields[2] == "*" || fields[2] == "?",
+		dowAny: fields[4] == "*" || fields[4] == "?",
+	}
+
+	var err error
+	if s.minute, err = parseField(fields[0], minuteField); err != nil {
+		return nil, err
+	}
+	if s.hour, err = parseField(fields[1], hourField); err != nil {
+		return nil, err
+	}
+	if s.dom, err = parseField(fields[2], domField); err != nil {
+		return nil, err
+	}
+	if s.month, err = parseField(fields[3], monthField); err != nil {
+		return nil, err
+	}
+	if s.dow, err = parseField(fields[4], dowField); err != nil {
+		return nil, err
+	}
+
+	// sunday can be written as 7
+	if s.dow&(1<<7) > 0 {
+		s.dow |= 1
+	}
+
+	return s, nil
+}
+
+// parseField parses a comma separated list of values, ranges and steps e.g. "1,5-10,*/15"
+func parseField(value string, f field) (uint64, error) {
+	var bits uint64
+
+	for _, part := range strings.Split(value, ",") {
+		step := 1
+		if i := strings.Index(part, "/"); i >= 0 {
+			n, err := strconv.Atoi(part[i+1:])
+			if err != nil || n <= 0 {
+				return 0, fmt.Errorf("%w: invalid step %q", ErrInvalidSchedule, part)
+			}
+			step = n
+			part = part[:i]
+		}
+
+		start, end := f.min, f.max
+		switch {
+		case part == "*" || part == "?":
+		case strings.Contains(part, "-"):
+			bounds := strings.SplitN(part, "-", 2)
+			var err error
+			if start, err = parseValue(bounds[0], f); err != nil {
+				return 0, err
+			}
+			if end, err = parseValue(bounds[1], f); err != nil {
+				return 0, err
+			}
+		default:
+			n, err := parseValue(part, f)
+			if err != nil {
+				return 0, err
+			}
+			start = n
+			// "5/15" means every 15 starting from 5
+			if step == 1 {
+				end = n
+			}
+		}
+		if start > end {
+			return 0, fmt.Errorf("%w: invalid range %q", ErrInvalidSchedule, part)
+		}
+
+		for i := start; i <= end; i += step {
+			bits |= 1 << uint(i)
+		}
+	}
+
+	return bits, nil
+}
+
+// parseValue parses a number or name within the bounds of the field
+func parseValue(value string, f field) (int, error) {
+	if n, ok := f.names[strings.ToLower(value)]; ok {
+		return n, nil
+	}
+
+	n, err := strconv.Atoi(value)
+	if err != nil || n < f.min || n > f.max {
+		return 0, fmt.Errorf("%w: invalid value %q", ErrInvalidSchedule, value)
+	}
+	return n, nil
+}
+
+// Next returns the first time after t the schedule is due. The zero time is returned if the
+// schedule is never due, e.g. "0 0 30 2 *".
+func (s *Schedule) Next(t time.Time) time.Time {
+	if s.every > 0 {
+		return t.Add(s.every).Truncate(time.Second)
+	}
+
+	// schedules have a resolution of a minute
+	t = t.Truncate(time.Minute).Add(time.Minute)
+	loc := t.Location()
+	limit := t.AddDate(5, 0, 0)
+
+	for t.Before(limit) {
+		if !has(s.month, int(t.Month())) {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, loc)
+			continue
+		}
+		if !s.dayMatches(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, loc)
+			continue
+		}
+		if !has(s.hour, t.Hour()) {
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, loc)
+			continue
+		}
+		if !has(s.minute, t.Minute()) {
+			t = t.Add(time.Minute)
+			continue
+		}
+		return t
+	}
+
+	return time.Time{}
+}
+
+func (s *Schedule) dayMatches(t time.Time) bool {
+	dom := has(s.dom, t.Day())
+	dow := has(s.dow, int(t.Weekday()))
+
+	if s.domAny || s.dowAny {
+		return dom && dow
+	}
+	return dom || dow
+}
+
+func has(bits uint64, i int) bool {
+	return bits&(1<<uint(i)) > 0
+}
@@ -0,0 +1,49 @@
+package cron
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNext(t *testing.T) {
+	// a wednesday
+	now := time.Date(2020, 9, 2, 10, 30, 15, 0, time.UTC)
+
+	tt := []struct {
+		Schedule string
+		Next     time.Time
+	}{
+		{"* * * * *", time.Date(2020, 9, 2, 10, 31, 0, 0, time.UTC)},
+		{"*/15 * * * *", time.Date(2020, 9, 2, 10, 45, 0, 0, time.UTC)},
+		{"0 2 * * *", time.Date(2020, 9, 3, 2, 0, 0, 0, time.UTC)},
+		{"@daily", time.Date(2020, 9, 3, 0, 0, 0, 0, time.UTC)},
+		{"@hourly", time.Date(2020, 9, 2, 11, 0, 0, 0, time.UTC)},
+		{"0 9 * * mon-fri", time.Date(2020, 9, 3, 9, 0, 0, 0, time.UTC)},
+		{"0 0 * * 7", time.Date(2020, 9, 6, 0, 0, 0, 0, time.UTC)},
+		{"0 0 1 jan *", time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{"0 0 13 * 5", time.Date(2020, 9, 4, 0, 0, 0, 0, time.UTC)},
+		{"0 0 29 2 *", time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC)},
+		{"@every 90s", time.Date(2020, 9, 2, 10, 31, 45, 0, time.UTC)},
+		{"0 0 30 2 *", time.Time{}},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.Schedule, func(t *testing.T) {
+			s, err := Parse(tc.Schedule)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if next := s.Next(now); !next.Equal(tc.Next) {
+				t.Errorf("Expected %v, got %v", tc.Next, next)
+			}
+		})
+	}
+}
+
+func TestParseInvalid(t *testing.T) {
+	for _, spec := range []string{"", "* * * *", "60 * * * *", "* 24 * * *", "5-1 * * * *", "*/0 * * * *", "@every 1x"} {
+		if _, err := Parse(spec); err == nil {
+			t.Errorf("Expected an error parsing %q", spec)
+		}
+	}
+}
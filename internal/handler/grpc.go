@@ -0,0 +1,351 @@
+package handler
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/micro/go-micro/v3/api/handler"
+	"github.com/micro/go-micro/v3/api/resolver"
+	goclient "github.com/micro/go-micro/v3/client"
+	cbytes "github.com/micro/go-micro/v3/codec/bytes"
+	goerrors "github.com/micro/go-micro/v3/errors"
+	"github.com/micro/micro/v3/internal/helper"
+	"github.com/micro/micro/v3/internal/namespace"
+	rrmicro "github.com/micro/micro/v3/internal/resolver/api"
+	"github.com/micro/micro/v3/service/client"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// grpcFrameHeaderSize is the size of the header of a message, a flag and the length
+	grpcFrameHeaderSize = 5
+	// grpcFlagCompressed is set on compressed messages
+	grpcFlagCompressed = 0x01
+	// grpcFlagTrailer is set on the frame of trailers in grpc-web responses
+	grpcFlagTrailer = 0x80
+	// grpcMaxMessageSize is the max size of a request message
+	grpcMaxMessageSize = 4 * 1024 * 1024
+)
+
+// IsGRPC returns true if the request is a gRPC or gRPC-Web request
+func IsGRPC(r *http.Request) bool {
+	ct := r.Header.Get("Content-Type")
+	return r.Method == "POST" && (ct == "application/grpc" || strings.HasPrefix(ct, "application/grpc+") ||
+		strings.HasPrefix(ct, "application/grpc-web"))
+}
+
+// isGRPCWeb returns true if the request is a gRPC-Web request
+func isGRPCWeb(r *http.Request) bool {
+	return strings.HasPrefix(r.Header.Get("Content-Type"), "application/grpc-web")
+}
+
+// isGRPCWebText returns true if the messages of the request are base64 encoded
+func isGRPCWebText(r *http.Request) bool {
+	return strings.HasPrefix(r.Header.Get("Content-Type"), "application/grpc-web-text")
+}
+
+// grpcRoute returns the service and endpoint of a gRPC request with the path /package.Service/Method,
+// e.g. /helloworld.Helloworld/Call is the Helloworld.Call endpoint of the helloworld service
+func grpcRoute(path string) (string, string, error) {
+	parts := strings.Split(strings.TrimPrefix(path, "/"), "/")
+	if len(parts) != 2 || len(parts[1]) == 0 {
+		return "", "", errors.New("invalid path")
+	}
+	idx := strings.LastIndex(parts[0], ".")
+	if idx <= 0 || idx == len(parts[0])-1 {
+		return "", "", errors.New("invalid path")
+	}
+	return parts[0][:idx], parts[0][idx+1:] + "." + parts[1], nil
+}
+
+// grpcResolver resolves gRPC requests using their path, other requests are resolved by the
+// resolver it wraps
+type grpcResolver struct {
+	resolver.Resolver
+	prefix string
+}
+
+func (g *grpcResolver) Resolve(req *http.Request, opts ...resolver.ResolveOption) (*resolver.Endpoint, error) {
+	if !IsGRPC(req) {
+		return g.Resolver.Resolve(req, opts...)
+	}
+
+	name, method, err := grpcRoute(req.URL.Path)
+	if err != nil {
+		return nil, resolver.ErrInvalidPath
+	}
+	if len(g.prefix) > 0 {
+		name = g.prefix + "." + name
+	}
+
+	domain := rrmicro.ResolveDomain(g.Resolver, req, opts...)
+	return &resolver.Endpoint{Name: name, Method: method, Domain: domain}, nil
+}
+
+// Domain returns the domain of the request if the wrapped resolver determines it from the
+// request, e.g. the subdomain resolver
+func (g *grpcResolver) Domain(req *http.Request) string {
+	return rrmicro.Domain(g.Resolver, req)
+}
+
+// NewGRPCResolver returns a resolver which resolves gRPC requests to the service and endpoint in
+// their path, the same as the gRPC handler, so they're authorized the same as JSON requests
+func NewGRPCResolver(r resolver.Resolver, prefix string) resolver.Resolver {
+	return &grpcResolver{Resolver: r, prefix: prefix}
+}
+
+type grpcHandler struct {
+	prefix string
+}
+
+func (h *grpcHandler) String() string {
+	return "internal/grpc"
+}
+
+// ServeHTTP forwards a gRPC or gRPC-Web request to a service. The messages are passed on as they
+// are using the proto codec, rather than being transcoded to JSON.
+func (h *grpcHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+
+	web := isGRPCWeb(r)
+	if !web && r.ProtoMajor != 2 {
+		http.Error(w, "gRPC requires HTTP/2", http.StatusHTTPVersionNotSupported)
+		return
+	}
+
+	// the response has the same content type as the request, e.g. application/grpc-web+proto
+	w.Header().Set("Content-Type", r.Header.Get("Content-Type"))
+	if web {
+		w.Header().Set("Access-Control-Expose-Headers", "grpc-status, grpc-message")
+	} else {
+		w.Header().Set("Trailer", "Grpc-Status, Grpc-Message")
+	}
+	out := newGRPCWriter(w, web, isGRPCWebText(r))
+
+	service, endpoint, err := grpcRoute(r.URL.Path)
+	if err != nil {
+		out.finish(status.Error(codes.Unimplemented, "invalid path "+r.URL.Path))
+		return
+	}
+	if len(h.prefix) > 0 {
+		service = h.prefix + "." + service
+	}
+
+	var body io.Reader = r.Body
+	if isGRPCWebText(r) {
+		body = base64.NewDecoder(base64.StdEncoding, r.Body)
+	}
+	msgs, err := readGRPCMessages(body)
+	if err != nil {
+		out.finish(err)
+		return
+	}
+
+	// create the context with the metadata of the request, e.g. the auth token
+	ctx := helper.RequestToContext(r)
+
+	var opts []goclient.CallOption
+	if ns := r.Header.Get(namespace.NamespaceKey); len(ns) > 0 {
+		opts = append(opts, goclient.WithNetwork(ns))
+	}
+	if timeout, err := parseGRPCTimeout(r.Header.Get("Grpc-Timeout")); err == nil && timeout > 0 {
+		opts = append(opts, goclient.WithRequestTimeout(timeout))
+	}
+
+	// a stream is used for every request so server streams can be forwarded as well
+	req := client.NewRequest(service, endpoint, &cbytes.Frame{}, goclient.WithContentType("application/grpc+proto"))
+	stream, err := client.Stream(ctx, req, opts...)
+	if err != nil {
+		out.finish(err)
+		return
+	}
+	defer stream.Close()
+
+	for _, m := range msgs {
+		if err := stream.Send(&cbytes.Frame{Data: m}); err != nil {
+			out.finish(err)
+			return
+		}
+	}
+	if cs, ok := stream.(interface{ CloseSend() error }); ok {
+		cs.CloseSend()
+	}
+
+	for {
+		var rsp cbytes.Frame
+		if err := stream.Recv(&rsp); err == io.EOF {
+			break
+		} else if err != nil {
+			out.finish(err)
+			return
+		}
+		if err := out.write(rsp.Data); err != nil {
+			return
+		}
+	}
+
+	out.finish(nil)
+}
+
+// NewGRPCHandler returns a handler which forwards gRPC and gRPC-Web requests to services, the
+// prefix is prepended to the names of the services
+func NewGRPCHandler(prefix string) handler.Handler {
+	return &grpcHandler{prefix: prefix}
+}
+
+// readGRPCMessages reads the length prefixed messages of a request
+func readGRPCMessages(r io.Reader) ([][]byte, error) {
+	br := bufio.NewReader(r)
+
+	var msgs [][]byte
+	for {
+		header := make([]byte, grpcFrameHeaderSize)
+		if _, err := io.ReadFull(br, header); err == io.EOF {
+			return msgs, nil
+		} else if err != nil {
+			return nil, status.Error(codes.InvalidArgument, "invalid message: "+err.Error())
+		}
+
+		if header[0]&grpcFlagCompressed != 0 {
+			return nil, status.Error(codes.Unimplemented, "compressed messages are not supported")
+		}
+		size := binary.BigEndian.Uint32(header[1:])
+		if size > grpcMaxMessageSize {
+			return nil, status.Errorf(codes.ResourceExhausted, "message larger than max (%d vs. %d)", size, grpcMaxMessageSize)
+		}
+
+		msg := make([]byte, size)
+		if _, err := io.ReadFull(br, msg); err != nil {
+			return nil, status.Error(codes.InvalidArgument, "invalid message: "+err.Error())
+		}
+		msgs = append(msgs, msg)
+	}
+}
+
+// grpcWriter writes the messages and status of a response. gRPC responses have the status in
+// the trailers, gRPC-Web responses have it in a frame at the end of the body since browsers
+// can't read trailers.
+type grpcWriter struct {
+	w    http.ResponseWriter
+	web  bool
+	text bool
+}
+
+func newGRPCWriter(w http.ResponseWriter, web, text bool) *grpcWriter {
+	return &grpcWriter{w: w, web: web, text: text}
+}
+
+// write a message frame
+func (g *grpcWriter) write(msg []byte) error {
+	return g.writeFrame(0, msg)
+}
+
+func (g *grpcWriter) writeFrame(flag byte, data []byte) error {
+	frame := make([]byte, grpcFrameHeaderSize+len(data))
+	frame[0] = flag
+	binary.BigEndian.PutUint32(frame[1:], uint32(len(data)))
+	copy(frame[grpcFrameHeaderSize:], data)
+
+	// each frame is encoded separately so they can be decoded as they're received
+	if g.text {
+		frame = []byte(base64.StdEncoding.EncodeToString(frame))
+	}
+	if _, err := g.w.Write(frame); err != nil {
+		return err
+	}
+	if f, ok := g.w.(http.Flusher); ok {
+		f.Flush()
+	}
+	return nil
+}
+
+// finish writes the status of the response
+func (g *grpcWriter) finish(err error) {
+	st := grpcStatus(err)
+
+	if !g.web {
+		g.w.Header().Set("Grpc-Status", strconv.Itoa(int(st.Code())))
+		g.w.Header().Set("Grpc-Message", st.Message())
+		return
+	}
+
+	var trailer bytes.Buffer
+	fmt.Fprintf(&trailer, "grpc-status: %d\r\n", st.Code())
+	if len(st.Message()) > 0 {
+		fmt.Fprintf(&trailer, "grpc-message: %s\r\n", st.Message())
+	}
+	g.writeFrame(grpcFlagTrailer, trailer.Bytes())
+}
+
+// grpcStatus returns the status of the error, micro errors are mapped to the equivalent code
+func grpcStatus(err error) *status.Status {
+	if err == nil {
+		return status.New(codes.OK, "")
+	}
+	if st, ok := status.FromError(err); ok {
+		return st
+	}
+
+	merr := goerrors.Parse(err.Error())
+	msg := merr.Detail
+	if len(msg) == 0 {
+		msg = err.Error()
+	}
+
+	switch merr.Code {
+	case http.StatusBadRequest:
+		return status.New(codes.InvalidArgument, msg)
+	case http.StatusUnauthorized:
+		return status.New(codes.Unauthenticated, msg)
+	case http.StatusForbidden:
+		return status.New(codes.PermissionDenied, msg)
+	case http.StatusNotFound:
+		return status.New(codes.NotFound, msg)
+	case http.StatusConflict:
+		return status.New(codes.AlreadyExists, msg)
+	case http.StatusRequestTimeout, http.StatusGatewayTimeout:
+		return status.New(codes.DeadlineExceeded, msg)
+	case http.StatusTooManyRequests:
+		return status.New(codes.ResourceExhausted, msg)
+	case http.StatusNotImplemented:
+		return status.New(codes.Unimplemented, msg)
+	case http.StatusServiceUnavailable:
+		return status.New(codes.Unavailable, msg)
+	default:
+		return status.New(codes.Internal, msg)
+	}
+}
+
+// parseGRPCTimeout parses the grpc-timeout header, e.g. 100m is 100 milliseconds
+func parseGRPCTimeout(s string) (time.Duration, error) {
+	if len(s) < 2 {
+		return 0, errors.New("invalid timeout")
+	}
+	units := map[byte]time.Duration{
+		'H': time.Hour,
+		'M': time.Minute,
+		'S': time.Second,
+		'm': time.Millisecond,
+		'u': time.Microsecond,
+		'n': time.Nanosecond,
+	}
+	unit, ok := units[s[len(s)-1]]
+	if !ok {
+		return 0, errors.New("invalid timeout unit")
+	}
+	v, err := strconv.ParseInt(s[:len(s)-1], 10, 64)
+	if err != nil {
+		return 0, err
+	}
+	return time.Duration(v) * unit, nil
+}
@@ -0,0 +1,150 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	goerrors "github.com/micro/go-micro/v3/errors"
+	"google.golang.org/grpc/codes"
+)
+
+func TestGRPCRoute(t *testing.T) {
+	testData := []struct {
+		path     string
+		service  string
+		endpoint string
+		err      bool
+	}{
+		{"/helloworld.Helloworld/Call", "helloworld", "Helloworld.Call", false},
+		{"/go.micro.srv.foo.Foo/Bar", "go.micro.srv.foo", "Foo.Bar", false},
+		{"/Helloworld/Call", "", "", true},
+		{"/helloworld.Helloworld", "", "", true},
+		{"/helloworld.Helloworld/Call/Extra", "", "", true},
+		{"/helloworld./Call", "", "", true},
+	}
+
+	for _, d := range testData {
+		service, endpoint, err := grpcRoute(d.path)
+		if d.err {
+			if err == nil {
+				t.Errorf("Expected an error for %v", d.path)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("Unexpected error for %v: %v", d.path, err)
+			continue
+		}
+		if service != d.service || endpoint != d.endpoint {
+			t.Errorf("Expected %v %v for %v, got %v %v", d.service, d.endpoint, d.path, service, endpoint)
+		}
+	}
+}
+
+func TestGRPCMessages(t *testing.T) {
+	rec := httptest.NewRecorder()
+	out := newGRPCWriter(rec, true, false)
+	out.write([]byte("foo"))
+	out.write([]byte{})
+	out.write([]byte("bar"))
+
+	msgs, err := readGRPCMessages(bytes.NewReader(rec.Body.Bytes()))
+	if err != nil {
+		t.Fatalf("Unexpected error reading messages: %v", err)
+	}
+	if len(msgs) != 3 || string(msgs[0]) != "foo" || len(msgs[1]) != 0 || string(msgs[2]) != "bar" {
+		t.Fatalf("Unexpected messages: %q", msgs)
+	}
+
+	// a truncated message is invalid
+	if _, err := readGRPCMessages(bytes.NewReader(rec.Body.Bytes()[:10])); err == nil {
+		t.Errorf("Expected an error reading a truncated message")
+	}
+
+	// compressed messages aren't supported
+	if _, err := readGRPCMessages(bytes.NewReader([]byte{grpcFlagCompressed, 0, 0, 0, 0})); err == nil {
+		t.Errorf("Expected an error reading a compressed message")
+	}
+}
+
+func TestGRPCWebTrailer(t *testing.T) {
+	rec := httptest.NewRecorder()
+	out := newGRPCWriter(rec, true, true)
+	out.finish(goerrors.NotFound("helloworld", "not found"))
+
+	frame, err := base64.StdEncoding.DecodeString(rec.Body.String())
+	if err != nil {
+		t.Fatalf("Unexpected error decoding the trailer: %v", err)
+	}
+	if frame[0] != grpcFlagTrailer {
+		t.Errorf("Expected the trailer flag, got %v", frame[0])
+	}
+	expected := "grpc-status: 5\r\ngrpc-message: not found\r\n"
+	if string(frame[grpcFrameHeaderSize:]) != expected {
+		t.Errorf("Expected trailer %q, got %q", expected, frame[grpcFrameHeaderSize:])
+	}
+
+	// native grpc responses have the status in the trailers
+	rec = httptest.NewRecorder()
+	out = newGRPCWriter(rec, false, false)
+	out.finish(nil)
+	if st := rec.Header().Get("Grpc-Status"); st != "0" {
+		t.Errorf("Expected status 0, got %v", st)
+	}
+}
+
+func TestGRPCStatus(t *testing.T) {
+	testData := []struct {
+		err  error
+		code codes.Code
+	}{
+		{nil, codes.OK},
+		{goerrors.BadRequest("foo", "bad"), codes.InvalidArgument},
+		{goerrors.Unauthorized("foo", "unauthorized"), codes.Unauthenticated},
+		{goerrors.Forbidden("foo", "forbidden"), codes.PermissionDenied},
+		{goerrors.Timeout("foo", "timeout"), codes.DeadlineExceeded},
+		{goerrors.InternalServerError("foo", "error"), codes.Internal},
+	}
+
+	for _, d := range testData {
+		if code := grpcStatus(d.err).Code(); code != d.code {
+			t.Errorf("Expected code %v for %v, got %v", d.code, d.err, code)
+		}
+	}
+}
+
+func TestParseGRPCTimeout(t *testing.T) {
+	if d, err := parseGRPCTimeout("100m"); err != nil || d != 100*time.Millisecond {
+		t.Errorf("Expected 100ms, got %v %v", d, err)
+	}
+	if d, err := parseGRPCTimeout("2S"); err != nil || d != 2*time.Second {
+		t.Errorf("Expected 2s, got %v %v", d, err)
+	}
+	if _, err := parseGRPCTimeout("10x"); err == nil {
+		t.Errorf("Expected an error for an invalid unit")
+	}
+}
+
+func TestIsGRPC(t *testing.T) {
+	testData := map[string]bool{
+		"application/grpc":               true,
+		"application/grpc+proto":         true,
+		"application/grpc-web+proto":     true,
+		"application/grpc-web-text":      true,
+		"application/json":               false,
+		"application/grpcfoo":            false,
+		"application/x-www-form-encoded": false,
+	}
+
+	for ct, expected := range testData {
+		req, _ := http.NewRequest("POST", "/helloworld.Helloworld/Call", nil)
+		req.Header.Set("Content-Type", ct)
+		if IsGRPC(req) != expected {
+			t.Errorf("Expected IsGRPC to be %v for %v", expected, ct)
+		}
+	}
+}
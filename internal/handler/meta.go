@@ -9,6 +9,7 @@ import (
 	"github.com/micro/go-micro/v3/api/handler/event"
 	"github.com/micro/go-micro/v3/api/router"
 	"github.com/micro/go-micro/v3/client"
+	urouter "github.com/micro/go-micro/v3/util/router"
 	"github.com/micro/micro/v3/service"
 	// TODO: only import handler package
 	aapi "github.com/micro/go-micro/v3/api/handler/api"
@@ -50,6 +51,16 @@ func (m *metaHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// websockets to streaming endpoints are bridged to a stream of the endpoint, other requests
+	// can't be authenticated by a stream
+	if IsWebSocket(r) && IsStream(service) {
+		ServeStream(w, r, service.Name, service.Endpoint.Name, client.WithRouter(urouter.New(service.Services)))
+		return
+	} else if streamAuthRequired(r.Context()) {
+		http.Error(w, "unauthorized request", http.StatusUnauthorized)
+		return
+	}
+
 	// TODO: don't do this ffs
 	switch service.Endpoint.Handler {
 	// web socket handler
@@ -0,0 +1,382 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/gobwas/httphead"
+	"github.com/gobwas/ws"
+	"github.com/gobwas/ws/wsutil"
+	"github.com/micro/go-micro/v3/api"
+	"github.com/micro/go-micro/v3/api/handler"
+	"github.com/micro/go-micro/v3/api/router"
+	goauth "github.com/micro/go-micro/v3/auth"
+	goclient "github.com/micro/go-micro/v3/client"
+	goerrors "github.com/micro/go-micro/v3/errors"
+	"github.com/micro/go-micro/v3/metadata"
+	"github.com/micro/micro/v3/internal/helper"
+	"github.com/micro/micro/v3/internal/namespace"
+	"github.com/micro/micro/v3/service/auth"
+	"github.com/micro/micro/v3/service/client"
+	"github.com/micro/micro/v3/service/logger"
+)
+
+const (
+	// maxCloseReason is the max length of the reason in a close frame, the payload of control
+	// frames is limited to 125 bytes including the code
+	maxCloseReason = 123
+	// streamMessageLimit is the max size of a message received from a websocket
+	streamMessageLimit = 4 * 1024 * 1024
+)
+
+var errMessageTooBig = errors.New("message too big")
+
+// streamRequest is the first message sent over a websocket. The service and endpoint are only
+// used by /rpc/stream, the api resolves them from the path. The token authenticates the stream
+// for clients which can't set headers, e.g. browsers, otherwise the micro-token cookie is used.
+type streamRequest struct {
+	Service  string
+	Endpoint string
+	Method   string
+	Request  json.RawMessage
+	Token    string
+}
+
+// streamAuthKey is the key of the context value set for websockets which are authenticated by
+// the token in their first message
+type streamAuthKey struct{}
+
+// WithStreamAuth returns a context requiring the token in the first message of the websocket to
+// authenticate the stream. The auth wrapper allows websocket upgrades to streaming endpoints
+// without credentials since browsers can't set headers on them.
+func WithStreamAuth(ctx context.Context) context.Context {
+	return context.WithValue(ctx, streamAuthKey{}, true)
+}
+
+// streamAuthRequired returns true if the stream must be authenticated by the first message
+func streamAuthRequired(ctx context.Context) bool {
+	v, _ := ctx.Value(streamAuthKey{}).(bool)
+	return v
+}
+
+type streamHandler struct{}
+
+func (h *streamHandler) String() string {
+	return "internal/stream"
+}
+
+// ServeHTTP upgrades the request to a websocket and bridges it to a stream of the service and
+// endpoint in the first message.
+func (h *streamHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !IsWebSocket(r) {
+		http.Error(w, "Websocket upgrade required", http.StatusUpgradeRequired)
+		return
+	}
+
+	var opts []goclient.CallOption
+	if ns := r.Header.Get(namespace.NamespaceKey); len(ns) > 0 {
+		opts = append(opts, goclient.WithNetwork(ns))
+	}
+	ServeStream(w, r, "", "", opts...)
+}
+
+// NewStreamHandler returns a handler which bridges websockets to streaming endpoints, the
+// service and endpoint are provided in the first message
+func NewStreamHandler() handler.Handler {
+	return &streamHandler{}
+}
+
+// IsWebSocket returns true if the request is a websocket upgrade
+func IsWebSocket(r *http.Request) bool {
+	contains := func(key, val string) bool {
+		for _, v := range strings.Split(r.Header.Get(key), ",") {
+			if strings.EqualFold(strings.TrimSpace(v), val) {
+				return true
+			}
+		}
+		return false
+	}
+	return contains("Connection", "upgrade") && contains("Upgrade", "websocket")
+}
+
+// IsStream returns true if the endpoint of the service is a streaming endpoint
+func IsStream(srv *api.Service) bool {
+	for _, s := range srv.Services {
+		for _, ep := range s.Endpoints {
+			if ep.Name == srv.Endpoint.Name && ep.Metadata["stream"] == "true" {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// StreamMatcher returns a func which returns true if a request is to one of the paths or is
+// routed to a streaming endpoint by the router, the router may be nil
+func StreamMatcher(rt router.Router, paths ...string) func(r *http.Request) bool {
+	return func(r *http.Request) bool {
+		for _, p := range paths {
+			if r.URL.Path == p {
+				return true
+			}
+		}
+		if rt == nil {
+			return false
+		}
+		srv, err := rt.Route(r)
+		return err == nil && IsStream(srv)
+	}
+}
+
+// ServeStream upgrades the request to a websocket and bridges it to a stream of the endpoint.
+// Messages are JSON encoded text frames. The first message is a streamRequest, the messages
+// after it are sent to the stream and the responses are written as they're received. When the
+// stream ends the websocket is closed with the normal closure code, or 4000 plus the status
+// code of the error, e.g. 4404 if the endpoint wasn't found. If the service is blank it's taken
+// from the first message.
+func ServeStream(w http.ResponseWriter, r *http.Request, service, endpoint string, opts ...goclient.CallOption) {
+	upgrader := ws.HTTPUpgrader{
+		Timeout: 5 * time.Second,
+		Extension: func(httphead.Option) bool {
+			// disable extensions for compatibility
+			return false
+		},
+	}
+	netConn, rw, _, err := upgrader.Upgrade(r, w)
+	if err != nil {
+		logger.Errorf("Error upgrading websocket: %v", err)
+		return
+	}
+	conn := &wsConn{conn: netConn, rd: rw.Reader}
+	defer conn.conn.Close()
+
+	// the first message sets up the stream
+	msg, err := conn.read()
+	if err != nil {
+		return
+	}
+	var req streamRequest
+	if err := json.Unmarshal(msg, &req); err != nil {
+		conn.close(closeCode(goerrors.BadRequest("micro.rpc", "invalid request: %v", err)))
+		return
+	}
+	if len(service) == 0 {
+		service, endpoint = req.Service, req.Endpoint
+		if len(endpoint) == 0 {
+			endpoint = req.Method
+		}
+	}
+	if len(service) == 0 || len(endpoint) == 0 {
+		conn.close(closeCode(goerrors.BadRequest("micro.rpc", "invalid service or endpoint")))
+		return
+	}
+
+	// create the context with the metadata of the request, e.g. the auth cookie
+	ctx := helper.RequestToContext(r)
+
+	// the token in the message is verified the same as a token in the header would be, it's
+	// required if the upgrade wasn't authenticated
+	if len(req.Token) == 0 && streamAuthRequired(r.Context()) {
+		conn.close(closeCode(goerrors.Unauthorized("micro.rpc", "unauthorized request")))
+		return
+	}
+	if len(req.Token) > 0 {
+		ns := r.Header.Get(namespace.NamespaceKey)
+		acc, err := auth.Inspect(req.Token)
+		if err != nil || acc.Issuer != ns {
+			conn.close(closeCode(goerrors.Unauthorized("micro.rpc", "invalid token")))
+			return
+		}
+		res := &goauth.Resource{Type: "service", Name: service, Endpoint: endpoint}
+		if err := auth.Verify(acc, res, goauth.VerifyNamespace(ns), goauth.VerifyContext(ctx)); err == goauth.ErrForbidden {
+			conn.close(closeCode(goerrors.Forbidden("micro.rpc", "Forbidden request")))
+			return
+		} else if err != nil {
+			conn.close(closeCode(goerrors.InternalServerError("micro.rpc", err.Error())))
+			return
+		}
+		ctx = metadata.Set(ctx, "Authorization", goauth.BearerScheme+req.Token)
+	}
+
+	creq := client.NewRequest(service, endpoint, &json.RawMessage{},
+		goclient.WithContentType("application/json"), goclient.StreamingRequest())
+	stream, err := client.Stream(ctx, creq, opts...)
+	if err != nil {
+		conn.close(closeCode(err))
+		return
+	}
+	defer stream.Close()
+
+	if len(req.Request) > 0 {
+		if err := stream.Send(&req.Request); err != nil {
+			conn.close(closeCode(err))
+			return
+		}
+	}
+
+	// send the messages from the client to the stream, the stream is closed once the client
+	// closes the websocket which ends the loop below
+	go func() {
+		defer stream.Close()
+
+		for {
+			msg, err := conn.read()
+			if err != nil {
+				return
+			}
+			m := json.RawMessage(msg)
+			if err := stream.Send(&m); err != nil {
+				return
+			}
+		}
+	}()
+
+	for {
+		var rsp json.RawMessage
+		if err := stream.Recv(&rsp); err == io.EOF {
+			conn.close(ws.StatusNormalClosure, "")
+			return
+		} else if err != nil {
+			conn.close(closeCode(err))
+			return
+		}
+		if err := conn.write(rsp); err != nil {
+			return
+		}
+	}
+}
+
+// closeCode returns the close code and reason for an rpc error
+func closeCode(err error) (ws.StatusCode, string) {
+	merr := goerrors.Parse(err.Error())
+	code := merr.Code
+	if code == 0 {
+		code = http.StatusInternalServerError
+	}
+
+	reason := merr.Detail
+	if len(reason) == 0 {
+		reason = err.Error()
+	}
+	if len(reason) > maxCloseReason {
+		reason = reason[:maxCloseReason]
+		// don't split a multi byte character
+		for !utf8.ValidString(reason) {
+			reason = reason[:len(reason)-1]
+		}
+	}
+	return ws.StatusCode(4000 + code), reason
+}
+
+// wsConn is a server side websocket connection. Frames are written whole while holding the lock
+// since the reader responds to control frames, e.g. pings, while responses are being written.
+type wsConn struct {
+	conn net.Conn
+	rd   io.Reader
+
+	sync.Mutex
+	closed bool
+}
+
+// read the next text or binary message from the client
+func (c *wsConn) read() ([]byte, error) {
+	// control frames are buffered so the response is written in a single write
+	onControl := func(h ws.Header, r io.Reader) error {
+		payload, err := ioutil.ReadAll(r)
+		if err != nil {
+			return err
+		}
+		var buf bytes.Buffer
+		handler := wsutil.ControlHandler{
+			Src:                 bytes.NewReader(payload),
+			Dst:                 &buf,
+			State:               ws.StateServerSide,
+			DisableSrcCiphering: true,
+		}
+		herr := handler.Handle(h)
+
+		c.Lock()
+		defer c.Unlock()
+		if buf.Len() > 0 && !c.closed {
+			c.conn.Write(buf.Bytes())
+		}
+		// the close frame has been echoed so no other close frame should be sent
+		if h.OpCode == ws.OpClose {
+			c.closed = true
+		}
+		return herr
+	}
+
+	rd := wsutil.Reader{
+		Source:         c.rd,
+		State:          ws.StateServerSide,
+		CheckUTF8:      true,
+		OnIntermediate: onControl,
+	}
+	for {
+		hdr, err := rd.NextFrame()
+		if err != nil {
+			return nil, err
+		}
+		if hdr.OpCode.IsControl() {
+			if err := onControl(hdr, &rd); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		if hdr.OpCode != ws.OpText && hdr.OpCode != ws.OpBinary {
+			if err := rd.Discard(); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		msg, err := ioutil.ReadAll(io.LimitReader(&rd, streamMessageLimit+1))
+		if err != nil {
+			return nil, err
+		}
+		if len(msg) > streamMessageLimit {
+			c.close(ws.StatusMessageTooBig, "message too big")
+			return nil, errMessageTooBig
+		}
+		return msg, nil
+	}
+}
+
+// write a text message
+func (c *wsConn) write(msg []byte) error {
+	b, err := ws.CompileFrame(ws.NewTextFrame(msg))
+	if err != nil {
+		return err
+	}
+	c.Lock()
+	defer c.Unlock()
+	_, err = c.conn.Write(b)
+	return err
+}
+
+// close sends a close frame, only the first close frame is sent
+func (c *wsConn) close(code ws.StatusCode, reason string) {
+	b, err := ws.CompileFrame(ws.NewCloseFrame(ws.NewCloseFrameBody(code, reason)))
+	if err != nil {
+		return
+	}
+
+	c.Lock()
+	defer c.Unlock()
+	if c.closed {
+		return
+	}
+	c.closed = true
+	c.conn.Write(b)
+}
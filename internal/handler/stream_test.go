@@ -0,0 +1,136 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gobwas/ws"
+	"github.com/gobwas/ws/wsutil"
+	goclient "github.com/micro/go-micro/v3/client"
+	goerrors "github.com/micro/go-micro/v3/errors"
+	"github.com/micro/micro/v3/service/client"
+)
+
+// echoClient opens streams which echo the messages sent to them
+type echoClient struct {
+	goclient.Client
+	req goclient.Request
+}
+
+func (e *echoClient) Stream(ctx context.Context, req goclient.Request, opts ...goclient.CallOption) (goclient.Stream, error) {
+	e.req = req
+	ctx, cancel := context.WithCancel(ctx)
+	return &echoStream{ctx: ctx, cancel: cancel, msgs: make(chan json.RawMessage, 10)}, nil
+}
+
+type echoStream struct {
+	goclient.Stream
+	ctx    context.Context
+	cancel context.CancelFunc
+	msgs   chan json.RawMessage
+}
+
+func (e *echoStream) Send(msg interface{}) error {
+	e.msgs <- *msg.(*json.RawMessage)
+	return nil
+}
+
+func (e *echoStream) Recv(msg interface{}) error {
+	select {
+	case m := <-e.msgs:
+		switch string(m) {
+		case `"end"`:
+			return io.EOF
+		case `"error"`:
+			return goerrors.NotFound("foo", "not found")
+		}
+		*msg.(*json.RawMessage) = m
+		return nil
+	case <-e.ctx.Done():
+		return e.ctx.Err()
+	}
+}
+
+func (e *echoStream) Close() error {
+	e.cancel()
+	return nil
+}
+
+func TestServeStream(t *testing.T) {
+	c := &echoClient{Client: client.DefaultClient}
+	defer func(dc goclient.Client) { client.DefaultClient = dc }(client.DefaultClient)
+	client.DefaultClient = c
+
+	srv := httptest.NewServer(NewStreamHandler())
+	defer srv.Close()
+
+	dial := func() (io.ReadWriter, func()) {
+		conn, _, _, err := ws.Dial(context.TODO(), "ws"+strings.TrimPrefix(srv.URL, "http"))
+		if err != nil {
+			t.Fatalf("Error dialing stream: %v", err)
+		}
+		return conn, func() { conn.Close() }
+	}
+
+	// the closed error is returned once the server closes the stream
+	expectClose := func(conn io.ReadWriter, code ws.StatusCode) {
+		_, _, err := wsutil.ReadServerData(conn)
+		cerr, ok := err.(wsutil.ClosedError)
+		if !ok {
+			t.Fatalf("Expected the stream to be closed, got %v", err)
+		}
+		if cerr.Code != code {
+			t.Errorf("Expected close code %v, got %v (%v)", code, cerr.Code, cerr.Reason)
+		}
+	}
+
+	conn, closeConn := dial()
+	defer closeConn()
+
+	first := `{"service": "foo", "endpoint": "Foo.Bar", "request": {"id": 1}}`
+	if err := wsutil.WriteClientText(conn, []byte(first)); err != nil {
+		t.Fatalf("Error writing first message: %v", err)
+	}
+	msg, err := wsutil.ReadServerText(conn)
+	if err != nil || string(msg) != `{"id": 1}` {
+		t.Fatalf("Expected the request to be echoed, got %s %v", msg, err)
+	}
+	if c.req.Service() != "foo" || c.req.Endpoint() != "Foo.Bar" {
+		t.Errorf("Expected a stream to foo Foo.Bar, got %v %v", c.req.Service(), c.req.Endpoint())
+	}
+
+	wsutil.WriteClientText(conn, []byte(`{"id": 2}`))
+	if msg, err := wsutil.ReadServerText(conn); err != nil || string(msg) != `{"id": 2}` {
+		t.Fatalf("Expected the message to be echoed, got %s %v", msg, err)
+	}
+
+	wsutil.WriteClientText(conn, []byte(`"end"`))
+	expectClose(conn, ws.StatusNormalClosure)
+
+	// errors are returned as the close code
+	conn, closeConn = dial()
+	defer closeConn()
+	wsutil.WriteClientText(conn, []byte(`{"service": "foo", "endpoint": "Foo.Bar", "request": "error"}`))
+	expectClose(conn, 4404)
+
+	// the service and endpoint are required
+	conn, closeConn = dial()
+	defer closeConn()
+	wsutil.WriteClientText(conn, []byte(`{"request": {}}`))
+	expectClose(conn, 4400)
+
+	// a token is required if the auth wrapper didn't authenticate the upgrade
+	h := NewStreamHandler()
+	srv.Config.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		h.ServeHTTP(w, r.WithContext(WithStreamAuth(r.Context())))
+	})
+	conn, closeConn = dial()
+	defer closeConn()
+	wsutil.WriteClientText(conn, []byte(`{"service": "foo", "endpoint": "Foo.Bar"}`))
+	expectClose(conn, 4401)
+}
@@ -0,0 +1,519 @@
+// Package mtls manages the certificates services use for mutual TLS. The certificates are issued
+// by the CA of the auth service to the service accounts, they identify the service using a URI,
+// e.g. micro://micro/helloworld, and they're short lived and rotated automatically. Peers must
+// present a certificate issued by the CA which hasn't been revoked and clients verify the server
+// is the service they looked up, e.g.
+//
+//	MICRO_ENABLE_MTLS=true MICRO_MTLS_CA_FILE=ca.pem MICRO_MTLS_CA_KEY_FILE=ca.key micro server
+//
+// The CA must be provided, it's never trusted on first use.
+package mtls
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/micro/go-micro/v3/client"
+	"github.com/micro/go-micro/v3/metadata"
+	"github.com/micro/micro/v3/internal/namespace"
+	"github.com/micro/micro/v3/service/logger"
+	"google.golang.org/grpc/credentials"
+)
+
+const (
+	// URIScheme is the scheme of the URI identifying the service a certificate was issued to
+	URIScheme = "micro"
+
+	// retryInterval is how long to wait before retrying to issue a certificate
+	retryInterval = time.Second * 10
+	// expectTTL is how long a service looked up at an address is expected to serve on it
+	expectTTL = time.Minute * 10
+)
+
+var (
+	// DefaultManager manages the certificate of the service, it's set when mutual TLS is enabled
+	DefaultManager *Manager
+
+	// RoutingServices in the default namespace route requests to other services, so they're
+	// expected to serve any service, e.g. when calling services through the proxy
+	RoutingServices = []string{"proxy", "network"}
+
+	// ErrNoCertificate is returned when the peer or the manager doesn't have a certificate
+	ErrNoCertificate = errors.New("no certificate")
+	// ErrRevoked is returned when the certificate of the peer has been revoked
+	ErrRevoked = errors.New("certificate revoked")
+	// ErrUnexpectedPeer is returned when the peer isn't the service expected
+	ErrUnexpectedPeer = errors.New("unexpected peer")
+)
+
+// Issuer issues the certificates, it's implemented by the auth
+type Issuer interface {
+	// IssueCertificate signs the PEM encoded certificate signing request, returning the PEM
+	// encoded certificate and the certificate of the CA
+	IssueCertificate(csr []byte, expiry time.Duration) ([]byte, []byte, error)
+	// CertificateAuthority returns the PEM encoded certificate of the CA and the serials of
+	// the certificates which have been revoked
+	CertificateAuthority() ([]byte, []string, error)
+}
+
+// Identity returns the namespace and name of the service the certificate was issued to, e.g.
+// micro/helloworld, or a blank string if it wasn't issued to a service
+func Identity(c *x509.Certificate) string {
+	for _, u := range c.URIs {
+		if u.Scheme == URIScheme && len(u.Host) > 0 && len(u.Path) > 1 {
+			return u.Host + "/" + strings.TrimPrefix(u.Path, "/")
+		}
+	}
+	return ""
+}
+
+// IdentityURI returns the URI identifying the service in the namespace
+func IdentityURI(ns, service string) *url.URL {
+	return &url.URL{Scheme: URIScheme, Host: ns, Path: "/" + service}
+}
+
+// Manager issues and rotates the certificate of the service and verifies its peers
+type Manager struct {
+	opts Options
+
+	sync.RWMutex
+	cert    *tls.Certificate
+	pool    *x509.CertPool
+	revoked map[string]bool
+	exit    chan bool
+
+	// the services expected at the addresses looked up by the client
+	expectMu sync.Mutex
+	expected map[string]map[string]time.Time
+}
+
+// New returns a manager with the options, the CA is required
+func New(opts ...Option) (*Manager, error) {
+	m := &Manager{
+		opts:     newOptions(opts...),
+		revoked:  make(map[string]bool),
+		expected: make(map[string]map[string]time.Time),
+	}
+	if err := m.setCA(m.opts.CA); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// Init the manager with the options, e.g. to change the issuer
+func (m *Manager) Init(opts ...Option) error {
+	m.Lock()
+	defer m.Unlock()
+	for _, o := range opts {
+		o(&m.opts)
+	}
+	return m.setCA(m.opts.CA)
+}
+
+// Start issues the first certificate and rotates it until the manager is stopped. An error is
+// returned if the first certificate couldn't be issued, it'll be retried.
+func (m *Manager) Start() error {
+	m.Lock()
+	if m.exit != nil {
+		m.Unlock()
+		return nil
+	}
+	m.exit = make(chan bool)
+	exit := m.exit
+	m.Unlock()
+
+	err := m.Rotate()
+	if err == nil {
+		err = m.Refresh()
+	}
+	go m.run(exit)
+	return err
+}
+
+// Stop rotating the certificate
+func (m *Manager) Stop() {
+	m.Lock()
+	defer m.Unlock()
+	if m.exit != nil {
+		close(m.exit)
+		m.exit = nil
+	}
+}
+
+// Rotate issues a new certificate using a new key
+func (m *Manager) Rotate() error {
+	m.RLock()
+	issuer := m.opts.Issuer
+	expiry := m.opts.Expiry
+	service := m.opts.Service
+	m.RUnlock()
+	if issuer == nil {
+		return fmt.Errorf("no issuer")
+	}
+	if len(service) == 0 {
+		return fmt.Errorf("no service name")
+	}
+
+	// the certificate is requested for the service, the issuer sets its namespace
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return err
+	}
+	der, err := x509.CreateCertificateRequest(rand.Reader, &x509.CertificateRequest{
+		Subject: pkix.Name{CommonName: service},
+	}, key)
+	if err != nil {
+		return err
+	}
+	csr := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE REQUEST", Bytes: der})
+
+	certPEM, _, err := issuer.IssueCertificate(csr, expiry)
+	if err != nil {
+		return err
+	}
+
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		return err
+	}
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+	cert, err := tls.X509KeyPair(certPEM, keyPEM)
+	if err != nil {
+		return err
+	}
+	if cert.Leaf, err = x509.ParseCertificate(cert.Certificate[0]); err != nil {
+		return err
+	}
+	if len(Identity(cert.Leaf)) == 0 {
+		return fmt.Errorf("certificate wasn't issued to a service")
+	}
+
+	m.Lock()
+	defer m.Unlock()
+
+	// only the CA provided by the options is trusted, never the one returned by the issuer
+	if _, err := cert.Leaf.Verify(x509.VerifyOptions{
+		Roots: m.pool, KeyUsages: []x509.ExtKeyUsage{x509.ExtKeyUsageAny},
+	}); err != nil {
+		return fmt.Errorf("certificate wasn't issued by the CA: %v", err)
+	}
+
+	m.cert = &cert
+	return nil
+}
+
+// Refresh the serials of the revoked certificates
+func (m *Manager) Refresh() error {
+	m.RLock()
+	issuer := m.opts.Issuer
+	m.RUnlock()
+	if issuer == nil {
+		return fmt.Errorf("no issuer")
+	}
+
+	_, serials, err := issuer.CertificateAuthority()
+	if err != nil {
+		return err
+	}
+
+	revoked := make(map[string]bool, len(serials))
+	for _, s := range serials {
+		revoked[s] = true
+	}
+
+	m.Lock()
+	m.revoked = revoked
+	m.Unlock()
+	return nil
+}
+
+// Certificate returns the certificate of the service, nil if it hasn't been issued yet
+func (m *Manager) Certificate() *x509.Certificate {
+	m.RLock()
+	defer m.RUnlock()
+	if m.cert == nil {
+		return nil
+	}
+	return m.cert.Leaf
+}
+
+// Config returns the TLS config of servers, clients must present a certificate issued by the CA
+// to a service. Clients use Credentials, which also verify the identity of the server.
+func (m *Manager) Config() *tls.Config {
+	return m.config(tls.RequireAnyClientCert, true, nil)
+}
+
+// BootstrapConfig returns the TLS config of servers which clients without a certificate
+// connect to, e.g. the auth service which issues them or the proxy. Certificates presented by
+// clients are still verified.
+func (m *Manager) BootstrapConfig() *tls.Config {
+	return m.config(tls.RequestClientCert, false, nil)
+}
+
+// PeerConfig returns the TLS config of clients and servers which only accept the services as
+// peers, e.g. micro/network for the tunnel between the network nodes
+func (m *Manager) PeerConfig(services ...string) *tls.Config {
+	return m.config(tls.RequireAnyClientCert, true, func(id string) bool {
+		for _, s := range services {
+			if s == id {
+				return true
+			}
+		}
+		return false
+	})
+}
+
+// Credentials returns the gRPC credentials of clients, the server must be issued a certificate
+// for the service looked up at the address being dialled
+func (m *Manager) Credentials() credentials.TransportCredentials {
+	return &clientCredentials{TransportCredentials: credentials.NewTLS(m.Config()), m: m}
+}
+
+// Lookup wraps the lookup of the client to record which services are expected at the addresses
+// it returns, the credentials verify the server at the address is one of them
+func (m *Manager) Lookup(fn client.LookupFunc) client.LookupFunc {
+	return func(ctx context.Context, req client.Request, opts client.CallOptions) ([]string, error) {
+		addrs, err := fn(ctx, req, opts)
+		if err != nil {
+			return nil, err
+		}
+
+		ns := opts.Network
+		if len(ns) == 0 {
+			ns, _ = metadata.Get(ctx, "Micro-Namespace")
+		}
+		if len(ns) == 0 {
+			ns = namespace.DefaultNamespace
+		}
+		m.expect(ns, req.Service(), addrs)
+		return addrs, nil
+	}
+}
+
+// expect records the service is expected to serve on the addresses. Services in the default
+// namespace and the routing services can serve on them too since tenants can't be issued their
+// certificates.
+func (m *Manager) expect(ns, service string, addrs []string) {
+	ids := []string{ns + "/" + service, namespace.DefaultNamespace + "/" + service}
+	for _, s := range RoutingServices {
+		ids = append(ids, namespace.DefaultNamespace+"/"+s)
+	}
+
+	now := time.Now()
+	m.expectMu.Lock()
+	defer m.expectMu.Unlock()
+	for _, addr := range addrs {
+		exp, ok := m.expected[addr]
+		if !ok {
+			exp = make(map[string]time.Time)
+			m.expected[addr] = exp
+		}
+		for _, id := range ids {
+			exp[id] = now
+		}
+	}
+
+	// prune the services which haven't been looked up recently
+	for addr, exp := range m.expected {
+		for id, t := range exp {
+			if now.Sub(t) > expectTTL {
+				delete(exp, id)
+			}
+		}
+		if len(exp) == 0 {
+			delete(m.expected, addr)
+		}
+	}
+}
+
+// expects returns whether the service is expected at the address
+func (m *Manager) expects(addr, id string) bool {
+	m.expectMu.Lock()
+	defer m.expectMu.Unlock()
+	t, ok := m.expected[addr][id]
+	return ok && time.Since(t) <= expectTTL
+}
+
+func (m *Manager) config(clientAuth tls.ClientAuthType, required bool, allow func(string) bool) *tls.Config {
+	return &tls.Config{
+		GetCertificate: func(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+			m.RLock()
+			defer m.RUnlock()
+			if m.cert == nil {
+				return nil, ErrNoCertificate
+			}
+			return m.cert, nil
+		},
+		GetClientCertificate: func(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+			m.RLock()
+			defer m.RUnlock()
+			// clients without a certificate can still connect to the auth service to get one
+			if m.cert == nil {
+				return &tls.Certificate{}, nil
+			}
+			return m.cert, nil
+		},
+		ClientAuth: clientAuth,
+		// peers are addressed by ip rather than by name, so they're verified using the CA and
+		// their identity in VerifyPeerCertificate rather than by the default verification
+		InsecureSkipVerify: true,
+		VerifyPeerCertificate: func(rawCerts [][]byte, _ [][]*x509.Certificate) error {
+			if len(rawCerts) == 0 {
+				if required {
+					return ErrNoCertificate
+				}
+				return nil
+			}
+			id, err := m.verify(rawCerts)
+			if err != nil {
+				return err
+			}
+			if allow != nil && !allow(id) {
+				return ErrUnexpectedPeer
+			}
+			return nil
+		},
+		NextProtos: []string{"h2"},
+		MinVersion: tls.VersionTLS12,
+	}
+}
+
+// verify the certificates of the peer were issued to a service by the CA and haven't been
+// revoked, returning the identity of the service
+func (m *Manager) verify(rawCerts [][]byte) (string, error) {
+	certs := make([]*x509.Certificate, 0, len(rawCerts))
+	for _, raw := range rawCerts {
+		c, err := x509.ParseCertificate(raw)
+		if err != nil {
+			return "", err
+		}
+		certs = append(certs, c)
+	}
+
+	m.RLock()
+	pool := m.pool
+	revoked := m.revoked[fmt.Sprintf("%x", certs[0].SerialNumber)]
+	m.RUnlock()
+
+	if revoked {
+		return "", ErrRevoked
+	}
+
+	intermediates := x509.NewCertPool()
+	for _, c := range certs[1:] {
+		intermediates.AddCert(c)
+	}
+	if _, err := certs[0].Verify(x509.VerifyOptions{
+		Roots:         pool,
+		Intermediates: intermediates,
+		KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageAny},
+	}); err != nil {
+		return "", err
+	}
+
+	id := Identity(certs[0])
+	if len(id) == 0 {
+		return "", ErrUnexpectedPeer
+	}
+	return id, nil
+}
+
+// setCA trusts the PEM encoded CA, the caller must hold the lock
+func (m *Manager) setCA(caPEM []byte) error {
+	if len(caPEM) == 0 {
+		return fmt.Errorf("a CA is required")
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(caPEM) {
+		return fmt.Errorf("invalid CA")
+	}
+	m.pool = pool
+	return nil
+}
+
+// rotateIn returns how long until the certificate should be rotated
+func (m *Manager) rotateIn() time.Duration {
+	m.RLock()
+	defer m.RUnlock()
+	if m.cert == nil {
+		return retryInterval
+	}
+
+	leaf := m.cert.Leaf
+	d := time.Until(leaf.NotBefore.Add(leaf.NotAfter.Sub(leaf.NotBefore) * 2 / 3))
+	if d < retryInterval {
+		return retryInterval
+	}
+	return d
+}
+
+func (m *Manager) run(exit chan bool) {
+	m.RLock()
+	refresh := time.NewTicker(m.opts.RefreshInterval)
+	m.RUnlock()
+	defer refresh.Stop()
+
+	rotate := time.NewTimer(m.rotateIn())
+	defer rotate.Stop()
+
+	for {
+		select {
+		case <-exit:
+			return
+		case <-refresh.C:
+			if err := m.Refresh(); err != nil {
+				logger.Warnf("Error refreshing the revoked certificates: %v", err)
+			}
+		case <-rotate.C:
+			if err := m.Rotate(); err != nil {
+				logger.Warnf("Error rotating the certificate: %v", err)
+			} else if logger.V(logger.DebugLevel, logger.DefaultLogger) {
+				logger.Debugf("Rotated the certificate, it expires at %v", m.Certificate().NotAfter)
+			}
+			rotate.Reset(m.rotateIn())
+		}
+	}
+}
+
+// clientCredentials are the gRPC credentials of clients which verify the server is a service
+// expected at the address being dialled
+type clientCredentials struct {
+	credentials.TransportCredentials
+	m *Manager
+}
+
+func (c *clientCredentials) ClientHandshake(ctx context.Context, authority string, conn net.Conn) (net.Conn, credentials.AuthInfo, error) {
+	config := c.m.Config()
+	verify := config.VerifyPeerCertificate
+	config.VerifyPeerCertificate = func(rawCerts [][]byte, chains [][]*x509.Certificate) error {
+		if err := verify(rawCerts, chains); err != nil {
+			return err
+		}
+		id, err := c.m.verify(rawCerts)
+		if err != nil {
+			return err
+		}
+		if !c.m.expects(authority, id) {
+			return fmt.Errorf("%v: %v isn't expected at %v", ErrUnexpectedPeer, id, authority)
+		}
+		return nil
+	}
+	return credentials.NewTLS(config).ClientHandshake(ctx, authority, conn)
+}
+
+func (c *clientCredentials) Clone() credentials.TransportCredentials {
+	return &clientCredentials{TransportCredentials: c.TransportCredentials.Clone(), m: c.m}
+}
@@ -0,0 +1,287 @@
+package mtls
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"net"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/micro/go-micro/v3/client"
+	"github.com/micro/go-micro/v3/client/mucp"
+)
+
+// testIssuer is a CA which issues certificates to the services in the namespace
+type testIssuer struct {
+	namespace string
+	ca        *x509.Certificate
+	caKey     *ecdsa.PrivateKey
+	caPEM     []byte
+	revoked   []string
+	serial    int64
+}
+
+func newTestIssuer(t *testing.T, namespace string) *testIssuer {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ca, _ := x509.ParseCertificate(der)
+	return &testIssuer{
+		namespace: namespace,
+		ca:        ca,
+		caKey:     key,
+		caPEM:     pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		serial:    1,
+	}
+}
+
+func (i *testIssuer) IssueCertificate(csrPEM []byte, expiry time.Duration) ([]byte, []byte, error) {
+	block, _ := pem.Decode(csrPEM)
+	csr, err := x509.ParseCertificateRequest(block.Bytes)
+	if err != nil {
+		return nil, nil, err
+	}
+	i.serial++
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(i.serial),
+		Subject:      csr.Subject,
+		URIs:         []*url.URL{IdentityURI(i.namespace, csr.Subject.CommonName)},
+		NotBefore:    time.Now().Add(-time.Minute),
+		NotAfter:     time.Now().Add(expiry),
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, i.ca, csr.PublicKey, i.caKey)
+	if err != nil {
+		return nil, nil, err
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), i.caPEM, nil
+}
+
+func (i *testIssuer) CertificateAuthority() ([]byte, []string, error) {
+	return i.caPEM, i.revoked, nil
+}
+
+// handshake connects the client to the server, returning the error of the server
+func handshake(client, server *tls.Config) error {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return err
+	}
+	defer l.Close()
+
+	go func() {
+		c, err := net.Dial("tcp", l.Addr().String())
+		if err != nil {
+			return
+		}
+		defer c.Close()
+		tc := tls.Client(c, client)
+		if err := tc.Handshake(); err != nil {
+			return
+		}
+		// wait for the server to finish the handshake
+		tc.Read(make([]byte, 1))
+	}()
+
+	s, err := l.Accept()
+	if err != nil {
+		return err
+	}
+	defer s.Close()
+	s.SetDeadline(time.Now().Add(time.Second * 5))
+	return tls.Server(s, server).Handshake()
+}
+
+// dial connects the client credentials to the server listening on the listener, returning the
+// error of the client
+func dial(l net.Listener, m *Manager, server *tls.Config) error {
+	go func() {
+		s, err := l.Accept()
+		if err != nil {
+			return
+		}
+		defer s.Close()
+		s.SetDeadline(time.Now().Add(time.Second * 5))
+		ts := tls.Server(s, server)
+		if err := ts.Handshake(); err != nil {
+			return
+		}
+		ts.Read(make([]byte, 1))
+	}()
+
+	c, err := net.Dial("tcp", l.Addr().String())
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+	_, _, err = m.Credentials().ClientHandshake(context.TODO(), l.Addr().String(), c)
+	return err
+}
+
+func TestManager(t *testing.T) {
+	issuer := newTestIssuer(t, "micro")
+
+	// the CA is never trusted on first use
+	if _, err := New(WithIssuer(issuer)); err == nil {
+		t.Fatal("Expected an error creating a manager without a CA")
+	}
+
+	srv, err := New(WithIssuer(issuer), CA(issuer.caPEM), Service("foo"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := srv.Rotate(); err != nil {
+		t.Fatalf("Unexpected error issuing certificate: %v", err)
+	}
+	if c := srv.Certificate(); c == nil || Identity(c) != "micro/foo" {
+		t.Fatalf("Expected a certificate issued to micro/foo, got %v", c)
+	}
+
+	cli, err := New(WithIssuer(issuer), CA(issuer.caPEM), Service("bar"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// clients without a certificate can only connect to bootstrap servers
+	if err := handshake(cli.Config(), srv.Config()); err == nil {
+		t.Fatal("Expected an error connecting without a certificate")
+	}
+	if err := handshake(cli.Config(), srv.BootstrapConfig()); err != nil {
+		t.Fatalf("Unexpected error connecting to the bootstrap server: %v", err)
+	}
+
+	if err := cli.Rotate(); err != nil {
+		t.Fatalf("Unexpected error issuing certificate: %v", err)
+	}
+	if err := handshake(cli.Config(), srv.Config()); err != nil {
+		t.Fatalf("Unexpected error connecting with a certificate: %v", err)
+	}
+
+	// certificates issued by other CAs aren't accepted
+	otherIssuer := newTestIssuer(t, "micro")
+	other, err := New(WithIssuer(otherIssuer), CA(otherIssuer.caPEM), Service("bar"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := other.Rotate(); err != nil {
+		t.Fatal(err)
+	}
+	if err := handshake(other.Config(), srv.Config()); err == nil {
+		t.Fatal("Expected an error connecting with a certificate issued by another CA")
+	}
+
+	// revoked certificates aren't accepted once the server refreshes the revoked serials
+	issuer.revoked = []string{fmt.Sprintf("%x", cli.Certificate().SerialNumber)}
+	if err := srv.Refresh(); err != nil {
+		t.Fatal(err)
+	}
+	if err := handshake(cli.Config(), srv.Config()); err != ErrRevoked {
+		t.Fatalf("Expected the revoked certificate to be rejected, got %v", err)
+	}
+	if err := cli.Rotate(); err != nil {
+		t.Fatal(err)
+	}
+	if err := handshake(cli.Config(), srv.Config()); err != nil {
+		t.Fatalf("Unexpected error connecting with a rotated certificate: %v", err)
+	}
+
+	// certificates not issued by the provided CA are rejected
+	pinned, err := New(WithIssuer(issuer), CA(newTestIssuer(t, "micro").caPEM), Service("baz"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := pinned.Rotate(); err == nil {
+		t.Fatal("Expected an error issuing a certificate by another CA")
+	}
+}
+
+func TestIdentity(t *testing.T) {
+	issuer := newTestIssuer(t, "micro")
+	newManager := func(i *testIssuer, service string) *Manager {
+		m, err := New(WithIssuer(i), CA(issuer.caPEM), Service(service))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := m.Rotate(); err != nil {
+			t.Fatal(err)
+		}
+		return m
+	}
+	srv := newManager(issuer, "foo")
+	cli := newManager(issuer, "bar")
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+	lookup := func(service, ns string) {
+		fn := cli.Lookup(func(context.Context, client.Request, client.CallOptions) ([]string, error) {
+			return []string{l.Addr().String()}, nil
+		})
+		req := mucp.NewClient().NewRequest(service, "Foo.Bar", nil)
+		if _, err := fn(context.TODO(), req, client.CallOptions{Network: ns}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// the server must be a service looked up at the address
+	if err := dial(l, cli, srv.Config()); err == nil {
+		t.Fatal("Expected an error connecting to a server which wasn't looked up")
+	}
+	lookup("baz", "micro")
+	if err := dial(l, cli, srv.Config()); err == nil {
+		t.Fatal("Expected an error connecting to a server which isn't the service looked up")
+	}
+	lookup("foo", "micro")
+	if err := dial(l, cli, srv.Config()); err != nil {
+		t.Fatalf("Unexpected error connecting to the service looked up: %v", err)
+	}
+
+	// tenants can't pose as the services of other namespaces
+	tenant := newManager(&testIssuer{
+		namespace: "tenant", ca: issuer.ca, caKey: issuer.caKey, caPEM: issuer.caPEM, serial: 100,
+	}, "foo")
+	if id := Identity(tenant.Certificate()); id != "tenant/foo" {
+		t.Fatalf("Expected tenant/foo, got %v", id)
+	}
+	if err := dial(l, cli, tenant.Config()); err == nil {
+		t.Fatal("Expected an error connecting to a service of another namespace")
+	}
+	lookup("foo", "tenant")
+	if err := dial(l, cli, tenant.Config()); err != nil {
+		t.Fatalf("Unexpected error connecting to the service of the namespace: %v", err)
+	}
+
+	// the network only accepts the network service as a peer
+	network := newManager(issuer, "network")
+	if err := handshake(cli.Config(), network.PeerConfig("micro/network")); err != ErrUnexpectedPeer {
+		t.Fatalf("Expected a peer other than the network to be rejected, got %v", err)
+	}
+	if err := handshake(network.PeerConfig("micro/network"), network.PeerConfig("micro/network")); err != nil {
+		t.Fatalf("Unexpected error connecting network peers: %v", err)
+	}
+}
@@ -0,0 +1,66 @@
+package mtls
+
+import "time"
+
+// Options of the manager
+type Options struct {
+	// Issuer of the certificates
+	Issuer Issuer
+	// Expiry of the certificates, they're rotated after two thirds of it has passed
+	Expiry time.Duration
+	// RefreshInterval is how often the revoked serials are refreshed
+	RefreshInterval time.Duration
+	// CA is the PEM encoded certificate of the CA peers must be issued by, it's required
+	CA []byte
+	// Service is the name of the service the certificates are requested for
+	Service string
+}
+
+// Option sets an option
+type Option func(o *Options)
+
+// WithIssuer sets the issuer of the certificates
+func WithIssuer(i Issuer) Option {
+	return func(o *Options) {
+		o.Issuer = i
+	}
+}
+
+// Expiry sets how long the certificates are valid for, e.g. 24h
+func Expiry(d time.Duration) Option {
+	return func(o *Options) {
+		o.Expiry = d
+	}
+}
+
+// RefreshInterval sets how often the revoked serials are refreshed
+func RefreshInterval(d time.Duration) Option {
+	return func(o *Options) {
+		o.RefreshInterval = d
+	}
+}
+
+// CA sets the PEM encoded certificate of the CA peers must be issued by
+func CA(ca []byte) Option {
+	return func(o *Options) {
+		o.CA = ca
+	}
+}
+
+// Service sets the name of the service the certificates are requested for
+func Service(name string) Option {
+	return func(o *Options) {
+		o.Service = name
+	}
+}
+
+func newOptions(opts ...Option) Options {
+	options := Options{
+		Expiry:          time.Hour * 24,
+		RefreshInterval: time.Minute,
+	}
+	for _, o := range opts {
+		o(&options)
+	}
+	return options
+}
@@ -0,0 +1,108 @@
+package openapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"html"
+	"net/http"
+
+	"github.com/micro/go-micro/v3/registry"
+	"github.com/micro/micro/v3/internal/namespace"
+)
+
+// SwaggerUIURL is the CDN swagger ui is loaded from by the docs UI
+var SwaggerUIURL = "https://unpkg.com/swagger-ui-dist@3"
+
+// docsTemplate is the page of the docs UI, it loads swagger ui and renders the spec
+var docsTemplate = `<!DOCTYPE html>
+<html>
+<head>
+  <meta charset="utf-8">
+  <title>%s</title>
+  <link rel="stylesheet" href="%s/swagger-ui.css">
+</head>
+<body>
+  <div id="swagger-ui"></div>
+  <script src="%s/swagger-ui-bundle.js"></script>
+  <script>
+    window.onload = function() {
+      SwaggerUIBundle({url: "%s", dom_id: "#swagger-ui"});
+    };
+  </script>
+</body>
+</html>
+`
+
+// Services returns the services in the namespace with their endpoints. All the services are
+// returned if no names are provided.
+func Services(reg registry.Registry, ns string, names ...string) ([]*registry.Service, error) {
+	if len(names) == 0 {
+		srvs, err := reg.ListServices(registry.ListDomain(ns))
+		if err != nil {
+			return nil, err
+		}
+		for _, s := range srvs {
+			names = append(names, s.Name)
+		}
+	}
+
+	var result []*registry.Service
+	for _, n := range names {
+		srvs, err := reg.GetService(n, registry.GetDomain(ns))
+		if err == registry.ErrNotFound {
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+		result = append(result, srvs...)
+	}
+	return result, nil
+}
+
+// Handler serves the spec of the services in the namespace of the request. The spec can be
+// limited to services using the service query param, e.g. /openapi.json?service=foo.
+func Handler(reg registry.Registry, opts ...Option) http.Handler {
+	var options Options
+	for _, o := range opts {
+		o(&options)
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "OPTIONS" {
+			return
+		}
+
+		// the namespace is set by the auth wrapper
+		ns := r.Header.Get(namespace.NamespaceKey)
+		if len(ns) == 0 {
+			ns = registry.DefaultDomain
+		}
+
+		// services are named without the prefix in the paths
+		names := r.URL.Query()["service"]
+		if len(options.ServicePrefix) > 0 {
+			for i, n := range names {
+				names[i] = options.ServicePrefix + "." + n
+			}
+		}
+
+		srvs, err := Services(reg, ns, names...)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(Generate(srvs, opts...))
+	})
+}
+
+// DocsHandler serves the docs UI for the spec at the url. The assets of swagger ui aren't served,
+// they're loaded by the browser from SwaggerUIURL so the docs require access to it.
+func DocsHandler(title, url string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		assets := html.EscapeString(SwaggerUIURL)
+		fmt.Fprintf(w, docsTemplate, html.EscapeString(title), assets, assets, html.EscapeString(url))
+	})
+}
@@ -0,0 +1,379 @@
+// Package openapi generates OpenAPI 3 specs for the services in the registry
+package openapi
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/micro/go-micro/v3/api"
+	"github.com/micro/go-micro/v3/registry"
+)
+
+const (
+	// Version of the OpenAPI specification the specs are generated for
+	Version = "3.0.3"
+)
+
+var (
+	versionRe = regexp.MustCompile("^v[0-9]+$")
+
+	// internalFields are the fields of generated protobuf structs which aren't part of the message
+	internalFields = map[string]bool{
+		"state":         true,
+		"sizeCache":     true,
+		"unknownFields": true,
+	}
+)
+
+// Spec is an OpenAPI 3 document
+type Spec struct {
+	OpenAPI    string              `json:"openapi"`
+	Info       *Info               `json:"info"`
+	Servers    []*Server           `json:"servers,omitempty"`
+	Tags       []*Tag              `json:"tags,omitempty"`
+	Paths      map[string]PathItem `json:"paths"`
+	Components *Components         `json:"components,omitempty"`
+}
+
+type Info struct {
+	Title       string `json:"title"`
+	Description string `json:"description,omitempty"`
+	Version     string `json:"version"`
+}
+
+type Server struct {
+	URL string `json:"url"`
+}
+
+type Tag struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+}
+
+// PathItem has the operations of a path, keyed by http method
+type PathItem map[string]*Operation
+
+type Operation struct {
+	OperationID string               `json:"operationId"`
+	Summary     string               `json:"summary,omitempty"`
+	Description string               `json:"description,omitempty"`
+	Tags        []string             `json:"tags,omitempty"`
+	RequestBody *RequestBody         `json:"requestBody,omitempty"`
+	Responses   map[string]*Response `json:"responses"`
+}
+
+type RequestBody struct {
+	Required bool                  `json:"required,omitempty"`
+	Content  map[string]*MediaType `json:"content"`
+}
+
+type Response struct {
+	Description string                `json:"description"`
+	Content     map[string]*MediaType `json:"content,omitempty"`
+}
+
+type MediaType struct {
+	Schema *Schema `json:"schema"`
+}
+
+type Schema struct {
+	Ref                  string             `json:"$ref,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+}
+
+type Components struct {
+	Schemas map[string]*Schema `json:"schemas,omitempty"`
+}
+
+type Options struct {
+	// Title of the spec
+	Title string
+	// Version of the API
+	Version string
+	// ServicePrefix is prefixed to the names of services by the resolver, e.g. "go.micro.api".
+	// Only the services with the prefix are included and it's removed from their paths.
+	ServicePrefix string
+	// Server is the url of the gateway
+	Server string
+}
+
+type Option func(o *Options)
+
+// Title of the spec
+func Title(t string) Option {
+	return func(o *Options) {
+		o.Title = t
+	}
+}
+
+// APIVersion sets the version of the API, which is different to the version of the spec format
+func APIVersion(v string) Option {
+	return func(o *Options) {
+		o.Version = v
+	}
+}
+
+// ServicePrefix is the prefix the resolver adds to the names of services
+func ServicePrefix(p string) Option {
+	return func(o *Options) {
+		o.ServicePrefix = p
+	}
+}
+
+// WithServer sets the url of the gateway the API is served by
+func WithServer(url string) Option {
+	return func(o *Options) {
+		o.Server = url
+	}
+}
+
+// Generate returns the spec of the endpoints of the services. The paths are the ones the micro
+// resolver routes to the endpoints, unless the endpoint was registered with its own paths.
+func Generate(services []*registry.Service, opts ...Option) *Spec {
+	options := Options{
+		Title:   "Micro API",
+		Version: "latest",
+	}
+	for _, o := range opts {
+		o(&options)
+	}
+
+	spec := &Spec{
+		OpenAPI:    Version,
+		Info:       &Info{Title: options.Title, Version: options.Version},
+		Paths:      make(map[string]PathItem),
+		Components: &Components{Schemas: make(map[string]*Schema)},
+	}
+	if len(options.Server) > 0 {
+		spec.Servers = []*Server{{URL: options.Server}}
+	}
+
+	// the same service may be registered multiple times, once for each version
+	seen := make(map[string]bool)
+	for _, srv := range services {
+		name := srv.Name
+		if len(options.ServicePrefix) > 0 {
+			if !strings.HasPrefix(name, options.ServicePrefix+".") {
+				continue
+			}
+			name = strings.TrimPrefix(name, options.ServicePrefix+".")
+		}
+
+		for _, ep := range srv.Endpoints {
+			// streams can't be described by openapi
+			if ep.Metadata["stream"] == "true" {
+				continue
+			}
+			// every service has the debug handler, it's not part of the api
+			if strings.HasPrefix(ep.Name, "Debug.") {
+				continue
+			}
+			if seen[srv.Name+":"+ep.Name] {
+				continue
+			}
+			seen[srv.Name+":"+ep.Name] = true
+
+			spec.addEndpoint(name, srv.Name, ep)
+		}
+
+		if !hasTag(spec.Tags, name) {
+			spec.Tags = append(spec.Tags, &Tag{Name: name})
+		}
+	}
+
+	sort.Slice(spec.Tags, func(i, j int) bool { return spec.Tags[i].Name < spec.Tags[j].Name })
+	return spec
+}
+
+// addEndpoint adds the operations of the endpoint to the spec
+func (s *Spec) addEndpoint(name, fullName string, ep *registry.Endpoint) {
+	op := &Operation{
+		OperationID: fullName + "." + ep.Name,
+		Summary:     ep.Name,
+		Tags:        []string{name},
+		Responses: map[string]*Response{
+			"200": {Description: "OK"},
+			"default": {
+				Description: "Error",
+				Content:     jsonContent(errorSchema()),
+			},
+		},
+	}
+
+	if ep.Request != nil {
+		op.RequestBody = &RequestBody{
+			Required: true,
+			Content:  jsonContent(s.schema(fullName, ep.Request)),
+		}
+	}
+	if ep.Response != nil {
+		op.Responses["200"].Content = jsonContent(s.schema(fullName, ep.Response))
+	}
+
+	methods := []string{"post"}
+	paths := []string{endpointPath(name, ep.Name)}
+
+	// endpoints registered with paths, e.g. using api.WithEndpoint, are served on those
+	if e := api.Decode(ep.Metadata); e != nil && len(e.Path) > 0 {
+		op.Description = e.Description
+		paths = paths[:0]
+		for _, p := range e.Path {
+			// regexps can't be expressed as openapi paths
+			if strings.HasPrefix(p, "^") {
+				continue
+			}
+			paths = append(paths, p)
+		}
+		if len(e.Method) > 0 {
+			methods = methods[:0]
+			for _, m := range e.Method {
+				methods = append(methods, strings.ToLower(m))
+			}
+		}
+	}
+
+	for _, p := range paths {
+		item, ok := s.Paths[p]
+		if !ok {
+			item = PathItem{}
+			s.Paths[p] = item
+		}
+		for _, m := range methods {
+			o := *op
+			if m == "get" || m == "head" || m == "delete" {
+				o.RequestBody = nil
+			}
+			item[m] = &o
+		}
+	}
+}
+
+// endpointPath returns the path the micro resolver routes to the endpoint of the service:
+// /foo/bar routes to service foo and endpoint Foo.Bar, /foo/bar/baz routes to service foo and
+// endpoint Bar.Baz and /v1/foo/bar routes to service v1.foo and endpoint Foo.Bar
+func endpointPath(service, endpoint string) string {
+	parts := strings.Split(service, ".")
+	path := "/" + strings.Join(parts, "/")
+
+	handler, method := endpoint, "Call"
+	if idx := strings.Index(endpoint, "."); idx > 0 {
+		handler, method = endpoint[:idx], endpoint[idx+1:]
+	}
+
+	// the handler is omitted when it has the name of the service, which the resolver only does
+	// for services with one part or a version
+	short := len(parts) == 1 || (len(parts) == 2 && versionRe.MatchString(parts[0]))
+	if short && handler == toCamel(parts[len(parts)-1]) {
+		// /foo routes to Foo.Call
+		if method == "Call" && len(parts) == 1 {
+			return path
+		}
+		return path + "/" + lowerFirst(method)
+	}
+
+	return path + "/" + lowerFirst(handler) + "/" + lowerFirst(method)
+}
+
+// schema returns the schema of the value, messages are added to the components of the spec
+// and referenced
+func (s *Spec) schema(service string, v *registry.Value) *Schema {
+	typ := strings.TrimPrefix(v.Type, "*")
+
+	switch {
+	case typ == "[]byte" || typ == "[]uint8":
+		return &Schema{Type: "string", Format: "byte"}
+	case strings.HasPrefix(typ, "[]"):
+		// the values of the elements of slices aren't registered
+		return &Schema{Type: "array", Items: s.schema(service, &registry.Value{Type: typ[2:]})}
+	case strings.HasPrefix(typ, "map["):
+		val := typ[strings.Index(typ, "]")+1:]
+		return &Schema{Type: "object", AdditionalProperties: s.schema(service, &registry.Value{Type: val})}
+	}
+
+	switch typ {
+	case "string":
+		return &Schema{Type: "string"}
+	case "bool":
+		return &Schema{Type: "boolean"}
+	case "int", "int8", "int16", "int32", "uint", "uint8", "uint16", "uint32":
+		return &Schema{Type: "integer", Format: "int32"}
+	case "int64", "uint64":
+		return &Schema{Type: "integer", Format: "int64"}
+	case "float32":
+		return &Schema{Type: "number", Format: "float"}
+	case "float64":
+		return &Schema{Type: "number", Format: "double"}
+	}
+
+	// the fields of messages nested too deeply aren't registered
+	if len(typ) == 0 || len(v.Values) == 0 {
+		if _, ok := s.Components.Schemas[service+"."+typ]; len(typ) > 0 && ok {
+			return &Schema{Ref: "#/components/schemas/" + service + "." + typ}
+		}
+		return &Schema{Type: "object"}
+	}
+
+	name := service + "." + typ
+	if _, ok := s.Components.Schemas[name]; !ok {
+		sch := &Schema{Type: "object", Properties: make(map[string]*Schema)}
+		s.Components.Schemas[name] = sch
+
+		for _, f := range v.Values {
+			if internalFields[f.Name] {
+				continue
+			}
+			sch.Properties[f.Name] = s.schema(service, f)
+		}
+	}
+
+	return &Schema{Ref: "#/components/schemas/" + name}
+}
+
+func jsonContent(s *Schema) map[string]*MediaType {
+	return map[string]*MediaType{"application/json": {Schema: s}}
+}
+
+// errorSchema is the schema of the errors returned by services
+func errorSchema() *Schema {
+	return &Schema{
+		Type: "object",
+		Properties: map[string]*Schema{
+			"id":     {Type: "string"},
+			"code":   {Type: "integer", Format: "int32"},
+			"detail": {Type: "string"},
+			"status": {Type: "string"},
+		},
+	}
+}
+
+func hasTag(tags []*Tag, name string) bool {
+	for _, t := range tags {
+		if t.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+func toCamel(s string) string {
+	var out string
+	for _, word := range strings.Split(s, "-") {
+		out += strings.Title(word)
+	}
+	return out
+}
+
+func lowerFirst(s string) string {
+	if len(s) == 0 {
+		return s
+	}
+	r := []rune(s)
+	r[0] = unicode.ToLower(r[0])
+	return string(r)
+}
@@ -0,0 +1,85 @@
+package openapi
+
+import (
+	"testing"
+
+	"github.com/micro/go-micro/v3/registry"
+)
+
+func TestEndpointPath(t *testing.T) {
+	tt := []struct {
+		Service  string
+		Endpoint string
+		Path     string
+	}{
+		{"foo", "Foo.Call", "/foo"},
+		{"foo", "Foo.Bar", "/foo/bar"},
+		{"foo", "Foo.SayHello", "/foo/sayHello"},
+		{"foo", "Bar.Baz", "/foo/bar/baz"},
+		{"foo-bar", "FooBar.Baz", "/foo-bar/baz"},
+		{"v1.foo", "Foo.Bar", "/v1/foo/bar"},
+		{"v1.foo", "Bar.Baz", "/v1/foo/bar/baz"},
+		{"foo.bar", "Bar.Baz", "/foo/bar/bar/baz"},
+	}
+
+	for _, tc := range tt {
+		if p := endpointPath(tc.Service, tc.Endpoint); p != tc.Path {
+			t.Errorf("Expected %v %v to have path %v, got %v", tc.Service, tc.Endpoint, tc.Path, p)
+		}
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	srvs := []*registry.Service{
+		{
+			Name: "go.micro.api.greeter",
+			Endpoints: []*registry.Endpoint{
+				{
+					Name: "Greeter.Hello",
+					Request: &registry.Value{Name: "Request", Type: "Request", Values: []*registry.Value{
+						{Name: "name", Type: "string"},
+						{Name: "count", Type: "int64"},
+						{Name: "tags", Type: "[]string"},
+						{Name: "state", Type: "MessageState"},
+					}},
+					Response: &registry.Value{Name: "Response", Type: "Response", Values: []*registry.Value{
+						{Name: "msg", Type: "string"},
+					}},
+				},
+				{Name: "Greeter.Stream", Metadata: map[string]string{"stream": "true"}},
+				{Name: "Debug.Health"},
+			},
+		},
+		{Name: "go.micro.service.other", Endpoints: []*registry.Endpoint{{Name: "Other.Call"}}},
+	}
+
+	spec := Generate(srvs, ServicePrefix("go.micro.api"))
+	if len(spec.Paths) != 1 {
+		t.Fatalf("Expected 1 path, got %v", len(spec.Paths))
+	}
+	op, ok := spec.Paths["/greeter/hello"]["post"]
+	if !ok {
+		t.Fatalf("Expected POST /greeter/hello, got %v", spec.Paths)
+	}
+	ref := op.RequestBody.Content["application/json"].Schema.Ref
+	if ref != "#/components/schemas/go.micro.api.greeter.Request" {
+		t.Errorf("Unexpected request ref %v", ref)
+	}
+
+	req := spec.Components.Schemas["go.micro.api.greeter.Request"]
+	if req == nil {
+		t.Fatal("Expected the request schema")
+	}
+	if len(req.Properties) != 3 {
+		t.Errorf("Expected 3 properties, got %v", len(req.Properties))
+	}
+	if s := req.Properties["count"]; s.Type != "integer" || s.Format != "int64" {
+		t.Errorf("Unexpected count schema %v %v", s.Type, s.Format)
+	}
+	if s := req.Properties["tags"]; s.Type != "array" || s.Items.Type != "string" {
+		t.Errorf("Unexpected tags schema %v", s.Type)
+	}
+	if _, ok := spec.Components.Schemas["go.micro.api.greeter.Response"]; !ok {
+		t.Error("Expected the response schema")
+	}
+}
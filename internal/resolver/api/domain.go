@@ -0,0 +1,34 @@
+package micro
+
+import (
+	"net/http"
+
+	"github.com/micro/go-micro/v3/api/resolver"
+	"github.com/micro/go-micro/v3/registry"
+	"github.com/micro/micro/v3/internal/namespace"
+)
+
+// Domain returns the domain the resolver determines from the request, e.g. the subdomain
+// resolver, or a blank string if it doesn't determine one
+func Domain(res resolver.Resolver, req *http.Request) string {
+	if d, ok := res.(interface{ Domain(*http.Request) string }); ok {
+		return d.Domain(req)
+	}
+	return ""
+}
+
+// ResolveDomain returns the domain of a request resolved by a resolver which wraps res. The
+// namespace header takes priority, followed by the domain passed as an option, the domain res
+// determines from the request and then the default domain.
+func ResolveDomain(res resolver.Resolver, req *http.Request, opts ...resolver.ResolveOption) string {
+	if dom := req.Header.Get(namespace.NamespaceKey); len(dom) > 0 {
+		return dom
+	}
+	if dom := resolver.NewResolveOptions(opts...).Domain; len(dom) > 0 {
+		return dom
+	}
+	if dom := Domain(res, req); len(dom) > 0 {
+		return dom
+	}
+	return registry.DefaultDomain
+}
@@ -8,6 +8,8 @@ import (
 	"runtime"
 	"sync"
 	"time"
+
+	"github.com/micro/micro/v3/internal/breaker"
 )
 
 type stats struct {
@@ -19,6 +21,8 @@ type stats struct {
 	GC      string `json:"gc_pause"`
 
 	Counters []*counter `json:"counters"`
+	// Breakers are the circuit breakers of the services called
+	Breakers []breaker.Stat `json:"breakers"`
 
 	running bool
 	exit    chan bool
@@ -124,9 +128,10 @@ func (s *stats) ServeHTTP(h http.Handler) http.Handler {
 
 func (s *stats) StatsHandler(w http.ResponseWriter, r *http.Request) {
 	if ct := r.Header.Get("Content-Type"); ct == "application/json" {
-		s.RLock()
+		s.Lock()
+		s.Breakers = breaker.DefaultBreakers.Stats()
 		b, err := json.Marshal(s)
-		s.RUnlock()
+		s.Unlock()
 		if err != nil {
 			http.Error(w, err.Error(), 500)
 			return
@@ -104,7 +104,23 @@ var (
 	statsTemplate = `
 {{define "title"}}Stats{{end}}
 {{define "content"}}
-  <div id="chart" style="height: 300px; width: 100%;">
+  <div id="chart" style="height: 300px; width: 100%;"></div>
+  <table class="table table-bordered breakers">
+    <caption>Circuit Breakers</caption>
+    <thead>
+      <tr>
+        <th>Namespace</th>
+        <th>Service</th>
+        <th>Node</th>
+        <th>State</th>
+        <th>Requests</th>
+        <th>Errors</th>
+        <th>Slow</th>
+        <th>Trips</th>
+      </tr>
+    </thead>
+    <tbody></tbody>
+  </table>
 {{end}}
 {{define "script"}}
 <script>
@@ -224,6 +240,29 @@ var (
   };
 
 
+  function loadBreakers(breakers) {
+    var body = $('.breakers tbody').empty();
+
+    for (i = 0; i < breakers.length; i++) {
+      var b = breakers[i];
+      var row = $('<tr>');
+      if (b["state"] == "open") {
+        row.addClass('danger');
+      } else if (b["state"] == "half-open") {
+        row.addClass('warning');
+      }
+      row.append($('<td>').text(b["namespace"]));
+      row.append($('<td>').text(b["service"]));
+      row.append($('<td>').text(b["node"] || "-"));
+      row.append($('<td>').text(b["state"]));
+      row.append($('<td>').text(b["requests"]));
+      row.append($('<td>').text(b["errors"]));
+      row.append($('<td>').text(b["slow"]));
+      row.append($('<td>').text(b["trips"]));
+      body.append(row);
+    }
+  };
+
   function loadStats() {
     var req = new XMLHttpRequest();
     req.onreadystatechange = function() {
@@ -284,6 +323,7 @@ var (
             $('.50x').text(fx);
 
             loadChart(data["counters"]);
+            loadBreakers(data["breakers"] || []);
 	}
     }
 
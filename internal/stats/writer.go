@@ -1,6 +1,9 @@
 package stats
 
 import (
+	"bufio"
+	"errors"
+	"net"
 	"net/http"
 )
 
@@ -13,3 +16,21 @@ func (w *writer) WriteHeader(code int) {
 	w.ResponseWriter.WriteHeader(code)
 	w.status = code
 }
+
+// Flush the response so streamed responses are written as they're received
+func (w *writer) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Hijack the connection so websocket requests can be upgraded
+func (w *writer) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	h, ok := w.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, errors.New("response writer does not support hijacking")
+	}
+	// the connection is switching protocols
+	w.status = http.StatusSwitchingProtocols
+	return h.Hijack()
+}
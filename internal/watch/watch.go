@@ -0,0 +1,50 @@
+// Package watch reloads values from the config service when they change
+package watch
+
+import (
+	"bytes"
+	"time"
+
+	"github.com/micro/go-micro/v3/config/reader"
+	"github.com/micro/micro/v3/service/config"
+)
+
+// Config calls load with the value at the path in the config service, then polls the config
+// every interval in a separate go routine and calls load again whenever the value changes. It
+// returns once the value has been loaded the first time.
+func Config(path []string, interval time.Duration, load func(reader.Value)) {
+	w := &watcher{path: path, load: load}
+	w.poll()
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for range ticker.C {
+			w.poll()
+		}
+	}()
+}
+
+type watcher struct {
+	path []string
+	load func(reader.Value)
+	// raw is the config the value was last loaded from
+	raw []byte
+}
+
+// poll loads the value if it changed since it was last loaded
+func (w *watcher) poll() {
+	if config.DefaultConfig == nil {
+		return
+	}
+
+	val := config.Get(w.path...)
+	raw := val.Bytes()
+	if w.raw != nil && bytes.Equal(raw, w.raw) {
+		return
+	}
+	w.raw = raw
+
+	w.load(val)
+}
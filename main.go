@@ -17,10 +17,13 @@ import (
 	_ "github.com/micro/micro/v3/client/cli/user"
 	_ "github.com/micro/micro/v3/platform/cli"
 	_ "github.com/micro/micro/v3/server"
+	_ "github.com/micro/micro/v3/service/api/cli"
 	_ "github.com/micro/micro/v3/service/auth/cli"
 	_ "github.com/micro/micro/v3/service/cli"
 	_ "github.com/micro/micro/v3/service/config/cli"
 	_ "github.com/micro/micro/v3/service/network/cli"
+	_ "github.com/micro/micro/v3/service/registry/cli"
+	_ "github.com/micro/micro/v3/service/router/cli"
 	_ "github.com/micro/micro/v3/service/runtime/cli"
 	_ "github.com/micro/micro/v3/service/store/cli"
 )
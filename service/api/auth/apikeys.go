@@ -0,0 +1,67 @@
+package auth
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"sync"
+	"time"
+
+	goauth "github.com/micro/go-micro/v3/auth"
+	"github.com/micro/micro/v3/service/auth"
+)
+
+// apiKeyCacheTTL is how long verified api keys are cached so the auth service isn't called for
+// every request. Revoked keys can be used until their entry expires.
+var apiKeyCacheTTL = time.Second * 15
+
+// apiKeyCache caches the accounts and tokens of verified api keys
+type apiKeyCache struct {
+	sync.Mutex
+	entries map[string]*apiKeyEntry
+}
+
+type apiKeyEntry struct {
+	account *goauth.Account
+	token   *goauth.Token
+	expiry  time.Time
+}
+
+func newAPIKeyCache() *apiKeyCache {
+	return &apiKeyCache{entries: make(map[string]*apiKeyEntry)}
+}
+
+// Verify returns the account and token of the api key issued by the namespace, verifying it
+// using the auth service if it isn't cached
+func (c *apiKeyCache) Verify(key, ns string) (*goauth.Account, *goauth.Token, error) {
+	// the keys themselves aren't kept in memory, only their hashes
+	h := sha256.Sum256([]byte(ns + ":" + key))
+	id := hex.EncodeToString(h[:])
+
+	c.Lock()
+	e, ok := c.entries[id]
+	c.Unlock()
+	if ok && time.Now().Before(e.expiry) {
+		return e.account, e.token, nil
+	}
+
+	acc, tok, err := auth.VerifyAPIKey(key, ns)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// the token must still be valid when it's used, so the entry expires before it does
+	expiry := time.Now().Add(apiKeyCacheTTL)
+	if tokExpiry := tok.Expiry.Add(-apiKeyCacheTTL); tokExpiry.Before(expiry) {
+		expiry = tokExpiry
+	}
+
+	c.Lock()
+	defer c.Unlock()
+	for k, e := range c.entries {
+		if time.Now().After(e.expiry) {
+			delete(c.entries, k)
+		}
+	}
+	c.entries[id] = &apiKeyEntry{account: acc, token: tok, expiry: expiry}
+	return acc, tok, nil
+}
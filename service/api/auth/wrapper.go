@@ -8,24 +8,46 @@ import (
 	"strings"
 
 	"github.com/micro/go-micro/v3/api/resolver"
-	"github.com/micro/go-micro/v3/api/resolver/subdomain"
 	"github.com/micro/go-micro/v3/api/server"
 	goauth "github.com/micro/go-micro/v3/auth"
 	"github.com/micro/go-micro/v3/util/ctx"
 	inauth "github.com/micro/micro/v3/internal/auth"
+	"github.com/micro/micro/v3/internal/handler"
 	"github.com/micro/micro/v3/internal/namespace"
 	"github.com/micro/micro/v3/service/auth"
+	"github.com/micro/micro/v3/service/errors"
 	"github.com/micro/micro/v3/service/logger"
 )
 
+// APIKeyHeader is the header api keys are provided in
+const APIKeyHeader = "X-Api-Key"
+
+// Option configures the wrapper
+type Option func(a *authWrapper)
+
+// Streams sets the func which returns true if a request is to a streaming endpoint. Websocket
+// upgrades to streaming endpoints without credentials are passed to the handler, which then
+// authenticates the stream using the token in its first message.
+func Streams(fn func(req *http.Request) bool) Option {
+	return func(a *authWrapper) {
+		a.isStream = fn
+	}
+}
+
 // Wrapper wraps a handler and authenticates requests
-func Wrapper(r resolver.Resolver, prefix string) server.Wrapper {
+func Wrapper(r resolver.Resolver, prefix string, opts ...Option) server.Wrapper {
+	keys := newAPIKeyCache()
 	return func(h http.Handler) http.Handler {
-		return authWrapper{
+		a := authWrapper{
 			handler:       h,
 			resolver:      r,
 			servicePrefix: prefix,
+			apiKeys:       keys,
 		}
+		for _, o := range opts {
+			o(&a)
+		}
+		return a
 	}
 }
 
@@ -33,6 +55,14 @@ type authWrapper struct {
 	handler       http.Handler
 	resolver      resolver.Resolver
 	servicePrefix string
+	apiKeys       *apiKeyCache
+	isStream      func(req *http.Request) bool
+}
+
+// domainResolver is a resolver which determines the domain from the request, e.g. the subdomain
+// resolver
+type domainResolver interface {
+	Domain(req *http.Request) string
 }
 
 func (a authWrapper) ServeHTTP(w http.ResponseWriter, req *http.Request) {
@@ -54,7 +84,7 @@ func (a authWrapper) ServeHTTP(w http.ResponseWriter, req *http.Request) {
 
 	// If an error occured looking up the route, the domain isn't returned. TODO: Find a better way
 	// of resolving network for non-standard requests, e.g. "/rpc".
-	if r, ok := a.resolver.(*subdomain.Resolver); ok && len(endpoint.Domain) == 0 {
+	if r, ok := a.resolver.(domainResolver); ok && len(endpoint.Domain) == 0 {
 		endpoint.Domain = r.Domain(req)
 	}
 
@@ -80,6 +110,29 @@ func (a authWrapper) ServeHTTP(w http.ResponseWriter, req *http.Request) {
 	// account doesn't necesserially mean a forbidden request
 	acc, _ := auth.Inspect(token)
 
+	// Machine clients can authenticate using an api key instead of a token. The key is exchanged
+	// for a short lived token so the services can verify requests made on behalf of the key.
+	if key := req.Header.Get(APIKeyHeader); len(key) > 0 && len(token) == 0 {
+		keyNs := req.Header.Get(namespace.NamespaceKey)
+		if len(keyNs) == 0 {
+			keyNs = endpoint.Domain
+		}
+
+		keyAcc, keyTok, err := a.apiKeys.Verify(key, keyNs)
+		if verr := errors.Parse(err); verr != nil && verr.Code == http.StatusBadRequest {
+			http.Error(w, verr.Detail, http.StatusUnauthorized)
+			return
+		} else if err != nil {
+			logger.Errorf("Error verifying api key: %v", err)
+			http.Error(w, "Error verifying api key", http.StatusInternalServerError)
+			return
+		}
+
+		acc = keyAcc
+		req.Header.Del(APIKeyHeader)
+		req.Header.Set("Authorization", goauth.BearerScheme+keyTok.AccessToken)
+	}
+
 	// Determine the namespace and set it in the header. If the user passed auth creds
 	// on the request, use the namespace that issued the account, otherwise check for
 	// the domain of the resolved endpoint.
@@ -121,7 +174,11 @@ func (a authWrapper) ServeHTTP(w http.ResponseWriter, req *http.Request) {
 	// the resource they're requesting
 	res := &goauth.Resource{Type: "service", Name: resName, Endpoint: resEndpoint}
 	if err := auth.Verify(acc, res, verifyOpts...); err == nil {
-		// The account has the necessary permissions to access the resource
+		// The account has the necessary permissions to access the resource, it's set in the
+		// context so it can be used by other wrappers, e.g. to rate limit the account
+		if acc != nil {
+			req = req.WithContext(goauth.ContextWithAccount(req.Context(), acc))
+		}
 		a.handler.ServeHTTP(w, req)
 		return
 	} else if err != goauth.ErrForbidden {
@@ -135,6 +192,13 @@ func (a authWrapper) ServeHTTP(w http.ResponseWriter, req *http.Request) {
 		return
 	}
 
+	// Browsers can't set headers on websocket upgrades, streams are authenticated using the
+	// token in their first message instead
+	if a.isStream != nil && handler.IsWebSocket(req) && a.isStream(req) {
+		a.handler.ServeHTTP(w, req.WithContext(handler.WithStreamAuth(req.Context())))
+		return
+	}
+
 	// If there is no auth login url set, 401
 	loginURL := auth.DefaultAuth.Options().LoginURL
 	if loginURL == "" {
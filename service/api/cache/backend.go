@@ -0,0 +1,164 @@
+package cache
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	gostore "github.com/micro/go-micro/v3/store"
+	"github.com/micro/micro/v3/service/store"
+)
+
+const (
+	// storePrefix is prefixed to the keys of the responses in the store
+	storePrefix = "apicache:"
+	// pruneInterval is how often expired responses are removed from memory
+	pruneInterval = time.Minute
+	// maxMemoryEntries is the max number of responses cached in memory
+	maxMemoryEntries = 10000
+)
+
+// ErrNotFound is returned when a response isn't cached
+var ErrNotFound = errors.New("not found")
+
+// Entry is a cached response
+type Entry struct {
+	Status  int         `json:"status"`
+	Header  http.Header `json:"header"`
+	Body    []byte      `json:"body"`
+	Created time.Time   `json:"created"`
+}
+
+// Backend stores the cached responses
+type Backend interface {
+	// Get the response with the key, ErrNotFound is returned if it's not cached or has expired
+	Get(key string) (*Entry, error)
+	// Set the response with the key, it expires after the ttl
+	Set(key string, e *Entry, ttl time.Duration) error
+	// Purge the responses with keys which have the prefix, returning the number purged
+	Purge(prefix string) (int, error)
+}
+
+// NewMemoryBackend returns a backend which caches responses in memory, each replica of the api
+// has its own cache
+func NewMemoryBackend() Backend {
+	return &memoryBackend{
+		entries: make(map[string]*memoryEntry),
+	}
+}
+
+type memoryEntry struct {
+	*Entry
+	expires time.Time
+}
+
+type memoryBackend struct {
+	sync.RWMutex
+	entries map[string]*memoryEntry
+	pruned  time.Time
+}
+
+func (m *memoryBackend) Get(key string) (*Entry, error) {
+	m.RLock()
+	defer m.RUnlock()
+
+	e, ok := m.entries[key]
+	if !ok || time.Now().After(e.expires) {
+		return nil, ErrNotFound
+	}
+	return e.Entry, nil
+}
+
+func (m *memoryBackend) Set(key string, e *Entry, ttl time.Duration) error {
+	m.Lock()
+	defer m.Unlock()
+
+	now := time.Now()
+	m.prune(now)
+
+	// the cache is full, new responses aren't cached until the existing ones expire
+	if _, ok := m.entries[key]; !ok && len(m.entries) >= maxMemoryEntries {
+		return nil
+	}
+
+	m.entries[key] = &memoryEntry{Entry: e, expires: now.Add(ttl)}
+	return nil
+}
+
+func (m *memoryBackend) Purge(prefix string) (int, error) {
+	m.Lock()
+	defer m.Unlock()
+
+	var count int
+	for k := range m.entries {
+		if strings.HasPrefix(k, prefix) {
+			delete(m.entries, k)
+			count++
+		}
+	}
+	return count, nil
+}
+
+// prune removes the expired responses
+func (m *memoryBackend) prune(now time.Time) {
+	if now.Sub(m.pruned) < pruneInterval && len(m.entries) < maxMemoryEntries {
+		return
+	}
+	m.pruned = now
+
+	for k, e := range m.entries {
+		if now.After(e.expires) {
+			delete(m.entries, k)
+		}
+	}
+}
+
+// NewStoreBackend returns a backend which caches responses in the store, so the cache is shared
+// by the replicas of the api
+func NewStoreBackend() Backend {
+	return &storeBackend{}
+}
+
+type storeBackend struct{}
+
+func (s *storeBackend) Get(key string) (*Entry, error) {
+	recs, err := store.Read(storePrefix + key)
+	if err == gostore.ErrNotFound || (err == nil && len(recs) == 0) {
+		return nil, ErrNotFound
+	} else if err != nil {
+		return nil, err
+	}
+
+	var e Entry
+	if err := json.Unmarshal(recs[0].Value, &e); err != nil {
+		return nil, err
+	}
+	return &e, nil
+}
+
+func (s *storeBackend) Set(key string, e *Entry, ttl time.Duration) error {
+	bytes, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	return store.Write(&gostore.Record{Key: storePrefix + key, Value: bytes, Expiry: ttl})
+}
+
+func (s *storeBackend) Purge(prefix string) (int, error) {
+	keys, err := store.List(gostore.ListPrefix(storePrefix + prefix))
+	if err != nil {
+		return 0, err
+	}
+
+	var count int
+	for _, k := range keys {
+		if err := store.Delete(k); err != nil && err != gostore.ErrNotFound {
+			return count, err
+		}
+		count++
+	}
+	return count, nil
+}
@@ -0,0 +1,445 @@
+// Package cache caches the responses of the api. Responses to GET and HEAD requests are cached
+// for the ttl of the route they match, or for the max-age set by the service in the
+// Cache-Control header. The routes are read from the config service, e.g.
+//
+//	micro config set api.cache '[{"route": "/helloworld", "ttl": 30}]'
+//
+// Routes which are idempotent but use other methods, e.g. RPC style POST requests, can opt in
+// using methods. Responses are cached separately for each namespace and account.
+package cache
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"path"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/micro/go-micro/v3/api/server"
+	goauth "github.com/micro/go-micro/v3/auth"
+	"github.com/micro/go-micro/v3/config/reader"
+	"github.com/micro/micro/v3/internal/namespace"
+	"github.com/micro/micro/v3/internal/watch"
+	"github.com/micro/micro/v3/service/events"
+	"github.com/micro/micro/v3/service/logger"
+)
+
+const (
+	// PurgeTopic is the topic purge requests are published to, every replica of the api purges
+	// its cache when one is received
+	PurgeTopic = "api.cache.purge"
+	// maxBodySize is the max size of a request body included in the cache key, requests with
+	// larger bodies aren't cached
+	maxBodySize = 1024 * 1024
+	// maxEntrySize is the max size of a response body which is cached
+	maxEntrySize = 1024 * 1024
+)
+
+var (
+	// ConfigPath is the path of the routes in the config service
+	ConfigPath = []string{"api", "cache"}
+	// RefreshInterval is how often the routes are reloaded from config
+	RefreshInterval = time.Second * 10
+)
+
+// Route is cached for the ttl
+type Route struct {
+	// Route is the path prefix of the requests which are cached
+	Route string `json:"route"`
+	// TTL is the number of seconds responses are cached for. If zero the max-age in the
+	// Cache-Control header of the response is used.
+	TTL int `json:"ttl,omitempty"`
+	// Methods which are cached in addition to GET and HEAD
+	Methods []string `json:"methods,omitempty"`
+}
+
+// match returns true if the route applies to the request
+func (r *Route) match(req *http.Request, route string) bool {
+	if !strings.HasPrefix(route, r.Route) {
+		return false
+	}
+	if req.Method == "GET" || req.Method == "HEAD" {
+		return true
+	}
+	for _, m := range r.Methods {
+		if strings.EqualFold(m, req.Method) {
+			return true
+		}
+	}
+	return false
+}
+
+// PurgeRequest is published to purge the responses of a route from the cache
+type PurgeRequest struct {
+	Namespace string `json:"namespace"`
+	Route     string `json:"route"`
+}
+
+// Purge the cached responses of the route in the namespace from every replica of the api
+func Purge(ns, route string) error {
+	return events.Publish(PurgeTopic, &PurgeRequest{Namespace: ns, Route: route})
+}
+
+type Options struct {
+	// Backend the responses are cached in, defaults to memory
+	Backend Backend
+}
+
+type Option func(o *Options)
+
+// WithBackend sets the backend the responses are cached in
+func WithBackend(b Backend) Option {
+	return func(o *Options) {
+		o.Backend = b
+	}
+}
+
+// Wrapper caches the responses of the api. It expects the namespace to be set in the request
+// header and the account in the context, so it should be wrapped by the auth wrapper.
+func Wrapper(opts ...Option) server.Wrapper {
+	options := Options{}
+	for _, o := range opts {
+		o(&options)
+	}
+	if options.Backend == nil {
+		options.Backend = NewMemoryBackend()
+	}
+
+	r := &routes{}
+	watch.Config(ConfigPath, RefreshInterval, r.load)
+	go subscribe(options.Backend)
+
+	return func(h http.Handler) http.Handler {
+		return cacheWrapper{
+			handler: h,
+			routes:  r,
+			opts:    options,
+		}
+	}
+}
+
+type cacheWrapper struct {
+	handler http.Handler
+	routes  *routes
+	opts    Options
+}
+
+func (c cacheWrapper) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	route := path.Clean("/" + req.URL.Path)
+
+	var rt *Route
+	for _, r := range c.routes.get() {
+		if r.match(req, route) {
+			rt = r
+			break
+		}
+	}
+	if streamed(req) || (rt == nil && req.Method != "GET" && req.Method != "HEAD") {
+		c.handler.ServeHTTP(w, req)
+		return
+	}
+
+	// the body is part of the key for methods such as POST
+	var body []byte
+	if req.Body != nil && req.Method != "GET" && req.Method != "HEAD" {
+		b, err := ioutil.ReadAll(http.MaxBytesReader(w, req.Body, maxBodySize))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusRequestEntityTooLarge)
+			return
+		}
+		req.Body.Close()
+		req.Body = ioutil.NopCloser(bytes.NewReader(b))
+		body = b
+	}
+	key := cacheKey(req, route, body)
+
+	// the client can ask for the response not to be served from the cache
+	reqCC := parseCacheControl(req.Header.Get("Cache-Control"))
+	if !reqCC.noCache && !reqCC.noStore {
+		if e, err := c.opts.Backend.Get(key); err == nil {
+			writeEntry(w, req, e, "HIT")
+			return
+		} else if err != ErrNotFound {
+			logger.Warnf("Error reading cached response: %v", err)
+		}
+	}
+
+	rec := &recorder{w: w, header: make(http.Header)}
+	c.handler.ServeHTTP(rec, req)
+	if rec.passthrough {
+		return
+	}
+
+	e := rec.entry()
+	if ttl := entryTTL(rt, e); ttl > 0 && !reqCC.noStore {
+		if err := c.opts.Backend.Set(key, e, ttl); err != nil {
+			logger.Warnf("Error caching response: %v", err)
+		}
+	}
+	writeEntry(w, req, e, "MISS")
+}
+
+// streamed returns true if the request is streamed, e.g. websockets and grpc, so can't be cached
+func streamed(req *http.Request) bool {
+	return len(req.Header.Get("Upgrade")) > 0 || strings.HasPrefix(req.Header.Get("Content-Type"), "application/grpc")
+}
+
+// cacheKey returns the key of the request, it's prefixed with the namespace and route so the
+// responses of a route can be purged
+func cacheKey(req *http.Request, route string, body []byte) string {
+	var account string
+	if acc, ok := goauth.AccountFromContext(req.Context()); ok {
+		account = acc.ID
+	}
+
+	bytes, _ := json.Marshal(map[string]interface{}{
+		"account": account,
+		"method":  req.Method,
+		"query":   req.URL.RawQuery,
+		"accept":  req.Header.Get("Accept"),
+		"type":    req.Header.Get("Content-Type"),
+		"body":    body,
+	})
+	h := sha256.Sum256(bytes)
+
+	return fmt.Sprintf("%v:%v#%v", req.Header.Get(namespace.NamespaceKey), route, hex.EncodeToString(h[:]))
+}
+
+// entryTTL returns how long the response should be cached for, zero if it shouldn't be cached
+func entryTTL(rt *Route, e *Entry) time.Duration {
+	if e.Status != http.StatusOK || len(e.Header.Get("Set-Cookie")) > 0 {
+		return 0
+	}
+
+	cc := parseCacheControl(e.Header.Get("Cache-Control"))
+	if cc.noStore || cc.noCache || cc.private {
+		return 0
+	}
+	if rt != nil && rt.TTL > 0 {
+		return time.Duration(rt.TTL) * time.Second
+	}
+	if cc.sMaxAge > 0 {
+		return time.Duration(cc.sMaxAge) * time.Second
+	}
+	if cc.maxAge > 0 {
+		return time.Duration(cc.maxAge) * time.Second
+	}
+	return 0
+}
+
+// writeEntry writes the response, or not modified if the client has the current version
+func writeEntry(w http.ResponseWriter, req *http.Request, e *Entry, status string) {
+	for k, v := range e.Header {
+		w.Header()[k] = v
+	}
+	w.Header().Set("X-Cache", status)
+	if status == "HIT" {
+		w.Header().Set("Age", strconv.Itoa(int(time.Since(e.Created).Seconds())))
+	}
+
+	if etag := e.Header.Get("ETag"); e.Status == http.StatusOK && matchETag(req.Header.Get("If-None-Match"), etag) {
+		w.Header().Del("Content-Length")
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	w.WriteHeader(e.Status)
+	if req.Method != "HEAD" {
+		w.Write(e.Body)
+	}
+}
+
+// matchETag returns true if the If-None-Match header matches the etag
+func matchETag(header, etag string) bool {
+	if len(header) == 0 || len(etag) == 0 {
+		return false
+	}
+	for _, t := range strings.Split(header, ",") {
+		t = strings.TrimSpace(t)
+		if t == "*" || strings.TrimPrefix(t, "W/") == strings.TrimPrefix(etag, "W/") {
+			return true
+		}
+	}
+	return false
+}
+
+// cacheControl is the directives of a Cache-Control header
+type cacheControl struct {
+	noCache bool
+	noStore bool
+	private bool
+	maxAge  int
+	sMaxAge int
+}
+
+func parseCacheControl(header string) cacheControl {
+	var cc cacheControl
+	for _, d := range strings.Split(header, ",") {
+		d = strings.ToLower(strings.TrimSpace(d))
+		name, val := d, ""
+		if idx := strings.Index(d, "="); idx >= 0 {
+			name, val = d[:idx], strings.Trim(d[idx+1:], `"`)
+		}
+
+		switch name {
+		case "no-cache":
+			cc.noCache = true
+		case "no-store":
+			cc.noStore = true
+		case "private":
+			cc.private = true
+		case "max-age":
+			cc.maxAge, _ = strconv.Atoi(val)
+		case "s-maxage":
+			cc.sMaxAge, _ = strconv.Atoi(val)
+		}
+	}
+	return cc
+}
+
+// recorder buffers the response so it can be cached. If the response is too large to cache or
+// is flushed, e.g. because it's streamed, it's written to the client as it's received instead.
+type recorder struct {
+	w           http.ResponseWriter
+	header      http.Header
+	status      int
+	body        bytes.Buffer
+	passthrough bool
+}
+
+func (r *recorder) Header() http.Header {
+	if r.passthrough {
+		return r.w.Header()
+	}
+	return r.header
+}
+
+func (r *recorder) WriteHeader(code int) {
+	if r.status == 0 {
+		r.status = code
+	}
+}
+
+func (r *recorder) Write(b []byte) (int, error) {
+	if r.status == 0 {
+		r.status = http.StatusOK
+	}
+	if r.passthrough {
+		return r.w.Write(b)
+	}
+	if r.body.Len()+len(b) > maxEntrySize {
+		r.startPassthrough()
+		return r.w.Write(b)
+	}
+	return r.body.Write(b)
+}
+
+func (r *recorder) Flush() {
+	if !r.passthrough {
+		r.startPassthrough()
+	}
+	if f, ok := r.w.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// startPassthrough writes the buffered response to the client
+func (r *recorder) startPassthrough() {
+	r.passthrough = true
+	if r.status == 0 {
+		r.status = http.StatusOK
+	}
+	for k, v := range r.header {
+		r.w.Header()[k] = v
+	}
+	r.w.WriteHeader(r.status)
+	r.w.Write(r.body.Bytes())
+	r.body.Reset()
+}
+
+// entry returns the buffered response, the etag is set if the service didn't set one
+func (r *recorder) entry() *Entry {
+	if r.status == 0 {
+		r.status = http.StatusOK
+	}
+	body := r.body.Bytes()
+
+	if len(r.header.Get("ETag")) == 0 && r.status == http.StatusOK {
+		h := sha256.Sum256(body)
+		r.header.Set("ETag", `"`+hex.EncodeToString(h[:16])+`"`)
+	}
+
+	return &Entry{
+		Status:  r.status,
+		Header:  r.header,
+		Body:    body,
+		Created: time.Now(),
+	}
+}
+
+// subscribe to purge requests, the subscription is retried until the events service is available
+func subscribe(b Backend) {
+	for {
+		evs, err := events.Subscribe(PurgeTopic)
+		if err != nil {
+			logger.Debugf("Error subscribing to cache purges: %v", err)
+			time.Sleep(RefreshInterval)
+			continue
+		}
+
+		for ev := range evs {
+			var req PurgeRequest
+			if err := ev.Unmarshal(&req); err != nil {
+				logger.Warnf("Error decoding cache purge: %v", err)
+				continue
+			}
+
+			prefix := fmt.Sprintf("%v:%v", req.Namespace, path.Clean("/"+req.Route))
+			if n, err := b.Purge(prefix); err != nil {
+				logger.Warnf("Error purging %v from the cache: %v", prefix, err)
+			} else {
+				logger.Infof("Purged %v responses to %v from the cache", n, prefix)
+			}
+		}
+	}
+}
+
+// routes are loaded from config and refreshed periodically
+type routes struct {
+	sync.RWMutex
+	routes []*Route
+}
+
+func (r *routes) get() []*Route {
+	r.RLock()
+	defer r.RUnlock()
+	return r.routes
+}
+
+func (r *routes) load(val reader.Value) {
+	var rts []*Route
+	if err := val.Scan(&rts); err != nil {
+		logger.Warnf("Error loading cache routes: %v", err)
+		return
+	}
+
+	valid := make([]*Route, 0, len(rts))
+	for _, rt := range rts {
+		if rt == nil || len(rt.Route) == 0 || rt.TTL < 0 {
+			logger.Warnf("Ignoring invalid cache route: %+v", rt)
+			continue
+		}
+		valid = append(valid, rt)
+	}
+
+	r.Lock()
+	r.routes = valid
+	r.Unlock()
+}
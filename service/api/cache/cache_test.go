@@ -0,0 +1,129 @@
+package cache
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	goauth "github.com/micro/go-micro/v3/auth"
+	"github.com/micro/micro/v3/internal/namespace"
+)
+
+func TestWrapper(t *testing.T) {
+	var calls int
+	h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if r.URL.Path == "/nostore" {
+			w.Header().Set("Cache-Control", "no-store")
+		} else if r.URL.Path == "/maxage" {
+			w.Header().Set("Cache-Control", "public, max-age=60")
+		}
+		fmt.Fprintf(w, "response %v", calls)
+	})
+
+	backend := NewMemoryBackend()
+	c := cacheWrapper{
+		handler: h,
+		routes: &routes{routes: []*Route{
+			{Route: "/foo", TTL: 10},
+			{Route: "/nostore", TTL: 10},
+			{Route: "/rpc", TTL: 10, Methods: []string{"POST"}},
+		}},
+		opts: Options{Backend: backend},
+	}
+
+	do := func(method, path, body string, header map[string]string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set(namespace.NamespaceKey, "micro")
+		for k, v := range header {
+			req.Header.Set(k, v)
+		}
+		if acc, ok := header["account"]; ok {
+			req = req.WithContext(goauth.ContextWithAccount(req.Context(), &goauth.Account{ID: acc}))
+		}
+		rsp := httptest.NewRecorder()
+		c.ServeHTTP(rsp, req)
+		return rsp
+	}
+
+	// the first request is cached for the route ttl
+	rsp := do("GET", "/foo/bar", "", nil)
+	if rsp.Body.String() != "response 1" || rsp.Header().Get("X-Cache") != "MISS" {
+		t.Fatalf("Expected an uncached response, got %v %v", rsp.Body.String(), rsp.Header().Get("X-Cache"))
+	}
+	etag := rsp.Header().Get("ETag")
+	if len(etag) == 0 {
+		t.Errorf("Expected an etag to be set")
+	}
+	rsp = do("GET", "/foo/bar", "", nil)
+	if rsp.Body.String() != "response 1" || rsp.Header().Get("X-Cache") != "HIT" {
+		t.Errorf("Expected a cached response, got %v %v", rsp.Body.String(), rsp.Header().Get("X-Cache"))
+	}
+
+	// the client has the current version
+	rsp = do("GET", "/foo/bar", "", map[string]string{"If-None-Match": etag})
+	if rsp.Code != http.StatusNotModified || rsp.Body.Len() > 0 {
+		t.Errorf("Expected not modified, got %v %v", rsp.Code, rsp.Body.String())
+	}
+
+	// responses are cached for each account
+	rsp = do("GET", "/foo/bar", "", map[string]string{"account": "john"})
+	if rsp.Header().Get("X-Cache") != "MISS" {
+		t.Errorf("Expected the response not to be cached for another account")
+	}
+
+	// the client can bypass the cache
+	rsp = do("GET", "/foo/bar", "", map[string]string{"Cache-Control": "no-cache"})
+	if rsp.Header().Get("X-Cache") != "MISS" {
+		t.Errorf("Expected the cache to be bypassed")
+	}
+
+	// responses the service doesn't want stored aren't cached
+	do("GET", "/nostore", "", nil)
+	if rsp := do("GET", "/nostore", "", nil); rsp.Header().Get("X-Cache") != "MISS" {
+		t.Errorf("Expected a no-store response not to be cached")
+	}
+
+	// routes without config use the max-age of the response
+	do("GET", "/maxage", "", nil)
+	if rsp := do("GET", "/maxage", "", nil); rsp.Header().Get("X-Cache") != "HIT" {
+		t.Errorf("Expected the response to be cached using max-age")
+	}
+	do("GET", "/other", "", nil)
+	if rsp := do("GET", "/other", "", nil); rsp.Header().Get("X-Cache") != "MISS" {
+		t.Errorf("Expected a response without a route or max-age not to be cached")
+	}
+
+	// other methods are only cached if the route allows it, keyed by the body
+	do("POST", "/rpc/call", `{"name": "john"}`, nil)
+	if rsp := do("POST", "/rpc/call", `{"name": "john"}`, nil); rsp.Header().Get("X-Cache") != "HIT" {
+		t.Errorf("Expected the POST response to be cached")
+	}
+	if rsp := do("POST", "/rpc/call", `{"name": "jane"}`, nil); rsp.Header().Get("X-Cache") != "MISS" {
+		t.Errorf("Expected a request with another body not to be cached")
+	}
+	if rsp := do("POST", "/foo/bar", "", nil); len(rsp.Header().Get("X-Cache")) > 0 {
+		t.Errorf("Expected the POST request to skip the cache")
+	}
+
+	// purging the route removes the responses for every account
+	if n, _ := backend.Purge("micro:/foo"); n != 2 {
+		t.Errorf("Expected 2 responses to be purged, got %v", n)
+	}
+	if rsp := do("GET", "/foo/bar", "", nil); rsp.Header().Get("X-Cache") != "MISS" {
+		t.Errorf("Expected the response to be purged")
+	}
+}
+
+func TestParseCacheControl(t *testing.T) {
+	cc := parseCacheControl(`public, max-age=60, s-maxage="120"`)
+	if cc.maxAge != 60 || cc.sMaxAge != 120 || cc.noStore || cc.private {
+		t.Errorf("Unexpected cache control: %+v", cc)
+	}
+	cc = parseCacheControl("Private, No-Store")
+	if !cc.private || !cc.noStore {
+		t.Errorf("Unexpected cache control: %+v", cc)
+	}
+}
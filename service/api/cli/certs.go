@@ -0,0 +1,41 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/micro/cli/v2"
+	"github.com/micro/micro/v3/internal/acme"
+)
+
+func listCerts(ctx *cli.Context) error {
+	storage, err := acme.Storage(ctx.String("storage"), ctx.String("path"))
+	if err != nil {
+		return err
+	}
+
+	certs, err := acme.Certificates(storage)
+	if err != nil {
+		return fmt.Errorf("Error listing certificates: %v", err)
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 8, 1, '\t', 0)
+	defer w.Flush()
+
+	now := time.Now()
+	fmt.Fprintln(w, strings.Join([]string{"Domain", "Names", "Issuer", "Expires", "Status"}, "\t\t"))
+	for _, c := range certs {
+		fmt.Fprintln(w, strings.Join([]string{
+			c.Domain,
+			strings.Join(c.Names, ", "),
+			c.Issuer,
+			c.NotAfter.Format(time.RFC3339),
+			c.Status(now),
+		}, "\t\t"))
+	}
+
+	return nil
+}
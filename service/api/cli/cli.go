@@ -0,0 +1,183 @@
+// Package cli implements the `micro api` subcommands
+// for example:
+//
+//	micro api spec --service helloworld
+//	micro api cache purge --route /helloworld
+//	micro api routes create --path /v1/users/{id} --method GET --service users --endpoint Users.Read
+//	micro api certs
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+
+	"github.com/micro/cli/v2"
+	"github.com/micro/micro/v3/client/cli/namespace"
+	"github.com/micro/micro/v3/client/cli/util"
+	"github.com/micro/micro/v3/cmd"
+	"github.com/micro/micro/v3/internal/helper"
+	"github.com/micro/micro/v3/internal/openapi"
+	"github.com/micro/micro/v3/service/api/cache"
+	"github.com/micro/micro/v3/service/registry"
+)
+
+func init() {
+	cmd.Register(&cli.Command{
+		Name:   "api",
+		Usage:  "Commands for the api exposed by the gateway",
+		Action: helper.UnexpectedSubcommand,
+		Subcommands: []*cli.Command{
+			{
+				Name:   "spec",
+				Usage:  "Write the OpenAPI spec of services to a file, e.g. micro api spec --service helloworld",
+				Action: writeSpec,
+				Flags: []cli.Flag{
+					&cli.StringSliceFlag{
+						Name:  "service",
+						Usage: "Set the services to include in the spec, all the services are included by default",
+					},
+					&cli.StringFlag{
+						Name:    "output",
+						Aliases: []string{"o"},
+						Usage:   "Set the file the spec is written to, use - to write it to stdout",
+						Value:   "openapi.json",
+					},
+				},
+			},
+			{
+				Name:   "certs",
+				Usage:  "List the certificates issued by ACME and when they expire, e.g. micro api certs",
+				Action: listCerts,
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:    "storage",
+						Usage:   "Set the storage of the certificates, store or file",
+						EnvVars: []string{"MICRO_ACME_STORAGE"},
+						Value:   "store",
+					},
+					&cli.StringFlag{
+						Name:    "path",
+						Usage:   "Set the directory of the certificates when using the file storage",
+						EnvVars: []string{"MICRO_ACME_STORAGE_PATH"},
+					},
+				},
+			},
+			{
+				Name:   "routes",
+				Usage:  "Manage the rules which route requests to service endpoints",
+				Action: helper.UnexpectedSubcommand,
+				Subcommands: []*cli.Command{
+					{
+						Name:   "list",
+						Usage:  "List the route rules, e.g. micro api routes list",
+						Action: listRoutes,
+					},
+					{
+						Name:   "create",
+						Usage:  "Create a route rule, e.g. micro api routes create --path /v1/users/{id} --method GET --service users --endpoint Users.Read",
+						Action: createRoute,
+						Flags: []cli.Flag{
+							&cli.StringFlag{
+								Name:     "path",
+								Usage:    "Set the path template, segments in braces are set in the request body, e.g. /v1/users/{id}",
+								Required: true,
+							},
+							&cli.StringFlag{
+								Name:  "method",
+								Usage: "Set the method of the requests, requests with any method match by default",
+							},
+							&cli.StringFlag{
+								Name:     "service",
+								Usage:    "Set the service the requests are sent to",
+								Required: true,
+							},
+							&cli.StringFlag{
+								Name:     "endpoint",
+								Usage:    "Set the endpoint the requests are sent to, e.g. Users.Read",
+								Required: true,
+							},
+							&cli.StringSliceFlag{
+								Name:  "header",
+								Usage: "Set a header in the metadata of the requests, e.g. --header key=value",
+							},
+							&cli.StringSliceFlag{
+								Name:  "field",
+								Usage: "Set a field of the response returned to the client, e.g. --field user.name. All fields are returned by default",
+							},
+						},
+					},
+					{
+						Name:   "delete",
+						Usage:  "Delete a route rule, e.g. micro api routes delete [id]",
+						Action: deleteRoute,
+					},
+				},
+			},
+			{
+				Name:   "cache",
+				Usage:  "Manage the response cache of the api",
+				Action: helper.UnexpectedSubcommand,
+				Subcommands: []*cli.Command{
+					{
+						Name:   "purge",
+						Usage:  "Purge the cached responses of a route, e.g. micro api cache purge --route /helloworld",
+						Action: purgeCache,
+						Flags: []cli.Flag{
+							&cli.StringFlag{
+								Name:     "route",
+								Usage:    "Set the path prefix of the responses to purge, use / to purge every route",
+								Required: true,
+							},
+						},
+					},
+				},
+			},
+		},
+	})
+}
+
+func writeSpec(ctx *cli.Context) error {
+	ns, err := namespace.Get(util.GetEnv(ctx).Name)
+	if err != nil {
+		return err
+	}
+
+	srvs, err := openapi.Services(registry.DefaultRegistry, ns, ctx.StringSlice("service")...)
+	if err != nil {
+		return fmt.Errorf("Error reading services: %v", err)
+	}
+	if len(srvs) == 0 {
+		return fmt.Errorf("No services found")
+	}
+
+	b, err := json.MarshalIndent(openapi.Generate(srvs), "", "  ")
+	if err != nil {
+		return err
+	}
+
+	out := ctx.String("output")
+	if out == "-" {
+		fmt.Println(string(b))
+		return nil
+	}
+	if err := ioutil.WriteFile(out, append(b, '\n'), 0644); err != nil {
+		return fmt.Errorf("Error writing spec: %v", err)
+	}
+	fmt.Printf("Spec written to %v\n", out)
+	return nil
+}
+
+func purgeCache(ctx *cli.Context) error {
+	ns, err := namespace.Get(util.GetEnv(ctx).Name)
+	if err != nil {
+		return err
+	}
+
+	route := ctx.String("route")
+	if err := cache.Purge(ns, route); err != nil {
+		return fmt.Errorf("Error purging cache: %v", err)
+	}
+	fmt.Printf("Purge of %v requested\n", route)
+	return nil
+}
@@ -0,0 +1,203 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/micro/cli/v2"
+	goclient "github.com/micro/go-micro/v3/client"
+	"github.com/micro/micro/v3/client/cli/namespace"
+	"github.com/micro/micro/v3/client/cli/util"
+	"github.com/micro/micro/v3/service/api/routes"
+	"github.com/micro/micro/v3/service/client"
+	pb "github.com/micro/micro/v3/service/config/proto"
+	"github.com/micro/micro/v3/service/context"
+	"github.com/micro/micro/v3/service/errors"
+)
+
+// routesPath is the path of the route rules in config
+var routesPath = strings.Join(routes.ConfigPath, ".")
+
+// readRoutes reads the route rules from the config of the namespace
+func readRoutes(ns string) ([]*routes.Rule, error) {
+	cli := pb.NewConfigService("config", client.DefaultClient)
+	rsp, err := cli.Read(context.DefaultContext, &pb.ReadRequest{
+		Namespace: ns,
+		Path:      routesPath,
+	}, goclient.WithAuthToken())
+	if verr := errors.Parse(err); verr != nil && verr.Code == 404 {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	if rsp.Change == nil || rsp.Change.ChangeSet == nil {
+		return nil, nil
+	}
+	data := rsp.Change.ChangeSet.Data
+	if len(data) == 0 || data == "null" {
+		return nil, nil
+	}
+
+	var rules []*routes.Rule
+	if err := json.Unmarshal([]byte(data), &rules); err != nil {
+		return nil, fmt.Errorf("invalid routes in config: %v", err)
+	}
+	return rules, nil
+}
+
+// writeRoutes writes the route rules to the config of the namespace
+func writeRoutes(ns string, rules []*routes.Rule) error {
+	if rules == nil {
+		rules = []*routes.Rule{}
+	}
+	b, err := json.Marshal(rules)
+	if err != nil {
+		return err
+	}
+
+	cli := pb.NewConfigService("config", client.DefaultClient)
+	_, err = cli.Update(context.DefaultContext, &pb.UpdateRequest{
+		Change: &pb.Change{
+			Namespace: ns,
+			Path:      routesPath,
+			ChangeSet: &pb.ChangeSet{
+				Data:      string(b),
+				Format:    "json",
+				Source:    "cli",
+				Timestamp: time.Now().Unix(),
+			},
+		},
+	}, goclient.WithAuthToken())
+	return err
+}
+
+func listRoutes(ctx *cli.Context) error {
+	ns, err := namespace.Get(util.GetEnv(ctx).Name)
+	if err != nil {
+		return fmt.Errorf("Error getting namespace: %v", err)
+	}
+
+	rules, err := readRoutes(ns)
+	if err != nil {
+		return fmt.Errorf("Error listing routes: %v", err)
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 8, 1, '\t', 0)
+	defer w.Flush()
+
+	fmt.Fprintln(w, strings.Join([]string{"ID", "Method", "Path", "Service", "Endpoint", "Headers", "Fields"}, "\t\t"))
+	for _, r := range rules {
+		method := r.Method
+		if len(method) == 0 {
+			method = "*"
+		}
+		headers := make([]string, 0, len(r.Headers))
+		for k, v := range r.Headers {
+			headers = append(headers, k+"="+v)
+		}
+		fields := strings.Join(r.Fields, ", ")
+		if len(fields) == 0 {
+			fields = "n/a"
+		}
+		hdrs := strings.Join(headers, ", ")
+		if len(hdrs) == 0 {
+			hdrs = "n/a"
+		}
+
+		fmt.Fprintln(w, strings.Join([]string{
+			r.ID,
+			method,
+			r.Path,
+			r.Service,
+			r.Endpoint,
+			hdrs,
+			fields,
+		}, "\t\t"))
+	}
+
+	return nil
+}
+
+func createRoute(ctx *cli.Context) error {
+	ns, err := namespace.Get(util.GetEnv(ctx).Name)
+	if err != nil {
+		return fmt.Errorf("Error getting namespace: %v", err)
+	}
+
+	rule := &routes.Rule{
+		ID:       uuid.New().String()[:8],
+		Method:   strings.ToUpper(ctx.String("method")),
+		Path:     ctx.String("path"),
+		Service:  ctx.String("service"),
+		Endpoint: ctx.String("endpoint"),
+		Fields:   ctx.StringSlice("field"),
+	}
+	for _, h := range ctx.StringSlice("header") {
+		comps := strings.SplitN(h, "=", 2)
+		if len(comps) != 2 || len(comps[0]) == 0 {
+			return fmt.Errorf("Invalid header %v, expected key=value", h)
+		}
+		if rule.Headers == nil {
+			rule.Headers = make(map[string]string)
+		}
+		rule.Headers[comps[0]] = comps[1]
+	}
+	if err := rule.Validate(); err != nil {
+		return err
+	}
+
+	rules, err := readRoutes(ns)
+	if err != nil {
+		return fmt.Errorf("Error reading routes: %v", err)
+	}
+	for _, r := range rules {
+		if r.Path == rule.Path && r.Method == rule.Method {
+			return fmt.Errorf("Route %v already exists for %v %v", r.ID, rule.Method, rule.Path)
+		}
+	}
+
+	if err := writeRoutes(ns, append(rules, rule)); err != nil {
+		return fmt.Errorf("Error creating route: %v", err)
+	}
+	fmt.Printf("Route %v created\n", rule.ID)
+	return nil
+}
+
+func deleteRoute(ctx *cli.Context) error {
+	if ctx.Args().Len() == 0 {
+		return fmt.Errorf("Missing argument: id")
+	}
+	id := ctx.Args().First()
+
+	ns, err := namespace.Get(util.GetEnv(ctx).Name)
+	if err != nil {
+		return fmt.Errorf("Error getting namespace: %v", err)
+	}
+
+	rules, err := readRoutes(ns)
+	if err != nil {
+		return fmt.Errorf("Error reading routes: %v", err)
+	}
+
+	result := make([]*routes.Rule, 0, len(rules))
+	for _, r := range rules {
+		if r.ID != id {
+			result = append(result, r)
+		}
+	}
+	if len(result) == len(rules) {
+		return fmt.Errorf("Route %v not found", id)
+	}
+
+	if err := writeRoutes(ns, result); err != nil {
+		return fmt.Errorf("Error deleting route: %v", err)
+	}
+	fmt.Println("Route deleted")
+	return nil
+}
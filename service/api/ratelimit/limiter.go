@@ -0,0 +1,184 @@
+package ratelimit
+
+import (
+	"encoding/json"
+	"math"
+	"sync"
+	"time"
+
+	gostore "github.com/micro/go-micro/v3/store"
+	"github.com/micro/micro/v3/service/store"
+)
+
+const (
+	// storePrefix is prefixed to the keys of the buckets in the store
+	storePrefix = "ratelimit:"
+	// pruneInterval is how often full buckets are removed from memory
+	pruneInterval = time.Minute
+)
+
+// Limiter stores token buckets
+type Limiter interface {
+	// Take a token from the bucket with the key, which is refilled at the rate per second up to
+	// the burst. If the bucket is empty the duration until a token is available is returned.
+	Take(key string, rate, burst float64) (time.Duration, error)
+}
+
+// bucket of tokens
+type bucket struct {
+	Tokens  float64   `json:"tokens"`
+	Updated time.Time `json:"updated"`
+}
+
+// take refills the bucket and takes a token from it, returning the duration until a token is
+// available if it's empty
+func (b *bucket) take(now time.Time, rate, burst float64) time.Duration {
+	if b.Updated.IsZero() {
+		b.Tokens = burst
+	} else if elapsed := now.Sub(b.Updated).Seconds(); elapsed > 0 {
+		b.Tokens = math.Min(burst, b.Tokens+elapsed*rate)
+	}
+	b.Updated = now
+
+	if b.Tokens >= 1 {
+		b.Tokens--
+		return 0
+	}
+	return time.Duration((1 - b.Tokens) / rate * float64(time.Second))
+}
+
+// full returns true if the bucket would be refilled to the burst by now
+func (b *bucket) full(now time.Time, rate, burst float64) bool {
+	return b.Tokens+now.Sub(b.Updated).Seconds()*rate >= burst
+}
+
+// NewMemoryLimiter returns a limiter which stores the buckets in memory, limiting the requests to
+// each replica of the api separately
+func NewMemoryLimiter() Limiter {
+	return &memoryLimiter{
+		buckets: make(map[string]*memoryBucket),
+	}
+}
+
+type memoryBucket struct {
+	bucket
+	rate  float64
+	burst float64
+}
+
+type memoryLimiter struct {
+	sync.Mutex
+	buckets map[string]*memoryBucket
+	pruned  time.Time
+}
+
+func (m *memoryLimiter) Take(key string, rate, burst float64) (time.Duration, error) {
+	m.Lock()
+	defer m.Unlock()
+
+	now := time.Now()
+	m.prune(now)
+
+	b, ok := m.buckets[key]
+	if !ok {
+		b = &memoryBucket{rate: rate, burst: burst}
+		m.buckets[key] = b
+	}
+	b.rate, b.burst = rate, burst
+
+	return b.take(now, rate, burst), nil
+}
+
+// prune removes the buckets which are full, since they're the same as new buckets
+func (m *memoryLimiter) prune(now time.Time) {
+	if now.Sub(m.pruned) < pruneInterval {
+		return
+	}
+	m.pruned = now
+
+	for k, b := range m.buckets {
+		if b.full(now, b.rate, b.burst) {
+			delete(m.buckets, k)
+		}
+	}
+}
+
+// NewStoreLimiter returns a limiter which stores the buckets in the store, so the limits are
+// shared by the replicas of the api. The store has no transactions so concurrent requests to
+// different replicas may occasionally exceed the limit.
+func NewStoreLimiter() Limiter {
+	return &storeLimiter{
+		locks: make(map[string]*keyLock),
+	}
+}
+
+// keyLock serializes the requests of the replica to a bucket, refs is the number of requests
+// holding or waiting for the lock so it can be removed once unused
+type keyLock struct {
+	sync.Mutex
+	refs int
+}
+
+type storeLimiter struct {
+	// locks of the buckets being taken from
+	locks map[string]*keyLock
+	// mu is locked while the locks are read or modified
+	mu sync.Mutex
+}
+
+// lock the bucket with the key, returning a func which unlocks it
+func (s *storeLimiter) lock(key string) func() {
+	s.mu.Lock()
+	l, ok := s.locks[key]
+	if !ok {
+		l = &keyLock{}
+		s.locks[key] = l
+	}
+	l.refs++
+	s.mu.Unlock()
+
+	l.Lock()
+	return func() {
+		l.Unlock()
+
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		if l.refs--; l.refs == 0 {
+			delete(s.locks, key)
+		}
+	}
+}
+
+func (s *storeLimiter) Take(key string, rate, burst float64) (time.Duration, error) {
+	// the replica still needs to serialize its own requests to the bucket
+	unlock := s.lock(key)
+	defer unlock()
+
+	var b bucket
+	recs, err := store.Read(storePrefix + key)
+	if err == nil && len(recs) > 0 {
+		if err := json.Unmarshal(recs[0].Value, &b); err != nil {
+			return 0, err
+		}
+	} else if err != nil && err != gostore.ErrNotFound {
+		return 0, err
+	}
+
+	retry := b.take(time.Now(), rate, burst)
+
+	bytes, err := json.Marshal(&b)
+	if err != nil {
+		return 0, err
+	}
+	// the bucket expires once it'd be full, since it's then the same as a new bucket
+	expiry := time.Duration((burst - b.Tokens) / rate * float64(time.Second))
+	if expiry < time.Second {
+		expiry = time.Second
+	}
+	rec := &gostore.Record{Key: storePrefix + key, Value: bytes, Expiry: expiry}
+	if err := store.Write(rec); err != nil {
+		return 0, err
+	}
+
+	return retry, nil
+}
@@ -0,0 +1,240 @@
+// Package ratelimit limits the rate of requests to the api using token buckets. The limits are
+// read from the config service so they can be changed without restarting the api, e.g.
+//
+//	micro config set api.ratelimits '[{"scope": "ip", "rate": 10, "burst": 20}]'
+package ratelimit
+
+import (
+	"fmt"
+	"math"
+	"net"
+	"net/http"
+	"path"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/micro/go-micro/v3/api/server"
+	goauth "github.com/micro/go-micro/v3/auth"
+	"github.com/micro/go-micro/v3/config/reader"
+	"github.com/micro/micro/v3/internal/namespace"
+	"github.com/micro/micro/v3/internal/watch"
+	"github.com/micro/micro/v3/service/logger"
+)
+
+const (
+	// ScopeRoute limits the requests to a route, identified by the path of the request
+	ScopeRoute = "route"
+	// ScopeNamespace limits the requests to a namespace
+	ScopeNamespace = "namespace"
+	// ScopeAccount limits the requests made by an account
+	ScopeAccount = "account"
+	// ScopeIP limits the requests made from an ip
+	ScopeIP = "ip"
+)
+
+var (
+	// ConfigPath is the path of the limits in the config service
+	ConfigPath = []string{"api", "ratelimits"}
+	// RefreshInterval is how often the limits are reloaded from config
+	RefreshInterval = time.Second * 10
+)
+
+// Limit is the rate requests in a scope are allowed at
+type Limit struct {
+	// Scope of the limit: route, namespace, account or ip
+	Scope string `json:"scope"`
+	// Match limits the requests to a route with the path prefix, or to the namespace, account id
+	// or ip. If blank each route, namespace, account or ip is limited separately.
+	Match string `json:"match,omitempty"`
+	// Rate is the number of requests allowed per second
+	Rate float64 `json:"rate"`
+	// Burst is the number of requests allowed at once, it defaults to the rate
+	Burst int `json:"burst,omitempty"`
+}
+
+// burst returns the size of the bucket of the limit
+func (l *Limit) burst() float64 {
+	if l.Burst > 0 {
+		return float64(l.Burst)
+	}
+	return math.Max(1, math.Ceil(l.Rate))
+}
+
+// key returns the key of the bucket the request takes a token from, or false if the limit
+// doesn't apply to the request
+func (l *Limit) key(req *request) (string, bool) {
+	var val string
+	switch l.Scope {
+	case ScopeRoute:
+		if len(l.Match) > 0 {
+			if !strings.HasPrefix(req.route, l.Match) {
+				return "", false
+			}
+			// all the routes with the prefix share a bucket
+			val = l.Match
+		} else {
+			val = req.route
+		}
+	case ScopeNamespace:
+		val = req.namespace
+	case ScopeAccount:
+		val = req.account
+	case ScopeIP:
+		val = req.ip
+	default:
+		return "", false
+	}
+
+	// limits don't apply to requests without the attribute, e.g. requests without an account
+	if len(val) == 0 || (len(l.Match) > 0 && l.Scope != ScopeRoute && val != l.Match) {
+		return "", false
+	}
+
+	return fmt.Sprintf("%v:%v:%v:%v", l.Scope, l.Match, val, strconv.FormatFloat(l.Rate, 'f', -1, 64)), true
+}
+
+// request is the attributes of a request the limits apply to
+type request struct {
+	route     string
+	namespace string
+	account   string
+	ip        string
+}
+
+type Options struct {
+	// Limiter the buckets are stored in, defaults to memory
+	Limiter Limiter
+	// TrustProxy uses the X-Forwarded-For header to determine the ip of the client, it should only
+	// be enabled if the api is behind a proxy which sets it
+	TrustProxy bool
+}
+
+type Option func(o *Options)
+
+// WithLimiter sets the limiter the buckets are stored in
+func WithLimiter(l Limiter) Option {
+	return func(o *Options) {
+		o.Limiter = l
+	}
+}
+
+// TrustProxy uses the X-Forwarded-For header to determine the ip of the client
+func TrustProxy(b bool) Option {
+	return func(o *Options) {
+		o.TrustProxy = b
+	}
+}
+
+// Wrapper limits the rate of requests. It expects the namespace to be set in the request header
+// and the account in the context, so it should be wrapped by the auth wrapper.
+func Wrapper(opts ...Option) server.Wrapper {
+	options := Options{}
+	for _, o := range opts {
+		o(&options)
+	}
+	if options.Limiter == nil {
+		options.Limiter = NewMemoryLimiter()
+	}
+
+	l := &limits{}
+	watch.Config(ConfigPath, RefreshInterval, l.load)
+
+	return func(h http.Handler) http.Handler {
+		return rateLimitWrapper{
+			handler: h,
+			limits:  l,
+			opts:    options,
+		}
+	}
+}
+
+type rateLimitWrapper struct {
+	handler http.Handler
+	limits  *limits
+	opts    Options
+}
+
+func (r rateLimitWrapper) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	limits := r.limits.get()
+	if len(limits) == 0 {
+		r.handler.ServeHTTP(w, req)
+		return
+	}
+
+	attrs := &request{
+		route:     path.Clean("/" + req.URL.Path),
+		namespace: req.Header.Get(namespace.NamespaceKey),
+		ip:        clientIP(req, r.opts.TrustProxy),
+	}
+	if acc, ok := goauth.AccountFromContext(req.Context()); ok {
+		attrs.account = acc.ID
+	}
+
+	for _, l := range limits {
+		key, ok := l.key(attrs)
+		if !ok {
+			continue
+		}
+
+		retry, err := r.opts.Limiter.Take(key, l.Rate, l.burst())
+		if err != nil {
+			// don't reject requests because the limiter is unavailable
+			logger.Warnf("Error taking token for rate limit %v: %v", key, err)
+			continue
+		}
+		if retry > 0 {
+			w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(retry.Seconds()))))
+			http.Error(w, "Too many requests", http.StatusTooManyRequests)
+			return
+		}
+	}
+
+	r.handler.ServeHTTP(w, req)
+}
+
+// clientIP returns the ip of the client which made the request
+func clientIP(req *http.Request, trustProxy bool) string {
+	if fwd := req.Header.Get("X-Forwarded-For"); trustProxy && len(fwd) > 0 {
+		return strings.TrimSpace(strings.Split(fwd, ",")[0])
+	}
+	host, _, err := net.SplitHostPort(req.RemoteAddr)
+	if err != nil {
+		return req.RemoteAddr
+	}
+	return host
+}
+
+// limits are loaded from config and refreshed periodically
+type limits struct {
+	sync.RWMutex
+	limits []*Limit
+}
+
+func (l *limits) get() []*Limit {
+	l.RLock()
+	defer l.RUnlock()
+	return l.limits
+}
+
+func (l *limits) load(val reader.Value) {
+	var lims []*Limit
+	if err := val.Scan(&lims); err != nil {
+		logger.Warnf("Error loading rate limits: %v", err)
+		return
+	}
+
+	valid := make([]*Limit, 0, len(lims))
+	for _, lim := range lims {
+		if lim == nil || lim.Rate <= 0 {
+			logger.Warnf("Ignoring rate limit with invalid rate: %+v", lim)
+			continue
+		}
+		valid = append(valid, lim)
+	}
+
+	l.Lock()
+	l.limits = valid
+	l.Unlock()
+}
@@ -0,0 +1,105 @@
+package ratelimit
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/micro/go-micro/v3/store/memory"
+	"github.com/micro/micro/v3/service/store"
+)
+
+func TestBucket(t *testing.T) {
+	now := time.Now()
+	b := &bucket{}
+
+	// the bucket starts full
+	for i := 0; i < 2; i++ {
+		if retry := b.take(now, 1, 2); retry != 0 {
+			t.Fatalf("Expected token %v to be taken, retry after %v", i, retry)
+		}
+	}
+	if retry := b.take(now, 1, 2); retry != time.Second {
+		t.Errorf("Expected to retry after 1s, got %v", retry)
+	}
+
+	// half a token is refilled after half a second
+	if retry := b.take(now.Add(time.Millisecond*500), 1, 2); retry != time.Millisecond*500 {
+		t.Errorf("Expected to retry after 500ms, got %v", retry)
+	}
+	if retry := b.take(now.Add(time.Second), 1, 2); retry != 0 {
+		t.Errorf("Expected token to be taken after refill, retry after %v", retry)
+	}
+
+	// the bucket isn't refilled beyond the burst
+	if retry := b.take(now.Add(time.Hour), 1, 2); retry != 0 || b.Tokens != 1 {
+		t.Errorf("Expected 1 token left, got %v", b.Tokens)
+	}
+}
+
+func TestStoreLimiter(t *testing.T) {
+	defaultStore := store.DefaultStore
+	store.DefaultStore = memory.NewStore()
+	defer func() { store.DefaultStore = defaultStore }()
+
+	l := NewStoreLimiter().(*storeLimiter)
+
+	// concurrent requests to a bucket don't take more than the burst
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	taken := map[string]int{}
+	for i := 0; i < 20; i++ {
+		for _, key := range []string{"foo", "bar"} {
+			wg.Add(1)
+			go func(key string) {
+				defer wg.Done()
+				retry, err := l.Take(key, 0.001, 5)
+				if err != nil {
+					t.Errorf("Unexpected error: %v", err)
+				}
+				if retry == 0 {
+					mu.Lock()
+					taken[key]++
+					mu.Unlock()
+				}
+			}(key)
+		}
+	}
+	wg.Wait()
+
+	if taken["foo"] != 5 || taken["bar"] != 5 {
+		t.Errorf("Expected 5 tokens to be taken from each bucket, got %v", taken)
+	}
+	if len(l.locks) != 0 {
+		t.Errorf("Expected the locks to be removed, got %v", len(l.locks))
+	}
+}
+
+func TestLimitKey(t *testing.T) {
+	req := &request{route: "/foo/bar", namespace: "micro", ip: "10.0.0.1"}
+
+	tt := []struct {
+		Name  string
+		Limit Limit
+		Key   string
+		Match bool
+	}{
+		{"RouteEach", Limit{Scope: ScopeRoute, Rate: 1}, "route::/foo/bar:1", true},
+		{"RoutePrefix", Limit{Scope: ScopeRoute, Match: "/foo", Rate: 1}, "route:/foo:/foo:1", true},
+		{"RouteOther", Limit{Scope: ScopeRoute, Match: "/baz", Rate: 1}, "", false},
+		{"Namespace", Limit{Scope: ScopeNamespace, Match: "micro", Rate: 2.5}, "namespace:micro:micro:2.5", true},
+		{"NamespaceOther", Limit{Scope: ScopeNamespace, Match: "foo", Rate: 1}, "", false},
+		{"NoAccount", Limit{Scope: ScopeAccount, Rate: 1}, "", false},
+		{"IP", Limit{Scope: ScopeIP, Rate: 1}, "ip::10.0.0.1:1", true},
+		{"InvalidScope", Limit{Scope: "foo", Rate: 1}, "", false},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.Name, func(t *testing.T) {
+			key, ok := tc.Limit.key(req)
+			if ok != tc.Match || key != tc.Key {
+				t.Errorf("Expected %v %v, got %v %v", tc.Key, tc.Match, key, ok)
+			}
+		})
+	}
+}
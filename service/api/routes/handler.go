@@ -0,0 +1,178 @@
+package routes
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/micro/go-micro/v3/api/server/cors"
+	goclient "github.com/micro/go-micro/v3/client"
+	goerrors "github.com/micro/go-micro/v3/errors"
+	"github.com/micro/go-micro/v3/metadata"
+	"github.com/micro/micro/v3/internal/helper"
+	"github.com/micro/micro/v3/internal/namespace"
+	"github.com/micro/micro/v3/service/client"
+	"github.com/micro/micro/v3/service/errors"
+)
+
+// maxBodySize is the max size of a request body
+const maxBodySize = 4 * 1024 * 1024
+
+// Matches returns true if a rule applies to the request
+func (r *Routes) Matches(req *http.Request) bool {
+	_, _, ok := r.Match(req)
+	return ok
+}
+
+// Handler sends requests to the endpoint of the rule they match
+func (r *Routes) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.Method == "OPTIONS" {
+			cors.SetHeaders(w, req)
+			return
+		}
+
+		rule, params, ok := r.Match(req)
+		if !ok {
+			writeError(w, errors.NotFound("micro.api", "no route for %v", req.URL.Path))
+			return
+		}
+		serve(w, req, rule, params)
+	})
+}
+
+func serve(w http.ResponseWriter, r *http.Request, rule *Rule, params map[string]string) {
+	defer r.Body.Close()
+
+	b, err := ioutil.ReadAll(http.MaxBytesReader(w, r.Body, maxBodySize))
+	if err != nil {
+		writeError(w, errors.BadRequest("micro.api", "error reading body: %v", err))
+		return
+	}
+	body, err := requestBody(b, r.URL.Query(), params)
+	if err != nil {
+		writeError(w, errors.BadRequest("micro.api", "%v", err))
+		return
+	}
+
+	// create the context with the metadata of the request and the headers of the rule
+	ctx := helper.RequestToContext(r)
+	for k, v := range rule.Headers {
+		ctx = metadata.Set(ctx, k, v)
+	}
+
+	var opts []goclient.CallOption
+	if ns := r.Header.Get(namespace.NamespaceKey); len(ns) > 0 {
+		opts = append(opts, goclient.WithNetwork(ns))
+	}
+
+	var rsp json.RawMessage
+	req := client.NewRequest(rule.Service, rule.Endpoint, &body, goclient.WithContentType("application/json"))
+	if err := client.Call(ctx, req, &rsp, opts...); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	if len(rule.Fields) > 0 {
+		if rsp, err = filterFields(rsp, rule.Fields); err != nil {
+			writeError(w, errors.InternalServerError("micro.api", "error filtering response: %v", err))
+			return
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Content-Length", strconv.Itoa(len(rsp)))
+	w.Write(rsp)
+}
+
+// requestBody returns the body of the request with the query and path params set in it. Path
+// params take priority over fields in the body, which take priority over query params.
+func requestBody(b []byte, query map[string][]string, params map[string]string) (json.RawMessage, error) {
+	body := make(map[string]interface{})
+	if len(strings.TrimSpace(string(b))) > 0 {
+		if err := json.Unmarshal(b, &body); err != nil {
+			return nil, fmt.Errorf("body must be a JSON object")
+		}
+	}
+
+	for k, v := range query {
+		if _, ok := getField(body, k); ok || len(v) == 0 {
+			continue
+		}
+		if len(v) == 1 {
+			setField(body, k, v[0])
+		} else {
+			setField(body, k, v)
+		}
+	}
+	for k, v := range params {
+		setField(body, k, v)
+	}
+
+	return json.Marshal(body)
+}
+
+// filterFields returns the response with only the fields
+func filterFields(rsp json.RawMessage, fields []string) (json.RawMessage, error) {
+	var src map[string]interface{}
+	if err := json.Unmarshal(rsp, &src); err != nil {
+		return nil, err
+	}
+
+	dst := make(map[string]interface{})
+	for _, f := range fields {
+		if v, ok := getField(src, f); ok {
+			setField(dst, f, v)
+		}
+	}
+	return json.Marshal(dst)
+}
+
+// getField returns the value of the field, nested fields are separated by dots, e.g. user.name
+func getField(m map[string]interface{}, field string) (interface{}, bool) {
+	parts := strings.Split(field, ".")
+	for i, p := range parts {
+		v, ok := m[p]
+		if !ok {
+			return nil, false
+		}
+		if i == len(parts)-1 {
+			return v, true
+		}
+		if m, ok = v.(map[string]interface{}); !ok {
+			return nil, false
+		}
+	}
+	return nil, false
+}
+
+// setField sets the value of the field, creating the objects of nested fields
+func setField(m map[string]interface{}, field string, val interface{}) {
+	parts := strings.Split(field, ".")
+	for _, p := range parts[:len(parts)-1] {
+		next, ok := m[p].(map[string]interface{})
+		if !ok {
+			next = make(map[string]interface{})
+			m[p] = next
+		}
+		m = next
+	}
+	m[parts[len(parts)-1]] = val
+}
+
+// writeError writes the error in the same format as the rpc handler
+func writeError(w http.ResponseWriter, err error) {
+	ce := goerrors.Parse(err.Error())
+	if ce.Code == 0 {
+		ce.Code = http.StatusInternalServerError
+		ce.Id = "micro.api"
+		ce.Status = http.StatusText(http.StatusInternalServerError)
+		ce.Detail = "error during request: " + ce.Detail
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(int(ce.Code))
+	w.Write([]byte(ce.Error()))
+}
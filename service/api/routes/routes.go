@@ -0,0 +1,202 @@
+// Package routes maps requests to the api to service endpoints using rules, so public urls don't
+// need to follow the names of services and endpoints. The rules are read from the config
+// service, e.g.
+//
+//	micro api routes create --path /v1/users/{id} --method GET --service users --endpoint Users.Read
+//
+// Path and query params are set in the request body, e.g. GET /v1/users/1?fields=name calls
+// Users.Read with {"id": "1", "fields": "name"}.
+package routes
+
+import (
+	"net/http"
+	"path"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/micro/go-micro/v3/api/resolver"
+	"github.com/micro/go-micro/v3/config/reader"
+	rrmicro "github.com/micro/micro/v3/internal/resolver/api"
+	"github.com/micro/micro/v3/internal/watch"
+	"github.com/micro/micro/v3/service/logger"
+)
+
+var (
+	// ConfigPath is the path of the rules in the config service
+	ConfigPath = []string{"api", "routes"}
+	// RefreshInterval is how often the rules are reloaded from config
+	RefreshInterval = time.Second * 10
+)
+
+// Rule maps requests to an endpoint of a service
+type Rule struct {
+	// ID of the rule
+	ID string `json:"id"`
+	// Method of the requests, e.g. GET. Requests with any method match if blank.
+	Method string `json:"method,omitempty"`
+	// Path template of the requests, e.g. /v1/users/{id}. Segments in braces are params which
+	// match a single segment, or the rest of the path if they end in ..., e.g. /files/{path...}
+	Path string `json:"path"`
+	// Service the requests are sent to
+	Service string `json:"service"`
+	// Endpoint of the service the requests are sent to, e.g. Users.Read
+	Endpoint string `json:"endpoint"`
+	// Headers set in the metadata of the requests sent to the service
+	Headers map[string]string `json:"headers,omitempty"`
+	// Fields of the response returned to the client, e.g. user.name. All the fields are
+	// returned if blank.
+	Fields []string `json:"fields,omitempty"`
+}
+
+// Validate the rule
+func (r *Rule) Validate() error {
+	if len(r.Path) == 0 || !strings.HasPrefix(r.Path, "/") {
+		return errInvalid("path must start with /")
+	}
+	if len(r.Service) == 0 {
+		return errInvalid("missing service")
+	}
+	if len(r.Endpoint) == 0 || !strings.Contains(r.Endpoint, ".") {
+		return errInvalid("endpoint must be of the form Service.Method")
+	}
+	for _, seg := range splitPath(r.Path) {
+		if isParam(seg) && len(paramName(seg)) == 0 {
+			return errInvalid("param without a name")
+		}
+	}
+	return nil
+}
+
+type errInvalid string
+
+func (e errInvalid) Error() string {
+	return "invalid rule: " + string(e)
+}
+
+// match returns the params of the request if the rule applies to it
+func (r *Rule) match(method string, segs []string) (map[string]string, bool) {
+	if len(r.Method) > 0 && !strings.EqualFold(r.Method, method) {
+		return nil, false
+	}
+
+	tmpl := splitPath(r.Path)
+	params := make(map[string]string)
+	for i, t := range tmpl {
+		// the rest of the path is matched by the param
+		if isParam(t) && strings.HasSuffix(t, "...}") {
+			if i >= len(segs) {
+				return nil, false
+			}
+			params[paramName(t)] = strings.Join(segs[i:], "/")
+			return params, true
+		}
+		if i >= len(segs) {
+			return nil, false
+		}
+		if isParam(t) {
+			params[paramName(t)] = segs[i]
+		} else if t != segs[i] {
+			return nil, false
+		}
+	}
+	if len(tmpl) != len(segs) {
+		return nil, false
+	}
+	return params, true
+}
+
+func splitPath(p string) []string {
+	p = strings.Trim(path.Clean("/"+p), "/")
+	if len(p) == 0 {
+		return nil
+	}
+	return strings.Split(p, "/")
+}
+
+func isParam(seg string) bool {
+	return strings.HasPrefix(seg, "{") && strings.HasSuffix(seg, "}")
+}
+
+func paramName(seg string) string {
+	return strings.TrimSuffix(strings.Trim(seg, "{}"), "...")
+}
+
+// Routes are the rules loaded from config, they're refreshed periodically
+type Routes struct {
+	sync.RWMutex
+	rules []*Rule
+}
+
+// New returns the routes loaded from config
+func New() *Routes {
+	r := &Routes{}
+	watch.Config(ConfigPath, RefreshInterval, r.load)
+	return r
+}
+
+// Match returns the rule which applies to the request and the params of the path
+func (r *Routes) Match(req *http.Request) (*Rule, map[string]string, bool) {
+	r.RLock()
+	rules := r.rules
+	r.RUnlock()
+
+	segs := splitPath(req.URL.Path)
+	for _, rule := range rules {
+		if params, ok := rule.match(req.Method, segs); ok {
+			return rule, params, true
+		}
+	}
+	return nil, nil, false
+}
+
+// Resolver returns a resolver which resolves requests matching a rule to the service and
+// endpoint of the rule, so they're authorized using them. Other requests are resolved by the
+// resolver.
+func (r *Routes) Resolver(res resolver.Resolver) resolver.Resolver {
+	return &routesResolver{Resolver: res, routes: r}
+}
+
+type routesResolver struct {
+	resolver.Resolver
+	routes *Routes
+}
+
+func (r *routesResolver) Resolve(req *http.Request, opts ...resolver.ResolveOption) (*resolver.Endpoint, error) {
+	rule, _, ok := r.routes.Match(req)
+	if !ok {
+		return r.Resolver.Resolve(req, opts...)
+	}
+
+	domain := rrmicro.ResolveDomain(r.Resolver, req, opts...)
+	return &resolver.Endpoint{Name: rule.Service, Method: rule.Endpoint, Domain: domain}, nil
+}
+
+// Domain returns the domain of the request if the resolver determines it from the request
+func (r *routesResolver) Domain(req *http.Request) string {
+	return rrmicro.Domain(r.Resolver, req)
+}
+
+func (r *Routes) load(val reader.Value) {
+	var rules []*Rule
+	if err := val.Scan(&rules); err != nil {
+		logger.Warnf("Error loading api routes: %v", err)
+		return
+	}
+
+	valid := make([]*Rule, 0, len(rules))
+	for _, rule := range rules {
+		if rule == nil {
+			continue
+		}
+		if err := rule.Validate(); err != nil {
+			logger.Warnf("Ignoring api route %v: %v", rule.ID, err)
+			continue
+		}
+		valid = append(valid, rule)
+	}
+
+	r.Lock()
+	r.rules = valid
+	r.Unlock()
+}
@@ -0,0 +1,113 @@
+package routes
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+func TestMatch(t *testing.T) {
+	r := &Routes{rules: []*Rule{
+		{ID: "1", Method: "GET", Path: "/v1/users/{id}", Service: "users", Endpoint: "Users.Read"},
+		{ID: "2", Path: "/v1/files/{path...}", Service: "files", Endpoint: "Files.Read"},
+	}}
+
+	tt := []struct {
+		Method string
+		Path   string
+		ID     string
+		Params map[string]string
+	}{
+		{Method: "GET", Path: "/v1/users/1", ID: "1", Params: map[string]string{"id": "1"}},
+		{Method: "POST", Path: "/v1/users/1"},
+		{Method: "GET", Path: "/v1/users/1/foo"},
+		{Method: "GET", Path: "/v1/users"},
+		{Method: "PUT", Path: "/v1/files/foo/bar.txt", ID: "2", Params: map[string]string{"path": "foo/bar.txt"}},
+		{Method: "GET", Path: "/v1/files"},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.Method+" "+tc.Path, func(t *testing.T) {
+			rule, params, ok := r.Match(httptest.NewRequest(tc.Method, tc.Path, nil))
+			if len(tc.ID) == 0 {
+				if ok {
+					t.Fatalf("Expected no match, got rule %v", rule.ID)
+				}
+				return
+			}
+			if !ok || rule.ID != tc.ID {
+				t.Fatalf("Expected rule %v to match", tc.ID)
+			}
+			for k, v := range tc.Params {
+				if params[k] != v {
+					t.Errorf("Expected param %v to be %v, got %v", k, v, params[k])
+				}
+			}
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	valid := &Rule{Path: "/v1/users/{id}", Service: "users", Endpoint: "Users.Read"}
+	if err := valid.Validate(); err != nil {
+		t.Errorf("Expected the rule to be valid, got %v", err)
+	}
+
+	invalid := []*Rule{
+		{Path: "v1/users", Service: "users", Endpoint: "Users.Read"},
+		{Path: "/v1/users", Endpoint: "Users.Read"},
+		{Path: "/v1/users", Service: "users", Endpoint: "Read"},
+		{Path: "/v1/users/{}", Service: "users", Endpoint: "Users.Read"},
+	}
+	for _, r := range invalid {
+		if err := r.Validate(); err == nil {
+			t.Errorf("Expected rule %+v to be invalid", r)
+		}
+	}
+}
+
+func TestRequestBody(t *testing.T) {
+	query := url.Values{"name": {"query"}, "page": {"2"}, "tags": {"a", "b"}}
+	params := map[string]string{"id": "1", "user.id": "2"}
+
+	b, err := requestBody([]byte(`{"name": "body", "id": "0"}`), query, params)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	var body map[string]interface{}
+	if err := json.Unmarshal(b, &body); err != nil {
+		t.Fatal(err)
+	}
+	if body["name"] != "body" {
+		t.Errorf("Expected the body to take priority over the query, got %v", body["name"])
+	}
+	if body["id"] != "1" {
+		t.Errorf("Expected the path params to take priority over the body, got %v", body["id"])
+	}
+	if body["page"] != "2" {
+		t.Errorf("Expected the query param to be set, got %v", body["page"])
+	}
+	if tags, ok := body["tags"].([]interface{}); !ok || len(tags) != 2 {
+		t.Errorf("Expected repeated query params to be set as a list, got %v", body["tags"])
+	}
+	if user, ok := body["user"].(map[string]interface{}); !ok || user["id"] != "2" {
+		t.Errorf("Expected the nested field to be set, got %v", body["user"])
+	}
+
+	if _, err := requestBody([]byte(`[1, 2]`), nil, nil); err == nil {
+		t.Errorf("Expected an error for a body which isn't an object")
+	}
+}
+
+func TestFilterFields(t *testing.T) {
+	rsp := json.RawMessage(`{"user": {"name": "john", "email": "john@example.com"}, "total": 1}`)
+	b, err := filterFields(rsp, []string{"user.name", "missing"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if string(b) != `{"user":{"name":"john"}}` {
+		t.Errorf("Unexpected response: %v", string(b))
+	}
+}
@@ -42,3 +42,13 @@ func Revoke(rule *auth.Rule) error {
 func Rules(...auth.RulesOption) ([]*auth.Rule, error) {
 	return DefaultAuth.Rules()
 }
+
+// VerifyAPIKey returns the account the api key issued by the namespace authenticates as and a
+// short lived token for the account
+func VerifyAPIKey(key, namespace string) (*auth.Account, *auth.Token, error) {
+	v, ok := DefaultAuth.(client.APIKeyVerifier)
+	if !ok {
+		return nil, nil, client.ErrAPIKeysNotSupported
+	}
+	return v.VerifyAPIKey(key, namespace)
+}
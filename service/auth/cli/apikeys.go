@@ -0,0 +1,118 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/micro/cli/v2"
+	goclient "github.com/micro/go-micro/v3/client"
+	"github.com/micro/micro/v3/client/cli/namespace"
+	"github.com/micro/micro/v3/client/cli/util"
+	pb "github.com/micro/micro/v3/service/auth/proto"
+	"github.com/micro/micro/v3/service/client"
+	"github.com/micro/micro/v3/service/context"
+)
+
+func listAPIKeys(ctx *cli.Context) error {
+	cli := pb.NewAPIKeysService("auth", client.DefaultClient)
+
+	ns, err := namespace.Get(util.GetEnv(ctx).Name)
+	if err != nil {
+		return fmt.Errorf("Error getting namespace: %v", err)
+	}
+
+	rsp, err := cli.List(context.DefaultContext, &pb.ListAPIKeysRequest{
+		Options: &pb.Options{Namespace: ns},
+	}, goclient.WithAuthToken())
+	if err != nil {
+		return fmt.Errorf("Error listing api keys: %v", err)
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 8, 1, '\t', 0)
+	defer w.Flush()
+
+	formatTime := func(t int64) string {
+		if t == 0 {
+			return "never"
+		}
+		return time.Unix(t, 0).Format(time.RFC3339)
+	}
+
+	fmt.Fprintln(w, strings.Join([]string{"ID", "Account", "Description", "Scopes", "Created", "Expires", "Last Used", "Usage"}, "\t\t"))
+	for _, k := range rsp.ApiKeys {
+		scopes := strings.Join(k.Scopes, ", ")
+		if len(scopes) == 0 {
+			scopes = "n/a"
+		}
+		desc := k.Description
+		if len(desc) == 0 {
+			desc = "n/a"
+		}
+
+		fmt.Fprintln(w, strings.Join([]string{
+			k.Id,
+			k.AccountId,
+			desc,
+			scopes,
+			formatTime(k.Created),
+			formatTime(k.Expiry),
+			formatTime(k.LastUsed),
+			fmt.Sprintf("%d", k.Usage),
+		}, "\t\t"))
+	}
+
+	return nil
+}
+
+func createAPIKey(ctx *cli.Context) error {
+	cli := pb.NewAPIKeysService("auth", client.DefaultClient)
+
+	ns, err := namespace.Get(util.GetEnv(ctx).Name)
+	if err != nil {
+		return fmt.Errorf("Error getting namespace: %v", err)
+	}
+
+	if ctx.Duration("expiry") < 0 {
+		return fmt.Errorf("Invalid expiry: %v", ctx.Duration("expiry"))
+	}
+
+	rsp, err := cli.Create(context.DefaultContext, &pb.CreateAPIKeyRequest{
+		Description: ctx.String("description"),
+		Scopes:      ctx.StringSlice("scopes"),
+		Expiry:      int64(ctx.Duration("expiry").Seconds()),
+		Options:     &pb.Options{Namespace: ns},
+	}, goclient.WithAuthToken())
+	if err != nil {
+		return fmt.Errorf("Error creating api key: %v", err)
+	}
+
+	fmt.Printf("API key created: %v\n", rsp.ApiKey.Id)
+	fmt.Printf("Key: %v\n", rsp.Key)
+	fmt.Println("Store the key somewhere safe, it can't be shown again. Provide it using the X-Api-Key header.")
+	return nil
+}
+
+func deleteAPIKey(ctx *cli.Context) error {
+	if ctx.Args().Len() == 0 {
+		return fmt.Errorf("Missing argument: ID")
+	}
+	cli := pb.NewAPIKeysService("auth", client.DefaultClient)
+
+	ns, err := namespace.Get(util.GetEnv(ctx).Name)
+	if err != nil {
+		return fmt.Errorf("Error getting namespace: %v", err)
+	}
+
+	_, err = cli.Revoke(context.DefaultContext, &pb.RevokeAPIKeyRequest{
+		Id:      ctx.Args().First(),
+		Options: &pb.Options{Namespace: ns},
+	}, goclient.WithAuthToken())
+	if err != nil {
+		return fmt.Errorf("Error revoking api key: %v", err)
+	}
+
+	return nil
+}
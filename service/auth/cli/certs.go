@@ -0,0 +1,76 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/micro/cli/v2"
+	goclient "github.com/micro/go-micro/v3/client"
+	"github.com/micro/micro/v3/client/cli/namespace"
+	"github.com/micro/micro/v3/client/cli/util"
+	pb "github.com/micro/micro/v3/service/auth/proto"
+	"github.com/micro/micro/v3/service/client"
+	"github.com/micro/micro/v3/service/context"
+)
+
+func listCerts(ctx *cli.Context) error {
+	cli := pb.NewCertsService("auth", client.DefaultClient)
+
+	ns, err := namespace.Get(util.GetEnv(ctx).Name)
+	if err != nil {
+		return fmt.Errorf("Error getting namespace: %v", err)
+	}
+
+	rsp, err := cli.List(context.DefaultContext, &pb.ListCertsRequest{
+		Options: &pb.Options{Namespace: ns},
+	}, goclient.WithAuthToken())
+	if err != nil {
+		return fmt.Errorf("Error listing certificates: %v", err)
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 8, 1, '\t', 0)
+	defer w.Flush()
+
+	fmt.Fprintln(w, strings.Join([]string{"Serial", "Account", "Issued", "Expires", "Status"}, "\t\t"))
+	for _, c := range rsp.Certs {
+		status := "valid"
+		if c.Revoked {
+			status = "revoked"
+		}
+
+		fmt.Fprintln(w, strings.Join([]string{
+			c.Serial,
+			c.AccountId,
+			time.Unix(c.Created, 0).Format(time.RFC3339),
+			time.Unix(c.Expiry, 0).Format(time.RFC3339),
+			status,
+		}, "\t\t"))
+	}
+
+	return nil
+}
+
+func revokeCert(ctx *cli.Context) error {
+	if ctx.Args().Len() == 0 {
+		return fmt.Errorf("Missing argument: serial")
+	}
+	cli := pb.NewCertsService("auth", client.DefaultClient)
+
+	ns, err := namespace.Get(util.GetEnv(ctx).Name)
+	if err != nil {
+		return fmt.Errorf("Error getting namespace: %v", err)
+	}
+
+	_, err = cli.Revoke(context.DefaultContext, &pb.RevokeCertRequest{
+		Serial:  ctx.Args().First(),
+		Options: &pb.Options{Namespace: ns},
+	}, goclient.WithAuthToken())
+	if err != nil {
+		return fmt.Errorf("Error revoking certificate: %v", err)
+	}
+
+	return nil
+}
@@ -40,6 +40,21 @@ var (
 			Usage: "Comma seperated list of scopes to give the account",
 		},
 	}
+	// apiKeyFlags are provided to the create api key command
+	apiKeyFlags = []cli.Flag{
+		&cli.StringFlag{
+			Name:  "description",
+			Usage: "What the key is used for",
+		},
+		&cli.StringSliceFlag{
+			Name:  "scopes",
+			Usage: "Comma seperated list of scopes to give the key, defaults to the scopes of your account",
+		},
+		&cli.DurationFlag{
+			Name:  "expiry",
+			Usage: "How long the key is valid for, e.g. 720h. By default it doesn't expire",
+		},
+	}
 )
 
 func init() {
@@ -63,6 +78,11 @@ func init() {
 							Usage:  "List auth accounts",
 							Action: listAccounts,
 						},
+						{
+							Name:   "apikeys",
+							Usage:  "List api keys and their usage",
+							Action: listAPIKeys,
+						},
 					},
 				},
 				{
@@ -81,6 +101,12 @@ func init() {
 							Flags:  accountFlags,
 							Action: createAccount,
 						},
+						{
+							Name:   "apikey",
+							Usage:  "Create an api key which authenticates as your account, e.g. micro auth create apikey --scopes=user --expiry=720h",
+							Flags:  apiKeyFlags,
+							Action: createAPIKey,
+						},
 					},
 				},
 				{
@@ -99,6 +125,27 @@ func init() {
 							Flags:  ruleFlags,
 							Action: deleteAccount,
 						},
+						{
+							Name:   "apikey",
+							Usage:  "Revoke an api key, e.g. micro auth delete apikey 3f2a9c1d5e7b8a60",
+							Action: deleteAPIKey,
+						},
+					},
+				},
+				{
+					Name:  "certs",
+					Usage: "Manage the certificates services use for mutual TLS",
+					Subcommands: []*cli.Command{
+						{
+							Name:   "list",
+							Usage:  "List the certificates which haven't expired",
+							Action: listCerts,
+						},
+						{
+							Name:   "revoke",
+							Usage:  "Revoke a certificate so it's no longer accepted, e.g. micro auth certs revoke 9a1f3c5e7b2d4f60",
+							Action: revokeCert,
+						},
 					},
 				},
 			},
@@ -0,0 +1,30 @@
+package client
+
+import (
+	"errors"
+
+	"github.com/micro/go-micro/v3/auth"
+	pb "github.com/micro/micro/v3/service/auth/proto"
+	"github.com/micro/micro/v3/service/context"
+)
+
+// ErrAPIKeysNotSupported is returned by auths which don't issue api keys
+var ErrAPIKeysNotSupported = errors.New("auth doesn't support api keys")
+
+// APIKeyVerifier is implemented by auths which issue api keys
+type APIKeyVerifier interface {
+	// VerifyAPIKey returns the account the api key issued by the namespace authenticates as and a
+	// short lived token for the account
+	VerifyAPIKey(key, namespace string) (*auth.Account, *auth.Token, error)
+}
+
+// VerifyAPIKey returns the account the api key authenticates as and a short lived token
+func (s *srv) VerifyAPIKey(key, namespace string) (*auth.Account, *auth.Token, error) {
+	rsp, err := s.apiKeys.Verify(context.DefaultContext, &pb.VerifyAPIKeyRequest{
+		Key: key, Options: &pb.Options{Namespace: namespace},
+	}, s.callOpts()...)
+	if err != nil {
+		return nil, nil, err
+	}
+	return serializeAccount(rsp.Account), serializeToken(rsp.Token), nil
+}
@@ -0,0 +1,42 @@
+package client
+
+import (
+	"errors"
+	"time"
+
+	pb "github.com/micro/micro/v3/service/auth/proto"
+	"github.com/micro/micro/v3/service/context"
+)
+
+// ErrCertsNotSupported is returned by auths which don't issue certificates
+var ErrCertsNotSupported = errors.New("auth doesn't support certificates")
+
+// CertIssuer is implemented by auths which issue the certificates services use for mutual TLS
+type CertIssuer interface {
+	// IssueCertificate signs the PEM encoded certificate signing request, returning the PEM
+	// encoded certificate issued to the account and the certificate of the CA
+	IssueCertificate(csr []byte, expiry time.Duration) ([]byte, []byte, error)
+	// CertificateAuthority returns the PEM encoded certificate of the CA and the serials of
+	// the certificates which have been revoked
+	CertificateAuthority() ([]byte, []string, error)
+}
+
+// IssueCertificate signs the certificate signing request
+func (s *srv) IssueCertificate(csr []byte, expiry time.Duration) ([]byte, []byte, error) {
+	rsp, err := s.certs.Issue(context.DefaultContext, &pb.IssueCertRequest{
+		Csr: csr, Expiry: int64(expiry.Seconds()),
+	}, s.callOpts()...)
+	if err != nil {
+		return nil, nil, err
+	}
+	return rsp.Certificate, rsp.Ca, nil
+}
+
+// CertificateAuthority returns the certificate of the CA and the revoked serials
+func (s *srv) CertificateAuthority() ([]byte, []string, error) {
+	rsp, err := s.certs.CA(context.DefaultContext, &pb.CARequest{}, s.callOpts()...)
+	if err != nil {
+		return nil, nil, err
+	}
+	return rsp.Ca, rsp.Revoked, nil
+}
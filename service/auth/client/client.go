@@ -19,6 +19,8 @@ type srv struct {
 	options auth.Options
 	auth    pb.AuthService
 	rules   pb.RulesService
+	apiKeys pb.APIKeysService
+	certs   pb.CertsService
 	token   token.Provider
 }
 
@@ -32,6 +34,8 @@ func (s *srv) Init(opts ...auth.Option) {
 	}
 	s.auth = pb.NewAuthService("auth", client.DefaultClient)
 	s.rules = pb.NewRulesService("auth", client.DefaultClient)
+	s.apiKeys = pb.NewAPIKeysService("auth", client.DefaultClient)
+	s.certs = pb.NewCertsService("auth", client.DefaultClient)
 	s.setupJWT()
 }
 
@@ -248,6 +252,7 @@ func serializeToken(t *pb.Token) *auth.Token {
 func serializeAccount(a *pb.Account) *auth.Account {
 	return &auth.Account{
 		ID:       a.Id,
+		Type:     a.Type,
 		Secret:   a.Secret,
 		Issuer:   a.Issuer,
 		Metadata: a.Metadata,
@@ -288,6 +293,8 @@ func NewAuth(opts ...auth.Option) auth.Auth {
 	service := &srv{
 		auth:    pb.NewAuthService("auth", client.DefaultClient),
 		rules:   pb.NewRulesService("auth", client.DefaultClient),
+		apiKeys: pb.NewAPIKeysService("auth", client.DefaultClient),
+		certs:   pb.NewCertsService("auth", client.DefaultClient),
 		options: auth.NewOptions(opts...),
 	}
 
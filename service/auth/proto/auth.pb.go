@@ -1,5 +1,5 @@
 // Code generated by protoc-gen-go. DO NOT EDIT.
-// source: service/auth/proto/auth.proto
+// source: github.com/micro/micro/service/auth/proto/auth.proto
 
 package auth
 
@@ -49,7 +49,7 @@ func (x Access) String() string {
 }
 
 func (Access) EnumDescriptor() ([]byte, []int) {
-	return fileDescriptor_6198f7e829fc4ef7, []int{0}
+	return fileDescriptor_e68f8b0d79fcf05e, []int{0}
 }
 
 type ListAccountsRequest struct {
@@ -63,7 +63,7 @@ func (m *ListAccountsRequest) Reset()         { *m = ListAccountsRequest{} }
 func (m *ListAccountsRequest) String() string { return proto.CompactTextString(m) }
 func (*ListAccountsRequest) ProtoMessage()    {}
 func (*ListAccountsRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_6198f7e829fc4ef7, []int{0}
+	return fileDescriptor_e68f8b0d79fcf05e, []int{0}
 }
 
 func (m *ListAccountsRequest) XXX_Unmarshal(b []byte) error {
@@ -102,7 +102,7 @@ func (m *ListAccountsResponse) Reset()         { *m = ListAccountsResponse{} }
 func (m *ListAccountsResponse) String() string { return proto.CompactTextString(m) }
 func (*ListAccountsResponse) ProtoMessage()    {}
 func (*ListAccountsResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_6198f7e829fc4ef7, []int{1}
+	return fileDescriptor_e68f8b0d79fcf05e, []int{1}
 }
 
 func (m *ListAccountsResponse) XXX_Unmarshal(b []byte) error {
@@ -142,7 +142,7 @@ func (m *DeleteAccountRequest) Reset()         { *m = DeleteAccountRequest{} }
 func (m *DeleteAccountRequest) String() string { return proto.CompactTextString(m) }
 func (*DeleteAccountRequest) ProtoMessage()    {}
 func (*DeleteAccountRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_6198f7e829fc4ef7, []int{2}
+	return fileDescriptor_e68f8b0d79fcf05e, []int{2}
 }
 
 func (m *DeleteAccountRequest) XXX_Unmarshal(b []byte) error {
@@ -187,7 +187,7 @@ func (m *DeleteAccountResponse) Reset()         { *m = DeleteAccountResponse{} }
 func (m *DeleteAccountResponse) String() string { return proto.CompactTextString(m) }
 func (*DeleteAccountResponse) ProtoMessage()    {}
 func (*DeleteAccountResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_6198f7e829fc4ef7, []int{3}
+	return fileDescriptor_e68f8b0d79fcf05e, []int{3}
 }
 
 func (m *DeleteAccountResponse) XXX_Unmarshal(b []byte) error {
@@ -222,7 +222,7 @@ func (m *Token) Reset()         { *m = Token{} }
 func (m *Token) String() string { return proto.CompactTextString(m) }
 func (*Token) ProtoMessage()    {}
 func (*Token) Descriptor() ([]byte, []int) {
-	return fileDescriptor_6198f7e829fc4ef7, []int{4}
+	return fileDescriptor_e68f8b0d79fcf05e, []int{4}
 }
 
 func (m *Token) XXX_Unmarshal(b []byte) error {
@@ -287,7 +287,7 @@ func (m *Account) Reset()         { *m = Account{} }
 func (m *Account) String() string { return proto.CompactTextString(m) }
 func (*Account) ProtoMessage()    {}
 func (*Account) Descriptor() ([]byte, []int) {
-	return fileDescriptor_6198f7e829fc4ef7, []int{5}
+	return fileDescriptor_e68f8b0d79fcf05e, []int{5}
 }
 
 func (m *Account) XXX_Unmarshal(b []byte) error {
@@ -363,7 +363,7 @@ func (m *Resource) Reset()         { *m = Resource{} }
 func (m *Resource) String() string { return proto.CompactTextString(m) }
 func (*Resource) ProtoMessage()    {}
 func (*Resource) Descriptor() ([]byte, []int) {
-	return fileDescriptor_6198f7e829fc4ef7, []int{6}
+	return fileDescriptor_e68f8b0d79fcf05e, []int{6}
 }
 
 func (m *Resource) XXX_Unmarshal(b []byte) error {
@@ -422,7 +422,7 @@ func (m *GenerateRequest) Reset()         { *m = GenerateRequest{} }
 func (m *GenerateRequest) String() string { return proto.CompactTextString(m) }
 func (*GenerateRequest) ProtoMessage()    {}
 func (*GenerateRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_6198f7e829fc4ef7, []int{7}
+	return fileDescriptor_e68f8b0d79fcf05e, []int{7}
 }
 
 func (m *GenerateRequest) XXX_Unmarshal(b []byte) error {
@@ -503,7 +503,7 @@ func (m *GenerateResponse) Reset()         { *m = GenerateResponse{} }
 func (m *GenerateResponse) String() string { return proto.CompactTextString(m) }
 func (*GenerateResponse) ProtoMessage()    {}
 func (*GenerateResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_6198f7e829fc4ef7, []int{8}
+	return fileDescriptor_e68f8b0d79fcf05e, []int{8}
 }
 
 func (m *GenerateResponse) XXX_Unmarshal(b []byte) error {
@@ -544,7 +544,7 @@ func (m *GrantRequest) Reset()         { *m = GrantRequest{} }
 func (m *GrantRequest) String() string { return proto.CompactTextString(m) }
 func (*GrantRequest) ProtoMessage()    {}
 func (*GrantRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_6198f7e829fc4ef7, []int{9}
+	return fileDescriptor_e68f8b0d79fcf05e, []int{9}
 }
 
 func (m *GrantRequest) XXX_Unmarshal(b []byte) error {
@@ -596,7 +596,7 @@ func (m *GrantResponse) Reset()         { *m = GrantResponse{} }
 func (m *GrantResponse) String() string { return proto.CompactTextString(m) }
 func (*GrantResponse) ProtoMessage()    {}
 func (*GrantResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_6198f7e829fc4ef7, []int{10}
+	return fileDescriptor_e68f8b0d79fcf05e, []int{10}
 }
 
 func (m *GrantResponse) XXX_Unmarshal(b []byte) error {
@@ -630,7 +630,7 @@ func (m *RevokeRequest) Reset()         { *m = RevokeRequest{} }
 func (m *RevokeRequest) String() string { return proto.CompactTextString(m) }
 func (*RevokeRequest) ProtoMessage()    {}
 func (*RevokeRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_6198f7e829fc4ef7, []int{11}
+	return fileDescriptor_e68f8b0d79fcf05e, []int{11}
 }
 
 func (m *RevokeRequest) XXX_Unmarshal(b []byte) error {
@@ -682,7 +682,7 @@ func (m *RevokeResponse) Reset()         { *m = RevokeResponse{} }
 func (m *RevokeResponse) String() string { return proto.CompactTextString(m) }
 func (*RevokeResponse) ProtoMessage()    {}
 func (*RevokeResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_6198f7e829fc4ef7, []int{12}
+	return fileDescriptor_e68f8b0d79fcf05e, []int{12}
 }
 
 func (m *RevokeResponse) XXX_Unmarshal(b []byte) error {
@@ -715,7 +715,7 @@ func (m *InspectRequest) Reset()         { *m = InspectRequest{} }
 func (m *InspectRequest) String() string { return proto.CompactTextString(m) }
 func (*InspectRequest) ProtoMessage()    {}
 func (*InspectRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_6198f7e829fc4ef7, []int{13}
+	return fileDescriptor_e68f8b0d79fcf05e, []int{13}
 }
 
 func (m *InspectRequest) XXX_Unmarshal(b []byte) error {
@@ -761,7 +761,7 @@ func (m *InspectResponse) Reset()         { *m = InspectResponse{} }
 func (m *InspectResponse) String() string { return proto.CompactTextString(m) }
 func (*InspectResponse) ProtoMessage()    {}
 func (*InspectResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_6198f7e829fc4ef7, []int{14}
+	return fileDescriptor_e68f8b0d79fcf05e, []int{14}
 }
 
 func (m *InspectResponse) XXX_Unmarshal(b []byte) error {
@@ -804,7 +804,7 @@ func (m *TokenRequest) Reset()         { *m = TokenRequest{} }
 func (m *TokenRequest) String() string { return proto.CompactTextString(m) }
 func (*TokenRequest) ProtoMessage()    {}
 func (*TokenRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_6198f7e829fc4ef7, []int{15}
+	return fileDescriptor_e68f8b0d79fcf05e, []int{15}
 }
 
 func (m *TokenRequest) XXX_Unmarshal(b []byte) error {
@@ -871,7 +871,7 @@ func (m *TokenResponse) Reset()         { *m = TokenResponse{} }
 func (m *TokenResponse) String() string { return proto.CompactTextString(m) }
 func (*TokenResponse) ProtoMessage()    {}
 func (*TokenResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_6198f7e829fc4ef7, []int{16}
+	return fileDescriptor_e68f8b0d79fcf05e, []int{16}
 }
 
 func (m *TokenResponse) XXX_Unmarshal(b []byte) error {
@@ -914,7 +914,7 @@ func (m *Rule) Reset()         { *m = Rule{} }
 func (m *Rule) String() string { return proto.CompactTextString(m) }
 func (*Rule) ProtoMessage()    {}
 func (*Rule) Descriptor() ([]byte, []int) {
-	return fileDescriptor_6198f7e829fc4ef7, []int{17}
+	return fileDescriptor_e68f8b0d79fcf05e, []int{17}
 }
 
 func (m *Rule) XXX_Unmarshal(b []byte) error {
@@ -981,7 +981,7 @@ func (m *Options) Reset()         { *m = Options{} }
 func (m *Options) String() string { return proto.CompactTextString(m) }
 func (*Options) ProtoMessage()    {}
 func (*Options) Descriptor() ([]byte, []int) {
-	return fileDescriptor_6198f7e829fc4ef7, []int{18}
+	return fileDescriptor_e68f8b0d79fcf05e, []int{18}
 }
 
 func (m *Options) XXX_Unmarshal(b []byte) error {
@@ -1021,7 +1021,7 @@ func (m *CreateRequest) Reset()         { *m = CreateRequest{} }
 func (m *CreateRequest) String() string { return proto.CompactTextString(m) }
 func (*CreateRequest) ProtoMessage()    {}
 func (*CreateRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_6198f7e829fc4ef7, []int{19}
+	return fileDescriptor_e68f8b0d79fcf05e, []int{19}
 }
 
 func (m *CreateRequest) XXX_Unmarshal(b []byte) error {
@@ -1066,7 +1066,7 @@ func (m *CreateResponse) Reset()         { *m = CreateResponse{} }
 func (m *CreateResponse) String() string { return proto.CompactTextString(m) }
 func (*CreateResponse) ProtoMessage()    {}
 func (*CreateResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_6198f7e829fc4ef7, []int{20}
+	return fileDescriptor_e68f8b0d79fcf05e, []int{20}
 }
 
 func (m *CreateResponse) XXX_Unmarshal(b []byte) error {
@@ -1099,7 +1099,7 @@ func (m *DeleteRequest) Reset()         { *m = DeleteRequest{} }
 func (m *DeleteRequest) String() string { return proto.CompactTextString(m) }
 func (*DeleteRequest) ProtoMessage()    {}
 func (*DeleteRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_6198f7e829fc4ef7, []int{21}
+	return fileDescriptor_e68f8b0d79fcf05e, []int{21}
 }
 
 func (m *DeleteRequest) XXX_Unmarshal(b []byte) error {
@@ -1144,7 +1144,7 @@ func (m *DeleteResponse) Reset()         { *m = DeleteResponse{} }
 func (m *DeleteResponse) String() string { return proto.CompactTextString(m) }
 func (*DeleteResponse) ProtoMessage()    {}
 func (*DeleteResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_6198f7e829fc4ef7, []int{22}
+	return fileDescriptor_e68f8b0d79fcf05e, []int{22}
 }
 
 func (m *DeleteResponse) XXX_Unmarshal(b []byte) error {
@@ -1176,7 +1176,7 @@ func (m *ListRequest) Reset()         { *m = ListRequest{} }
 func (m *ListRequest) String() string { return proto.CompactTextString(m) }
 func (*ListRequest) ProtoMessage()    {}
 func (*ListRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_6198f7e829fc4ef7, []int{23}
+	return fileDescriptor_e68f8b0d79fcf05e, []int{23}
 }
 
 func (m *ListRequest) XXX_Unmarshal(b []byte) error {
@@ -1215,7 +1215,7 @@ func (m *ListResponse) Reset()         { *m = ListResponse{} }
 func (m *ListResponse) String() string { return proto.CompactTextString(m) }
 func (*ListResponse) ProtoMessage()    {}
 func (*ListResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_6198f7e829fc4ef7, []int{24}
+	return fileDescriptor_e68f8b0d79fcf05e, []int{24}
 }
 
 func (m *ListResponse) XXX_Unmarshal(b []byte) error {
@@ -1257,7 +1257,7 @@ func (m *ChangeSecretRequest) Reset()         { *m = ChangeSecretRequest{} }
 func (m *ChangeSecretRequest) String() string { return proto.CompactTextString(m) }
 func (*ChangeSecretRequest) ProtoMessage()    {}
 func (*ChangeSecretRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_6198f7e829fc4ef7, []int{25}
+	return fileDescriptor_e68f8b0d79fcf05e, []int{25}
 }
 
 func (m *ChangeSecretRequest) XXX_Unmarshal(b []byte) error {
@@ -1316,7 +1316,7 @@ func (m *ChangeSecretResponse) Reset()         { *m = ChangeSecretResponse{} }
 func (m *ChangeSecretResponse) String() string { return proto.CompactTextString(m) }
 func (*ChangeSecretResponse) ProtoMessage()    {}
 func (*ChangeSecretResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_6198f7e829fc4ef7, []int{26}
+	return fileDescriptor_e68f8b0d79fcf05e, []int{26}
 }
 
 func (m *ChangeSecretResponse) XXX_Unmarshal(b []byte) error {
@@ -1337,6 +1337,884 @@ func (m *ChangeSecretResponse) XXX_DiscardUnknown() {
 
 var xxx_messageInfo_ChangeSecretResponse proto.InternalMessageInfo
 
+type APIKey struct {
+	Id string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// the account the key authenticates as
+	AccountId   string   `protobuf:"bytes,2,opt,name=account_id,json=accountId,proto3" json:"account_id,omitempty"`
+	Description string   `protobuf:"bytes,3,opt,name=description,proto3" json:"description,omitempty"`
+	Scopes      []string `protobuf:"bytes,4,rep,name=scopes,proto3" json:"scopes,omitempty"`
+	Created     int64    `protobuf:"varint,5,opt,name=created,proto3" json:"created,omitempty"`
+	// unix timestamp the key expires at, zero if it never expires
+	Expiry   int64 `protobuf:"varint,6,opt,name=expiry,proto3" json:"expiry,omitempty"`
+	LastUsed int64 `protobuf:"varint,7,opt,name=last_used,json=lastUsed,proto3" json:"last_used,omitempty"`
+	// number of requests authenticated using the key
+	Usage                int64    `protobuf:"varint,8,opt,name=usage,proto3" json:"usage,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *APIKey) Reset()         { *m = APIKey{} }
+func (m *APIKey) String() string { return proto.CompactTextString(m) }
+func (*APIKey) ProtoMessage()    {}
+func (*APIKey) Descriptor() ([]byte, []int) {
+	return fileDescriptor_e68f8b0d79fcf05e, []int{27}
+}
+
+func (m *APIKey) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_APIKey.Unmarshal(m, b)
+}
+func (m *APIKey) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_APIKey.Marshal(b, m, deterministic)
+}
+func (m *APIKey) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_APIKey.Merge(m, src)
+}
+func (m *APIKey) XXX_Size() int {
+	return xxx_messageInfo_APIKey.Size(m)
+}
+func (m *APIKey) XXX_DiscardUnknown() {
+	xxx_messageInfo_APIKey.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_APIKey proto.InternalMessageInfo
+
+func (m *APIKey) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+func (m *APIKey) GetAccountId() string {
+	if m != nil {
+		return m.AccountId
+	}
+	return ""
+}
+
+func (m *APIKey) GetDescription() string {
+	if m != nil {
+		return m.Description
+	}
+	return ""
+}
+
+func (m *APIKey) GetScopes() []string {
+	if m != nil {
+		return m.Scopes
+	}
+	return nil
+}
+
+func (m *APIKey) GetCreated() int64 {
+	if m != nil {
+		return m.Created
+	}
+	return 0
+}
+
+func (m *APIKey) GetExpiry() int64 {
+	if m != nil {
+		return m.Expiry
+	}
+	return 0
+}
+
+func (m *APIKey) GetLastUsed() int64 {
+	if m != nil {
+		return m.LastUsed
+	}
+	return 0
+}
+
+func (m *APIKey) GetUsage() int64 {
+	if m != nil {
+		return m.Usage
+	}
+	return 0
+}
+
+type CreateAPIKeyRequest struct {
+	Description string `protobuf:"bytes,1,opt,name=description,proto3" json:"description,omitempty"`
+	// scopes of the key, defaults to the scopes of the account creating it
+	Scopes []string `protobuf:"bytes,2,rep,name=scopes,proto3" json:"scopes,omitempty"`
+	// seconds until the key expires, zero if it never expires
+	Expiry               int64    `protobuf:"varint,3,opt,name=expiry,proto3" json:"expiry,omitempty"`
+	Options              *Options `protobuf:"bytes,4,opt,name=options,proto3" json:"options,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *CreateAPIKeyRequest) Reset()         { *m = CreateAPIKeyRequest{} }
+func (m *CreateAPIKeyRequest) String() string { return proto.CompactTextString(m) }
+func (*CreateAPIKeyRequest) ProtoMessage()    {}
+func (*CreateAPIKeyRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_e68f8b0d79fcf05e, []int{28}
+}
+
+func (m *CreateAPIKeyRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CreateAPIKeyRequest.Unmarshal(m, b)
+}
+func (m *CreateAPIKeyRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CreateAPIKeyRequest.Marshal(b, m, deterministic)
+}
+func (m *CreateAPIKeyRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CreateAPIKeyRequest.Merge(m, src)
+}
+func (m *CreateAPIKeyRequest) XXX_Size() int {
+	return xxx_messageInfo_CreateAPIKeyRequest.Size(m)
+}
+func (m *CreateAPIKeyRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_CreateAPIKeyRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CreateAPIKeyRequest proto.InternalMessageInfo
+
+func (m *CreateAPIKeyRequest) GetDescription() string {
+	if m != nil {
+		return m.Description
+	}
+	return ""
+}
+
+func (m *CreateAPIKeyRequest) GetScopes() []string {
+	if m != nil {
+		return m.Scopes
+	}
+	return nil
+}
+
+func (m *CreateAPIKeyRequest) GetExpiry() int64 {
+	if m != nil {
+		return m.Expiry
+	}
+	return 0
+}
+
+func (m *CreateAPIKeyRequest) GetOptions() *Options {
+	if m != nil {
+		return m.Options
+	}
+	return nil
+}
+
+type CreateAPIKeyResponse struct {
+	ApiKey *APIKey `protobuf:"bytes,1,opt,name=api_key,json=apiKey,proto3" json:"api_key,omitempty"`
+	// the key, it's only returned when created since it's stored hashed
+	Key                  string   `protobuf:"bytes,2,opt,name=key,proto3" json:"key,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *CreateAPIKeyResponse) Reset()         { *m = CreateAPIKeyResponse{} }
+func (m *CreateAPIKeyResponse) String() string { return proto.CompactTextString(m) }
+func (*CreateAPIKeyResponse) ProtoMessage()    {}
+func (*CreateAPIKeyResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_e68f8b0d79fcf05e, []int{29}
+}
+
+func (m *CreateAPIKeyResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CreateAPIKeyResponse.Unmarshal(m, b)
+}
+func (m *CreateAPIKeyResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CreateAPIKeyResponse.Marshal(b, m, deterministic)
+}
+func (m *CreateAPIKeyResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CreateAPIKeyResponse.Merge(m, src)
+}
+func (m *CreateAPIKeyResponse) XXX_Size() int {
+	return xxx_messageInfo_CreateAPIKeyResponse.Size(m)
+}
+func (m *CreateAPIKeyResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_CreateAPIKeyResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CreateAPIKeyResponse proto.InternalMessageInfo
+
+func (m *CreateAPIKeyResponse) GetApiKey() *APIKey {
+	if m != nil {
+		return m.ApiKey
+	}
+	return nil
+}
+
+func (m *CreateAPIKeyResponse) GetKey() string {
+	if m != nil {
+		return m.Key
+	}
+	return ""
+}
+
+type ListAPIKeysRequest struct {
+	Options              *Options `protobuf:"bytes,1,opt,name=options,proto3" json:"options,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ListAPIKeysRequest) Reset()         { *m = ListAPIKeysRequest{} }
+func (m *ListAPIKeysRequest) String() string { return proto.CompactTextString(m) }
+func (*ListAPIKeysRequest) ProtoMessage()    {}
+func (*ListAPIKeysRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_e68f8b0d79fcf05e, []int{30}
+}
+
+func (m *ListAPIKeysRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListAPIKeysRequest.Unmarshal(m, b)
+}
+func (m *ListAPIKeysRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListAPIKeysRequest.Marshal(b, m, deterministic)
+}
+func (m *ListAPIKeysRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListAPIKeysRequest.Merge(m, src)
+}
+func (m *ListAPIKeysRequest) XXX_Size() int {
+	return xxx_messageInfo_ListAPIKeysRequest.Size(m)
+}
+func (m *ListAPIKeysRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListAPIKeysRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListAPIKeysRequest proto.InternalMessageInfo
+
+func (m *ListAPIKeysRequest) GetOptions() *Options {
+	if m != nil {
+		return m.Options
+	}
+	return nil
+}
+
+type ListAPIKeysResponse struct {
+	ApiKeys              []*APIKey `protobuf:"bytes,1,rep,name=api_keys,json=apiKeys,proto3" json:"api_keys,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}  `json:"-"`
+	XXX_unrecognized     []byte    `json:"-"`
+	XXX_sizecache        int32     `json:"-"`
+}
+
+func (m *ListAPIKeysResponse) Reset()         { *m = ListAPIKeysResponse{} }
+func (m *ListAPIKeysResponse) String() string { return proto.CompactTextString(m) }
+func (*ListAPIKeysResponse) ProtoMessage()    {}
+func (*ListAPIKeysResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_e68f8b0d79fcf05e, []int{31}
+}
+
+func (m *ListAPIKeysResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListAPIKeysResponse.Unmarshal(m, b)
+}
+func (m *ListAPIKeysResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListAPIKeysResponse.Marshal(b, m, deterministic)
+}
+func (m *ListAPIKeysResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListAPIKeysResponse.Merge(m, src)
+}
+func (m *ListAPIKeysResponse) XXX_Size() int {
+	return xxx_messageInfo_ListAPIKeysResponse.Size(m)
+}
+func (m *ListAPIKeysResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListAPIKeysResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListAPIKeysResponse proto.InternalMessageInfo
+
+func (m *ListAPIKeysResponse) GetApiKeys() []*APIKey {
+	if m != nil {
+		return m.ApiKeys
+	}
+	return nil
+}
+
+type RevokeAPIKeyRequest struct {
+	Id                   string   `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Options              *Options `protobuf:"bytes,2,opt,name=options,proto3" json:"options,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *RevokeAPIKeyRequest) Reset()         { *m = RevokeAPIKeyRequest{} }
+func (m *RevokeAPIKeyRequest) String() string { return proto.CompactTextString(m) }
+func (*RevokeAPIKeyRequest) ProtoMessage()    {}
+func (*RevokeAPIKeyRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_e68f8b0d79fcf05e, []int{32}
+}
+
+func (m *RevokeAPIKeyRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RevokeAPIKeyRequest.Unmarshal(m, b)
+}
+func (m *RevokeAPIKeyRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RevokeAPIKeyRequest.Marshal(b, m, deterministic)
+}
+func (m *RevokeAPIKeyRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RevokeAPIKeyRequest.Merge(m, src)
+}
+func (m *RevokeAPIKeyRequest) XXX_Size() int {
+	return xxx_messageInfo_RevokeAPIKeyRequest.Size(m)
+}
+func (m *RevokeAPIKeyRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_RevokeAPIKeyRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RevokeAPIKeyRequest proto.InternalMessageInfo
+
+func (m *RevokeAPIKeyRequest) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+func (m *RevokeAPIKeyRequest) GetOptions() *Options {
+	if m != nil {
+		return m.Options
+	}
+	return nil
+}
+
+type RevokeAPIKeyResponse struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *RevokeAPIKeyResponse) Reset()         { *m = RevokeAPIKeyResponse{} }
+func (m *RevokeAPIKeyResponse) String() string { return proto.CompactTextString(m) }
+func (*RevokeAPIKeyResponse) ProtoMessage()    {}
+func (*RevokeAPIKeyResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_e68f8b0d79fcf05e, []int{33}
+}
+
+func (m *RevokeAPIKeyResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RevokeAPIKeyResponse.Unmarshal(m, b)
+}
+func (m *RevokeAPIKeyResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RevokeAPIKeyResponse.Marshal(b, m, deterministic)
+}
+func (m *RevokeAPIKeyResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RevokeAPIKeyResponse.Merge(m, src)
+}
+func (m *RevokeAPIKeyResponse) XXX_Size() int {
+	return xxx_messageInfo_RevokeAPIKeyResponse.Size(m)
+}
+func (m *RevokeAPIKeyResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_RevokeAPIKeyResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RevokeAPIKeyResponse proto.InternalMessageInfo
+
+type VerifyAPIKeyRequest struct {
+	Key                  string   `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	Options              *Options `protobuf:"bytes,2,opt,name=options,proto3" json:"options,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *VerifyAPIKeyRequest) Reset()         { *m = VerifyAPIKeyRequest{} }
+func (m *VerifyAPIKeyRequest) String() string { return proto.CompactTextString(m) }
+func (*VerifyAPIKeyRequest) ProtoMessage()    {}
+func (*VerifyAPIKeyRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_e68f8b0d79fcf05e, []int{34}
+}
+
+func (m *VerifyAPIKeyRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_VerifyAPIKeyRequest.Unmarshal(m, b)
+}
+func (m *VerifyAPIKeyRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_VerifyAPIKeyRequest.Marshal(b, m, deterministic)
+}
+func (m *VerifyAPIKeyRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_VerifyAPIKeyRequest.Merge(m, src)
+}
+func (m *VerifyAPIKeyRequest) XXX_Size() int {
+	return xxx_messageInfo_VerifyAPIKeyRequest.Size(m)
+}
+func (m *VerifyAPIKeyRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_VerifyAPIKeyRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_VerifyAPIKeyRequest proto.InternalMessageInfo
+
+func (m *VerifyAPIKeyRequest) GetKey() string {
+	if m != nil {
+		return m.Key
+	}
+	return ""
+}
+
+func (m *VerifyAPIKeyRequest) GetOptions() *Options {
+	if m != nil {
+		return m.Options
+	}
+	return nil
+}
+
+type VerifyAPIKeyResponse struct {
+	Account *Account `protobuf:"bytes,1,opt,name=account,proto3" json:"account,omitempty"`
+	// short lived token for the account, used to call services on behalf of the key
+	Token                *Token   `protobuf:"bytes,2,opt,name=token,proto3" json:"token,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *VerifyAPIKeyResponse) Reset()         { *m = VerifyAPIKeyResponse{} }
+func (m *VerifyAPIKeyResponse) String() string { return proto.CompactTextString(m) }
+func (*VerifyAPIKeyResponse) ProtoMessage()    {}
+func (*VerifyAPIKeyResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_e68f8b0d79fcf05e, []int{35}
+}
+
+func (m *VerifyAPIKeyResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_VerifyAPIKeyResponse.Unmarshal(m, b)
+}
+func (m *VerifyAPIKeyResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_VerifyAPIKeyResponse.Marshal(b, m, deterministic)
+}
+func (m *VerifyAPIKeyResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_VerifyAPIKeyResponse.Merge(m, src)
+}
+func (m *VerifyAPIKeyResponse) XXX_Size() int {
+	return xxx_messageInfo_VerifyAPIKeyResponse.Size(m)
+}
+func (m *VerifyAPIKeyResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_VerifyAPIKeyResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_VerifyAPIKeyResponse proto.InternalMessageInfo
+
+func (m *VerifyAPIKeyResponse) GetAccount() *Account {
+	if m != nil {
+		return m.Account
+	}
+	return nil
+}
+
+func (m *VerifyAPIKeyResponse) GetToken() *Token {
+	if m != nil {
+		return m.Token
+	}
+	return nil
+}
+
+type Cert struct {
+	// hex encoded serial number of the certificate
+	Serial string `protobuf:"bytes,1,opt,name=serial,proto3" json:"serial,omitempty"`
+	// the account the certificate was issued to
+	AccountId string `protobuf:"bytes,2,opt,name=account_id,json=accountId,proto3" json:"account_id,omitempty"`
+	Created   int64  `protobuf:"varint,3,opt,name=created,proto3" json:"created,omitempty"`
+	// unix timestamp the certificate expires at
+	Expiry               int64    `protobuf:"varint,4,opt,name=expiry,proto3" json:"expiry,omitempty"`
+	Revoked              bool     `protobuf:"varint,5,opt,name=revoked,proto3" json:"revoked,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Cert) Reset()         { *m = Cert{} }
+func (m *Cert) String() string { return proto.CompactTextString(m) }
+func (*Cert) ProtoMessage()    {}
+func (*Cert) Descriptor() ([]byte, []int) {
+	return fileDescriptor_e68f8b0d79fcf05e, []int{36}
+}
+
+func (m *Cert) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Cert.Unmarshal(m, b)
+}
+func (m *Cert) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Cert.Marshal(b, m, deterministic)
+}
+func (m *Cert) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Cert.Merge(m, src)
+}
+func (m *Cert) XXX_Size() int {
+	return xxx_messageInfo_Cert.Size(m)
+}
+func (m *Cert) XXX_DiscardUnknown() {
+	xxx_messageInfo_Cert.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Cert proto.InternalMessageInfo
+
+func (m *Cert) GetSerial() string {
+	if m != nil {
+		return m.Serial
+	}
+	return ""
+}
+
+func (m *Cert) GetAccountId() string {
+	if m != nil {
+		return m.AccountId
+	}
+	return ""
+}
+
+func (m *Cert) GetCreated() int64 {
+	if m != nil {
+		return m.Created
+	}
+	return 0
+}
+
+func (m *Cert) GetExpiry() int64 {
+	if m != nil {
+		return m.Expiry
+	}
+	return 0
+}
+
+func (m *Cert) GetRevoked() bool {
+	if m != nil {
+		return m.Revoked
+	}
+	return false
+}
+
+type IssueCertRequest struct {
+	// PEM encoded certificate signing request
+	Csr []byte `protobuf:"bytes,1,opt,name=csr,proto3" json:"csr,omitempty"`
+	// seconds until the certificate expires, defaults to a day
+	Expiry               int64    `protobuf:"varint,2,opt,name=expiry,proto3" json:"expiry,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *IssueCertRequest) Reset()         { *m = IssueCertRequest{} }
+func (m *IssueCertRequest) String() string { return proto.CompactTextString(m) }
+func (*IssueCertRequest) ProtoMessage()    {}
+func (*IssueCertRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_e68f8b0d79fcf05e, []int{37}
+}
+
+func (m *IssueCertRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_IssueCertRequest.Unmarshal(m, b)
+}
+func (m *IssueCertRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_IssueCertRequest.Marshal(b, m, deterministic)
+}
+func (m *IssueCertRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_IssueCertRequest.Merge(m, src)
+}
+func (m *IssueCertRequest) XXX_Size() int {
+	return xxx_messageInfo_IssueCertRequest.Size(m)
+}
+func (m *IssueCertRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_IssueCertRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_IssueCertRequest proto.InternalMessageInfo
+
+func (m *IssueCertRequest) GetCsr() []byte {
+	if m != nil {
+		return m.Csr
+	}
+	return nil
+}
+
+func (m *IssueCertRequest) GetExpiry() int64 {
+	if m != nil {
+		return m.Expiry
+	}
+	return 0
+}
+
+type IssueCertResponse struct {
+	Cert *Cert `protobuf:"bytes,1,opt,name=cert,proto3" json:"cert,omitempty"`
+	// PEM encoded certificate issued to the account
+	Certificate []byte `protobuf:"bytes,2,opt,name=certificate,proto3" json:"certificate,omitempty"`
+	// PEM encoded certificate of the CA which issued it
+	Ca                   []byte   `protobuf:"bytes,3,opt,name=ca,proto3" json:"ca,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *IssueCertResponse) Reset()         { *m = IssueCertResponse{} }
+func (m *IssueCertResponse) String() string { return proto.CompactTextString(m) }
+func (*IssueCertResponse) ProtoMessage()    {}
+func (*IssueCertResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_e68f8b0d79fcf05e, []int{38}
+}
+
+func (m *IssueCertResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_IssueCertResponse.Unmarshal(m, b)
+}
+func (m *IssueCertResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_IssueCertResponse.Marshal(b, m, deterministic)
+}
+func (m *IssueCertResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_IssueCertResponse.Merge(m, src)
+}
+func (m *IssueCertResponse) XXX_Size() int {
+	return xxx_messageInfo_IssueCertResponse.Size(m)
+}
+func (m *IssueCertResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_IssueCertResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_IssueCertResponse proto.InternalMessageInfo
+
+func (m *IssueCertResponse) GetCert() *Cert {
+	if m != nil {
+		return m.Cert
+	}
+	return nil
+}
+
+func (m *IssueCertResponse) GetCertificate() []byte {
+	if m != nil {
+		return m.Certificate
+	}
+	return nil
+}
+
+func (m *IssueCertResponse) GetCa() []byte {
+	if m != nil {
+		return m.Ca
+	}
+	return nil
+}
+
+type ListCertsRequest struct {
+	Options              *Options `protobuf:"bytes,1,opt,name=options,proto3" json:"options,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ListCertsRequest) Reset()         { *m = ListCertsRequest{} }
+func (m *ListCertsRequest) String() string { return proto.CompactTextString(m) }
+func (*ListCertsRequest) ProtoMessage()    {}
+func (*ListCertsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_e68f8b0d79fcf05e, []int{39}
+}
+
+func (m *ListCertsRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListCertsRequest.Unmarshal(m, b)
+}
+func (m *ListCertsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListCertsRequest.Marshal(b, m, deterministic)
+}
+func (m *ListCertsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListCertsRequest.Merge(m, src)
+}
+func (m *ListCertsRequest) XXX_Size() int {
+	return xxx_messageInfo_ListCertsRequest.Size(m)
+}
+func (m *ListCertsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListCertsRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListCertsRequest proto.InternalMessageInfo
+
+func (m *ListCertsRequest) GetOptions() *Options {
+	if m != nil {
+		return m.Options
+	}
+	return nil
+}
+
+type ListCertsResponse struct {
+	Certs                []*Cert  `protobuf:"bytes,1,rep,name=certs,proto3" json:"certs,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ListCertsResponse) Reset()         { *m = ListCertsResponse{} }
+func (m *ListCertsResponse) String() string { return proto.CompactTextString(m) }
+func (*ListCertsResponse) ProtoMessage()    {}
+func (*ListCertsResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_e68f8b0d79fcf05e, []int{40}
+}
+
+func (m *ListCertsResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListCertsResponse.Unmarshal(m, b)
+}
+func (m *ListCertsResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListCertsResponse.Marshal(b, m, deterministic)
+}
+func (m *ListCertsResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListCertsResponse.Merge(m, src)
+}
+func (m *ListCertsResponse) XXX_Size() int {
+	return xxx_messageInfo_ListCertsResponse.Size(m)
+}
+func (m *ListCertsResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListCertsResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListCertsResponse proto.InternalMessageInfo
+
+func (m *ListCertsResponse) GetCerts() []*Cert {
+	if m != nil {
+		return m.Certs
+	}
+	return nil
+}
+
+type RevokeCertRequest struct {
+	Serial               string   `protobuf:"bytes,1,opt,name=serial,proto3" json:"serial,omitempty"`
+	Options              *Options `protobuf:"bytes,2,opt,name=options,proto3" json:"options,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *RevokeCertRequest) Reset()         { *m = RevokeCertRequest{} }
+func (m *RevokeCertRequest) String() string { return proto.CompactTextString(m) }
+func (*RevokeCertRequest) ProtoMessage()    {}
+func (*RevokeCertRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_e68f8b0d79fcf05e, []int{41}
+}
+
+func (m *RevokeCertRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RevokeCertRequest.Unmarshal(m, b)
+}
+func (m *RevokeCertRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RevokeCertRequest.Marshal(b, m, deterministic)
+}
+func (m *RevokeCertRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RevokeCertRequest.Merge(m, src)
+}
+func (m *RevokeCertRequest) XXX_Size() int {
+	return xxx_messageInfo_RevokeCertRequest.Size(m)
+}
+func (m *RevokeCertRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_RevokeCertRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RevokeCertRequest proto.InternalMessageInfo
+
+func (m *RevokeCertRequest) GetSerial() string {
+	if m != nil {
+		return m.Serial
+	}
+	return ""
+}
+
+func (m *RevokeCertRequest) GetOptions() *Options {
+	if m != nil {
+		return m.Options
+	}
+	return nil
+}
+
+type RevokeCertResponse struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *RevokeCertResponse) Reset()         { *m = RevokeCertResponse{} }
+func (m *RevokeCertResponse) String() string { return proto.CompactTextString(m) }
+func (*RevokeCertResponse) ProtoMessage()    {}
+func (*RevokeCertResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_e68f8b0d79fcf05e, []int{42}
+}
+
+func (m *RevokeCertResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RevokeCertResponse.Unmarshal(m, b)
+}
+func (m *RevokeCertResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RevokeCertResponse.Marshal(b, m, deterministic)
+}
+func (m *RevokeCertResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RevokeCertResponse.Merge(m, src)
+}
+func (m *RevokeCertResponse) XXX_Size() int {
+	return xxx_messageInfo_RevokeCertResponse.Size(m)
+}
+func (m *RevokeCertResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_RevokeCertResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RevokeCertResponse proto.InternalMessageInfo
+
+type CARequest struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *CARequest) Reset()         { *m = CARequest{} }
+func (m *CARequest) String() string { return proto.CompactTextString(m) }
+func (*CARequest) ProtoMessage()    {}
+func (*CARequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_e68f8b0d79fcf05e, []int{43}
+}
+
+func (m *CARequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CARequest.Unmarshal(m, b)
+}
+func (m *CARequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CARequest.Marshal(b, m, deterministic)
+}
+func (m *CARequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CARequest.Merge(m, src)
+}
+func (m *CARequest) XXX_Size() int {
+	return xxx_messageInfo_CARequest.Size(m)
+}
+func (m *CARequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_CARequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CARequest proto.InternalMessageInfo
+
+type CAResponse struct {
+	// PEM encoded certificate of the CA
+	Ca []byte `protobuf:"bytes,1,opt,name=ca,proto3" json:"ca,omitempty"`
+	// serials of the certificates revoked before they expire
+	Revoked              []string `protobuf:"bytes,2,rep,name=revoked,proto3" json:"revoked,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *CAResponse) Reset()         { *m = CAResponse{} }
+func (m *CAResponse) String() string { return proto.CompactTextString(m) }
+func (*CAResponse) ProtoMessage()    {}
+func (*CAResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_e68f8b0d79fcf05e, []int{44}
+}
+
+func (m *CAResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CAResponse.Unmarshal(m, b)
+}
+func (m *CAResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CAResponse.Marshal(b, m, deterministic)
+}
+func (m *CAResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CAResponse.Merge(m, src)
+}
+func (m *CAResponse) XXX_Size() int {
+	return xxx_messageInfo_CAResponse.Size(m)
+}
+func (m *CAResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_CAResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CAResponse proto.InternalMessageInfo
+
+func (m *CAResponse) GetCa() []byte {
+	if m != nil {
+		return m.Ca
+	}
+	return nil
+}
+
+func (m *CAResponse) GetRevoked() []string {
+	if m != nil {
+		return m.Revoked
+	}
+	return nil
+}
+
 func init() {
 	proto.RegisterEnum("auth.Access", Access_name, Access_value)
 	proto.RegisterType((*ListAccountsRequest)(nil), "auth.ListAccountsRequest")
@@ -1368,78 +2246,129 @@ func init() {
 	proto.RegisterType((*ListResponse)(nil), "auth.ListResponse")
 	proto.RegisterType((*ChangeSecretRequest)(nil), "auth.ChangeSecretRequest")
 	proto.RegisterType((*ChangeSecretResponse)(nil), "auth.ChangeSecretResponse")
+	proto.RegisterType((*APIKey)(nil), "auth.APIKey")
+	proto.RegisterType((*CreateAPIKeyRequest)(nil), "auth.CreateAPIKeyRequest")
+	proto.RegisterType((*CreateAPIKeyResponse)(nil), "auth.CreateAPIKeyResponse")
+	proto.RegisterType((*ListAPIKeysRequest)(nil), "auth.ListAPIKeysRequest")
+	proto.RegisterType((*ListAPIKeysResponse)(nil), "auth.ListAPIKeysResponse")
+	proto.RegisterType((*RevokeAPIKeyRequest)(nil), "auth.RevokeAPIKeyRequest")
+	proto.RegisterType((*RevokeAPIKeyResponse)(nil), "auth.RevokeAPIKeyResponse")
+	proto.RegisterType((*VerifyAPIKeyRequest)(nil), "auth.VerifyAPIKeyRequest")
+	proto.RegisterType((*VerifyAPIKeyResponse)(nil), "auth.VerifyAPIKeyResponse")
+	proto.RegisterType((*Cert)(nil), "auth.Cert")
+	proto.RegisterType((*IssueCertRequest)(nil), "auth.IssueCertRequest")
+	proto.RegisterType((*IssueCertResponse)(nil), "auth.IssueCertResponse")
+	proto.RegisterType((*ListCertsRequest)(nil), "auth.ListCertsRequest")
+	proto.RegisterType((*ListCertsResponse)(nil), "auth.ListCertsResponse")
+	proto.RegisterType((*RevokeCertRequest)(nil), "auth.RevokeCertRequest")
+	proto.RegisterType((*RevokeCertResponse)(nil), "auth.RevokeCertResponse")
+	proto.RegisterType((*CARequest)(nil), "auth.CARequest")
+	proto.RegisterType((*CAResponse)(nil), "auth.CAResponse")
 }
 
-func init() { proto.RegisterFile("service/auth/proto/auth.proto", fileDescriptor_6198f7e829fc4ef7) }
-
-var fileDescriptor_6198f7e829fc4ef7 = []byte{
-	// 1046 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xc4, 0x56, 0xdd, 0x6e, 0xe3, 0x44,
-	0x14, 0xae, 0x63, 0xe7, 0xa7, 0x27, 0x3f, 0x0d, 0xd3, 0xb4, 0x84, 0x2c, 0x8b, 0x5a, 0x2f, 0x52,
-	0xcb, 0x5e, 0x24, 0x90, 0x6a, 0x61, 0xb5, 0x95, 0x58, 0x95, 0x36, 0x2a, 0x2b, 0x20, 0x95, 0xcc,
-	0x22, 0x10, 0x37, 0x95, 0xd7, 0x39, 0x6c, 0xac, 0xa6, 0xb6, 0xf1, 0xd8, 0x59, 0xc2, 0x1d, 0xf7,
-	0x3c, 0x01, 0x0f, 0x00, 0xdc, 0xf2, 0x38, 0x3c, 0x03, 0x2f, 0x81, 0x3c, 0x73, 0xc6, 0xb1, 0x53,
-	0x37, 0x14, 0xed, 0xc5, 0xde, 0x58, 0x73, 0xfe, 0x66, 0xce, 0xf7, 0xcd, 0x39, 0xc7, 0x03, 0xf7,
-	0x39, 0x86, 0x73, 0xd7, 0xc1, 0x81, 0x1d, 0x47, 0xd3, 0x41, 0x10, 0xfa, 0x91, 0x2f, 0x96, 0x7d,
-	0xb1, 0x64, 0x46, 0xb2, 0x36, 0x3f, 0x85, 0xed, 0x2f, 0x5d, 0x1e, 0x9d, 0x38, 0x8e, 0x1f, 0x7b,
-	0x11, 0xb7, 0xf0, 0xc7, 0x18, 0x79, 0xc4, 0x0e, 0xa0, 0xea, 0x07, 0x91, 0xeb, 0x7b, 0xbc, 0xab,
-	0xed, 0x69, 0x87, 0xf5, 0x61, 0xb3, 0x2f, 0x42, 0x2f, 0xa4, 0xd2, 0x52, 0x56, 0xf3, 0x04, 0x3a,
-	0xf9, 0x78, 0x1e, 0xf8, 0x1e, 0x47, 0xf6, 0x01, 0xd4, 0x6c, 0xd2, 0x75, 0xb5, 0x3d, 0x7d, 0xb9,
-	0x03, 0x79, 0x5a, 0xa9, 0xd9, 0xbc, 0x80, 0xce, 0x19, 0xce, 0x30, 0x42, 0x65, 0xa2, 0x1c, 0x5a,
-	0x50, 0x72, 0x27, 0xe2, 0xf8, 0x4d, 0xab, 0xe4, 0x4e, 0xb2, 0x39, 0x95, 0xd6, 0xe6, 0xf4, 0x36,
-	0xec, 0xac, 0x6c, 0x28, 0x93, 0x32, 0x7f, 0xd1, 0xa0, 0xfc, 0xdc, 0xbf, 0x42, 0x8f, 0xed, 0x43,
-	0xc3, 0x76, 0x1c, 0xe4, 0xfc, 0x32, 0x4a, 0x64, 0x3a, 0xa5, 0x2e, 0x75, 0xd2, 0xe5, 0x01, 0x34,
-	0x43, 0xfc, 0x21, 0x44, 0x3e, 0x25, 0x9f, 0x92, 0xf0, 0x69, 0x90, 0x52, 0x3a, 0x75, 0xa1, 0xea,
-	0x84, 0x68, 0x47, 0x38, 0xe9, 0xea, 0x7b, 0xda, 0xa1, 0x6e, 0x29, 0x91, 0xed, 0x42, 0x05, 0x7f,
-	0x0a, 0xdc, 0x70, 0xd1, 0x35, 0x84, 0x81, 0x24, 0xf3, 0x1f, 0x0d, 0xaa, 0x94, 0xd7, 0x0d, 0x84,
-	0x0c, 0x8c, 0x68, 0x11, 0x20, 0x9d, 0x24, 0xd6, 0xec, 0x13, 0xa8, 0x5d, 0x63, 0x64, 0x4f, 0xec,
-	0xc8, 0xee, 0x1a, 0x82, 0xc8, 0x7b, 0x39, 0x22, 0xfb, 0x5f, 0x91, 0x75, 0xe4, 0x45, 0xe1, 0xc2,
-	0x4a, 0x9d, 0x93, 0x04, 0xb8, 0xe3, 0x07, 0xc8, 0xbb, 0xe5, 0x3d, 0xfd, 0x70, 0xd3, 0x22, 0x29,
-	0xd1, 0xbb, 0x9c, 0xc7, 0x18, 0x76, 0x2b, 0xe2, 0x18, 0x92, 0x84, 0x3f, 0x3a, 0x21, 0x46, 0xdd,
-	0xaa, 0xd4, 0x4b, 0xa9, 0x77, 0x0c, 0xcd, 0xdc, 0x11, 0xac, 0x0d, 0xfa, 0x15, 0x2e, 0x28, 0xed,
-	0x64, 0xc9, 0x3a, 0x50, 0x9e, 0xdb, 0xb3, 0x58, 0x25, 0x2e, 0x85, 0x27, 0xa5, 0xc7, 0x9a, 0x39,
-	0x86, 0x9a, 0x85, 0xdc, 0x8f, 0x43, 0x07, 0x13, 0x74, 0x9e, 0x7d, 0x8d, 0x14, 0x28, 0xd6, 0x85,
-	0x88, 0x7b, 0x50, 0x43, 0x6f, 0x12, 0xf8, 0xae, 0x17, 0x09, 0x52, 0x37, 0xad, 0x54, 0x36, 0xff,
-	0x2c, 0xc1, 0xd6, 0x39, 0x7a, 0x18, 0xda, 0x11, 0xde, 0x56, 0x27, 0x4f, 0x33, 0x8c, 0xe9, 0x82,
-	0xb1, 0x07, 0x92, 0xb1, 0x95, 0xc0, 0x3b, 0x30, 0x67, 0xac, 0x32, 0x47, 0x0c, 0x95, 0xb3, 0x0c,
-	0xa5, 0x20, 0x2a, 0x79, 0x10, 0x41, 0xe8, 0xcf, 0xdd, 0x09, 0x86, 0xc4, 0x67, 0x2a, 0x67, 0x0b,
-	0xb9, 0xb6, 0xae, 0x90, 0x5f, 0x8f, 0xfa, 0x63, 0x68, 0x2f, 0x01, 0x53, 0x57, 0x1e, 0x40, 0x95,
-	0xda, 0x2e, 0xdf, 0xd6, 0xaa, 0x51, 0x94, 0xd5, 0x5c, 0x40, 0xe3, 0x3c, 0xb4, 0x97, 0xbd, 0xd8,
-	0x81, 0xb2, 0x20, 0x81, 0x8e, 0x96, 0x02, 0x7b, 0x08, 0xb5, 0x90, 0x6e, 0x97, 0x5a, 0xb2, 0x25,
-	0xf7, 0x53, 0x77, 0x6e, 0xa5, 0xf6, 0x2c, 0x68, 0x7d, 0x6d, 0xf7, 0x6e, 0x41, 0x93, 0x8e, 0xa6,
-	0xae, 0xfd, 0x19, 0x9a, 0x16, 0xce, 0xfd, 0x2b, 0x7c, 0x03, 0xc9, 0xb4, 0xa1, 0xa5, 0xce, 0xa6,
-	0x6c, 0x2e, 0xa0, 0xf5, 0xcc, 0xe3, 0x01, 0x3a, 0x59, 0x6e, 0xb2, 0x43, 0x44, 0x0a, 0x77, 0x9f,
-	0x56, 0x4f, 0x60, 0x2b, 0xdd, 0xf0, 0xff, 0x5e, 0xd3, 0x1f, 0x1a, 0x34, 0xc4, 0x20, 0xba, 0xad,
-	0x17, 0x96, 0x25, 0x5b, 0xca, 0x95, 0xec, 0x8d, 0xe1, 0xa6, 0x17, 0x0c, 0xb7, 0x7d, 0x68, 0x08,
-	0xe3, 0x65, 0x6e, 0x90, 0xd5, 0x85, 0x6e, 0x24, 0x54, 0x59, 0x94, 0xe5, 0xb5, 0x28, 0x87, 0xd0,
-	0xa4, 0x44, 0x09, 0xe3, 0x7e, 0x96, 0xb5, 0xfa, 0xb0, 0x2e, 0xe3, 0xa4, 0x8f, 0xb4, 0x98, 0xbf,
-	0x69, 0x60, 0x58, 0xf1, 0x0c, 0x6f, 0xa0, 0x4a, 0x0b, 0xa0, 0x74, 0x5b, 0x01, 0xe8, 0xff, 0x51,
-	0x00, 0xef, 0x43, 0x45, 0xce, 0x7a, 0x01, 0xaa, 0x35, 0x6c, 0xa4, 0x04, 0x23, 0xe7, 0x16, 0xd9,
-	0x64, 0x13, 0xbb, 0x7e, 0xe8, 0x46, 0x0b, 0x01, 0xaf, 0x6c, 0xa5, 0xb2, 0x79, 0x00, 0x55, 0x02,
-	0xc9, 0xde, 0x85, 0xcd, 0x64, 0x98, 0xf1, 0xc0, 0x76, 0x54, 0x4d, 0x2e, 0x15, 0xe6, 0x77, 0xd0,
-	0x3c, 0x15, 0xff, 0x04, 0x75, 0x47, 0xef, 0x81, 0x11, 0xc6, 0x33, 0x24, 0xe0, 0x40, 0x39, 0xc6,
-	0x33, 0xb4, 0x84, 0xfe, 0xee, 0x95, 0xd3, 0x86, 0x96, 0xda, 0x99, 0x8a, 0xf3, 0x73, 0x68, 0xca,
-	0x3f, 0xdf, 0x6b, 0xff, 0x43, 0xdb, 0xd0, 0x52, 0x3b, 0xd1, 0xde, 0x1f, 0x43, 0x3d, 0xf9, 0xd3,
-	0x17, 0xbc, 0x10, 0xd6, 0xef, 0xf4, 0x21, 0x34, 0x64, 0x1c, 0x5d, 0xfc, 0x1e, 0x94, 0x13, 0x98,
-	0xea, 0x59, 0x90, 0xc5, 0x2f, 0x0d, 0xe6, 0xaf, 0x1a, 0x6c, 0x9f, 0x4e, 0x6d, 0xef, 0x25, 0x7e,
-	0x2d, 0xaa, 0xf5, 0x36, 0x30, 0xf7, 0x01, 0xfc, 0xd9, 0xe4, 0x32, 0x57, 0xe0, 0x9b, 0xfe, 0x6c,
-	0x22, 0xa3, 0x12, 0xb3, 0x87, 0xaf, 0x94, 0x59, 0xa7, 0x7b, 0xc1, 0x57, 0x64, 0xce, 0x00, 0x30,
-	0xd6, 0x02, 0xd8, 0x85, 0x4e, 0x3e, 0x1b, 0x09, 0xe4, 0x61, 0x1f, 0x2a, 0xb2, 0x5e, 0x58, 0x1d,
-	0xaa, 0xdf, 0x8c, 0xbf, 0x18, 0x5f, 0x7c, 0x3b, 0x6e, 0x6f, 0x24, 0xc2, 0xb9, 0x75, 0x32, 0x7e,
-	0x3e, 0x3a, 0x6b, 0x6b, 0x0c, 0xa0, 0x72, 0x36, 0x1a, 0x3f, 0x1b, 0x9d, 0xb5, 0x4b, 0xc3, 0xbf,
-	0x34, 0x30, 0x4e, 0xe2, 0x68, 0xca, 0x8e, 0xa1, 0xa6, 0x26, 0x33, 0xdb, 0x29, 0xfc, 0x35, 0xf5,
-	0x76, 0x57, 0xd5, 0x74, 0x09, 0x1b, 0xec, 0x31, 0x54, 0x69, 0x5c, 0xb0, 0x8e, 0x74, 0xca, 0x8f,
-	0xa3, 0xde, 0xce, 0x8a, 0x36, 0x8d, 0x1c, 0xaa, 0xc7, 0x0f, 0xcb, 0xf6, 0x1a, 0x45, 0x6d, 0xe7,
-	0x74, 0x2a, 0x66, 0xf8, 0xb7, 0x06, 0x35, 0xf5, 0xb6, 0x63, 0x4f, 0xc1, 0x48, 0x6e, 0x92, 0xbd,
-	0x23, 0x7d, 0x0b, 0xde, 0x8d, 0xbd, 0x5e, 0x91, 0x29, 0xcd, 0xe0, 0x14, 0x2a, 0xb2, 0xa8, 0x18,
-	0xf9, 0x15, 0xbd, 0xfb, 0x7a, 0xf7, 0x0a, 0x6d, 0xe9, 0x26, 0xe7, 0xd0, 0xc8, 0x5e, 0x87, 0xca,
-	0xa6, 0xa0, 0x60, 0x54, 0x36, 0x45, 0xb7, 0x67, 0x6e, 0x0c, 0x7f, 0xd7, 0xa0, 0x9c, 0x94, 0x1d,
-	0x67, 0x8f, 0xa0, 0x22, 0x1b, 0x89, 0x11, 0x0d, 0xb9, 0x86, 0xed, 0x75, 0xf2, 0xca, 0x34, 0x93,
-	0x47, 0x29, 0x9c, 0xed, 0x6c, 0xca, 0x2b, 0x61, 0x2b, 0x6d, 0xb4, 0xc1, 0x06, 0x44, 0xe3, 0x5b,
-	0x4b, 0xae, 0x54, 0x08, 0xcb, 0xaa, 0x54, 0xc0, 0x67, 0x47, 0xdf, 0x7f, 0xf4, 0xd2, 0x8d, 0xa6,
-	0xf1, 0x8b, 0xbe, 0xe3, 0x5f, 0x0f, 0xae, 0x5d, 0x27, 0xf4, 0xe9, 0x3b, 0x3f, 0x1a, 0xdc, 0x7c,
-	0xe4, 0x1f, 0x27, 0xcb, 0x17, 0x15, 0xb1, 0x3e, 0xfa, 0x37, 0x00, 0x00, 0xff, 0xff, 0xa5, 0x1f,
-	0xe0, 0x03, 0x06, 0x0c, 0x00, 0x00,
+func init() {
+	proto.RegisterFile("github.com/micro/micro/service/auth/proto/auth.proto", fileDescriptor_e68f8b0d79fcf05e)
+}
+
+var fileDescriptor_e68f8b0d79fcf05e = []byte{
+	// 1548 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xc4, 0x58, 0x4d, 0x73, 0x1b, 0x45,
+	0x13, 0xce, 0xae, 0x3e, 0xdd, 0xfa, 0xb0, 0x3c, 0x52, 0x6c, 0x65, 0xf3, 0xbe, 0x29, 0x67, 0xf3,
+	0xbe, 0x95, 0x8f, 0x83, 0x0d, 0x0a, 0x09, 0x21, 0x26, 0xa4, 0x84, 0xec, 0x32, 0xae, 0x80, 0x9c,
+	0x5a, 0x12, 0xa0, 0xb8, 0xb8, 0x36, 0xab, 0x49, 0xbc, 0x15, 0x59, 0x2b, 0x76, 0x76, 0x1d, 0xc4,
+	0x8d, 0x23, 0x55, 0x1c, 0xe0, 0xca, 0x0f, 0x00, 0xae, 0xfc, 0x19, 0xaa, 0xf8, 0x07, 0x54, 0xf1,
+	0x27, 0xa8, 0x9d, 0xe9, 0x19, 0xcd, 0x4a, 0x2b, 0xc5, 0x26, 0x07, 0x2e, 0xf6, 0x4c, 0xf7, 0x74,
+	0x4f, 0xf7, 0x33, 0xfd, 0xb5, 0x82, 0x77, 0x5e, 0xf8, 0xd1, 0x71, 0xfc, 0x6c, 0xcb, 0x0b, 0x4e,
+	0xb6, 0x4f, 0x7c, 0x2f, 0x0c, 0xf0, 0x2f, 0xa3, 0xe1, 0xa9, 0xef, 0xd1, 0x6d, 0x37, 0x8e, 0x8e,
+	0xb7, 0xc7, 0x61, 0x10, 0x05, 0x7c, 0xb9, 0xc5, 0x97, 0x24, 0x9f, 0xac, 0xed, 0x0f, 0xa0, 0xf9,
+	0xb1, 0xcf, 0xa2, 0xae, 0xe7, 0x05, 0xf1, 0x28, 0x62, 0x0e, 0xfd, 0x2a, 0xa6, 0x2c, 0x22, 0xd7,
+	0xa1, 0x14, 0x8c, 0x23, 0x3f, 0x18, 0xb1, 0xb6, 0xb1, 0x69, 0xdc, 0xa8, 0x74, 0x6a, 0x5b, 0x5c,
+	0xf4, 0x50, 0x10, 0x1d, 0xc9, 0xb5, 0xbb, 0xd0, 0x4a, 0xcb, 0xb3, 0x71, 0x30, 0x62, 0x94, 0xdc,
+	0x84, 0xb2, 0x8b, 0xb4, 0xb6, 0xb1, 0x99, 0x9b, 0x6a, 0xc0, 0x93, 0x8e, 0x62, 0xdb, 0x87, 0xd0,
+	0xda, 0xa5, 0x43, 0x1a, 0x51, 0xc9, 0x42, 0x1b, 0xea, 0x60, 0xfa, 0x03, 0x7e, 0xfd, 0x8a, 0x63,
+	0xfa, 0x03, 0xdd, 0x26, 0x73, 0xa9, 0x4d, 0x1b, 0x70, 0x71, 0x46, 0xa1, 0x30, 0xca, 0xfe, 0xd6,
+	0x80, 0xc2, 0x93, 0xe0, 0x25, 0x1d, 0x91, 0xab, 0x50, 0x75, 0x3d, 0x8f, 0x32, 0x76, 0x14, 0x25,
+	0x7b, 0xbc, 0xa5, 0x22, 0x68, 0xe2, 0xc8, 0x35, 0xa8, 0x85, 0xf4, 0x79, 0x48, 0xd9, 0x31, 0x9e,
+	0x31, 0xf9, 0x99, 0x2a, 0x12, 0xc5, 0xa1, 0x36, 0x94, 0xbc, 0x90, 0xba, 0x11, 0x1d, 0xb4, 0x73,
+	0x9b, 0xc6, 0x8d, 0x9c, 0x23, 0xb7, 0x64, 0x1d, 0x8a, 0xf4, 0xeb, 0xb1, 0x1f, 0x4e, 0xda, 0x79,
+	0xce, 0xc0, 0x9d, 0xfd, 0x97, 0x01, 0x25, 0xb4, 0x6b, 0xce, 0x43, 0x02, 0xf9, 0x68, 0x32, 0xa6,
+	0x78, 0x13, 0x5f, 0x93, 0x77, 0xa1, 0x7c, 0x42, 0x23, 0x77, 0xe0, 0x46, 0x6e, 0x3b, 0xcf, 0x81,
+	0xbc, 0x9c, 0x02, 0x72, 0xeb, 0x13, 0xe4, 0xee, 0x8d, 0xa2, 0x70, 0xe2, 0xa8, 0xc3, 0x89, 0x01,
+	0xcc, 0x0b, 0xc6, 0x94, 0xb5, 0x0b, 0x9b, 0xb9, 0x1b, 0x2b, 0x0e, 0xee, 0x12, 0xba, 0xcf, 0x58,
+	0x4c, 0xc3, 0x76, 0x91, 0x5f, 0x83, 0x3b, 0x7e, 0x9e, 0x7a, 0x21, 0x8d, 0xda, 0x25, 0x41, 0x17,
+	0x3b, 0x6b, 0x07, 0x6a, 0xa9, 0x2b, 0x48, 0x03, 0x72, 0x2f, 0xe9, 0x04, 0xcd, 0x4e, 0x96, 0xa4,
+	0x05, 0x85, 0x53, 0x77, 0x18, 0x4b, 0xc3, 0xc5, 0xe6, 0xbe, 0x79, 0xcf, 0xb0, 0xfb, 0x50, 0x76,
+	0x28, 0x0b, 0xe2, 0xd0, 0xa3, 0x89, 0x77, 0x23, 0xf7, 0x84, 0xa2, 0x20, 0x5f, 0x67, 0x7a, 0x6c,
+	0x41, 0x99, 0x8e, 0x06, 0xe3, 0xc0, 0x1f, 0x45, 0x1c, 0xd4, 0x15, 0x47, 0xed, 0xed, 0x5f, 0x4d,
+	0x58, 0xdd, 0xa7, 0x23, 0x1a, 0xba, 0x11, 0x5d, 0x14, 0x27, 0x0f, 0x35, 0xc4, 0x72, 0x1c, 0xb1,
+	0x6b, 0x02, 0xb1, 0x19, 0xc1, 0x33, 0x20, 0x97, 0x9f, 0x45, 0x0e, 0x11, 0x2a, 0xe8, 0x08, 0x29,
+	0x27, 0x8a, 0x69, 0x27, 0xc6, 0x61, 0x70, 0xea, 0x0f, 0x68, 0x88, 0x78, 0xaa, 0xbd, 0x1e, 0xc8,
+	0xe5, 0x65, 0x81, 0xfc, 0x66, 0xd0, 0xef, 0x40, 0x63, 0xea, 0x30, 0x66, 0xe5, 0x75, 0x28, 0x61,
+	0xda, 0xa5, 0xd3, 0x5a, 0x26, 0x8a, 0xe4, 0xda, 0x13, 0xa8, 0xee, 0x87, 0xee, 0x34, 0x17, 0x5b,
+	0x50, 0xe0, 0x20, 0xe0, 0xd5, 0x62, 0x43, 0x6e, 0x41, 0x39, 0xc4, 0xd7, 0xc5, 0x94, 0xac, 0x0b,
+	0x7d, 0xf2, 0xcd, 0x1d, 0xc5, 0xd7, 0x9d, 0xce, 0x2d, 0xcd, 0xde, 0x55, 0xa8, 0xe1, 0xd5, 0x98,
+	0xb5, 0xdf, 0x40, 0xcd, 0xa1, 0xa7, 0xc1, 0x4b, 0xfa, 0x2f, 0x18, 0xd3, 0x80, 0xba, 0xbc, 0x1b,
+	0xad, 0x39, 0x84, 0xfa, 0xc1, 0x88, 0x8d, 0xa9, 0xa7, 0x63, 0xa3, 0x17, 0x11, 0xb1, 0x39, 0x7b,
+	0xb5, 0xba, 0x0f, 0xab, 0x4a, 0xe1, 0x79, 0x9f, 0xe9, 0x17, 0x03, 0xaa, 0xbc, 0x10, 0x2d, 0xca,
+	0x85, 0x69, 0xc8, 0x9a, 0xa9, 0x90, 0x9d, 0x2b, 0x6e, 0xb9, 0x8c, 0xe2, 0x76, 0x15, 0xaa, 0x9c,
+	0x79, 0x94, 0x2a, 0x64, 0x15, 0x4e, 0xdb, 0xe3, 0x24, 0xdd, 0xcb, 0xc2, 0x52, 0x2f, 0x3b, 0x50,
+	0x43, 0x43, 0xd1, 0xc7, 0xab, 0x3a, 0x6a, 0x95, 0x4e, 0x45, 0xc8, 0x89, 0x33, 0x82, 0x63, 0xff,
+	0x64, 0x40, 0xde, 0x89, 0x87, 0x74, 0xce, 0x2b, 0x15, 0x00, 0xe6, 0xa2, 0x00, 0xc8, 0xbd, 0x26,
+	0x00, 0xfe, 0x07, 0x45, 0x51, 0xeb, 0xb9, 0x53, 0xf5, 0x4e, 0x55, 0x01, 0x4c, 0x19, 0x73, 0x90,
+	0x27, 0x92, 0xd8, 0x0f, 0x42, 0x3f, 0x9a, 0x70, 0xf7, 0x0a, 0x8e, 0xda, 0xdb, 0xd7, 0xa1, 0x84,
+	0x4e, 0x92, 0xff, 0xc0, 0x4a, 0x52, 0xcc, 0xd8, 0xd8, 0xf5, 0x64, 0x4c, 0x4e, 0x09, 0xf6, 0x17,
+	0x50, 0xeb, 0xf1, 0x9e, 0x20, 0xdf, 0xe8, 0x0a, 0xe4, 0xc3, 0x78, 0x48, 0xd1, 0x71, 0x40, 0x1b,
+	0xe3, 0x21, 0x75, 0x38, 0xfd, 0xec, 0x91, 0xd3, 0x80, 0xba, 0xd4, 0x8c, 0xc1, 0xf9, 0x11, 0xd4,
+	0x44, 0xe7, 0x7b, 0xe3, 0x1e, 0xda, 0x80, 0xba, 0xd4, 0x84, 0xba, 0xef, 0x42, 0x25, 0xe9, 0xf4,
+	0x19, 0x13, 0xc2, 0x72, 0x4d, 0x6f, 0x41, 0x55, 0xc8, 0xe1, 0xc3, 0x6f, 0x42, 0x21, 0x71, 0x53,
+	0x8e, 0x05, 0xba, 0xff, 0x82, 0x61, 0x7f, 0x6f, 0x40, 0xb3, 0x77, 0xec, 0x8e, 0x5e, 0xd0, 0x4f,
+	0x79, 0xb4, 0x2e, 0x72, 0xe6, 0xbf, 0x00, 0xc1, 0x70, 0x70, 0x94, 0x0a, 0xf0, 0x95, 0x60, 0x38,
+	0x10, 0x52, 0x09, 0x7b, 0x44, 0x5f, 0x49, 0x76, 0x0e, 0xdf, 0x85, 0xbe, 0x42, 0xb6, 0xe6, 0x40,
+	0x7e, 0xa9, 0x03, 0xeb, 0xd0, 0x4a, 0x5b, 0x83, 0x80, 0xfc, 0x6e, 0x40, 0xb1, 0xfb, 0xf8, 0xe0,
+	0x11, 0x9d, 0x64, 0x59, 0x86, 0x29, 0x7a, 0xe4, 0x0f, 0xa4, 0x65, 0x48, 0x39, 0x18, 0x90, 0x4d,
+	0xa8, 0x0c, 0x28, 0xf3, 0x42, 0x9f, 0xdf, 0x80, 0xa6, 0xe9, 0xa4, 0x85, 0x2d, 0x48, 0x9b, 0x37,
+	0x0a, 0x8b, 0xe6, 0x8d, 0xa2, 0x3e, 0x6f, 0x90, 0xcb, 0xb0, 0x32, 0x74, 0x59, 0x74, 0x14, 0x33,
+	0x3a, 0xe0, 0x9d, 0x28, 0xe7, 0x94, 0x13, 0xc2, 0x53, 0x46, 0x79, 0x22, 0xc5, 0xcc, 0x7d, 0x41,
+	0x79, 0x1f, 0xca, 0x39, 0x62, 0x63, 0xff, 0x90, 0xe0, 0xcf, 0xd5, 0x0a, 0xf7, 0x24, 0xfe, 0x33,
+	0x66, 0x1b, 0xcb, 0xcc, 0x36, 0x67, 0x3b, 0x27, 0x1a, 0x97, 0x4b, 0x19, 0x77, 0xe6, 0x37, 0x38,
+	0x84, 0x56, 0xda, 0x22, 0x0c, 0xa6, 0xff, 0x43, 0xc9, 0x1d, 0xfb, 0x47, 0xb2, 0x29, 0x56, 0x54,
+	0x22, 0x8b, 0x63, 0x45, 0x77, 0xec, 0x3f, 0xa2, 0xaa, 0x6f, 0x9a, 0xaa, 0x6f, 0xda, 0x0f, 0x80,
+	0xf0, 0xb9, 0x95, 0x9f, 0x3b, 0xff, 0xd8, 0x2b, 0xc7, 0x66, 0x29, 0xae, 0x0a, 0x77, 0x19, 0xcd,
+	0x91, 0xe1, 0x9d, 0xb6, 0xa7, 0x24, 0xec, 0x61, 0x76, 0x1f, 0x9a, 0xa2, 0xaf, 0xa4, 0x11, 0xfe,
+	0xc7, 0xe9, 0xba, 0x0e, 0xad, 0xb4, 0x3e, 0x8c, 0xd1, 0xc7, 0xd0, 0xfc, 0x8c, 0x86, 0xfe, 0xf3,
+	0x49, 0xfa, 0x9e, 0xf9, 0x39, 0xe2, 0xcc, 0x37, 0x3d, 0x83, 0x56, 0x5a, 0xe3, 0x39, 0x7b, 0xd6,
+	0xb4, 0xf0, 0x9b, 0x0b, 0x0b, 0xff, 0x77, 0x06, 0xe4, 0x7b, 0x34, 0x8c, 0x44, 0xfb, 0x0a, 0x7d,
+	0x77, 0x88, 0xa6, 0xe2, 0xee, 0x75, 0xf9, 0x75, 0xee, 0xa9, 0x3c, 0x91, 0x08, 0x39, 0x7e, 0x22,
+	0xaf, 0xca, 0x8e, 0xdc, 0xda, 0xef, 0x43, 0xe3, 0x80, 0xb1, 0x98, 0x26, 0xf6, 0x68, 0xf0, 0x79,
+	0x2c, 0xe4, 0x36, 0x55, 0x9d, 0x64, 0xa9, 0xe9, 0x35, 0x53, 0xd3, 0x3e, 0x85, 0x35, 0x4d, 0x1a,
+	0xa1, 0xba, 0x02, 0x79, 0x8f, 0x86, 0x51, 0xba, 0x01, 0xf0, 0x13, 0x9c, 0x9e, 0xe4, 0x59, 0xf2,
+	0xdf, 0x7f, 0xee, 0x7b, 0x6e, 0x24, 0x9a, 0x5c, 0xd5, 0xd1, 0x49, 0x49, 0x9c, 0x78, 0x2e, 0xf7,
+	0xad, 0xea, 0x98, 0x9e, 0x9b, 0xcc, 0x7a, 0x49, 0x38, 0x26, 0x3a, 0xce, 0x1f, 0xcb, 0x77, 0x60,
+	0x4d, 0x13, 0x9e, 0x56, 0xe9, 0xe4, 0xc2, 0x99, 0x2a, 0xcd, 0x8d, 0x14, 0x0c, 0xfb, 0x09, 0xac,
+	0x89, 0x90, 0xd3, 0x91, 0x59, 0xf4, 0x60, 0x67, 0x0e, 0xaf, 0x16, 0x10, 0x5d, 0x2b, 0x86, 0x71,
+	0x05, 0x56, 0x7a, 0x5d, 0xbc, 0xc3, 0xbe, 0x0b, 0xd0, 0xeb, 0x4a, 0x16, 0x42, 0x61, 0x48, 0x28,
+	0xf4, 0x97, 0x14, 0x35, 0x48, 0x6e, 0x6f, 0x6d, 0x41, 0x51, 0xf4, 0x77, 0x52, 0x81, 0xd2, 0xd3,
+	0xfe, 0xa3, 0xfe, 0xe1, 0xe7, 0xfd, 0xc6, 0x85, 0x64, 0xb3, 0xef, 0x74, 0xfb, 0x4f, 0xf6, 0x76,
+	0x1b, 0x06, 0x01, 0x28, 0xee, 0xee, 0xf5, 0x0f, 0xf6, 0x76, 0x1b, 0x66, 0xe7, 0x37, 0x03, 0xf2,
+	0xdd, 0x38, 0x3a, 0x26, 0x3b, 0x50, 0x96, 0x93, 0x34, 0xb9, 0x98, 0xf9, 0x29, 0x61, 0xad, 0xcf,
+	0x92, 0xd1, 0xf0, 0x0b, 0xe4, 0x1e, 0x94, 0x70, 0xbc, 0x23, 0x2d, 0x71, 0x28, 0x3d, 0x3e, 0x5a,
+	0x17, 0x67, 0xa8, 0x4a, 0xb2, 0x23, 0x3f, 0x56, 0x89, 0x9e, 0x22, 0x28, 0xd5, 0x4c, 0xd1, 0xa4,
+	0x4c, 0xe7, 0x0f, 0x03, 0xca, 0xf2, 0x5b, 0x9c, 0x3c, 0x84, 0x7c, 0xf2, 0xb0, 0xe4, 0x92, 0x38,
+	0x9b, 0xf1, 0x9d, 0x6f, 0x59, 0x59, 0x2c, 0x65, 0x41, 0x0f, 0x8a, 0x62, 0x08, 0x20, 0x78, 0x2e,
+	0xeb, 0x3b, 0xdd, 0xba, 0x9c, 0xc9, 0x53, 0x4a, 0xf6, 0xa1, 0xaa, 0xb7, 0x4f, 0x69, 0x4d, 0x46,
+	0x83, 0xb7, 0xac, 0x2c, 0x96, 0xf2, 0xed, 0x47, 0x13, 0x4a, 0x58, 0x70, 0x49, 0x17, 0x8a, 0xa2,
+	0x1f, 0x28, 0x75, 0xf3, 0xfd, 0xca, 0xb2, 0xb2, 0x58, 0xca, 0xae, 0x07, 0x88, 0x4e, 0x5b, 0x83,
+	0x20, 0xd5, 0x0d, 0xac, 0x4b, 0x19, 0x1c, 0x25, 0xde, 0x85, 0xa2, 0x08, 0x54, 0x69, 0x41, 0x46,
+	0x3d, 0xb7, 0xac, 0x2c, 0x96, 0xae, 0x42, 0x94, 0x52, 0xa9, 0x22, 0xa3, 0x54, 0x5b, 0x56, 0x16,
+	0x4b, 0x61, 0xf2, 0xa7, 0x01, 0x05, 0x9e, 0xb8, 0xe4, 0x3e, 0x14, 0x78, 0xa5, 0x21, 0x18, 0x8a,
+	0xb3, 0x45, 0xcb, 0xda, 0x98, 0xa3, 0x2b, 0x43, 0xde, 0x43, 0x28, 0xd6, 0xa7, 0x0e, 0xeb, 0xa5,
+	0xc4, 0xda, 0x98, 0xa3, 0x6b, 0x28, 0x4a, 0x18, 0x36, 0x74, 0x5f, 0xf5, 0x8b, 0xdb, 0xf3, 0x0c,
+	0x25, 0x7e, 0x13, 0xcc, 0x5e, 0x97, 0xac, 0xe2, 0x43, 0xc9, 0x14, 0xb7, 0x1a, 0x53, 0x82, 0x72,
+	0xf5, 0x67, 0x03, 0x0a, 0xc9, 0x94, 0xc8, 0xc8, 0x1d, 0xf5, 0xf8, 0x4d, 0xfd, 0x85, 0xa5, 0x70,
+	0x2b, 0x4d, 0x54, 0x77, 0xdd, 0x51, 0xd1, 0xdc, 0xd4, 0x23, 0x76, 0x46, 0x6c, 0x66, 0xea, 0xbd,
+	0x40, 0xb6, 0x11, 0x9c, 0xb5, 0x29, 0x08, 0x52, 0x84, 0xe8, 0x24, 0x29, 0xf0, 0xe1, 0xed, 0x2f,
+	0xdf, 0x5e, 0xf0, 0x83, 0xdc, 0xe9, 0xed, 0x8c, 0xdf, 0xe4, 0x76, 0x92, 0xe5, 0xb3, 0x22, 0x5f,
+	0xdf, 0xfe, 0x7b, 0x00, 0xf6, 0x0a, 0x3b, 0x3c, 0xcc, 0x13, 0x00, 0x00,
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -1591,7 +2520,7 @@ var _Auth_serviceDesc = grpc.ServiceDesc{
 		},
 	},
 	Streams:  []grpc.StreamDesc{},
-	Metadata: "service/auth/proto/auth.proto",
+	Metadata: "github.com/micro/micro/service/auth/proto/auth.proto",
 }
 
 // AccountsClient is the client API for Accounts service.
@@ -1735,7 +2664,367 @@ var _Accounts_serviceDesc = grpc.ServiceDesc{
 		},
 	},
 	Streams:  []grpc.StreamDesc{},
-	Metadata: "service/auth/proto/auth.proto",
+	Metadata: "github.com/micro/micro/service/auth/proto/auth.proto",
+}
+
+// APIKeysClient is the client API for APIKeys service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type APIKeysClient interface {
+	Create(ctx context.Context, in *CreateAPIKeyRequest, opts ...grpc.CallOption) (*CreateAPIKeyResponse, error)
+	List(ctx context.Context, in *ListAPIKeysRequest, opts ...grpc.CallOption) (*ListAPIKeysResponse, error)
+	Revoke(ctx context.Context, in *RevokeAPIKeyRequest, opts ...grpc.CallOption) (*RevokeAPIKeyResponse, error)
+	Verify(ctx context.Context, in *VerifyAPIKeyRequest, opts ...grpc.CallOption) (*VerifyAPIKeyResponse, error)
+}
+
+type aPIKeysClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewAPIKeysClient(cc *grpc.ClientConn) APIKeysClient {
+	return &aPIKeysClient{cc}
+}
+
+func (c *aPIKeysClient) Create(ctx context.Context, in *CreateAPIKeyRequest, opts ...grpc.CallOption) (*CreateAPIKeyResponse, error) {
+	out := new(CreateAPIKeyResponse)
+	err := c.cc.Invoke(ctx, "/auth.APIKeys/Create", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *aPIKeysClient) List(ctx context.Context, in *ListAPIKeysRequest, opts ...grpc.CallOption) (*ListAPIKeysResponse, error) {
+	out := new(ListAPIKeysResponse)
+	err := c.cc.Invoke(ctx, "/auth.APIKeys/List", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *aPIKeysClient) Revoke(ctx context.Context, in *RevokeAPIKeyRequest, opts ...grpc.CallOption) (*RevokeAPIKeyResponse, error) {
+	out := new(RevokeAPIKeyResponse)
+	err := c.cc.Invoke(ctx, "/auth.APIKeys/Revoke", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *aPIKeysClient) Verify(ctx context.Context, in *VerifyAPIKeyRequest, opts ...grpc.CallOption) (*VerifyAPIKeyResponse, error) {
+	out := new(VerifyAPIKeyResponse)
+	err := c.cc.Invoke(ctx, "/auth.APIKeys/Verify", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// APIKeysServer is the server API for APIKeys service.
+type APIKeysServer interface {
+	Create(context.Context, *CreateAPIKeyRequest) (*CreateAPIKeyResponse, error)
+	List(context.Context, *ListAPIKeysRequest) (*ListAPIKeysResponse, error)
+	Revoke(context.Context, *RevokeAPIKeyRequest) (*RevokeAPIKeyResponse, error)
+	Verify(context.Context, *VerifyAPIKeyRequest) (*VerifyAPIKeyResponse, error)
+}
+
+// UnimplementedAPIKeysServer can be embedded to have forward compatible implementations.
+type UnimplementedAPIKeysServer struct {
+}
+
+func (*UnimplementedAPIKeysServer) Create(ctx context.Context, req *CreateAPIKeyRequest) (*CreateAPIKeyResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Create not implemented")
+}
+func (*UnimplementedAPIKeysServer) List(ctx context.Context, req *ListAPIKeysRequest) (*ListAPIKeysResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method List not implemented")
+}
+func (*UnimplementedAPIKeysServer) Revoke(ctx context.Context, req *RevokeAPIKeyRequest) (*RevokeAPIKeyResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Revoke not implemented")
+}
+func (*UnimplementedAPIKeysServer) Verify(ctx context.Context, req *VerifyAPIKeyRequest) (*VerifyAPIKeyResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Verify not implemented")
+}
+
+func RegisterAPIKeysServer(s *grpc.Server, srv APIKeysServer) {
+	s.RegisterService(&_APIKeys_serviceDesc, srv)
+}
+
+func _APIKeys_Create_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateAPIKeyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(APIKeysServer).Create(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/auth.APIKeys/Create",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(APIKeysServer).Create(ctx, req.(*CreateAPIKeyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _APIKeys_List_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListAPIKeysRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(APIKeysServer).List(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/auth.APIKeys/List",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(APIKeysServer).List(ctx, req.(*ListAPIKeysRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _APIKeys_Revoke_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RevokeAPIKeyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(APIKeysServer).Revoke(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/auth.APIKeys/Revoke",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(APIKeysServer).Revoke(ctx, req.(*RevokeAPIKeyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _APIKeys_Verify_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(VerifyAPIKeyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(APIKeysServer).Verify(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/auth.APIKeys/Verify",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(APIKeysServer).Verify(ctx, req.(*VerifyAPIKeyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _APIKeys_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "auth.APIKeys",
+	HandlerType: (*APIKeysServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Create",
+			Handler:    _APIKeys_Create_Handler,
+		},
+		{
+			MethodName: "List",
+			Handler:    _APIKeys_List_Handler,
+		},
+		{
+			MethodName: "Revoke",
+			Handler:    _APIKeys_Revoke_Handler,
+		},
+		{
+			MethodName: "Verify",
+			Handler:    _APIKeys_Verify_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "github.com/micro/micro/service/auth/proto/auth.proto",
+}
+
+// CertsClient is the client API for Certs service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type CertsClient interface {
+	Issue(ctx context.Context, in *IssueCertRequest, opts ...grpc.CallOption) (*IssueCertResponse, error)
+	List(ctx context.Context, in *ListCertsRequest, opts ...grpc.CallOption) (*ListCertsResponse, error)
+	Revoke(ctx context.Context, in *RevokeCertRequest, opts ...grpc.CallOption) (*RevokeCertResponse, error)
+	CA(ctx context.Context, in *CARequest, opts ...grpc.CallOption) (*CAResponse, error)
+}
+
+type certsClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewCertsClient(cc *grpc.ClientConn) CertsClient {
+	return &certsClient{cc}
+}
+
+func (c *certsClient) Issue(ctx context.Context, in *IssueCertRequest, opts ...grpc.CallOption) (*IssueCertResponse, error) {
+	out := new(IssueCertResponse)
+	err := c.cc.Invoke(ctx, "/auth.Certs/Issue", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *certsClient) List(ctx context.Context, in *ListCertsRequest, opts ...grpc.CallOption) (*ListCertsResponse, error) {
+	out := new(ListCertsResponse)
+	err := c.cc.Invoke(ctx, "/auth.Certs/List", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *certsClient) Revoke(ctx context.Context, in *RevokeCertRequest, opts ...grpc.CallOption) (*RevokeCertResponse, error) {
+	out := new(RevokeCertResponse)
+	err := c.cc.Invoke(ctx, "/auth.Certs/Revoke", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *certsClient) CA(ctx context.Context, in *CARequest, opts ...grpc.CallOption) (*CAResponse, error) {
+	out := new(CAResponse)
+	err := c.cc.Invoke(ctx, "/auth.Certs/CA", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// CertsServer is the server API for Certs service.
+type CertsServer interface {
+	Issue(context.Context, *IssueCertRequest) (*IssueCertResponse, error)
+	List(context.Context, *ListCertsRequest) (*ListCertsResponse, error)
+	Revoke(context.Context, *RevokeCertRequest) (*RevokeCertResponse, error)
+	CA(context.Context, *CARequest) (*CAResponse, error)
+}
+
+// UnimplementedCertsServer can be embedded to have forward compatible implementations.
+type UnimplementedCertsServer struct {
+}
+
+func (*UnimplementedCertsServer) Issue(ctx context.Context, req *IssueCertRequest) (*IssueCertResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Issue not implemented")
+}
+func (*UnimplementedCertsServer) List(ctx context.Context, req *ListCertsRequest) (*ListCertsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method List not implemented")
+}
+func (*UnimplementedCertsServer) Revoke(ctx context.Context, req *RevokeCertRequest) (*RevokeCertResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Revoke not implemented")
+}
+func (*UnimplementedCertsServer) CA(ctx context.Context, req *CARequest) (*CAResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CA not implemented")
+}
+
+func RegisterCertsServer(s *grpc.Server, srv CertsServer) {
+	s.RegisterService(&_Certs_serviceDesc, srv)
+}
+
+func _Certs_Issue_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(IssueCertRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CertsServer).Issue(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/auth.Certs/Issue",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CertsServer).Issue(ctx, req.(*IssueCertRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Certs_List_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListCertsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CertsServer).List(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/auth.Certs/List",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CertsServer).List(ctx, req.(*ListCertsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Certs_Revoke_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RevokeCertRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CertsServer).Revoke(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/auth.Certs/Revoke",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CertsServer).Revoke(ctx, req.(*RevokeCertRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Certs_CA_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CARequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CertsServer).CA(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/auth.Certs/CA",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CertsServer).CA(ctx, req.(*CARequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _Certs_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "auth.Certs",
+	HandlerType: (*CertsServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Issue",
+			Handler:    _Certs_Issue_Handler,
+		},
+		{
+			MethodName: "List",
+			Handler:    _Certs_List_Handler,
+		},
+		{
+			MethodName: "Revoke",
+			Handler:    _Certs_Revoke_Handler,
+		},
+		{
+			MethodName: "CA",
+			Handler:    _Certs_CA_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "github.com/micro/micro/service/auth/proto/auth.proto",
 }
 
 // RulesClient is the client API for Rules service.
@@ -1879,5 +3168,5 @@ var _Rules_serviceDesc = grpc.ServiceDesc{
 		},
 	},
 	Streams:  []grpc.StreamDesc{},
-	Metadata: "service/auth/proto/auth.proto",
+	Metadata: "github.com/micro/micro/service/auth/proto/auth.proto",
 }
@@ -1,5 +1,5 @@
 // Code generated by protoc-gen-micro. DO NOT EDIT.
-// source: service/auth/proto/auth.proto
+// source: github.com/micro/micro/service/auth/proto/auth.proto
 
 package auth
 
@@ -219,6 +219,226 @@ func (h *accountsHandler) ChangeSecret(ctx context.Context, in *ChangeSecretRequ
 	return h.AccountsHandler.ChangeSecret(ctx, in, out)
 }
 
+// Api Endpoints for APIKeys service
+
+func NewAPIKeysEndpoints() []*api.Endpoint {
+	return []*api.Endpoint{}
+}
+
+// Client API for APIKeys service
+
+type APIKeysService interface {
+	Create(ctx context.Context, in *CreateAPIKeyRequest, opts ...client.CallOption) (*CreateAPIKeyResponse, error)
+	List(ctx context.Context, in *ListAPIKeysRequest, opts ...client.CallOption) (*ListAPIKeysResponse, error)
+	Revoke(ctx context.Context, in *RevokeAPIKeyRequest, opts ...client.CallOption) (*RevokeAPIKeyResponse, error)
+	Verify(ctx context.Context, in *VerifyAPIKeyRequest, opts ...client.CallOption) (*VerifyAPIKeyResponse, error)
+}
+
+type aPIKeysService struct {
+	c    client.Client
+	name string
+}
+
+func NewAPIKeysService(name string, c client.Client) APIKeysService {
+	return &aPIKeysService{
+		c:    c,
+		name: name,
+	}
+}
+
+func (c *aPIKeysService) Create(ctx context.Context, in *CreateAPIKeyRequest, opts ...client.CallOption) (*CreateAPIKeyResponse, error) {
+	req := c.c.NewRequest(c.name, "APIKeys.Create", in)
+	out := new(CreateAPIKeyResponse)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *aPIKeysService) List(ctx context.Context, in *ListAPIKeysRequest, opts ...client.CallOption) (*ListAPIKeysResponse, error) {
+	req := c.c.NewRequest(c.name, "APIKeys.List", in)
+	out := new(ListAPIKeysResponse)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *aPIKeysService) Revoke(ctx context.Context, in *RevokeAPIKeyRequest, opts ...client.CallOption) (*RevokeAPIKeyResponse, error) {
+	req := c.c.NewRequest(c.name, "APIKeys.Revoke", in)
+	out := new(RevokeAPIKeyResponse)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *aPIKeysService) Verify(ctx context.Context, in *VerifyAPIKeyRequest, opts ...client.CallOption) (*VerifyAPIKeyResponse, error) {
+	req := c.c.NewRequest(c.name, "APIKeys.Verify", in)
+	out := new(VerifyAPIKeyResponse)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// Server API for APIKeys service
+
+type APIKeysHandler interface {
+	Create(context.Context, *CreateAPIKeyRequest, *CreateAPIKeyResponse) error
+	List(context.Context, *ListAPIKeysRequest, *ListAPIKeysResponse) error
+	Revoke(context.Context, *RevokeAPIKeyRequest, *RevokeAPIKeyResponse) error
+	Verify(context.Context, *VerifyAPIKeyRequest, *VerifyAPIKeyResponse) error
+}
+
+func RegisterAPIKeysHandler(s server.Server, hdlr APIKeysHandler, opts ...server.HandlerOption) error {
+	type aPIKeys interface {
+		Create(ctx context.Context, in *CreateAPIKeyRequest, out *CreateAPIKeyResponse) error
+		List(ctx context.Context, in *ListAPIKeysRequest, out *ListAPIKeysResponse) error
+		Revoke(ctx context.Context, in *RevokeAPIKeyRequest, out *RevokeAPIKeyResponse) error
+		Verify(ctx context.Context, in *VerifyAPIKeyRequest, out *VerifyAPIKeyResponse) error
+	}
+	type APIKeys struct {
+		aPIKeys
+	}
+	h := &aPIKeysHandler{hdlr}
+	return s.Handle(s.NewHandler(&APIKeys{h}, opts...))
+}
+
+type aPIKeysHandler struct {
+	APIKeysHandler
+}
+
+func (h *aPIKeysHandler) Create(ctx context.Context, in *CreateAPIKeyRequest, out *CreateAPIKeyResponse) error {
+	return h.APIKeysHandler.Create(ctx, in, out)
+}
+
+func (h *aPIKeysHandler) List(ctx context.Context, in *ListAPIKeysRequest, out *ListAPIKeysResponse) error {
+	return h.APIKeysHandler.List(ctx, in, out)
+}
+
+func (h *aPIKeysHandler) Revoke(ctx context.Context, in *RevokeAPIKeyRequest, out *RevokeAPIKeyResponse) error {
+	return h.APIKeysHandler.Revoke(ctx, in, out)
+}
+
+func (h *aPIKeysHandler) Verify(ctx context.Context, in *VerifyAPIKeyRequest, out *VerifyAPIKeyResponse) error {
+	return h.APIKeysHandler.Verify(ctx, in, out)
+}
+
+// Api Endpoints for Certs service
+
+func NewCertsEndpoints() []*api.Endpoint {
+	return []*api.Endpoint{}
+}
+
+// Client API for Certs service
+
+type CertsService interface {
+	Issue(ctx context.Context, in *IssueCertRequest, opts ...client.CallOption) (*IssueCertResponse, error)
+	List(ctx context.Context, in *ListCertsRequest, opts ...client.CallOption) (*ListCertsResponse, error)
+	Revoke(ctx context.Context, in *RevokeCertRequest, opts ...client.CallOption) (*RevokeCertResponse, error)
+	CA(ctx context.Context, in *CARequest, opts ...client.CallOption) (*CAResponse, error)
+}
+
+type certsService struct {
+	c    client.Client
+	name string
+}
+
+func NewCertsService(name string, c client.Client) CertsService {
+	return &certsService{
+		c:    c,
+		name: name,
+	}
+}
+
+func (c *certsService) Issue(ctx context.Context, in *IssueCertRequest, opts ...client.CallOption) (*IssueCertResponse, error) {
+	req := c.c.NewRequest(c.name, "Certs.Issue", in)
+	out := new(IssueCertResponse)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *certsService) List(ctx context.Context, in *ListCertsRequest, opts ...client.CallOption) (*ListCertsResponse, error) {
+	req := c.c.NewRequest(c.name, "Certs.List", in)
+	out := new(ListCertsResponse)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *certsService) Revoke(ctx context.Context, in *RevokeCertRequest, opts ...client.CallOption) (*RevokeCertResponse, error) {
+	req := c.c.NewRequest(c.name, "Certs.Revoke", in)
+	out := new(RevokeCertResponse)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *certsService) CA(ctx context.Context, in *CARequest, opts ...client.CallOption) (*CAResponse, error) {
+	req := c.c.NewRequest(c.name, "Certs.CA", in)
+	out := new(CAResponse)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// Server API for Certs service
+
+type CertsHandler interface {
+	Issue(context.Context, *IssueCertRequest, *IssueCertResponse) error
+	List(context.Context, *ListCertsRequest, *ListCertsResponse) error
+	Revoke(context.Context, *RevokeCertRequest, *RevokeCertResponse) error
+	CA(context.Context, *CARequest, *CAResponse) error
+}
+
+func RegisterCertsHandler(s server.Server, hdlr CertsHandler, opts ...server.HandlerOption) error {
+	type certs interface {
+		Issue(ctx context.Context, in *IssueCertRequest, out *IssueCertResponse) error
+		List(ctx context.Context, in *ListCertsRequest, out *ListCertsResponse) error
+		Revoke(ctx context.Context, in *RevokeCertRequest, out *RevokeCertResponse) error
+		CA(ctx context.Context, in *CARequest, out *CAResponse) error
+	}
+	type Certs struct {
+		certs
+	}
+	h := &certsHandler{hdlr}
+	return s.Handle(s.NewHandler(&Certs{h}, opts...))
+}
+
+type certsHandler struct {
+	CertsHandler
+}
+
+func (h *certsHandler) Issue(ctx context.Context, in *IssueCertRequest, out *IssueCertResponse) error {
+	return h.CertsHandler.Issue(ctx, in, out)
+}
+
+func (h *certsHandler) List(ctx context.Context, in *ListCertsRequest, out *ListCertsResponse) error {
+	return h.CertsHandler.List(ctx, in, out)
+}
+
+func (h *certsHandler) Revoke(ctx context.Context, in *RevokeCertRequest, out *RevokeCertResponse) error {
+	return h.CertsHandler.Revoke(ctx, in, out)
+}
+
+func (h *certsHandler) CA(ctx context.Context, in *CARequest, out *CAResponse) error {
+	return h.CertsHandler.CA(ctx, in, out)
+}
+
 // Api Endpoints for Rules service
 
 func NewRulesEndpoints() []*api.Endpoint {
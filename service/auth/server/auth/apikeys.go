@@ -0,0 +1,473 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/micro/go-micro/v3/auth"
+	gostore "github.com/micro/go-micro/v3/store"
+	"github.com/micro/go-micro/v3/util/token"
+	"github.com/micro/micro/v3/internal/namespace"
+	pb "github.com/micro/micro/v3/service/auth/proto"
+	"github.com/micro/micro/v3/service/errors"
+	"github.com/micro/micro/v3/service/logger"
+)
+
+const (
+	storePrefixAPIKeys = "apikey"
+	// apiKeyPrefix is prefixed to api keys so they can be told apart from tokens
+	apiKeyPrefix = "mk_"
+	// apiKeyType is the type of the accounts api keys authenticate as
+	apiKeyType = "apikey"
+	// apiKeyTokenExpiry is how long the tokens generated when keys are verified are valid for
+	apiKeyTokenExpiry = time.Minute
+	// apiKeyUsageInterval is how often the usage of the keys is written to the store, it's
+	// counted in memory between writes so verifying a key doesn't write to the store
+	apiKeyUsageInterval = time.Second * 10
+)
+
+// APIKeys processes RPC calls for api keys, which are stored using the store of the auth handler
+type APIKeys struct {
+	Auth *Auth
+	// locked while the usage is written or keys are revoked so the usage isn't written to
+	// revoked keys
+	sync.Mutex
+
+	// usage of the keys which hasn't been written to the store yet, keyed by store key
+	usage     map[string]*apiKeyUsage
+	usageMu   sync.Mutex
+	lastFlush time.Time
+}
+
+// apiKeyUsage is the usage of a key since it was last written to the store
+type apiKeyUsage struct {
+	Count    int64
+	LastUsed time.Time
+}
+
+// apiKey is the record of an api key in the store, the key itself isn't stored, only its hash.
+// The issuer is the namespace the key is for, which can differ from the namespace of the account
+// which created it when an admin of the default namespace creates a key for another namespace.
+type apiKey struct {
+	ID            string    `json:"id"`
+	AccountID     string    `json:"account_id"`
+	AccountIssuer string    `json:"account_issuer"`
+	Description   string    `json:"description"`
+	Scopes        []string  `json:"scopes"`
+	Hash          string    `json:"hash"`
+	Issuer        string    `json:"issuer"`
+	Created       time.Time `json:"created"`
+	Expiry        time.Time `json:"expiry"`
+	LastUsed      time.Time `json:"last_used"`
+	Usage         int64     `json:"usage"`
+}
+
+// accountIssuer returns the namespace of the account which created the key, keys created before
+// it was recorded were always created in the namespace of the account
+func (k *apiKey) accountIssuer() string {
+	if len(k.AccountIssuer) > 0 {
+		return k.AccountIssuer
+	}
+	return k.Issuer
+}
+
+// Create issues a key which authenticates as the account creating it
+func (h *APIKeys) Create(ctx context.Context, req *pb.CreateAPIKeyRequest, rsp *pb.CreateAPIKeyResponse) error {
+	// validate the request
+	if req.Expiry < 0 {
+		return errors.BadRequest("auth.APIKeys.Create", "Invalid expiry")
+	}
+
+	// set defaults
+	if req.Options == nil {
+		req.Options = &pb.Options{}
+	}
+	if len(req.Options.Namespace) == 0 {
+		req.Options.Namespace = namespace.DefaultNamespace
+	}
+
+	// authorize the request
+	if err := namespace.Authorize(ctx, req.Options.Namespace); err == namespace.ErrForbidden {
+		return errors.Forbidden("auth.APIKeys.Create", err.Error())
+	} else if err == namespace.ErrUnauthorized {
+		return errors.Unauthorized("auth.APIKeys.Create", err.Error())
+	} else if err != nil {
+		return errors.InternalServerError("auth.APIKeys.Create", err.Error())
+	}
+
+	// the key authenticates as the account which created it, so it can't have scopes the
+	// account doesn't have unless the account is an admin
+	acc, ok := auth.AccountFromContext(ctx)
+	if !ok || len(acc.ID) == 0 {
+		return errors.Unauthorized("auth.APIKeys.Create", "An account is required to create api keys")
+	}
+	if acc.Type == apiKeyType {
+		return errors.Forbidden("auth.APIKeys.Create", "API keys can't create api keys")
+	}
+	scopes := req.Scopes
+	if len(scopes) == 0 {
+		scopes = acc.Scopes
+	}
+	if !hasScope(acc.Scopes, "admin") {
+		for _, s := range scopes {
+			if !hasScope(acc.Scopes, s) {
+				return errors.Forbidden("auth.APIKeys.Create", "Account doesn't have scope %v", s)
+			}
+		}
+	}
+
+	id, err := randomHex(8)
+	if err != nil {
+		return errors.InternalServerError("auth.APIKeys.Create", "Unable to generate key: %v", err)
+	}
+	secret, err := randomHex(32)
+	if err != nil {
+		return errors.InternalServerError("auth.APIKeys.Create", "Unable to generate key: %v", err)
+	}
+
+	key := &apiKey{
+		ID:            id,
+		AccountID:     acc.ID,
+		AccountIssuer: acc.Issuer,
+		Description:   req.Description,
+		Scopes:        scopes,
+		Hash:          hashAPIKey(secret),
+		Issuer:        req.Options.Namespace,
+		Created:       time.Now(),
+	}
+	if req.Expiry > 0 {
+		key.Expiry = key.Created.Add(time.Duration(req.Expiry) * time.Second)
+	}
+	if err := h.writeAPIKey(key); err != nil {
+		return errors.InternalServerError("auth.APIKeys.Create", "Unable to write key to store: %v", err)
+	}
+
+	rsp.ApiKey = serializeAPIKey(key)
+	rsp.Key = apiKeyPrefix + id + "_" + secret
+	return nil
+}
+
+// List returns the api keys in the namespace with their usage
+func (h *APIKeys) List(ctx context.Context, req *pb.ListAPIKeysRequest, rsp *pb.ListAPIKeysResponse) error {
+	// set defaults
+	if req.Options == nil {
+		req.Options = &pb.Options{}
+	}
+	if len(req.Options.Namespace) == 0 {
+		req.Options.Namespace = namespace.DefaultNamespace
+	}
+
+	// authorize the request
+	if err := namespace.Authorize(ctx, req.Options.Namespace); err == namespace.ErrForbidden {
+		return errors.Forbidden("auth.APIKeys.List", err.Error())
+	} else if err == namespace.ErrUnauthorized {
+		return errors.Unauthorized("auth.APIKeys.List", err.Error())
+	} else if err != nil {
+		return errors.InternalServerError("auth.APIKeys.List", err.Error())
+	}
+
+	// accounts can only list their own keys unless they're an admin
+	acc, ok := auth.AccountFromContext(ctx)
+	if !ok {
+		return errors.Unauthorized("auth.APIKeys.List", "An account is required to list api keys")
+	}
+	admin := hasScope(acc.Scopes, "admin")
+
+	// the usage counted since it was last written is included
+	h.flushUsage()
+
+	key := strings.Join([]string{storePrefixAPIKeys, req.Options.Namespace, ""}, joinKey)
+	recs, err := h.Auth.Options.Store.Read(key, gostore.ReadPrefix())
+	if err != nil {
+		return errors.InternalServerError("auth.APIKeys.List", "Unable to read from store: %v", err)
+	}
+
+	keys := make([]*apiKey, 0, len(recs))
+	for _, rec := range recs {
+		var k *apiKey
+		if err := json.Unmarshal(rec.Value, &k); err != nil {
+			return errors.InternalServerError("auth.APIKeys.List", "Unable to unmarshal key: %v", err)
+		}
+		if !admin && k.AccountID != acc.ID {
+			continue
+		}
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i].Created.Before(keys[j].Created) })
+
+	rsp.ApiKeys = make([]*pb.APIKey, 0, len(keys))
+	for _, k := range keys {
+		rsp.ApiKeys = append(rsp.ApiKeys, serializeAPIKey(k))
+	}
+	return nil
+}
+
+// Revoke deletes an api key so it can no longer be used
+func (h *APIKeys) Revoke(ctx context.Context, req *pb.RevokeAPIKeyRequest, rsp *pb.RevokeAPIKeyResponse) error {
+	// validate the request
+	if len(req.Id) == 0 {
+		return errors.BadRequest("auth.APIKeys.Revoke", "Missing ID")
+	}
+
+	// set defaults
+	if req.Options == nil {
+		req.Options = &pb.Options{}
+	}
+	if len(req.Options.Namespace) == 0 {
+		req.Options.Namespace = namespace.DefaultNamespace
+	}
+
+	// authorize the request
+	if err := namespace.Authorize(ctx, req.Options.Namespace); err == namespace.ErrForbidden {
+		return errors.Forbidden("auth.APIKeys.Revoke", err.Error())
+	} else if err == namespace.ErrUnauthorized {
+		return errors.Unauthorized("auth.APIKeys.Revoke", err.Error())
+	} else if err != nil {
+		return errors.InternalServerError("auth.APIKeys.Revoke", err.Error())
+	}
+
+	h.Lock()
+	defer h.Unlock()
+
+	k, err := h.readAPIKey(req.Options.Namespace, req.Id)
+	if err == gostore.ErrNotFound {
+		return errors.NotFound("auth.APIKeys.Revoke", "API key not found with this ID")
+	} else if err != nil {
+		return errors.InternalServerError("auth.APIKeys.Revoke", "Unable to read from store: %v", err)
+	}
+
+	// only admins can revoke the keys of other accounts
+	if acc, ok := auth.AccountFromContext(ctx); !ok || (!hasScope(acc.Scopes, "admin") && k.AccountID != acc.ID) {
+		return errors.Forbidden("auth.APIKeys.Revoke", "Only admins can revoke the api keys of other accounts")
+	}
+
+	key := strings.Join([]string{storePrefixAPIKeys, req.Options.Namespace, req.Id}, joinKey)
+	if err := h.Auth.Options.Store.Delete(key); err != nil {
+		return errors.InternalServerError("auth.APIKeys.Revoke", "Unable to delete key: %v", err)
+	}
+	return nil
+}
+
+// Verify returns the account the api key authenticates as and records its usage
+func (h *APIKeys) Verify(ctx context.Context, req *pb.VerifyAPIKeyRequest, rsp *pb.VerifyAPIKeyResponse) error {
+	// set defaults
+	if req.Options == nil {
+		req.Options = &pb.Options{}
+	}
+	if len(req.Options.Namespace) == 0 {
+		req.Options.Namespace = namespace.DefaultNamespace
+	}
+
+	// authorize the request
+	if err := namespace.Authorize(ctx, req.Options.Namespace); err == namespace.ErrForbidden {
+		return errors.Forbidden("auth.APIKeys.Verify", err.Error())
+	} else if err == namespace.ErrUnauthorized {
+		return errors.Unauthorized("auth.APIKeys.Verify", err.Error())
+	} else if err != nil {
+		return errors.InternalServerError("auth.APIKeys.Verify", err.Error())
+	}
+
+	// keys have the format mk_id_secret
+	parts := strings.Split(strings.TrimPrefix(req.Key, apiKeyPrefix), "_")
+	if !strings.HasPrefix(req.Key, apiKeyPrefix) || len(parts) != 2 {
+		return errors.BadRequest("auth.APIKeys.Verify", "Invalid API key")
+	}
+
+	key, err := h.readAPIKey(req.Options.Namespace, parts[0])
+	if err == gostore.ErrNotFound {
+		return errors.BadRequest("auth.APIKeys.Verify", "Invalid API key")
+	} else if err != nil {
+		return errors.InternalServerError("auth.APIKeys.Verify", "Unable to read from store: %v", err)
+	}
+	if subtle.ConstantTimeCompare([]byte(key.Hash), []byte(hashAPIKey(parts[1]))) != 1 {
+		return errors.BadRequest("auth.APIKeys.Verify", "Invalid API key")
+	}
+	if !key.Expiry.IsZero() && time.Now().After(key.Expiry) {
+		return errors.BadRequest("auth.APIKeys.Verify", "API key expired")
+	}
+
+	// keys don't outlive the account which created them and can't have scopes it no longer has
+	owner, err := h.readAccount(key.accountIssuer(), key.AccountID)
+	if err == gostore.ErrNotFound {
+		return errors.BadRequest("auth.APIKeys.Verify", "Invalid API key")
+	} else if err != nil {
+		return errors.InternalServerError("auth.APIKeys.Verify", "Unable to read from store: %v", err)
+	}
+	scopes := key.Scopes
+	if !hasScope(owner.Scopes, "admin") {
+		scopes = make([]string, 0, len(key.Scopes))
+		for _, s := range key.Scopes {
+			if hasScope(owner.Scopes, s) {
+				scopes = append(scopes, s)
+			}
+		}
+	}
+
+	// record the usage of the key, it's written to the store periodically
+	h.recordUsage(key)
+
+	acc := &auth.Account{
+		ID:       key.AccountID,
+		Type:     apiKeyType,
+		Scopes:   scopes,
+		Issuer:   key.Issuer,
+		Metadata: map[string]string{"apikey": key.ID},
+	}
+
+	// the token lets the caller make requests to services on behalf of the key
+	tok, err := h.Auth.TokenProvider.Generate(acc, token.WithExpiry(apiKeyTokenExpiry))
+	if err != nil {
+		return errors.InternalServerError("auth.APIKeys.Verify", "Unable to generate token: %v", err)
+	}
+
+	rsp.Account = serializeAccount(acc)
+	rsp.Token = serializeToken(tok, "")
+	return nil
+}
+
+// recordUsage counts the usage of the key in memory, writing the usage of every key to the store
+// once the apiKeyUsageInterval has passed since it was last written
+func (h *APIKeys) recordUsage(k *apiKey) {
+	key := strings.Join([]string{storePrefixAPIKeys, k.Issuer, k.ID}, joinKey)
+
+	h.usageMu.Lock()
+	if h.usage == nil {
+		h.usage = make(map[string]*apiKeyUsage)
+		h.lastFlush = time.Now()
+	}
+	u, ok := h.usage[key]
+	if !ok {
+		u = &apiKeyUsage{}
+		h.usage[key] = u
+	}
+	u.Count++
+	u.LastUsed = time.Now()
+	flush := time.Since(h.lastFlush) > apiKeyUsageInterval
+	if flush {
+		h.lastFlush = time.Now()
+	}
+	h.usageMu.Unlock()
+
+	if flush {
+		go h.flushUsage()
+	}
+}
+
+// flushUsage writes the usage counted in memory to the store, the usage of keys which have been
+// revoked since is discarded
+func (h *APIKeys) flushUsage() {
+	h.usageMu.Lock()
+	usage := h.usage
+	h.usage = make(map[string]*apiKeyUsage)
+	h.usageMu.Unlock()
+
+	h.Lock()
+	defer h.Unlock()
+
+	for key, u := range usage {
+		recs, err := h.Auth.Options.Store.Read(key)
+		if err == gostore.ErrNotFound {
+			continue
+		} else if err != nil {
+			logger.Warnf("Error recording usage of api key %v: %v", key, err)
+			continue
+		}
+		var k *apiKey
+		if err := json.Unmarshal(recs[0].Value, &k); err != nil {
+			logger.Warnf("Error recording usage of api key %v: %v", key, err)
+			continue
+		}
+
+		k.Usage += u.Count
+		if u.LastUsed.After(k.LastUsed) {
+			k.LastUsed = u.LastUsed
+		}
+		if err := h.writeAPIKey(k); err != nil {
+			logger.Warnf("Error recording usage of api key %v: %v", k.ID, err)
+		}
+	}
+}
+
+// readAccount reads the account which created a key
+func (h *APIKeys) readAccount(ns, id string) (*auth.Account, error) {
+	recs, err := h.Auth.Options.Store.Read(strings.Join([]string{storePrefixAccounts, ns, id}, joinKey))
+	if err != nil {
+		return nil, err
+	}
+	var acc *auth.Account
+	if err := json.Unmarshal(recs[0].Value, &acc); err != nil {
+		return nil, err
+	}
+	return acc, nil
+}
+
+func (h *APIKeys) readAPIKey(ns, id string) (*apiKey, error) {
+	recs, err := h.Auth.Options.Store.Read(strings.Join([]string{storePrefixAPIKeys, ns, id}, joinKey))
+	if err != nil {
+		return nil, err
+	}
+	var k *apiKey
+	if err := json.Unmarshal(recs[0].Value, &k); err != nil {
+		return nil, err
+	}
+	return k, nil
+}
+
+func (h *APIKeys) writeAPIKey(k *apiKey) error {
+	bytes, err := json.Marshal(k)
+	if err != nil {
+		return err
+	}
+	key := strings.Join([]string{storePrefixAPIKeys, k.Issuer, k.ID}, joinKey)
+	return h.Auth.Options.Store.Write(&gostore.Record{Key: key, Value: bytes})
+}
+
+// hashAPIKey returns the hash of the secret of an api key. The secrets are random so unlike
+// passwords they don't need a slow hash, which would be too expensive to verify every request.
+func hashAPIKey(secret string) string {
+	h := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(h[:])
+}
+
+func randomHex(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+func hasScope(scopes []string, scope string) bool {
+	for _, s := range scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+func serializeAPIKey(k *apiKey) *pb.APIKey {
+	rsp := &pb.APIKey{
+		Id:          k.ID,
+		AccountId:   k.AccountID,
+		Description: k.Description,
+		Scopes:      k.Scopes,
+		Created:     k.Created.Unix(),
+		Usage:       k.Usage,
+	}
+	if !k.Expiry.IsZero() {
+		rsp.Expiry = k.Expiry.Unix()
+	}
+	if !k.LastUsed.IsZero() {
+		rsp.LastUsed = k.LastUsed.Unix()
+	}
+	return rsp
+}
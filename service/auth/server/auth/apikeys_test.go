@@ -0,0 +1,152 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/micro/go-micro/v3/auth"
+	gostore "github.com/micro/go-micro/v3/store"
+	"github.com/micro/go-micro/v3/store/memory"
+	"github.com/micro/go-micro/v3/util/token"
+	"github.com/micro/go-micro/v3/util/token/basic"
+	pb "github.com/micro/micro/v3/service/auth/proto"
+)
+
+func TestAPIKeys(t *testing.T) {
+	st := memory.NewStore()
+	h := &APIKeys{Auth: &Auth{
+		Options:       auth.Options{Store: st},
+		TokenProvider: basic.NewTokenProvider(token.WithStore(st)),
+	}}
+
+	// keys are verified using the account which created them
+	john := &auth.Account{ID: "john", Issuer: "micro", Scopes: []string{"user", "billing"}}
+	writeAccount := func(acc *auth.Account) {
+		bytes, _ := json.Marshal(acc)
+		key := strings.Join([]string{storePrefixAccounts, acc.Issuer, acc.ID}, joinKey)
+		if err := st.Write(&gostore.Record{Key: key, Value: bytes}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	writeAccount(john)
+	ctx := auth.ContextWithAccount(context.TODO(), john)
+
+	// keys can't have scopes the account doesn't have
+	var createRsp pb.CreateAPIKeyResponse
+	err := h.Create(ctx, &pb.CreateAPIKeyRequest{Scopes: []string{"admin"}}, &createRsp)
+	if err == nil {
+		t.Fatal("Expected an error creating a key with the admin scope")
+	}
+
+	if err := h.Create(ctx, &pb.CreateAPIKeyRequest{Description: "ci"}, &createRsp); err != nil {
+		t.Fatalf("Unexpected error creating key: %v", err)
+	}
+	if !strings.HasPrefix(createRsp.Key, apiKeyPrefix+createRsp.ApiKey.Id+"_") {
+		t.Fatalf("Unexpected key format %v", createRsp.Key)
+	}
+
+	// the key isn't stored, only its hash
+	recs, _ := st.Read(storePrefixAPIKeys, gostore.ReadPrefix())
+	if len(recs) != 1 || strings.Contains(string(recs[0].Value), strings.Split(createRsp.Key, "_")[2]) {
+		t.Fatal("Expected the key to be stored hashed")
+	}
+
+	var verifyRsp pb.VerifyAPIKeyResponse
+	if err := h.Verify(ctx, &pb.VerifyAPIKeyRequest{Key: createRsp.Key}, &verifyRsp); err != nil {
+		t.Fatalf("Unexpected error verifying key: %v", err)
+	}
+	if verifyRsp.Account.Id != "john" || len(verifyRsp.Account.Scopes) != 2 {
+		t.Errorf("Unexpected account %v", verifyRsp.Account)
+	}
+	if len(verifyRsp.Token.AccessToken) == 0 {
+		t.Error("Expected a token")
+	}
+	if err := h.Verify(ctx, &pb.VerifyAPIKeyRequest{Key: createRsp.Key + "x"}, &verifyRsp); err == nil {
+		t.Error("Expected an error verifying an invalid key")
+	}
+
+	// keys can't have scopes the account no longer has
+	writeAccount(&auth.Account{ID: "john", Issuer: "micro", Scopes: []string{"user"}})
+	if err := h.Verify(ctx, &pb.VerifyAPIKeyRequest{Key: createRsp.Key}, &verifyRsp); err != nil {
+		t.Fatalf("Unexpected error verifying key: %v", err)
+	}
+	if len(verifyRsp.Account.Scopes) != 1 || verifyRsp.Account.Scopes[0] != "user" {
+		t.Errorf("Expected the key to only have the user scope, got %v", verifyRsp.Account.Scopes)
+	}
+
+	var listRsp pb.ListAPIKeysResponse
+	if err := h.List(ctx, &pb.ListAPIKeysRequest{}, &listRsp); err != nil {
+		t.Fatalf("Unexpected error listing keys: %v", err)
+	}
+	if len(listRsp.ApiKeys) != 1 || listRsp.ApiKeys[0].Usage != 2 {
+		t.Errorf("Expected 1 key used twice, got %v", listRsp.ApiKeys)
+	}
+
+	// accounts can only list and revoke their own keys unless they're an admin
+	janeCtx := auth.ContextWithAccount(context.TODO(), &auth.Account{ID: "jane", Issuer: "micro", Scopes: []string{"user"}})
+	if err := h.List(janeCtx, &pb.ListAPIKeysRequest{}, &listRsp); err != nil {
+		t.Fatalf("Unexpected error listing keys: %v", err)
+	}
+	if len(listRsp.ApiKeys) != 0 {
+		t.Errorf("Expected no keys, got %v", listRsp.ApiKeys)
+	}
+	if err := h.Revoke(janeCtx, &pb.RevokeAPIKeyRequest{Id: createRsp.ApiKey.Id}, &pb.RevokeAPIKeyResponse{}); err == nil {
+		t.Fatal("Expected an error revoking the key of another account")
+	}
+	adminCtx := auth.ContextWithAccount(context.TODO(), &auth.Account{ID: "admin", Issuer: "micro", Scopes: []string{"admin"}})
+	if err := h.List(adminCtx, &pb.ListAPIKeysRequest{}, &listRsp); err != nil {
+		t.Fatalf("Unexpected error listing keys: %v", err)
+	}
+	if len(listRsp.ApiKeys) != 1 {
+		t.Errorf("Expected the admin to list every key, got %v", listRsp.ApiKeys)
+	}
+
+	// keys don't outlive the account which created them
+	if err := st.Delete(strings.Join([]string{storePrefixAccounts, "micro", "john"}, joinKey)); err != nil {
+		t.Fatal(err)
+	}
+	if err := h.Verify(ctx, &pb.VerifyAPIKeyRequest{Key: createRsp.Key}, &verifyRsp); err == nil {
+		t.Error("Expected an error verifying the key of a deleted account")
+	}
+	writeAccount(john)
+
+	if err := h.Revoke(ctx, &pb.RevokeAPIKeyRequest{Id: createRsp.ApiKey.Id}, &pb.RevokeAPIKeyResponse{}); err != nil {
+		t.Fatalf("Unexpected error revoking key: %v", err)
+	}
+	if err := h.Verify(ctx, &pb.VerifyAPIKeyRequest{Key: createRsp.Key}, &verifyRsp); err == nil {
+		t.Error("Expected an error verifying a revoked key")
+	}
+}
+
+func TestAPIKeysOtherNamespace(t *testing.T) {
+	st := memory.NewStore()
+	h := &APIKeys{Auth: &Auth{
+		Options:       auth.Options{Store: st},
+		TokenProvider: basic.NewTokenProvider(token.WithStore(st)),
+	}}
+
+	// an admin of the default namespace creates a key for another namespace
+	admin := &auth.Account{ID: "admin", Issuer: "micro", Scopes: []string{"admin"}}
+	bytes, _ := json.Marshal(admin)
+	key := strings.Join([]string{storePrefixAccounts, admin.Issuer, admin.ID}, joinKey)
+	if err := st.Write(&gostore.Record{Key: key, Value: bytes}); err != nil {
+		t.Fatal(err)
+	}
+	ctx := auth.ContextWithAccount(context.TODO(), admin)
+
+	var createRsp pb.CreateAPIKeyResponse
+	opts := &pb.Options{Namespace: "foo"}
+	if err := h.Create(ctx, &pb.CreateAPIKeyRequest{Options: opts}, &createRsp); err != nil {
+		t.Fatalf("Unexpected error creating key: %v", err)
+	}
+
+	var verifyRsp pb.VerifyAPIKeyResponse
+	if err := h.Verify(ctx, &pb.VerifyAPIKeyRequest{Key: createRsp.Key, Options: opts}, &verifyRsp); err != nil {
+		t.Fatalf("Unexpected error verifying key: %v", err)
+	}
+	if verifyRsp.Account.Id != "admin" || verifyRsp.Account.Issuer != "foo" {
+		t.Errorf("Expected the key to authenticate as the admin in namespace foo, got %v", verifyRsp.Account)
+	}
+}
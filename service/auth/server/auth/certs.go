@@ -0,0 +1,409 @@
+package auth
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/micro/go-micro/v3/auth"
+	gostore "github.com/micro/go-micro/v3/store"
+	"github.com/micro/micro/v3/internal/mtls"
+	"github.com/micro/micro/v3/internal/namespace"
+	pb "github.com/micro/micro/v3/service/auth/proto"
+	"github.com/micro/micro/v3/service/errors"
+)
+
+const (
+	storePrefixCerts = "cert"
+	// certDefaultExpiry is how long certificates are valid for unless requested otherwise
+	certDefaultExpiry = time.Hour * 24
+	// certMaxExpiry is the longest certificates can be valid for, they're short lived since
+	// they can only be revoked until they expire
+	certMaxExpiry = time.Hour * 24 * 7
+)
+
+// Certs processes RPC calls for the certificates services use for mutual TLS. The certificates
+// are issued to service accounts by the CA the handler is initialised with, services without it
+// must be provided its certificate so it's never trusted on first use.
+type Certs struct {
+	Auth *Auth
+	// locked while the CA is set or certificates are revoked
+	sync.Mutex
+
+	ca    *x509.Certificate
+	caKey crypto.Signer
+	caPEM []byte
+}
+
+// cert is the record of a certificate in the store, the certificate itself isn't stored
+type cert struct {
+	Serial    string    `json:"serial"`
+	AccountID string    `json:"account_id"`
+	Issuer    string    `json:"issuer"`
+	Created   time.Time `json:"created"`
+	Expiry    time.Time `json:"expiry"`
+	Revoked   bool      `json:"revoked"`
+}
+
+// Init sets the PEM encoded certificate and private key of the CA which issues the certificates
+func (c *Certs) Init(certPEM, keyPEM []byte) error {
+	certBlock, _ := pem.Decode(certPEM)
+	keyBlock, _ := pem.Decode(keyPEM)
+	if certBlock == nil || keyBlock == nil {
+		return fmt.Errorf("invalid CA")
+	}
+	crt, err := x509.ParseCertificate(certBlock.Bytes)
+	if err != nil {
+		return err
+	}
+	if !crt.IsCA {
+		return fmt.Errorf("the certificate isn't a CA")
+	}
+	key, err := parsePrivateKey(keyBlock.Bytes)
+	if err != nil {
+		return err
+	}
+
+	c.Lock()
+	defer c.Unlock()
+	c.ca = crt
+	c.caKey = key
+	c.caPEM = certPEM
+	return nil
+}
+
+// Issue signs the certificate signing request for the service named by its common name. The
+// certificate is issued to the service account making the request and identifies the service
+// in the namespace of the account using a URI, e.g. micro://micro/helloworld.
+func (c *Certs) Issue(ctx context.Context, req *pb.IssueCertRequest, rsp *pb.IssueCertResponse) error {
+	// validate the request
+	expiry := time.Duration(req.Expiry) * time.Second
+	if expiry < 0 || expiry > certMaxExpiry {
+		return errors.BadRequest("auth.Certs.Issue", "Invalid expiry, the maximum is %v", certMaxExpiry)
+	}
+	if len(req.Csr) == 0 {
+		return errors.BadRequest("auth.Certs.Issue", "Missing CSR")
+	}
+
+	// the certificate is tied to the account requesting it
+	acc, ok := auth.AccountFromContext(ctx)
+	if !ok || len(acc.ID) == 0 {
+		return errors.Unauthorized("auth.Certs.Issue", "An account is required to issue certificates")
+	}
+	if acc.Type != "service" {
+		return errors.Forbidden("auth.Certs.Issue", "Certificates are only issued to services")
+	}
+
+	crt, certPEM, caPEM, err := c.issue(acc, req.Csr, expiry)
+	if err != nil {
+		return err
+	}
+
+	rsp.Cert = serializeCert(crt)
+	rsp.Certificate = certPEM
+	rsp.Ca = caPEM
+	return nil
+}
+
+// List returns the certificates issued to accounts in the namespace which haven't expired
+func (c *Certs) List(ctx context.Context, req *pb.ListCertsRequest, rsp *pb.ListCertsResponse) error {
+	// set defaults
+	if req.Options == nil {
+		req.Options = &pb.Options{}
+	}
+	if len(req.Options.Namespace) == 0 {
+		req.Options.Namespace = namespace.DefaultNamespace
+	}
+
+	// authorize the request
+	if err := namespace.Authorize(ctx, req.Options.Namespace); err == namespace.ErrForbidden {
+		return errors.Forbidden("auth.Certs.List", err.Error())
+	} else if err == namespace.ErrUnauthorized {
+		return errors.Unauthorized("auth.Certs.List", err.Error())
+	} else if err != nil {
+		return errors.InternalServerError("auth.Certs.List", err.Error())
+	}
+
+	certs, err := c.listCerts(req.Options.Namespace)
+	if err != nil {
+		return errors.InternalServerError("auth.Certs.List", "Unable to read from store: %v", err)
+	}
+
+	rsp.Certs = make([]*pb.Cert, 0, len(certs))
+	for _, crt := range certs {
+		rsp.Certs = append(rsp.Certs, serializeCert(crt))
+	}
+	return nil
+}
+
+// Revoke a certificate so it's no longer accepted by services, until it expires it's included
+// in the revoked serials returned with the CA
+func (c *Certs) Revoke(ctx context.Context, req *pb.RevokeCertRequest, rsp *pb.RevokeCertResponse) error {
+	// validate the request
+	if len(req.Serial) == 0 {
+		return errors.BadRequest("auth.Certs.Revoke", "Missing serial")
+	}
+
+	// set defaults
+	if req.Options == nil {
+		req.Options = &pb.Options{}
+	}
+	if len(req.Options.Namespace) == 0 {
+		req.Options.Namespace = namespace.DefaultNamespace
+	}
+
+	// authorize the request
+	if err := namespace.Authorize(ctx, req.Options.Namespace); err == namespace.ErrForbidden {
+		return errors.Forbidden("auth.Certs.Revoke", err.Error())
+	} else if err == namespace.ErrUnauthorized {
+		return errors.Unauthorized("auth.Certs.Revoke", err.Error())
+	} else if err != nil {
+		return errors.InternalServerError("auth.Certs.Revoke", err.Error())
+	}
+
+	c.Lock()
+	defer c.Unlock()
+
+	key := strings.Join([]string{storePrefixCerts, req.Options.Namespace, req.Serial}, joinKey)
+	recs, err := c.Auth.Options.Store.Read(key)
+	if err == gostore.ErrNotFound {
+		return errors.NotFound("auth.Certs.Revoke", "Certificate not found with this serial")
+	} else if err != nil {
+		return errors.InternalServerError("auth.Certs.Revoke", "Unable to read from store: %v", err)
+	}
+	var crt *cert
+	if err := json.Unmarshal(recs[0].Value, &crt); err != nil {
+		return errors.InternalServerError("auth.Certs.Revoke", "Unable to unmarshal certificate: %v", err)
+	}
+
+	// only admins can revoke the certificates of other accounts
+	if acc, _ := auth.AccountFromContext(ctx); !hasScope(acc.Scopes, "admin") && crt.AccountID != acc.ID {
+		return errors.Forbidden("auth.Certs.Revoke", "Only admins can revoke the certificates of other accounts")
+	}
+
+	crt.Revoked = true
+	if err := c.writeCert(crt); err != nil {
+		return errors.InternalServerError("auth.Certs.Revoke", "Unable to write certificate to store: %v", err)
+	}
+	return nil
+}
+
+// CA returns the certificate of the CA and the serials of the certificates which have been
+// revoked. It's used to verify peers so it doesn't require an account.
+func (c *Certs) CA(ctx context.Context, req *pb.CARequest, rsp *pb.CAResponse) error {
+	caPEM, revoked, err := c.authority()
+	if err != nil {
+		return err
+	}
+
+	rsp.Ca = caPEM
+	rsp.Revoked = revoked
+	return nil
+}
+
+// LocalIssuer issues certificates to the auth service itself, which can't request them using
+// RPC calls since it may be the only instance of the service
+type LocalIssuer struct {
+	Certs *Certs
+	// Account the certificates are issued to
+	Account *auth.Account
+}
+
+// IssueCertificate signs the PEM encoded certificate signing request
+func (l *LocalIssuer) IssueCertificate(csr []byte, expiry time.Duration) ([]byte, []byte, error) {
+	_, certPEM, caPEM, err := l.Certs.issue(l.Account, csr, expiry)
+	return certPEM, caPEM, err
+}
+
+// CertificateAuthority returns the PEM encoded certificate of the CA and the revoked serials
+func (l *LocalIssuer) CertificateAuthority() ([]byte, []string, error) {
+	return l.Certs.authority()
+}
+
+func (c *Certs) issue(acc *auth.Account, csrPEM []byte, expiry time.Duration) (*cert, []byte, []byte, error) {
+	if expiry == 0 {
+		expiry = certDefaultExpiry
+	}
+
+	block, _ := pem.Decode(csrPEM)
+	if block == nil || block.Type != "CERTIFICATE REQUEST" {
+		return nil, nil, nil, errors.BadRequest("auth.Certs.Issue", "Invalid CSR")
+	}
+	csr, err := x509.ParseCertificateRequest(block.Bytes)
+	if err != nil {
+		return nil, nil, nil, errors.BadRequest("auth.Certs.Issue", "Invalid CSR: %v", err)
+	}
+	if err := csr.CheckSignature(); err != nil {
+		return nil, nil, nil, errors.BadRequest("auth.Certs.Issue", "Invalid CSR signature: %v", err)
+	}
+
+	// the service is requested using the common name, the accounts generated for services by
+	// the runtime record the name of the service in their metadata since the version in their
+	// ID, e.g. helloworld-latest, doesn't allow the name to be recovered from it
+	service := csr.Subject.CommonName
+	if len(service) == 0 || strings.ContainsAny(service, "/ ") {
+		return nil, nil, nil, errors.BadRequest("auth.Certs.Issue", "Invalid service name in the CSR")
+	}
+	if acc.ID != service && acc.Metadata["service"] != service {
+		// only the services of the default namespace, e.g. the core services, can be issued
+		// certificates for services other than their own
+		if acc.Issuer != namespace.DefaultNamespace || !hasScope(acc.Scopes, "service") {
+			return nil, nil, nil, errors.Forbidden("auth.Certs.Issue", "Certificates can only be issued for the service of the account")
+		}
+	}
+
+	c.Lock()
+	defer c.Unlock()
+
+	if c.ca == nil {
+		return nil, nil, nil, errors.InternalServerError("auth.Certs.Issue", "No CA")
+	}
+
+	serial, err := randomSerial()
+	if err != nil {
+		return nil, nil, nil, errors.InternalServerError("auth.Certs.Issue", "Unable to generate serial: %v", err)
+	}
+
+	// the subject identifies the account and the URI the service, the namespace is always the
+	// one of the account so services can't pose as the services of other namespaces
+	now := time.Now()
+	tmpl := &x509.Certificate{
+		SerialNumber: serial,
+		Subject: pkix.Name{
+			CommonName:         acc.ID,
+			Organization:       []string{acc.Issuer},
+			OrganizationalUnit: []string{acc.Type},
+		},
+		URIs:        []*url.URL{mtls.IdentityURI(acc.Issuer, service)},
+		NotBefore:   now.Add(-time.Minute),
+		NotAfter:    now.Add(expiry),
+		KeyUsage:    x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment,
+		ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, c.ca, csr.PublicKey, c.caKey)
+	if err != nil {
+		return nil, nil, nil, errors.InternalServerError("auth.Certs.Issue", "Unable to issue certificate: %v", err)
+	}
+
+	crt := &cert{
+		Serial:    fmt.Sprintf("%x", serial),
+		AccountID: acc.ID,
+		Issuer:    acc.Issuer,
+		Created:   now,
+		Expiry:    tmpl.NotAfter,
+	}
+	if err := c.writeCert(crt); err != nil {
+		return nil, nil, nil, errors.InternalServerError("auth.Certs.Issue", "Unable to write certificate to store: %v", err)
+	}
+
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	return crt, certPEM, c.caPEM, nil
+}
+
+func (c *Certs) authority() ([]byte, []string, error) {
+	c.Lock()
+	caPEM := c.caPEM
+	c.Unlock()
+	if caPEM == nil {
+		return nil, nil, errors.InternalServerError("auth.Certs.CA", "No CA")
+	}
+
+	// certificates are revoked in every namespace since the CA is shared
+	certs, err := c.listCerts("")
+	if err != nil {
+		return nil, nil, errors.InternalServerError("auth.Certs.CA", "Unable to read from store: %v", err)
+	}
+	var revoked []string
+	for _, crt := range certs {
+		if crt.Revoked {
+			revoked = append(revoked, crt.Serial)
+		}
+	}
+	return caPEM, revoked, nil
+}
+
+// listCerts returns the certificates in the namespace, or every namespace if it's blank, ordered
+// by when they were issued
+func (c *Certs) listCerts(ns string) ([]*cert, error) {
+	prefix := strings.Join([]string{storePrefixCerts, ""}, joinKey)
+	if len(ns) > 0 {
+		prefix = strings.Join([]string{storePrefixCerts, ns, ""}, joinKey)
+	}
+	recs, err := c.Auth.Options.Store.Read(prefix, gostore.ReadPrefix())
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	certs := make([]*cert, 0, len(recs))
+	for _, rec := range recs {
+		var crt *cert
+		if err := json.Unmarshal(rec.Value, &crt); err != nil {
+			return nil, err
+		}
+		// not every store expires records
+		if now.After(crt.Expiry) {
+			continue
+		}
+		certs = append(certs, crt)
+	}
+	sort.Slice(certs, func(i, j int) bool { return certs[i].Created.Before(certs[j].Created) })
+	return certs, nil
+}
+
+func (c *Certs) writeCert(crt *cert) error {
+	bytes, err := json.Marshal(crt)
+	if err != nil {
+		return err
+	}
+	key := strings.Join([]string{storePrefixCerts, crt.Issuer, crt.Serial}, joinKey)
+	return c.Auth.Options.Store.Write(&gostore.Record{
+		Key:   key,
+		Value: bytes,
+		// the record is only needed until the certificate expires
+		Expiry: time.Until(crt.Expiry),
+	})
+}
+
+// parsePrivateKey parses the DER encoded private key of the CA, in either PKCS #8, EC or
+// PKCS #1 form
+func parsePrivateKey(der []byte) (crypto.Signer, error) {
+	if key, err := x509.ParsePKCS8PrivateKey(der); err == nil {
+		if signer, ok := key.(crypto.Signer); ok {
+			return signer, nil
+		}
+		return nil, fmt.Errorf("unsupported private key")
+	}
+	if key, err := x509.ParseECPrivateKey(der); err == nil {
+		return key, nil
+	}
+	if key, err := x509.ParsePKCS1PrivateKey(der); err == nil {
+		return key, nil
+	}
+	return nil, fmt.Errorf("invalid private key")
+}
+
+func randomSerial() (*big.Int, error) {
+	return rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+}
+
+func serializeCert(c *cert) *pb.Cert {
+	return &pb.Cert{
+		Serial:    c.Serial,
+		AccountId: c.AccountID,
+		Created:   c.Created.Unix(),
+		Expiry:    c.Expiry.Unix(),
+		Revoked:   c.Revoked,
+	}
+}
@@ -0,0 +1,168 @@
+package auth
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/micro/go-micro/v3/auth"
+	"github.com/micro/go-micro/v3/store/memory"
+	"github.com/micro/micro/v3/internal/mtls"
+	pb "github.com/micro/micro/v3/service/auth/proto"
+)
+
+// testCA returns the PEM encoded certificate and key of a CA
+func testCA(t *testing.T) ([]byte, []byte) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+}
+
+// testCSR returns a PEM encoded certificate signing request for the service
+func testCSR(t *testing.T, service string) []byte {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	der, err := x509.CreateCertificateRequest(rand.Reader, &x509.CertificateRequest{
+		Subject: pkix.Name{CommonName: service},
+	}, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE REQUEST", Bytes: der})
+}
+
+func TestCerts(t *testing.T) {
+	h := &Certs{Auth: &Auth{Options: auth.Options{Store: memory.NewStore()}}}
+
+	ctx := auth.ContextWithAccount(context.TODO(), &auth.Account{
+		ID: "foo-latest", Type: "service", Issuer: "tenant", Scopes: []string{"service"},
+		Metadata: map[string]string{"service": "foo"},
+	})
+	csr := testCSR(t, "foo")
+
+	// certificates can't be issued without a CA
+	var issueRsp pb.IssueCertResponse
+	if err := h.Issue(ctx, &pb.IssueCertRequest{Csr: csr}, &issueRsp); err == nil {
+		t.Fatal("Expected an error issuing a certificate without a CA")
+	}
+	caPEM, keyPEM := testCA(t)
+	if err := h.Init(caPEM, keyPEM); err != nil {
+		t.Fatal(err)
+	}
+
+	// certificates must be short lived
+	err := h.Issue(ctx, &pb.IssueCertRequest{Csr: csr, Expiry: int64(certMaxExpiry.Seconds()) + 1}, &issueRsp)
+	if err == nil {
+		t.Fatal("Expected an error issuing a long lived certificate")
+	}
+	if err := h.Issue(context.TODO(), &pb.IssueCertRequest{Csr: csr}, &issueRsp); err == nil {
+		t.Fatal("Expected an error issuing a certificate without an account")
+	}
+
+	// certificates are only issued to services, for their own service
+	userCtx := auth.ContextWithAccount(context.TODO(), &auth.Account{ID: "foo", Type: "user", Issuer: "tenant"})
+	if err := h.Issue(userCtx, &pb.IssueCertRequest{Csr: csr}, &issueRsp); err == nil {
+		t.Fatal("Expected an error issuing a certificate to a user")
+	}
+	if err := h.Issue(ctx, &pb.IssueCertRequest{Csr: testCSR(t, "bar")}, &issueRsp); err == nil {
+		t.Fatal("Expected an error issuing a certificate for another service")
+	}
+	// the account of foo-bar has an ID which starts with foo but it isn't the account of foo
+	fooBarCtx := auth.ContextWithAccount(context.TODO(), &auth.Account{
+		ID: "foo-bar-latest", Type: "service", Issuer: "tenant", Scopes: []string{"service"},
+		Metadata: map[string]string{"service": "foo-bar"},
+	})
+	if err := h.Issue(fooBarCtx, &pb.IssueCertRequest{Csr: csr}, &issueRsp); err == nil {
+		t.Fatal("Expected an error issuing a certificate for a service which prefixes the account ID")
+	}
+
+	if err := h.Issue(ctx, &pb.IssueCertRequest{Csr: csr}, &issueRsp); err != nil {
+		t.Fatalf("Unexpected error issuing certificate: %v", err)
+	}
+
+	// the certificate is issued to the account by the CA
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(issueRsp.Ca) {
+		t.Fatal("Expected a CA")
+	}
+	block, _ := pem.Decode(issueRsp.Certificate)
+	crt, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := crt.Verify(x509.VerifyOptions{Roots: pool, KeyUsages: []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth}}); err != nil {
+		t.Fatalf("Expected the certificate to be issued by the CA: %v", err)
+	}
+	if crt.Subject.CommonName != "foo-latest" {
+		t.Errorf("Expected the certificate to be issued to foo-latest, got %v", crt.Subject.CommonName)
+	}
+	if id := mtls.Identity(crt); id != "tenant/foo" {
+		t.Errorf("Expected the certificate to identify tenant/foo, got %v", id)
+	}
+	if exp := crt.NotAfter.Sub(time.Now()); exp > certDefaultExpiry || exp < certDefaultExpiry-time.Minute {
+		t.Errorf("Expected the certificate to expire in a day, got %v", exp)
+	}
+
+	var listRsp pb.ListCertsResponse
+	if err := h.List(ctx, &pb.ListCertsRequest{Options: &pb.Options{Namespace: "tenant"}}, &listRsp); err != nil {
+		t.Fatalf("Unexpected error listing certificates: %v", err)
+	}
+	if len(listRsp.Certs) != 1 || listRsp.Certs[0].Serial != issueRsp.Cert.Serial || listRsp.Certs[0].AccountId != "foo-latest" {
+		t.Fatalf("Expected the issued certificate, got %v", listRsp.Certs)
+	}
+
+	opts := &pb.Options{Namespace: "tenant"}
+	if err := h.Revoke(ctx, &pb.RevokeCertRequest{Serial: "unknown", Options: opts}, &pb.RevokeCertResponse{}); err == nil {
+		t.Error("Expected an error revoking an unknown certificate")
+	}
+
+	// only the owner and admins can revoke the certificate
+	otherCtx := auth.ContextWithAccount(context.TODO(), &auth.Account{ID: "bar", Type: "user", Issuer: "tenant"})
+	if err := h.Revoke(otherCtx, &pb.RevokeCertRequest{Serial: issueRsp.Cert.Serial, Options: opts}, &pb.RevokeCertResponse{}); err == nil {
+		t.Fatal("Expected an error revoking the certificate of another account")
+	}
+	if err := h.Revoke(ctx, &pb.RevokeCertRequest{Serial: issueRsp.Cert.Serial, Options: opts}, &pb.RevokeCertResponse{}); err != nil {
+		t.Fatalf("Unexpected error revoking certificate: %v", err)
+	}
+
+	// the revoked certificate is returned with the CA
+	var caRsp pb.CAResponse
+	if err := h.CA(context.TODO(), &pb.CARequest{}, &caRsp); err != nil {
+		t.Fatalf("Unexpected error getting the CA: %v", err)
+	}
+	if string(caRsp.Ca) != string(issueRsp.Ca) {
+		t.Error("Expected the same CA")
+	}
+	if len(caRsp.Revoked) != 1 || caRsp.Revoked[0] != issueRsp.Cert.Serial {
+		t.Errorf("Expected the certificate to be revoked, got %v", caRsp.Revoked)
+	}
+}
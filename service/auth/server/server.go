@@ -1,11 +1,16 @@
 package server
 
 import (
+	"io/ioutil"
+
 	"github.com/micro/cli/v2"
 	"github.com/micro/go-micro/v3/auth"
+	sgrpc "github.com/micro/go-micro/v3/server/grpc"
 	"github.com/micro/go-micro/v3/store"
 	"github.com/micro/go-micro/v3/util/token"
 	"github.com/micro/go-micro/v3/util/token/jwt"
+	"github.com/micro/micro/v3/internal/mtls"
+	"github.com/micro/micro/v3/internal/namespace"
 	"github.com/micro/micro/v3/service"
 	pb "github.com/micro/micro/v3/service/auth/proto"
 	authHandler "github.com/micro/micro/v3/service/auth/server/auth"
@@ -46,9 +51,48 @@ func Run(ctx *cli.Context) error {
 	ruleH.Init(auth.Store(mustore.DefaultStore))
 
 	// register handlers
+	certsH := &authHandler.Certs{Auth: authH}
 	pb.RegisterAuthHandler(srv.Server(), authH)
 	pb.RegisterRulesHandler(srv.Server(), ruleH)
 	pb.RegisterAccountsHandler(srv.Server(), authH)
+	pb.RegisterAPIKeysHandler(srv.Server(), &authHandler.APIKeys{Auth: authH})
+	pb.RegisterCertsHandler(srv.Server(), certsH)
+
+	// the certificates are issued by the CA provided, it's never generated so services are
+	// always provided the CA they verify their peers with
+	if caFile, keyFile := ctx.String("mtls_ca_file"), ctx.String("mtls_ca_key_file"); len(caFile) > 0 && len(keyFile) > 0 {
+		caPEM, err := ioutil.ReadFile(caFile)
+		if err != nil {
+			log.Fatalf("Error reading the CA: %v", err)
+		}
+		keyPEM, err := ioutil.ReadFile(keyFile)
+		if err != nil {
+			log.Fatalf("Error reading the CA key: %v", err)
+		}
+		if err := certsH.Init(caPEM, keyPEM); err != nil {
+			log.Fatalf("Error loading the CA: %v", err)
+		}
+	} else if mtls.DefaultManager != nil {
+		log.Fatal("The mtls_ca_key_file flag is required to issue the mutual TLS certificates")
+	}
+
+	// the auth service issues its own certificate since it can't call itself before it's running,
+	// clients without a certificate are accepted so services can request one
+	if m := mtls.DefaultManager; m != nil {
+		m.Init(mtls.WithIssuer(&authHandler.LocalIssuer{
+			Certs: certsH,
+			Account: &auth.Account{
+				ID: name, Type: "service", Issuer: namespace.DefaultNamespace, Scopes: []string{"service"},
+			},
+		}))
+		if err := m.Rotate(); err != nil {
+			log.Fatalf("Error issuing the mutual TLS certificate: %v", err)
+		}
+		if err := m.Refresh(); err != nil {
+			log.Fatalf("Error loading the revoked certificates: %v", err)
+		}
+		srv.Server().Init(sgrpc.AuthTLS(m.BootstrapConfig()))
+	}
 
 	// run service
 	if err := srv.Run(); err != nil {
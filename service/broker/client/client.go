@@ -67,10 +67,22 @@ func (b *serviceBroker) Subscribe(topic string, handler broker.Handler, opts ...
 	if logger.V(logger.DebugLevel, logger.DefaultLogger) {
 		logger.Debugf("Subscribing to topic %s queue %s broker %v", topic, options.Queue, b.Addrs)
 	}
-	stream, err := b.Client.Subscribe(context.DefaultContext, &pb.SubscribeRequest{
+	req := &pb.SubscribeRequest{
 		Topic: topic,
 		Queue: options.Queue,
-	}, goclient.WithAuthToken(), goclient.WithAddress(b.Addrs...), goclient.WithRequestTimeout(time.Hour))
+	}
+	if ctx := options.Context; ctx != nil {
+		if size, ok := ctx.Value(bufferKey{}).(int); ok {
+			req.Buffer = int64(size)
+		}
+		if policy, ok := ctx.Value(overflowKey{}).(string); ok {
+			req.Overflow = policy
+		}
+		if alo, ok := ctx.Value(atLeastOnceKey{}).(bool); ok {
+			req.AtLeastOnce = alo
+		}
+	}
+	stream, err := b.Client.Subscribe(context.DefaultContext, req, goclient.WithAuthToken(), goclient.WithAddress(b.Addrs...), goclient.WithRequestTimeout(time.Hour))
 	if err != nil {
 		return nil, err
 	}
@@ -82,6 +94,7 @@ func (b *serviceBroker) Subscribe(topic string, handler broker.Handler, opts ...
 		stream:  stream,
 		closed:  make(chan bool),
 		options: options,
+		broker:  b,
 	}
 
 	go func() {
@@ -101,10 +114,7 @@ func (b *serviceBroker) Subscribe(topic string, handler broker.Handler, opts ...
 					if logger.V(logger.DebugLevel, logger.DefaultLogger) {
 						logger.Debugf("Resubscribing to topic %s broker %v", topic, b.Addrs)
 					}
-					stream, err := b.Client.Subscribe(context.DefaultContext, &pb.SubscribeRequest{
-						Topic: topic,
-						Queue: options.Queue,
-					}, goclient.WithAddress(b.Addrs...), goclient.WithRequestTimeout(time.Hour))
+					stream, err := b.Client.Subscribe(context.DefaultContext, req, goclient.WithAddress(b.Addrs...), goclient.WithRequestTimeout(time.Hour))
 					if err != nil {
 						if logger.V(logger.DebugLevel, logger.DefaultLogger) {
 							logger.Debugf("Failed to resubscribe to topic %s: %v", topic, err)
@@ -0,0 +1,37 @@
+package client
+
+import (
+	"context"
+
+	"github.com/micro/go-micro/v3/broker"
+)
+
+type bufferKey struct{}
+type overflowKey struct{}
+type atLeastOnceKey struct{}
+
+// Buffer sets the number of messages the broker service buffers for the subscriber
+func Buffer(size int) broker.SubscribeOption {
+	return setSubscribeOption(bufferKey{}, size)
+}
+
+// Overflow sets the policy applied by the broker service when the subscriber's
+// buffer is full: block, drop-oldest or disconnect
+func Overflow(policy string) broker.SubscribeOption {
+	return setSubscribeOption(overflowKey{}, policy)
+}
+
+// AtLeastOnce asks the broker service to redeliver messages to the subscribers of
+// the queue until they're acked, messages are acked once the handler returns nil
+func AtLeastOnce() broker.SubscribeOption {
+	return setSubscribeOption(atLeastOnceKey{}, true)
+}
+
+func setSubscribeOption(k, v interface{}) broker.SubscribeOption {
+	return func(o *broker.SubscribeOptions) {
+		if o.Context == nil {
+			o.Context = context.Background()
+		}
+		o.Context = context.WithValue(o.Context, k, v)
+	}
+}
@@ -2,7 +2,9 @@ package client
 
 import (
 	"github.com/micro/go-micro/v3/broker"
+	goclient "github.com/micro/go-micro/v3/client"
 	pb "github.com/micro/micro/v3/service/broker/proto"
+	"github.com/micro/micro/v3/service/context"
 	"github.com/micro/micro/v3/service/logger"
 )
 
@@ -13,6 +15,8 @@ type serviceSub struct {
 	stream  pb.Broker_SubscribeService
 	closed  chan bool
 	options broker.SubscribeOptions
+	// broker the subscription was made with, messages are acked using it
+	broker *serviceBroker
 }
 
 type serviceEvent struct {
@@ -85,10 +89,27 @@ func (s *serviceSub) run() error {
 
 		// TODO: exec the subscriber error handler
 		// in the event of an error
-		s.handler(m)
+		if err := s.handler(m); err != nil || len(msg.Id) == 0 {
+			continue
+		}
+
+		// messages of at-least-once subscriptions are redelivered unless they're acked
+		// once they've been handled
+		if err := s.ack(msg.Id); err != nil {
+			logger.Warnf("Error acking message on topic %s: %v", s.Topic(), err)
+		}
 	}
 }
 
+func (s *serviceSub) ack(id string) error {
+	_, err := s.broker.Client.Ack(context.DefaultContext, &pb.AckRequest{
+		Topic: s.topic,
+		Queue: s.queue,
+		Ids:   []string{id},
+	}, goclient.WithAuthToken(), goclient.WithAddress(s.broker.Addrs...))
+	return err
+}
+
 func (s *serviceSub) Options() broker.SubscribeOptions {
 	return s.options
 }
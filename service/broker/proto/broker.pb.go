@@ -103,8 +103,16 @@ func (m *PublishRequest) GetMessage() *Message {
 }
 
 type SubscribeRequest struct {
-	Topic                string   `protobuf:"bytes,1,opt,name=topic,proto3" json:"topic,omitempty"`
-	Queue                string   `protobuf:"bytes,2,opt,name=queue,proto3" json:"queue,omitempty"`
+	Topic string `protobuf:"bytes,1,opt,name=topic,proto3" json:"topic,omitempty"`
+	Queue string `protobuf:"bytes,2,opt,name=queue,proto3" json:"queue,omitempty"`
+	// number of messages buffered for the subscriber
+	// before the overflow policy applies
+	Buffer int64 `protobuf:"varint,3,opt,name=buffer,proto3" json:"buffer,omitempty"`
+	// overflow policy: block, drop-oldest or disconnect
+	Overflow string `protobuf:"bytes,4,opt,name=overflow,proto3" json:"overflow,omitempty"`
+	// redeliver messages which aren't acked before the
+	// ack timeout to the subscribers of the queue
+	AtLeastOnce          bool     `protobuf:"varint,5,opt,name=at_least_once,json=atLeastOnce,proto3" json:"at_least_once,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -149,19 +157,99 @@ func (m *SubscribeRequest) GetQueue() string {
 	return ""
 }
 
+func (m *SubscribeRequest) GetBuffer() int64 {
+	if m != nil {
+		return m.Buffer
+	}
+	return 0
+}
+
+func (m *SubscribeRequest) GetOverflow() string {
+	if m != nil {
+		return m.Overflow
+	}
+	return ""
+}
+
+func (m *SubscribeRequest) GetAtLeastOnce() bool {
+	if m != nil {
+		return m.AtLeastOnce
+	}
+	return false
+}
+
+type AckRequest struct {
+	Topic string `protobuf:"bytes,1,opt,name=topic,proto3" json:"topic,omitempty"`
+	Queue string `protobuf:"bytes,2,opt,name=queue,proto3" json:"queue,omitempty"`
+	// ids of the messages handled by the subscriber
+	Ids                  []string `protobuf:"bytes,3,rep,name=ids,proto3" json:"ids,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *AckRequest) Reset()         { *m = AckRequest{} }
+func (m *AckRequest) String() string { return proto.CompactTextString(m) }
+func (*AckRequest) ProtoMessage()    {}
+func (*AckRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_d3500c77d79745d9, []int{3}
+}
+
+func (m *AckRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AckRequest.Unmarshal(m, b)
+}
+func (m *AckRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AckRequest.Marshal(b, m, deterministic)
+}
+func (m *AckRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AckRequest.Merge(m, src)
+}
+func (m *AckRequest) XXX_Size() int {
+	return xxx_messageInfo_AckRequest.Size(m)
+}
+func (m *AckRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_AckRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AckRequest proto.InternalMessageInfo
+
+func (m *AckRequest) GetTopic() string {
+	if m != nil {
+		return m.Topic
+	}
+	return ""
+}
+
+func (m *AckRequest) GetQueue() string {
+	if m != nil {
+		return m.Queue
+	}
+	return ""
+}
+
+func (m *AckRequest) GetIds() []string {
+	if m != nil {
+		return m.Ids
+	}
+	return nil
+}
+
 type Message struct {
-	Header               map[string]string `protobuf:"bytes,1,rep,name=header,proto3" json:"header,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
-	Body                 []byte            `protobuf:"bytes,2,opt,name=body,proto3" json:"body,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
-	XXX_unrecognized     []byte            `json:"-"`
-	XXX_sizecache        int32             `json:"-"`
+	Header map[string]string `protobuf:"bytes,1,rep,name=header,proto3" json:"header,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	Body   []byte            `protobuf:"bytes,2,opt,name=body,proto3" json:"body,omitempty"`
+	// id of the message, set for at-least-once subscriptions
+	// which must ack it once it's handled
+	Id                   string   `protobuf:"bytes,3,opt,name=id,proto3" json:"id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
 func (m *Message) Reset()         { *m = Message{} }
 func (m *Message) String() string { return proto.CompactTextString(m) }
 func (*Message) ProtoMessage()    {}
 func (*Message) Descriptor() ([]byte, []int) {
-	return fileDescriptor_d3500c77d79745d9, []int{3}
+	return fileDescriptor_d3500c77d79745d9, []int{4}
 }
 
 func (m *Message) XXX_Unmarshal(b []byte) error {
@@ -196,10 +284,18 @@ func (m *Message) GetBody() []byte {
 	return nil
 }
 
+func (m *Message) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
 func init() {
 	proto.RegisterType((*Empty)(nil), "broker.Empty")
 	proto.RegisterType((*PublishRequest)(nil), "broker.PublishRequest")
 	proto.RegisterType((*SubscribeRequest)(nil), "broker.SubscribeRequest")
+	proto.RegisterType((*AckRequest)(nil), "broker.AckRequest")
 	proto.RegisterType((*Message)(nil), "broker.Message")
 	proto.RegisterMapType((map[string]string)(nil), "broker.Message.HeaderEntry")
 }
@@ -207,27 +303,34 @@ func init() {
 func init() { proto.RegisterFile("service/broker/proto/broker.proto", fileDescriptor_d3500c77d79745d9) }
 
 var fileDescriptor_d3500c77d79745d9 = []byte{
-	// 312 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x7c, 0x91, 0xcb, 0x4f, 0xc2, 0x40,
-	0x10, 0xc6, 0x59, 0x90, 0x36, 0x0c, 0x3e, 0xc8, 0x86, 0x98, 0x06, 0x2f, 0xd8, 0x13, 0x5e, 0x5a,
-	0x53, 0x62, 0x54, 0x4c, 0x3c, 0x90, 0x90, 0x78, 0x31, 0xd1, 0x7a, 0xf3, 0xc6, 0x96, 0x09, 0x34,
-	0x50, 0x17, 0xf6, 0x41, 0xec, 0x1f, 0xe1, 0xff, 0x6c, 0xd8, 0xdd, 0xfa, 0x20, 0xc6, 0xcb, 0x66,
-	0x7e, 0x33, 0x93, 0x6f, 0xbe, 0x7c, 0x0b, 0xe7, 0x12, 0xc5, 0x36, 0xcf, 0x30, 0x66, 0x82, 0x2f,
-	0x51, 0xc4, 0x6b, 0xc1, 0x15, 0x77, 0x10, 0x19, 0xa0, 0x9e, 0xa5, 0xd0, 0x87, 0xe6, 0xa4, 0x58,
-	0xab, 0x32, 0x7c, 0x86, 0xe3, 0x27, 0xcd, 0x56, 0xb9, 0x5c, 0xa4, 0xb8, 0xd1, 0x28, 0x15, 0xed,
-	0x42, 0x53, 0xf1, 0x75, 0x9e, 0x05, 0xa4, 0x4f, 0x06, 0xad, 0xd4, 0x02, 0xbd, 0x00, 0xbf, 0x40,
-	0x29, 0xa7, 0x73, 0x0c, 0xea, 0x7d, 0x32, 0x68, 0x27, 0x27, 0x91, 0x13, 0x7e, 0xb4, 0xed, 0xb4,
-	0x9a, 0x87, 0xf7, 0xd0, 0x79, 0xd1, 0x4c, 0x66, 0x22, 0x67, 0xf8, 0xbf, 0x68, 0x17, 0x9a, 0x1b,
-	0x8d, 0xda, 0x4a, 0xb6, 0x52, 0x0b, 0xe1, 0x07, 0x01, 0xdf, 0x89, 0xd2, 0x21, 0x78, 0x0b, 0x9c,
-	0xce, 0x50, 0x04, 0xa4, 0xdf, 0x18, 0xb4, 0x93, 0xb3, 0xbd, 0xab, 0xd1, 0x83, 0x99, 0x4e, 0xde,
-	0x94, 0x28, 0x53, 0xb7, 0x4a, 0x29, 0x1c, 0x30, 0x3e, 0x2b, 0x8d, 0xea, 0x61, 0x6a, 0xea, 0xde,
-	0x2d, 0xb4, 0x7f, 0xac, 0xd2, 0x0e, 0x34, 0x96, 0x58, 0x3a, 0x37, 0xbb, 0x72, 0xe7, 0x65, 0x3b,
-	0x5d, 0x7d, 0x7b, 0x31, 0x30, 0xaa, 0xdf, 0x90, 0xe4, 0x1d, 0xbc, 0xb1, 0x39, 0x4a, 0x13, 0xf0,
-	0x5d, 0x58, 0xf4, 0xb4, 0x32, 0xf2, 0x3b, 0xbd, 0xde, 0x51, 0xd5, 0xb7, 0xf1, 0xd6, 0xe8, 0x08,
-	0x5a, 0x5f, 0x69, 0xd0, 0xa0, 0x9a, 0xee, 0x07, 0xd4, 0xdb, 0x8f, 0x33, 0xac, 0x5d, 0x92, 0xf1,
-	0xf5, 0xeb, 0xd5, 0x3c, 0x57, 0x0b, 0xcd, 0xa2, 0x8c, 0x17, 0x71, 0x91, 0x67, 0x82, 0xbb, 0x77,
-	0x3b, 0x8c, 0xff, 0xfa, 0xec, 0x3b, 0x0b, 0xcc, 0x33, 0x34, 0xfc, 0x0c, 0x00, 0x00, 0xff, 0xff,
-	0xd9, 0x8d, 0xd7, 0xc3, 0x12, 0x02, 0x00, 0x00,
+	// 419 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x9c, 0x92, 0xcf, 0x6e, 0xd3, 0x40,
+	0x10, 0xc6, 0xbb, 0x71, 0xe3, 0xd4, 0x13, 0x5a, 0xaa, 0x51, 0x55, 0x59, 0xe1, 0x62, 0x7c, 0x32,
+	0x1c, 0x12, 0x94, 0x08, 0x01, 0xe5, 0xd4, 0x4a, 0x95, 0x10, 0x02, 0x01, 0xcb, 0x8d, 0x4b, 0x65,
+	0xaf, 0x27, 0xcd, 0x2a, 0x7f, 0x36, 0xdd, 0x5d, 0x07, 0xe5, 0x45, 0xb8, 0x71, 0xe3, 0x41, 0x91,
+	0xd7, 0xeb, 0x06, 0x02, 0xe2, 0xc0, 0xc5, 0x9a, 0xdf, 0x8c, 0x77, 0xf6, 0xdb, 0xf9, 0x06, 0x1e,
+	0x1b, 0xd2, 0x1b, 0x29, 0x68, 0x54, 0x68, 0x35, 0x27, 0x3d, 0x5a, 0x6b, 0x65, 0x95, 0x87, 0xa1,
+	0x03, 0x0c, 0x1b, 0x4a, 0x7b, 0xd0, 0xbd, 0x5e, 0xae, 0xed, 0x36, 0xfd, 0x04, 0x27, 0x1f, 0xab,
+	0x62, 0x21, 0xcd, 0x8c, 0xd3, 0x5d, 0x45, 0xc6, 0xe2, 0x19, 0x74, 0xad, 0x5a, 0x4b, 0x11, 0xb3,
+	0x84, 0x65, 0x11, 0x6f, 0x00, 0x9f, 0x40, 0x6f, 0x49, 0xc6, 0xe4, 0xb7, 0x14, 0x77, 0x12, 0x96,
+	0xf5, 0xc7, 0x0f, 0x87, 0xbe, 0xf1, 0xfb, 0x26, 0xcd, 0xdb, 0x7a, 0xfa, 0x8d, 0xc1, 0xe9, 0xe7,
+	0xaa, 0x30, 0x42, 0xcb, 0x82, 0xfe, 0xdd, 0xf5, 0x0c, 0xba, 0x77, 0x15, 0x55, 0x4d, 0xcf, 0x88,
+	0x37, 0x80, 0xe7, 0x10, 0x16, 0xd5, 0x74, 0x4a, 0x3a, 0x0e, 0x12, 0x96, 0x05, 0xdc, 0x13, 0x0e,
+	0xe0, 0x48, 0x6d, 0x48, 0x4f, 0x17, 0xea, 0x6b, 0x7c, 0xe8, 0x0e, 0xdc, 0x33, 0xa6, 0x70, 0x9c,
+	0xdb, 0x9b, 0x05, 0xe5, 0xc6, 0xde, 0xa8, 0x95, 0xa0, 0xb8, 0x9b, 0xb0, 0xec, 0x88, 0xf7, 0x73,
+	0xfb, 0xae, 0xce, 0x7d, 0x58, 0x09, 0x4a, 0xdf, 0x02, 0x5c, 0x8a, 0xf9, 0xff, 0x28, 0x3a, 0x85,
+	0x40, 0x96, 0x26, 0x0e, 0x92, 0x20, 0x8b, 0x78, 0x1d, 0xa6, 0xdf, 0x19, 0xf4, 0xfc, 0xcb, 0x71,
+	0x02, 0xe1, 0x8c, 0xf2, 0x92, 0x74, 0xcc, 0x92, 0x20, 0xeb, 0x8f, 0x1f, 0xed, 0x8d, 0x66, 0xf8,
+	0xc6, 0x55, 0xaf, 0x57, 0x56, 0x6f, 0xb9, 0xff, 0x15, 0x11, 0x0e, 0x0b, 0x55, 0x6e, 0xdd, 0x3d,
+	0x0f, 0xb8, 0x8b, 0xf1, 0x04, 0x3a, 0xb2, 0x74, 0x8f, 0x8e, 0x78, 0x47, 0x96, 0x83, 0x57, 0xd0,
+	0xff, 0xe5, 0x68, 0xad, 0x62, 0x4e, 0x5b, 0xaf, 0xb7, 0x0e, 0x6b, 0xb5, 0x9b, 0x7c, 0xb1, 0x53,
+	0xeb, 0xe0, 0xa2, 0xf3, 0x92, 0x8d, 0x7f, 0x30, 0x08, 0xaf, 0x9c, 0x0a, 0x1c, 0x43, 0xcf, 0x5b,
+	0x8c, 0xe7, 0xad, 0xb2, 0xdf, 0x3d, 0x1f, 0x1c, 0xb7, 0xf9, 0x66, 0x29, 0x0e, 0xf0, 0x02, 0xa2,
+	0x7b, 0x0b, 0x31, 0x6e, 0xab, 0xfb, 0xae, 0x0e, 0xf6, 0x97, 0x20, 0x3d, 0x78, 0xc6, 0xf0, 0x29,
+	0x04, 0x97, 0x62, 0x8e, 0xd8, 0xd6, 0x76, 0x33, 0xff, 0xe3, 0x9e, 0xab, 0x17, 0x5f, 0x9e, 0xdf,
+	0x4a, 0x3b, 0xab, 0x8a, 0xa1, 0x50, 0xcb, 0xd1, 0x52, 0x0a, 0xad, 0xfc, 0x77, 0x33, 0x19, 0xfd,
+	0x6d, 0x9d, 0x5f, 0x37, 0x50, 0x84, 0x8e, 0x26, 0x3f, 0x07, 0x00, 0x2f, 0x19, 0xf4, 0x49, 0xf4,
+	0x02, 0x00, 0x00,
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -244,6 +347,7 @@ const _ = grpc.SupportPackageIsVersion4
 type BrokerClient interface {
 	Publish(ctx context.Context, in *PublishRequest, opts ...grpc.CallOption) (*Empty, error)
 	Subscribe(ctx context.Context, in *SubscribeRequest, opts ...grpc.CallOption) (Broker_SubscribeClient, error)
+	Ack(ctx context.Context, in *AckRequest, opts ...grpc.CallOption) (*Empty, error)
 }
 
 type brokerClient struct {
@@ -295,10 +399,20 @@ func (x *brokerSubscribeClient) Recv() (*Message, error) {
 	return m, nil
 }
 
+func (c *brokerClient) Ack(ctx context.Context, in *AckRequest, opts ...grpc.CallOption) (*Empty, error) {
+	out := new(Empty)
+	err := c.cc.Invoke(ctx, "/broker.Broker/Ack", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // BrokerServer is the server API for Broker service.
 type BrokerServer interface {
 	Publish(context.Context, *PublishRequest) (*Empty, error)
 	Subscribe(*SubscribeRequest, Broker_SubscribeServer) error
+	Ack(context.Context, *AckRequest) (*Empty, error)
 }
 
 // UnimplementedBrokerServer can be embedded to have forward compatible implementations.
@@ -311,6 +425,9 @@ func (*UnimplementedBrokerServer) Publish(ctx context.Context, req *PublishReque
 func (*UnimplementedBrokerServer) Subscribe(req *SubscribeRequest, srv Broker_SubscribeServer) error {
 	return status.Errorf(codes.Unimplemented, "method Subscribe not implemented")
 }
+func (*UnimplementedBrokerServer) Ack(ctx context.Context, req *AckRequest) (*Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Ack not implemented")
+}
 
 func RegisterBrokerServer(s *grpc.Server, srv BrokerServer) {
 	s.RegisterService(&_Broker_serviceDesc, srv)
@@ -355,6 +472,24 @@ func (x *brokerSubscribeServer) Send(m *Message) error {
 	return x.ServerStream.SendMsg(m)
 }
 
+func _Broker_Ack_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AckRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BrokerServer).Ack(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/broker.Broker/Ack",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BrokerServer).Ack(ctx, req.(*AckRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 var _Broker_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "broker.Broker",
 	HandlerType: (*BrokerServer)(nil),
@@ -363,6 +498,10 @@ var _Broker_serviceDesc = grpc.ServiceDesc{
 			MethodName: "Publish",
 			Handler:    _Broker_Publish_Handler,
 		},
+		{
+			MethodName: "Ack",
+			Handler:    _Broker_Ack_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
@@ -44,6 +44,7 @@ func NewBrokerEndpoints() []*api.Endpoint {
 type BrokerService interface {
 	Publish(ctx context.Context, in *PublishRequest, opts ...client.CallOption) (*Empty, error)
 	Subscribe(ctx context.Context, in *SubscribeRequest, opts ...client.CallOption) (Broker_SubscribeService, error)
+	Ack(ctx context.Context, in *AckRequest, opts ...client.CallOption) (*Empty, error)
 }
 
 type brokerService struct {
@@ -117,17 +118,29 @@ func (x *brokerServiceSubscribe) Recv() (*Message, error) {
 	return m, nil
 }
 
+func (c *brokerService) Ack(ctx context.Context, in *AckRequest, opts ...client.CallOption) (*Empty, error) {
+	req := c.c.NewRequest(c.name, "Broker.Ack", in)
+	out := new(Empty)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // Server API for Broker service
 
 type BrokerHandler interface {
 	Publish(context.Context, *PublishRequest, *Empty) error
 	Subscribe(context.Context, *SubscribeRequest, Broker_SubscribeStream) error
+	Ack(context.Context, *AckRequest, *Empty) error
 }
 
 func RegisterBrokerHandler(s server.Server, hdlr BrokerHandler, opts ...server.HandlerOption) error {
 	type broker interface {
 		Publish(ctx context.Context, in *PublishRequest, out *Empty) error
 		Subscribe(ctx context.Context, stream server.Stream) error
+		Ack(ctx context.Context, in *AckRequest, out *Empty) error
 	}
 	type Broker struct {
 		broker
@@ -183,3 +196,7 @@ func (x *brokerSubscribeStream) RecvMsg(m interface{}) error {
 func (x *brokerSubscribeStream) Send(m *Message) error {
 	return x.stream.Send(m)
 }
+
+func (h *brokerHandler) Ack(ctx context.Context, in *AckRequest, out *Empty) error {
+	return h.BrokerHandler.Ack(ctx, in, out)
+}
@@ -2,10 +2,14 @@ package handler
 
 import (
 	"context"
+	"fmt"
+	"sync"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/micro/cli/v2"
 	"github.com/micro/go-micro/v3/broker"
+	goerrors "github.com/micro/go-micro/v3/errors"
 	"github.com/micro/micro/v3/internal/namespace"
 	"github.com/micro/micro/v3/service"
 	mubroker "github.com/micro/micro/v3/service/broker"
@@ -13,11 +17,37 @@ import (
 	"github.com/micro/micro/v3/service/errors"
 	"github.com/micro/micro/v3/service/logger"
 	log "github.com/micro/micro/v3/service/logger"
+	"github.com/micro/micro/v3/service/metrics"
 )
 
 var (
 	name    = "broker"
 	address = ":8003"
+
+	// Flags specific to the broker
+	Flags = []cli.Flag{
+		&cli.IntFlag{
+			Name:    "buffer_size",
+			Usage:   "Number of messages buffered per subscriber before the overflow policy applies",
+			EnvVars: []string{"MICRO_BROKER_BUFFER_SIZE"},
+			Value:   DefaultBufferSize,
+		},
+		&cli.StringFlag{
+			Name:    "overflow",
+			Usage:   "Policy for subscribers with a full buffer: block, drop-oldest or disconnect",
+			EnvVars: []string{"MICRO_BROKER_OVERFLOW"},
+			Value:   DefaultOverflow,
+		},
+		&cli.IntFlag{
+			Name:    "ack_timeout",
+			Usage:   "Seconds at-least-once subscribers have to ack a message before it's redelivered",
+			EnvVars: []string{"MICRO_BROKER_ACK_TIMEOUT"},
+			Value:   int(DefaultAckTimeout.Seconds()),
+		},
+	}
+
+	// redeliverFrequency is how often messages which weren't acked in time are redelivered
+	redeliverFrequency = time.Second
 )
 
 // Run the micro broker
@@ -34,6 +64,24 @@ func Run(ctx *cli.Context) error {
 		srvOpts = append(srvOpts, service.RegisterInterval(i*time.Second))
 	}
 
+	h := &handler{
+		bufferSize: DefaultBufferSize,
+		overflow:   DefaultOverflow,
+		ackTimeout: DefaultAckTimeout,
+	}
+	if i := ctx.Int("buffer_size"); i > 0 {
+		h.bufferSize = i
+	}
+	if o := ctx.String("overflow"); len(o) > 0 {
+		if !validOverflow(o) {
+			return fmt.Errorf("invalid overflow policy %q", o)
+		}
+		h.overflow = o
+	}
+	if i := ctx.Int("ack_timeout"); i > 0 {
+		h.ackTimeout = time.Duration(i) * time.Second
+	}
+
 	// new service
 	srv := service.New(srvOpts...)
 
@@ -41,7 +89,10 @@ func Run(ctx *cli.Context) error {
 	mubroker.DefaultBroker.Connect()
 
 	// register the broker handler
-	pb.RegisterBrokerHandler(srv.Server(), new(handler))
+	pb.RegisterBrokerHandler(srv.Server(), h)
+
+	// redeliver the messages which aren't acked in time
+	go h.watchAcks()
 
 	// run the service
 	if err := srv.Run(); err != nil {
@@ -50,7 +101,21 @@ func Run(ctx *cli.Context) error {
 	return nil
 }
 
-type handler struct{}
+type handler struct {
+	// default buffer size and overflow policy for subscribers
+	bufferSize int
+	overflow   string
+	// how long at-least-once subscribers have to ack messages
+	ackTimeout time.Duration
+	// undelivered messages of at-least-once queue subscriptions
+	pending pending
+	// messages sent to at-least-once subscribers which haven't been acked
+	unacked unacked
+
+	// subscribers of at-least-once queues, keyed by queue, which messages are redelivered to
+	sync.Mutex
+	subscribers map[string][]*subscriber
+}
 
 func (h *handler) Publish(ctx context.Context, req *pb.PublishRequest, rsp *pb.Empty) error {
 	ns := namespace.FromContext(ctx)
@@ -78,7 +143,6 @@ func (h *handler) Publish(ctx context.Context, req *pb.PublishRequest, rsp *pb.E
 
 func (h *handler) Subscribe(ctx context.Context, req *pb.SubscribeRequest, stream pb.Broker_SubscribeStream) error {
 	ns := namespace.FromContext(ctx)
-	errChan := make(chan error, 1)
 
 	// authorize the request
 	if err := namespace.Authorize(ctx, ns); err == namespace.ErrForbidden {
@@ -89,38 +153,175 @@ func (h *handler) Subscribe(ctx context.Context, req *pb.SubscribeRequest, strea
 		return errors.InternalServerError("broker.Broker.Subscribe", err.Error())
 	}
 
-	// message handler to stream back messages from broker
+	if len(req.Overflow) > 0 && !validOverflow(req.Overflow) {
+		return errors.BadRequest("broker.Broker.Subscribe", "invalid overflow policy %q", req.Overflow)
+	}
+	overflow := req.Overflow
+	if len(overflow) == 0 {
+		overflow = h.overflow
+	}
+	size := int(req.Buffer)
+	if size <= 0 {
+		size = h.bufferSize
+	}
+
+	topic := ns + "." + req.Topic
+	queue := ns + "." + req.Queue
+	sub := newSubscriber(topic, queue, overflow, size)
+
+	// redelivery only makes sense for a queue, otherwise every subscriber
+	// already receives its own copy of each message
+	atLeastOnce := req.AtLeastOnce && len(req.Queue) > 0
+	key := sub.key()
+	if atLeastOnce {
+		sub.redeliver = h.pending.take(key)
+		sub.acks = &h.unacked
+	}
+
+	// message handler to queue messages from the broker for the subscriber, messages of
+	// at-least-once subscriptions are given an id to ack them with
 	handler := func(m *broker.Message) error {
-		if err := stream.Send(&pb.Message{
+		msg := &pb.Message{
 			Header: m.Header,
 			Body:   m.Body,
-		}); err != nil {
-			select {
-			case errChan <- err:
-				return err
-			default:
-				return err
-			}
 		}
-		return nil
+		if atLeastOnce {
+			msg.Id = uuid.New().String()
+		}
+		return sub.push(msg)
 	}
 
 	log.Debugf("Subscribing to %s topic in namespace %v", req.Topic, ns)
-	sub, err := mubroker.DefaultBroker.Subscribe(ns+"."+req.Topic, handler, broker.Queue(ns+"."+req.Queue))
+	bsub, err := mubroker.DefaultBroker.Subscribe(topic, handler, broker.Queue(queue))
 	if err != nil {
+		if atLeastOnce {
+			h.pending.store(key, sub.redeliver)
+		}
 		return errors.InternalServerError("broker.Broker.Subscribe", err.Error())
 	}
-	defer func() {
-		log.Debugf("Unsubscribing from topic %s in namespace %v", req.Topic, ns)
-		sub.Unsubscribe()
-	}()
+
+	// stream messages back to the subscriber
+	go sub.run(stream)
+	if atLeastOnce {
+		h.addSubscriber(sub)
+	}
 
 	select {
 	case <-ctx.Done():
 		log.Debugf("Context done for subscription to topic %s", req.Topic)
-		return nil
-	case err := <-errChan:
-		log.Debugf("Subscription error for topic %s: %v", req.Topic, err)
-		return err
+	case <-sub.closed:
+		log.Debugf("Subscription error for topic %s: %v", req.Topic, sub.err)
+	}
+
+	log.Debugf("Unsubscribing from topic %s in namespace %v", req.Topic, ns)
+	bsub.Unsubscribe()
+	sub.close(nil)
+	<-sub.exit
+
+	// the messages sent but not acked are redelivered once the ack timeout expires
+	if atLeastOnce {
+		h.removeSubscriber(sub)
+		if msgs := sub.undelivered(); len(msgs) > 0 {
+			log.Debugf("Holding %d undelivered messages for queue %s on topic %s", len(msgs), req.Queue, req.Topic)
+			h.pending.store(key, msgs)
+		}
+	}
+
+	if sub.err == ErrOverflow {
+		return goerrors.New("broker.Broker.Subscribe", sub.err.Error(), 429)
+	}
+	return sub.err
+}
+
+func (h *handler) Ack(ctx context.Context, req *pb.AckRequest, rsp *pb.Empty) error {
+	ns := namespace.FromContext(ctx)
+
+	// authorize the request
+	if err := namespace.Authorize(ctx, ns); err == namespace.ErrForbidden {
+		return errors.Forbidden("broker.Broker.Ack", err.Error())
+	} else if err == namespace.ErrUnauthorized {
+		return errors.Unauthorized("broker.Broker.Ack", err.Error())
+	} else if err != nil {
+		return errors.InternalServerError("broker.Broker.Ack", err.Error())
+	}
+
+	if len(req.Queue) == 0 {
+		return errors.BadRequest("broker.Broker.Ack", "Missing queue")
+	}
+
+	h.unacked.ack(queueKey(ns+"."+req.Topic, ns+"."+req.Queue), req.Ids)
+	return nil
+}
+
+func (h *handler) addSubscriber(sub *subscriber) {
+	h.Lock()
+	defer h.Unlock()
+	if h.subscribers == nil {
+		h.subscribers = make(map[string][]*subscriber)
+	}
+	h.subscribers[sub.key()] = append(h.subscribers[sub.key()], sub)
+}
+
+func (h *handler) removeSubscriber(sub *subscriber) {
+	h.Lock()
+	defer h.Unlock()
+	subs := h.subscribers[sub.key()]
+	for i, s := range subs {
+		if s == sub {
+			subs = append(subs[:i], subs[i+1:]...)
+			break
+		}
+	}
+	if len(subs) == 0 {
+		delete(h.subscribers, sub.key())
+	} else {
+		h.subscribers[sub.key()] = subs
+	}
+}
+
+// watchAcks periodically redelivers the messages which weren't acked in time and should be run
+// in a separate go routine
+func (h *handler) watchAcks() {
+	ticker := time.NewTicker(redeliverFrequency)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		h.redeliver(time.Now())
+	}
+}
+
+// redeliver returns the messages which weren't acked within the ack timeout to their queue, e.g.
+// because the subscriber crashed while handling them. They're pushed to a subscriber of the queue
+// or held until the next one subscribes.
+func (h *handler) redeliver(now time.Time) {
+	for key, msgs := range h.unacked.expired(now.Add(-h.ackTimeout)) {
+		log.Debugf("Redelivering %d messages which weren't acked for queue %s", len(msgs), key)
+		h.pending.store(key, msgs)
+	}
+
+	h.Lock()
+	subs := make(map[string]*subscriber, len(h.subscribers))
+	for key, s := range h.subscribers {
+		subs[key] = s[0]
+	}
+	h.Unlock()
+
+	for key, sub := range subs {
+		msgs := h.pending.take(key)
+		if len(msgs) == 0 {
+			continue
+		}
+
+		// pushing blocks while the buffer of the subscriber is full, depending on its overflow
+		// policy, so the other queues aren't delayed by it
+		go func(key string, sub *subscriber, msgs []*pb.Message) {
+			for i, m := range msgs {
+				if err := sub.push(m); err != nil {
+					h.pending.store(key, msgs[i:])
+					return
+				}
+				metrics.Count("broker.subscriber.redelivered", 1, sub.tags())
+			}
+		}(key, sub, msgs)
 	}
 }
@@ -0,0 +1,116 @@
+package handler
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/micro/go-micro/v3/auth"
+	"github.com/micro/go-micro/v3/broker"
+	"github.com/micro/go-micro/v3/broker/memory"
+	"github.com/micro/micro/v3/internal/namespace"
+	mubroker "github.com/micro/micro/v3/service/broker"
+	pb "github.com/micro/micro/v3/service/broker/proto"
+)
+
+type testStream struct {
+	pb.Broker_SubscribeStream
+	msgs chan *pb.Message
+}
+
+func (s *testStream) Send(m *pb.Message) error {
+	s.msgs <- m
+	return nil
+}
+
+// testSubscribe subscribes to the at-least-once queue and returns the stream and a func which
+// closes the subscription as if the subscriber crashed
+func testSubscribe(t *testing.T, h *handler, ctx context.Context) (*testStream, func()) {
+	ctx, cancel := context.WithCancel(ctx)
+	stream := &testStream{msgs: make(chan *pb.Message, 10)}
+	done := make(chan error)
+	go func() {
+		done <- h.Subscribe(ctx, &pb.SubscribeRequest{Topic: "foo", Queue: "bar", AtLeastOnce: true}, stream)
+	}()
+
+	for i := 0; ; i++ {
+		h.Lock()
+		n := len(h.subscribers)
+		h.Unlock()
+		if n > 0 {
+			break
+		} else if i == 100 {
+			t.Fatal("Expected the subscription to be made")
+		}
+		time.Sleep(time.Millisecond * 10)
+	}
+
+	return stream, func() {
+		cancel()
+		if err := <-done; err != nil {
+			t.Fatalf("Unexpected error from the subscription: %v", err)
+		}
+	}
+}
+
+func testReceive(t *testing.T, stream *testStream) *pb.Message {
+	select {
+	case m := <-stream.msgs:
+		return m
+	case <-time.After(time.Second):
+		t.Fatal("Expected a message to be received")
+		return nil
+	}
+}
+
+func TestRedeliverUnacked(t *testing.T) {
+	defer func(b broker.Broker) { mubroker.DefaultBroker = b }(mubroker.DefaultBroker)
+	mubroker.DefaultBroker = memory.NewBroker()
+	mubroker.DefaultBroker.Connect()
+
+	h := &handler{bufferSize: 10, overflow: OverflowBlock, ackTimeout: time.Minute}
+	ctx := auth.ContextWithAccount(context.TODO(), &auth.Account{Issuer: namespace.DefaultNamespace})
+	publish := func(body string) {
+		req := &pb.PublishRequest{Topic: "foo", Message: testMessage(body)}
+		if err := h.Publish(ctx, req, &pb.Empty{}); err != nil {
+			t.Fatalf("Unexpected error publishing: %v", err)
+		}
+	}
+
+	// the subscriber crashes after the message is sent to it but before it's acked
+	stream, crash := testSubscribe(t, h, ctx)
+	publish("1")
+	sent := testReceive(t, stream)
+	if len(sent.Id) == 0 {
+		t.Fatal("Expected the message of an at-least-once subscription to have an id")
+	}
+	crash()
+
+	// the message isn't redelivered until the ack timeout expires
+	stream, unsubscribe := testSubscribe(t, h, ctx)
+	defer unsubscribe()
+	h.redeliver(time.Now())
+	select {
+	case m := <-stream.msgs:
+		t.Fatalf("Expected the message not to be redelivered before the ack timeout, got %v", string(m.Body))
+	case <-time.After(time.Millisecond * 50):
+	}
+
+	h.redeliver(time.Now().Add(time.Minute + time.Second))
+	if m := testReceive(t, stream); m.Id != sent.Id || string(m.Body) != "1" {
+		t.Fatalf("Expected the unacked message to be redelivered, got %v", string(m.Body))
+	}
+
+	// acked messages aren't redelivered
+	publish("2")
+	m := testReceive(t, stream)
+	if err := h.Ack(ctx, &pb.AckRequest{Topic: "foo", Queue: "bar", Ids: []string{sent.Id, m.Id}}, &pb.Empty{}); err != nil {
+		t.Fatalf("Unexpected error acking: %v", err)
+	}
+	h.redeliver(time.Now().Add(time.Minute + time.Second))
+	select {
+	case m := <-stream.msgs:
+		t.Fatalf("Expected acked messages not to be redelivered, got %v", string(m.Body))
+	case <-time.After(time.Millisecond * 50):
+	}
+}
@@ -0,0 +1,314 @@
+package handler
+
+import (
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	gometrics "github.com/micro/go-micro/v3/metrics"
+	pb "github.com/micro/micro/v3/service/broker/proto"
+	"github.com/micro/micro/v3/service/metrics"
+)
+
+const (
+	// OverflowBlock blocks the broker until the subscriber has room in its buffer
+	OverflowBlock = "block"
+	// OverflowDropOldest discards the oldest buffered message to make room for the new one
+	OverflowDropOldest = "drop-oldest"
+	// OverflowDisconnect closes the subscription once the buffer is full
+	OverflowDisconnect = "disconnect"
+)
+
+var (
+	// DefaultBufferSize is the number of messages buffered per subscriber
+	DefaultBufferSize = 64
+	// DefaultOverflow is the policy applied when a subscriber's buffer is full
+	DefaultOverflow = OverflowBlock
+	// MaxPending is the number of undelivered messages kept per queue for redelivery
+	MaxPending = 1024
+	// DefaultAckTimeout is how long at-least-once subscribers have to ack a message before
+	// it's redelivered to the queue
+	DefaultAckTimeout = time.Second * 30
+
+	// ErrOverflow is returned when a subscriber is disconnected because it fell too far behind
+	ErrOverflow = errors.New("subscriber buffer is full")
+	// ErrClosed is returned when publishing to a subscriber which has gone away
+	ErrClosed = errors.New("subscriber is closed")
+)
+
+// validOverflow returns true if p is a known overflow policy
+func validOverflow(p string) bool {
+	switch p {
+	case OverflowBlock, OverflowDropOldest, OverflowDisconnect:
+		return true
+	}
+	return false
+}
+
+// subscriber decouples the broker from the grpc stream of a single subscription. Messages
+// from the broker are queued in a bounded buffer and sent to the stream by a separate
+// goroutine so a slow consumer never blocks the broker beyond what its overflow policy allows.
+type subscriber struct {
+	topic    string
+	queue    string
+	overflow string
+	buffer   chan *pb.Message
+
+	// redeliver holds messages to send before anything in the buffer
+	redeliver []*pb.Message
+	// unsent is the message which failed to be written to the stream
+	unsent *pb.Message
+	// acks holds the messages sent to the subscriber until they're acked, it's only set for
+	// at-least-once subscriptions
+	acks *unacked
+
+	once   sync.Once
+	err    error
+	exit   chan bool
+	closed chan bool
+}
+
+func newSubscriber(topic, queue, overflow string, size int) *subscriber {
+	if size <= 0 {
+		size = DefaultBufferSize
+	}
+	if !validOverflow(overflow) {
+		overflow = DefaultOverflow
+	}
+	return &subscriber{
+		topic:    topic,
+		queue:    queue,
+		overflow: overflow,
+		buffer:   make(chan *pb.Message, size),
+		exit:     make(chan bool),
+		closed:   make(chan bool),
+	}
+}
+
+// queueKey returns the key of the queue of a topic, which at-least-once messages are held under
+func queueKey(topic, queue string) string {
+	return topic + ":" + queue
+}
+
+func (s *subscriber) key() string {
+	return queueKey(s.topic, s.queue)
+}
+
+func (s *subscriber) tags() gometrics.Tags {
+	return gometrics.Tags{"topic": s.topic, "queue": s.queue, "overflow": s.overflow}
+}
+
+// close stops the subscriber, recording err as the reason if it is the first call
+func (s *subscriber) close(err error) {
+	s.once.Do(func() {
+		s.err = err
+		close(s.closed)
+	})
+}
+
+// push is the broker handler which queues a message for the subscriber
+func (s *subscriber) push(m *pb.Message) error {
+	select {
+	case <-s.closed:
+		return ErrClosed
+	default:
+	}
+
+	switch s.overflow {
+	case OverflowDropOldest:
+		for {
+			select {
+			case s.buffer <- m:
+				metrics.Gauge("broker.subscriber.lag", float64(len(s.buffer)), s.tags())
+				return nil
+			default:
+			}
+			// make room by discarding the head of the buffer
+			select {
+			case <-s.buffer:
+				metrics.Count("broker.subscriber.dropped", 1, s.tags())
+			default:
+			}
+		}
+	case OverflowDisconnect:
+		select {
+		case s.buffer <- m:
+		default:
+			metrics.Count("broker.subscriber.dropped", 1, s.tags())
+			s.close(ErrOverflow)
+			return ErrOverflow
+		}
+	default:
+		select {
+		case s.buffer <- m:
+		case <-s.closed:
+			return ErrClosed
+		}
+	}
+
+	metrics.Gauge("broker.subscriber.lag", float64(len(s.buffer)), s.tags())
+	return nil
+}
+
+// run writes queued messages to the stream until the subscriber is closed
+func (s *subscriber) run(stream pb.Broker_SubscribeStream) {
+	defer close(s.exit)
+
+	send := func(m *pb.Message) bool {
+		// the message is held before it's sent so the ack can't arrive first
+		if s.acks != nil {
+			s.acks.add(s.key(), m)
+		}
+		if err := stream.Send(m); err != nil {
+			if s.acks != nil {
+				s.acks.ack(s.key(), []string{m.Id})
+			}
+			s.unsent = m
+			s.close(err)
+			return false
+		}
+		metrics.Count("broker.subscriber.delivered", 1, s.tags())
+		return true
+	}
+
+	for len(s.redeliver) > 0 {
+		m := s.redeliver[0]
+		s.redeliver = s.redeliver[1:]
+		if !send(m) {
+			return
+		}
+		metrics.Count("broker.subscriber.redelivered", 1, s.tags())
+	}
+
+	for {
+		select {
+		case <-s.closed:
+			return
+		case m := <-s.buffer:
+			if !send(m) {
+				return
+			}
+			metrics.Gauge("broker.subscriber.lag", float64(len(s.buffer)), s.tags())
+		}
+	}
+}
+
+// undelivered returns every message which was not written to the stream. It
+// must only be called once the broker subscription and the run loop have exited.
+func (s *subscriber) undelivered() []*pb.Message {
+	var msgs []*pb.Message
+	if s.unsent != nil {
+		msgs = append(msgs, s.unsent)
+	}
+	msgs = append(msgs, s.redeliver...)
+	for {
+		select {
+		case m := <-s.buffer:
+			msgs = append(msgs, m)
+		default:
+			return msgs
+		}
+	}
+}
+
+// pending holds messages awaiting redelivery for at-least-once queue subscriptions
+type pending struct {
+	sync.Mutex
+	messages map[string][]*pb.Message
+}
+
+// take removes and returns the messages pending for a queue
+func (p *pending) take(queue string) []*pb.Message {
+	p.Lock()
+	defer p.Unlock()
+	msgs := p.messages[queue]
+	delete(p.messages, queue)
+	return msgs
+}
+
+// store appends messages to a queue, discarding the oldest beyond MaxPending
+func (p *pending) store(queue string, msgs []*pb.Message) {
+	if len(msgs) == 0 {
+		return
+	}
+	p.Lock()
+	defer p.Unlock()
+	if p.messages == nil {
+		p.messages = make(map[string][]*pb.Message)
+	}
+	msgs = append(p.messages[queue], msgs...)
+	if over := len(msgs) - MaxPending; over > 0 {
+		metrics.Count("broker.subscriber.dropped", int64(over), gometrics.Tags{"queue": queue})
+		msgs = msgs[over:]
+	}
+	p.messages[queue] = msgs
+	metrics.Gauge("broker.queue.pending", float64(len(msgs)), gometrics.Tags{"queue": queue})
+}
+
+// unacked holds the messages sent to the subscribers of at-least-once queues until they're acked
+type unacked struct {
+	sync.Mutex
+	messages map[string]map[string]*unackedMessage
+}
+
+type unackedMessage struct {
+	message *pb.Message
+	sent    time.Time
+}
+
+// add holds a message sent to a subscriber of the queue
+func (u *unacked) add(queue string, m *pb.Message) {
+	u.Lock()
+	defer u.Unlock()
+	if u.messages == nil {
+		u.messages = make(map[string]map[string]*unackedMessage)
+	}
+	if u.messages[queue] == nil {
+		u.messages[queue] = make(map[string]*unackedMessage)
+	}
+	u.messages[queue][m.Id] = &unackedMessage{message: m, sent: time.Now()}
+}
+
+// ack removes the messages with the ids, unknown ids are ignored since the message may have
+// expired and been redelivered already
+func (u *unacked) ack(queue string, ids []string) {
+	u.Lock()
+	defer u.Unlock()
+	for _, id := range ids {
+		delete(u.messages[queue], id)
+	}
+	if len(u.messages[queue]) == 0 {
+		delete(u.messages, queue)
+	}
+}
+
+// expired removes and returns the messages sent before the time by queue, in the order they
+// were sent
+func (u *unacked) expired(before time.Time) map[string][]*pb.Message {
+	u.Lock()
+	defer u.Unlock()
+
+	result := make(map[string][]*pb.Message)
+	for queue, msgs := range u.messages {
+		var exp []*unackedMessage
+		for id, m := range msgs {
+			if m.sent.Before(before) {
+				exp = append(exp, m)
+				delete(msgs, id)
+			}
+		}
+		if len(msgs) == 0 {
+			delete(u.messages, queue)
+		}
+		if len(exp) == 0 {
+			continue
+		}
+
+		sort.Slice(exp, func(i, j int) bool { return exp[i].sent.Before(exp[j].sent) })
+		for _, m := range exp {
+			result[queue] = append(result[queue], m.message)
+		}
+	}
+	return result
+}
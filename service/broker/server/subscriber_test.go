@@ -0,0 +1,98 @@
+package handler
+
+import (
+	"testing"
+	"time"
+
+	pb "github.com/micro/micro/v3/service/broker/proto"
+)
+
+func testMessage(body string) *pb.Message {
+	return &pb.Message{Body: []byte(body)}
+}
+
+func TestSubscriberDropOldest(t *testing.T) {
+	sub := newSubscriber("topic", "queue", OverflowDropOldest, 2)
+	for _, b := range []string{"1", "2", "3"} {
+		if err := sub.push(testMessage(b)); err != nil {
+			t.Fatalf("Unexpected error pushing message %v: %v", b, err)
+		}
+	}
+
+	msgs := sub.undelivered()
+	if len(msgs) != 2 {
+		t.Fatalf("Expected 2 buffered messages, got %v", len(msgs))
+	}
+	if string(msgs[0].Body) != "2" || string(msgs[1].Body) != "3" {
+		t.Errorf("Expected the oldest message to be dropped, got %v and %v", string(msgs[0].Body), string(msgs[1].Body))
+	}
+}
+
+func TestSubscriberDisconnect(t *testing.T) {
+	sub := newSubscriber("topic", "queue", OverflowDisconnect, 1)
+	if err := sub.push(testMessage("1")); err != nil {
+		t.Fatalf("Unexpected error pushing message: %v", err)
+	}
+	if err := sub.push(testMessage("2")); err != ErrOverflow {
+		t.Fatalf("Expected ErrOverflow, got %v", err)
+	}
+	if sub.err != ErrOverflow {
+		t.Errorf("Expected the subscriber to be closed with ErrOverflow, got %v", sub.err)
+	}
+	if err := sub.push(testMessage("3")); err != ErrClosed {
+		t.Errorf("Expected ErrClosed, got %v", err)
+	}
+}
+
+func TestSubscriberBlockUnblocksOnClose(t *testing.T) {
+	sub := newSubscriber("topic", "queue", OverflowBlock, 1)
+	if err := sub.push(testMessage("1")); err != nil {
+		t.Fatalf("Unexpected error pushing message: %v", err)
+	}
+
+	errChan := make(chan error)
+	go func() { errChan <- sub.push(testMessage("2")) }()
+
+	sub.close(nil)
+	if err := <-errChan; err != ErrClosed {
+		t.Errorf("Expected ErrClosed, got %v", err)
+	}
+}
+
+func TestPendingStore(t *testing.T) {
+	max := MaxPending
+	MaxPending = 2
+	defer func() { MaxPending = max }()
+
+	var p pending
+	p.store("q", []*pb.Message{testMessage("1"), testMessage("2")})
+	p.store("q", []*pb.Message{testMessage("3")})
+
+	msgs := p.take("q")
+	if len(msgs) != 2 || string(msgs[0].Body) != "2" {
+		t.Fatalf("Expected the two newest messages, got %v", msgs)
+	}
+	if msgs := p.take("q"); len(msgs) != 0 {
+		t.Errorf("Expected no pending messages after take, got %v", len(msgs))
+	}
+}
+
+func TestUnackedExpired(t *testing.T) {
+	var u unacked
+	first, second := testMessage("1"), testMessage("2")
+	first.Id, second.Id = "1", "2"
+	u.add("q", first)
+	u.add("q", second)
+	u.ack("q", []string{"1", "unknown"})
+
+	if msgs := u.expired(time.Now().Add(-time.Minute)); len(msgs) != 0 {
+		t.Fatalf("Expected no expired messages, got %v", msgs)
+	}
+	msgs := u.expired(time.Now().Add(time.Minute))
+	if len(msgs["q"]) != 1 || msgs["q"][0].Id != "2" {
+		t.Fatalf("Expected the unacked message to expire, got %v", msgs)
+	}
+	if msgs := u.expired(time.Now().Add(time.Minute)); len(msgs) != 0 {
+		t.Errorf("Expected the expired messages to be removed, got %v", msgs)
+	}
+}
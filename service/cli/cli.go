@@ -155,6 +155,7 @@ var srvCommands = []srvCommand{
 	{
 		Name:    "broker",
 		Command: broker.Run,
+		Flags:   broker.Flags,
 	},
 	{
 		Name:    "config",
@@ -183,6 +184,7 @@ var srvCommands = []srvCommand{
 	{
 		Name:    "registry",
 		Command: registry.Run,
+		Flags:   registry.Flags,
 	},
 	{
 		Name:    "router",
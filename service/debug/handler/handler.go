@@ -9,6 +9,7 @@ import (
 	"github.com/micro/go-micro/v3/debug/log"
 	"github.com/micro/go-micro/v3/debug/stats"
 	"github.com/micro/go-micro/v3/debug/trace"
+	"github.com/micro/micro/v3/internal/breaker"
 	"github.com/micro/micro/v3/service/debug"
 	pb "github.com/micro/micro/v3/service/debug/proto"
 )
@@ -58,6 +59,21 @@ func (d *Debug) Stats(ctx context.Context, req *pb.StatsRequest, rsp *pb.StatsRe
 	rsp.Requests = stats[0].Requests
 	rsp.Errors = stats[0].Errors
 
+	// the circuit breakers of the services called, e.g. by the proxy
+	for _, b := range breaker.DefaultBreakers.Stats() {
+		rsp.Breakers = append(rsp.Breakers, &pb.Breaker{
+			Namespace: b.Namespace,
+			Service:   b.Service,
+			Node:      b.Node,
+			State:     b.State,
+			Requests:  uint64(b.Requests),
+			Errors:    uint64(b.Errors),
+			Slow:      uint64(b.Slow),
+			Opened:    b.Opened,
+			Trips:     uint64(b.Trips),
+		})
+	}
+
 	return nil
 }
 
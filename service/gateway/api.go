@@ -4,9 +4,8 @@ package gateway
 import (
 	"fmt"
 	"net/http"
-	"os"
+	"time"
 
-	"github.com/go-acme/lego/v3/providers/dns/cloudflare"
 	"github.com/gorilla/mux"
 	"github.com/micro/cli/v2"
 	ahandler "github.com/micro/go-micro/v3/api/handler"
@@ -21,22 +20,26 @@ import (
 	"github.com/micro/go-micro/v3/api/resolver/path"
 	"github.com/micro/go-micro/v3/api/router"
 	"github.com/micro/go-micro/v3/api/server"
-	"github.com/micro/go-micro/v3/api/server/acme"
-	"github.com/micro/go-micro/v3/api/server/acme/autocert"
-	"github.com/micro/go-micro/v3/api/server/acme/certmagic"
 	httpapi "github.com/micro/go-micro/v3/api/server/http"
-	"github.com/micro/go-micro/v3/sync/memory"
 	"github.com/micro/micro/v3/client"
+	"github.com/micro/micro/v3/internal/acme"
+	"github.com/micro/micro/v3/internal/breaker"
 	"github.com/micro/micro/v3/internal/handler"
 	"github.com/micro/micro/v3/internal/helper"
+	"github.com/micro/micro/v3/internal/openapi"
 	rrmicro "github.com/micro/micro/v3/internal/resolver/api"
 	"github.com/micro/micro/v3/internal/stats"
 	"github.com/micro/micro/v3/plugin"
 	"github.com/micro/micro/v3/service"
 	"github.com/micro/micro/v3/service/api/auth"
+	"github.com/micro/micro/v3/service/api/cache"
+	"github.com/micro/micro/v3/service/api/ratelimit"
+	"github.com/micro/micro/v3/service/api/routes"
 	log "github.com/micro/micro/v3/service/logger"
 	muregistry "github.com/micro/micro/v3/service/registry"
-	"github.com/micro/micro/v3/service/store"
+
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/h2c"
 
 	gwRouter "github.com/micro/micro/v3/service/gateway/router"
 	regRouter "github.com/micro/micro/v3/service/gateway/router/registry"
@@ -45,19 +48,16 @@ import (
 const gatewayModule = "gateway"
 
 var (
-	Name                  = "go.micro.api"
-	Address               = ":8080"
-	Handler               = "meta"
-	Resolver              = "micro"
-	RPCPath               = "/rpc"
-	APIPath               = "/"
-	ProxyPath             = "/{service:[a-zA-Z0-9]+}"
-	Namespace             = "go.micro"
-	HeaderPrefix          = "X-Micro-"
-	EnableRPC             = false
-	ACMEProvider          = "autocert"
-	ACMEChallengeProvider = "cloudflare"
-	ACMECA                = acme.LetsEncryptProductionCA
+	Name         = "go.micro.api"
+	Address      = ":8080"
+	Handler      = "meta"
+	Resolver     = "micro"
+	RPCPath      = "/rpc"
+	APIPath      = "/"
+	ProxyPath    = "/{service:[a-zA-Z0-9]+}"
+	Namespace    = "go.micro"
+	HeaderPrefix = "X-Micro-"
+	EnableRPC    = false
 )
 
 func Run(ctx *cli.Context, gwOpt ...gwRouter.Option) error {
@@ -76,9 +76,6 @@ func Run(ctx *cli.Context, gwOpt ...gwRouter.Option) error {
 	if len(ctx.String("enable_rpc")) > 0 {
 		EnableRPC = ctx.Bool("enable_rpc")
 	}
-	if len(ctx.String("acme_provider")) > 0 {
-		ACMEProvider = ctx.String("acme_provider")
-	}
 	if len(ctx.String("namespace")) > 0 {
 		Namespace = ctx.String("namespace")
 	}
@@ -91,56 +88,32 @@ func Run(ctx *cli.Context, gwOpt ...gwRouter.Option) error {
 	if len(ctx.String("api_namespace")) > 0 {
 		Namespace = ctx.String("api_namespace")
 	}
+	// fail fast for failing services and eject failing nodes from selection
+	srvOpts := []service.Option{service.Name(Name)}
+	if ctx.Bool("enable_breaker") {
+		breaker.DefaultBreakers = breaker.New(
+			breaker.ErrorRate(ctx.Float64("breaker_error_rate")),
+			breaker.MinRequests(ctx.Int("breaker_min_requests")),
+			breaker.Latency(ctx.Duration("breaker_latency")),
+			breaker.Cooldown(ctx.Duration("breaker_cooldown")),
+		)
+		srvOpts = append(srvOpts, service.WrapClient(breaker.DefaultBreakers.Wrapper))
+	}
+
 	// initialise service
-	srv := service.New(service.Name(Name))
+	srv := service.New(srvOpts...)
 
 	// Init API
 	var opts []server.Option
 
 	if ctx.Bool("enable_acme") {
-		hosts := helper.ACMEHosts(ctx)
-		opts = append(opts, server.EnableACME(true))
-		opts = append(opts, server.ACMEHosts(hosts...))
-		switch ACMEProvider {
-		case "autocert":
-			opts = append(opts, server.ACMEProvider(autocert.NewProvider()))
-		case "certmagic":
-			if ACMEChallengeProvider != "cloudflare" {
-				log.Fatal("The only implemented DNS challenge provider is cloudflare")
-			}
-
-			apiToken := os.Getenv("CF_API_TOKEN")
-			if len(apiToken) == 0 {
-				log.Fatal("env variables CF_API_TOKEN and CF_ACCOUNT_ID must be set")
-			}
-
-			storage := certmagic.NewStorage(
-				memory.NewSync(),
-				store.DefaultStore,
-			)
-
-			config := cloudflare.NewDefaultConfig()
-			config.AuthToken = apiToken
-			config.ZoneToken = apiToken
-			challengeProvider, err := cloudflare.NewDNSProviderConfig(config)
-			if err != nil {
-				log.Fatal(err.Error())
-			}
-
-			opts = append(opts,
-				server.ACMEProvider(
-					certmagic.NewProvider(
-						acme.AcceptToS(true),
-						acme.CA(ACMECA),
-						acme.Cache(storage),
-						acme.ChallengeProvider(challengeProvider),
-						acme.OnDemand(false),
-					),
-				),
-			)
-		default:
-			log.Fatalf("%s is not a valid ACME provider\n", ACMEProvider)
+		ap, err := acme.NewProvider(ctx)
+		if err != nil {
+			log.Fatal(err)
 		}
+		opts = append(opts, server.EnableACME(true))
+		opts = append(opts, server.ACMEHosts(helper.ACMEHosts(ctx)...))
+		opts = append(opts, server.ACMEProvider(ap))
 	} else if ctx.Bool("enable_tls") {
 		config, err := helper.TLSConfig(ctx)
 		if err != nil {
@@ -148,6 +121,11 @@ func Run(ctx *cli.Context, gwOpt ...gwRouter.Option) error {
 			return err
 		}
 
+		// negotiate http/2 so gRPC clients can connect using tls
+		if ctx.Bool("enable_grpc") {
+			config.NextProtos = append(config.NextProtos, "h2", "http/1.1")
+		}
+
 		opts = append(opts, server.EnableTLS(true))
 		opts = append(opts, server.TLSConfig(config))
 	}
@@ -182,6 +160,18 @@ func Run(ctx *cli.Context, gwOpt ...gwRouter.Option) error {
 	// strip favicon.ico
 	r.HandleFunc("/favicon.ico", func(w http.ResponseWriter, r *http.Request) {})
 
+	// serve the openapi spec of the services and optionally the docs ui, which loads its
+	// assets from a CDN
+	r.Handle("/openapi.json", openapi.Handler(
+		muregistry.DefaultRegistry,
+		openapi.ServicePrefix(Namespace),
+		openapi.APIVersion(ctx.App.Version),
+	))
+	if ctx.Bool("enable_cdn_docs") {
+		log.Infof("Registering API Docs at /docs")
+		r.Handle("/docs", openapi.DocsHandler("Micro API", "/openapi.json"))
+	}
+
 	// resolver options
 	ropts := []resolver.Option{
 		resolver.WithServicePrefix(Namespace),
@@ -200,12 +190,33 @@ func Run(ctx *cli.Context, gwOpt ...gwRouter.Option) error {
 		rr = grpc.NewResolver(ropts...)
 	}
 
+	// register the handler of the route rules, they take priority over the routes derived from
+	// the names of services and endpoints
+	rts := routes.New()
+	r.MatcherFunc(func(r *http.Request, _ *mux.RouteMatch) bool {
+		return rts.Matches(r)
+	}).Handler(rts.Handler())
+
+	// register the grpc handler, grpc requests are matched by content type rather than path
+	if ctx.Bool("enable_grpc") {
+		log.Infof("Registering gRPC Handler")
+		r.MatcherFunc(func(r *http.Request, _ *mux.RouteMatch) bool {
+			return handler.IsGRPC(r)
+		}).Handler(handler.NewGRPCHandler(Namespace))
+	}
+
 	// register rpc handler
 	if EnableRPC {
 		log.Infof("Registering RPC Handler at %s", RPCPath)
 		r.Handle(RPCPath, handler.NewRPCHandler(rr))
+		log.Infof("Registering RPC Stream Handler at %s", RPCPath+"/stream")
+		r.Handle(RPCPath+"/stream", handler.NewStreamHandler())
 	}
 
+	// the router of the meta handler, websockets to its streaming endpoints are bridged to
+	// streams
+	var streamRouter router.Router
+
 	switch Handler {
 	case "rpc":
 		log.Infof("Registering API RPC Handler at %s", APIPath)
@@ -285,6 +296,7 @@ func Run(ctx *cli.Context, gwOpt ...gwRouter.Option) error {
 			router.WithRegistry(muregistry.DefaultRegistry),
 		)
 		r.PathPrefix(APIPath).Handler(handler.Meta(srv, rt, Namespace))
+		streamRouter = rt
 	}
 
 	// register all the http handler plugins
@@ -294,9 +306,46 @@ func Run(ctx *cli.Context, gwOpt ...gwRouter.Option) error {
 		}
 	}
 
-	// create the auth wrapper and the server
-	authWrapper := auth.Wrapper(rr, Namespace)
-	api := httpapi.NewServer(Address, server.WrapHandler(authWrapper))
+	// create the rate limit wrapper, the limits are loaded from config
+	rlOpts := []ratelimit.Option{ratelimit.TrustProxy(ctx.Bool("ratelimit_trust_proxy"))}
+	if ctx.Bool("ratelimit_store") {
+		rlOpts = append(rlOpts, ratelimit.WithLimiter(ratelimit.NewStoreLimiter()))
+	}
+
+	// create the auth wrapper, the auth wrapper sets the namespace used by the rate limits so it
+	// wraps the rate limit wrapper. grpc requests and requests matching a route rule are resolved
+	// to the endpoint they are sent to.
+	// websockets to streaming endpoints without credentials are authenticated by the stream
+	var streamPaths []string
+	if EnableRPC {
+		streamPaths = append(streamPaths, RPCPath+"/stream")
+	}
+	isStream := handler.StreamMatcher(streamRouter, streamPaths...)
+	authWrapper := auth.Wrapper(handler.NewGRPCResolver(rts.Resolver(rr), Namespace), Namespace,
+		auth.Streams(func(req *http.Request) bool {
+			return !rts.Matches(req) && isStream(req)
+		}),
+	)
+	wrappers := []server.Wrapper{ratelimit.Wrapper(rlOpts...), authWrapper}
+
+	// the cache is keyed by the account so it's wrapped by the auth wrapper, cached responses
+	// are still rate limited
+	if ctx.Bool("enable_cache") {
+		var cacheOpts []cache.Option
+		if ctx.Bool("cache_store") {
+			cacheOpts = append(cacheOpts, cache.WithBackend(cache.NewStoreBackend()))
+		}
+		wrappers = append([]server.Wrapper{cache.Wrapper(cacheOpts...)}, wrappers...)
+	}
+
+	// accept http/2 requests without tls, which native gRPC clients make
+	if ctx.Bool("enable_grpc") {
+		wrappers = append(wrappers, func(h http.Handler) http.Handler {
+			return h2c.NewHandler(h, &http2.Server{})
+		})
+	}
+
+	api := httpapi.NewServer(Address, server.WrapHandler(wrappers...))
 
 	api.Init(opts...)
 	api.Handle("/", h)
@@ -374,6 +423,66 @@ func Commands(opt ...gwRouter.Option) *cli.Command {
 				EnvVars: []string{"MICRO_API_ENABLE_CORS"},
 				Value:   true,
 			},
+			&cli.BoolFlag{
+				Name:    "enable_grpc",
+				Usage:   "Enable forwarding gRPC and gRPC-Web requests to services",
+				EnvVars: []string{"MICRO_API_ENABLE_GRPC"},
+				Value:   true,
+			},
+			&cli.BoolFlag{
+				Name:    "enable_cdn_docs",
+				Usage:   "Enable the docs UI for the API at /docs, the browser loads swagger ui from the unpkg.com CDN",
+				EnvVars: []string{"MICRO_API_ENABLE_CDN_DOCS"},
+			},
+			&cli.BoolFlag{
+				Name:    "enable_cache",
+				Usage:   "Enable caching the responses of routes set in config or with a Cache-Control max-age",
+				EnvVars: []string{"MICRO_API_ENABLE_CACHE"},
+			},
+			&cli.BoolFlag{
+				Name:    "cache_store",
+				Usage:   "Share the cache between replicas of the API using the store",
+				EnvVars: []string{"MICRO_API_CACHE_STORE"},
+			},
+			&cli.BoolFlag{
+				Name:    "ratelimit_store",
+				Usage:   "Share the rate limits between replicas of the API using the store",
+				EnvVars: []string{"MICRO_API_RATELIMIT_STORE"},
+			},
+			&cli.BoolFlag{
+				Name:    "ratelimit_trust_proxy",
+				Usage:   "Rate limit clients by the X-Forwarded-For header, only enable it if the API is behind a proxy",
+				EnvVars: []string{"MICRO_API_RATELIMIT_TRUST_PROXY"},
+			},
+			&cli.BoolFlag{
+				Name:    "enable_breaker",
+				Usage:   "Enable circuit breakers which fail fast for failing services and eject failing nodes",
+				EnvVars: []string{"MICRO_API_ENABLE_BREAKER"},
+				Value:   true,
+			},
+			&cli.Float64Flag{
+				Name:    "breaker_error_rate",
+				Usage:   "Set the rate of failed or slow calls which opens a circuit breaker",
+				EnvVars: []string{"MICRO_API_BREAKER_ERROR_RATE"},
+				Value:   0.5,
+			},
+			&cli.IntFlag{
+				Name:    "breaker_min_requests",
+				Usage:   "Set the number of calls in a window before a circuit breaker can open",
+				EnvVars: []string{"MICRO_API_BREAKER_MIN_REQUESTS"},
+				Value:   20,
+			},
+			&cli.DurationFlag{
+				Name:    "breaker_latency",
+				Usage:   "Set the latency above which calls count against a circuit breaker e.g 5s, latency is ignored by default",
+				EnvVars: []string{"MICRO_API_BREAKER_LATENCY"},
+			},
+			&cli.DurationFlag{
+				Name:    "breaker_cooldown",
+				Usage:   "Set how long an open circuit breaker rejects calls before probing the service or node",
+				EnvVars: []string{"MICRO_API_BREAKER_COOLDOWN"},
+				Value:   time.Second * 30,
+			},
 		),
 	}
 
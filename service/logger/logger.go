@@ -115,3 +115,8 @@ func Fatalf(template string, args ...interface{}) {
 func V(lvl Level, logger logger.Logger) bool {
 	return logger.Options().Level <= lvl
 }
+
+// SetFields sets the fields included in every record, e.g. the name of the service
+func SetFields(f map[string]interface{}) {
+	fields = f
+}
@@ -17,6 +17,7 @@ import (
 	clic "github.com/micro/micro/v3/internal/command"
 	"github.com/micro/micro/v3/service/client"
 	"github.com/micro/micro/v3/service/context"
+	pb "github.com/micro/micro/v3/service/network/proto"
 	"github.com/olekukonko/tablewriter"
 )
 
@@ -39,6 +40,13 @@ func init() {
 				Name:   "graph",
 				Usage:  "Get the network graph",
 				Action: util.Print(networkGraph),
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:  "format",
+						Usage: "Set the output format; json (default), dot, mermaid",
+						Value: "json",
+					},
+				},
 			},
 			{
 				Name:   "nodes",
@@ -70,6 +78,10 @@ func init() {
 						Name:  "network",
 						Usage: "Filter by network",
 					},
+					&cli.BoolFlag{
+						Name:  "trace",
+						Usage: "Show the hop by hop path of the routes to the service",
+					},
 				},
 			},
 			{
@@ -175,17 +187,74 @@ func networkConnections(c *cli.Context, args []string) ([]byte, error) {
 }
 
 func networkGraph(c *cli.Context, args []string) ([]byte, error) {
+	format := c.String("format")
+	switch format {
+	case "json", "dot", "mermaid":
+	default:
+		return nil, fmt.Errorf("Invalid format %q, must be json, dot or mermaid", format)
+	}
 
-	var rsp map[string]interface{}
+	g, err := getGraph(&pb.RoutesRequest{})
+	if err != nil {
+		return nil, err
+	}
 
-	req := client.NewRequest("network", "Network.Graph", map[string]interface{}{}, goclient.WithContentType("application/json"))
-	err := client.Call(context.DefaultContext, req, &rsp, goclient.WithAuthToken())
+	switch format {
+	case "dot":
+		return renderDot(g), nil
+	case "mermaid":
+		return renderMermaid(g), nil
+	default:
+		return renderJSON(g)
+	}
+}
+
+// getGraph builds the graph from the topology and the routes matching the request
+func getGraph(req *pb.RoutesRequest) (*graph, error) {
+	cli := pb.NewNetworkService("network", client.DefaultClient)
+
+	grsp, err := cli.Graph(context.DefaultContext, &pb.GraphRequest{}, goclient.WithAuthToken())
 	if err != nil {
 		return nil, err
 	}
 
-	b, _ := json.MarshalIndent(rsp, "", "\t")
-	return b, nil
+	rrsp, err := cli.Routes(context.DefaultContext, req, goclient.WithAuthToken())
+	if err != nil {
+		return nil, err
+	}
+
+	return newGraph(grsp.Root, rrsp.Routes), nil
+}
+
+// networkTrace shows the path of each of the routes to a service
+func networkTrace(c *cli.Context) ([]byte, error) {
+	if len(c.String("service")) == 0 {
+		return nil, fmt.Errorf("--service is required to trace routes")
+	}
+
+	req := &pb.RoutesRequest{Query: &pb.Query{
+		Service: c.String("service"),
+		Address: c.String("address"),
+		Gateway: c.String("gateway"),
+		Router:  c.String("router"),
+		Network: c.String("network"),
+	}}
+
+	cli := pb.NewNetworkService("network", client.DefaultClient)
+	rsp, err := cli.Routes(context.DefaultContext, req, goclient.WithAuthToken())
+	if err != nil {
+		return nil, err
+	}
+	if len(rsp.Routes) == 0 {
+		return []byte(fmt.Sprintf("No routes to %s", req.Query.Service)), nil
+	}
+
+	g, err := getGraph(&pb.RoutesRequest{})
+	if err != nil {
+		return nil, err
+	}
+
+	return renderTrace(g, rsp.Routes), nil
 }
 
 func networkNodes(c *cli.Context, args []string) ([]byte, error) {
@@ -230,6 +299,9 @@ func networkNodes(c *cli.Context, args []string) ([]byte, error) {
 }
 
 func networkRoutes(c *cli.Context, args []string) ([]byte, error) {
+	if c.Bool("trace") {
+		return networkTrace(c)
+	}
 
 	query := map[string]string{}
 
@@ -0,0 +1,282 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	pb "github.com/micro/micro/v3/service/network/proto"
+	pbRtr "github.com/micro/micro/v3/service/router/proto"
+)
+
+// graphNode is a node of the network topology
+type graphNode struct {
+	Id      string `json:"id"`
+	Address string `json:"address"`
+	Network string `json:"network,omitempty"`
+	Errors  uint32 `json:"errors"`
+	Error   string `json:"error,omitempty"`
+}
+
+// graphLink is a link between two nodes of the network. The metric and number of
+// routes are only known for the links of the root node since the metrics of the
+// routes are calculated by the node the routing table is read from.
+type graphLink struct {
+	From   string `json:"from"`
+	To     string `json:"to"`
+	Metric int64  `json:"metric,omitempty"`
+	Routes int    `json:"routes,omitempty"`
+}
+
+// graph is the network topology as seen from the root node
+type graph struct {
+	Root  string       `json:"root"`
+	Nodes []*graphNode `json:"nodes"`
+	Links []*graphLink `json:"links"`
+}
+
+// newGraph builds the graph from the peer topology and the routing table of the root node
+func newGraph(root *pb.Peer, routes []*pbRtr.Route) *graph {
+	g := &graph{}
+	if root == nil || root.Node == nil {
+		return g
+	}
+	g.Root = root.Node.Id
+
+	seen := make(map[string]bool)
+	links := make(map[[2]string]bool)
+
+	var walk func(p *pb.Peer)
+	walk = func(p *pb.Peer) {
+		if p.Node == nil {
+			return
+		}
+		if !seen[p.Node.Id] {
+			seen[p.Node.Id] = true
+			n := &graphNode{Id: p.Node.Id, Address: p.Node.Address, Network: p.Node.Network}
+			if p.Node.Status != nil && p.Node.Status.Error != nil {
+				n.Errors = p.Node.Status.Error.Count
+				n.Error = p.Node.Status.Error.Msg
+			}
+			g.Nodes = append(g.Nodes, n)
+		}
+
+		for _, peer := range p.Peers {
+			if peer.Node == nil {
+				continue
+			}
+			key := [2]string{p.Node.Id, peer.Node.Id}
+			// links are bidirectional so only render them once
+			if links[key] || links[[2]string{key[1], key[0]}] {
+				continue
+			}
+			links[key] = true
+
+			l := &graphLink{From: p.Node.Id, To: peer.Node.Id}
+			if p.Node.Id == g.Root {
+				l.Metric, l.Routes = linkMetric(peer.Node, routes)
+			}
+			g.Links = append(g.Links, l)
+
+			walk(peer)
+		}
+	}
+	walk(root)
+
+	return g
+}
+
+// linkMetric returns the lowest metric and the number of the routes via the peer
+func linkMetric(peer *pb.Node, routes []*pbRtr.Route) (int64, int) {
+	var metric int64
+	var count int
+
+	for _, r := range routes {
+		if r.Gateway != peer.Address {
+			continue
+		}
+		if count == 0 || r.Metric < metric {
+			metric = r.Metric
+		}
+		count++
+	}
+
+	return metric, count
+}
+
+// node returns the node with the id or address
+func (g *graph) node(key string) *graphNode {
+	for _, n := range g.Nodes {
+		if n.Id == key || n.Address == key {
+			return n
+		}
+	}
+	return nil
+}
+
+// path returns the nodes from the root to the node with the id
+func (g *graph) path(id string) []*graphNode {
+	// breadth first search over the links
+	adj := make(map[string][]string)
+	for _, l := range g.Links {
+		adj[l.From] = append(adj[l.From], l.To)
+		adj[l.To] = append(adj[l.To], l.From)
+	}
+
+	prev := map[string]string{g.Root: ""}
+	queue := []string{g.Root}
+	for len(queue) > 0 && len(id) > 0 {
+		cur := queue[0]
+		queue = queue[1:]
+		if cur == id {
+			break
+		}
+		for _, next := range adj[cur] {
+			if _, ok := prev[next]; ok {
+				continue
+			}
+			prev[next] = cur
+			queue = append(queue, next)
+		}
+	}
+
+	if _, ok := prev[id]; !ok {
+		return nil
+	}
+
+	var path []*graphNode
+	for cur := id; len(cur) > 0; cur = prev[cur] {
+		if n := g.node(cur); n != nil {
+			path = append([]*graphNode{n}, path...)
+		}
+	}
+	return path
+}
+
+func formatMetric(m int64) string {
+	if m == math.MaxInt64 {
+		return "∞"
+	}
+	return fmt.Sprintf("%d", m)
+}
+
+// linkLabel returns the label of a link, blank if there are no metrics for it
+func linkLabel(l *graphLink) string {
+	if l.Routes == 0 {
+		return ""
+	}
+	return fmt.Sprintf("metric %s, %d routes", formatMetric(l.Metric), l.Routes)
+}
+
+// renderJSON renders the graph as JSON
+func renderJSON(g *graph) ([]byte, error) {
+	return json.MarshalIndent(g, "", "\t")
+}
+
+// renderDot renders the graph in the graphviz DOT language
+func renderDot(g *graph) []byte {
+	b := bytes.NewBuffer(nil)
+
+	fmt.Fprintln(b, "graph network {")
+	for _, n := range g.Nodes {
+		label := fmt.Sprintf("%s\\n%s", n.Id, n.Address)
+		attrs := []string{fmt.Sprintf("label=%q", label)}
+		if n.Id == g.Root {
+			attrs = append(attrs, "shape=doublecircle")
+		}
+		if n.Errors > 0 {
+			attrs = append(attrs, "color=red")
+		}
+		fmt.Fprintf(b, "\t%q [%s];\n", n.Id, strings.Join(attrs, ", "))
+	}
+	for _, l := range g.Links {
+		if label := linkLabel(l); len(label) > 0 {
+			fmt.Fprintf(b, "\t%q -- %q [label=%q];\n", l.From, l.To, label)
+		} else {
+			fmt.Fprintf(b, "\t%q -- %q;\n", l.From, l.To)
+		}
+	}
+	fmt.Fprint(b, "}")
+
+	return b.Bytes()
+}
+
+// renderMermaid renders the graph as a mermaid flowchart
+func renderMermaid(g *graph) []byte {
+	b := bytes.NewBuffer(nil)
+
+	// mermaid ids can't contain most punctuation so the nodes are numbered
+	ids := make(map[string]string, len(g.Nodes))
+	for i, n := range g.Nodes {
+		ids[n.Id] = fmt.Sprintf("n%d", i)
+	}
+
+	fmt.Fprintln(b, "graph LR")
+	for _, n := range g.Nodes {
+		label := fmt.Sprintf("%s<br/>%s", n.Id, n.Address)
+		if n.Id == g.Root {
+			fmt.Fprintf(b, "\t%s((\"%s\"))\n", ids[n.Id], label)
+		} else {
+			fmt.Fprintf(b, "\t%s[\"%s\"]\n", ids[n.Id], label)
+		}
+	}
+	for _, l := range g.Links {
+		if label := linkLabel(l); len(label) > 0 {
+			fmt.Fprintf(b, "\t%s ---|\"%s\"| %s\n", ids[l.From], label, ids[l.To])
+		} else {
+			fmt.Fprintf(b, "\t%s --- %s\n", ids[l.From], ids[l.To])
+		}
+	}
+
+	return bytes.TrimRight(b.Bytes(), "\n")
+}
+
+// renderTrace renders the hop by hop path of each route, the route a call would take first
+func renderTrace(g *graph, routes []*pbRtr.Route) []byte {
+	b := bytes.NewBuffer(nil)
+
+	// the client picks the route with the lowest metric
+	sort.SliceStable(routes, func(i, j int) bool {
+		return routes[i].Metric < routes[j].Metric
+	})
+
+	for i, r := range routes {
+		if i > 0 {
+			fmt.Fprintln(b)
+		}
+		selected := ""
+		if i == 0 {
+			selected = " (selected)"
+		}
+		fmt.Fprintf(b, "%s %s metric %s%s\n", r.Service, r.Address, formatMetric(r.Metric), selected)
+
+		// routes advertised by the root node are served locally
+		hops := g.path(r.Router)
+		if len(hops) == 0 {
+			if root := g.node(g.Root); root != nil {
+				hops = []*graphNode{root}
+			}
+			// the gateway is the next hop when the router isn't in the graph
+			if gw := g.node(r.Gateway); gw != nil && gw.Id != g.Root {
+				hops = append(hops, gw)
+			}
+		}
+
+		for n, hop := range hops {
+			via := ""
+			if n > 0 {
+				via = fmt.Sprintf(" via %s link", r.Link)
+			}
+			fmt.Fprintf(b, "  %d. %s %s%s\n", n+1, hop.Id, hop.Address, via)
+		}
+		fmt.Fprintf(b, "  %d. %s %s", len(hops)+1, r.Service, r.Address)
+		if i < len(routes)-1 {
+			fmt.Fprintln(b)
+		}
+	}
+
+	return b.Bytes()
+}
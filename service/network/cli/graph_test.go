@@ -0,0 +1,79 @@
+package cli
+
+import (
+	"strings"
+	"testing"
+
+	pb "github.com/micro/micro/v3/service/network/proto"
+	pbRtr "github.com/micro/micro/v3/service/router/proto"
+)
+
+func testTopology() (*pb.Peer, []*pbRtr.Route) {
+	root := &pb.Peer{
+		Node: &pb.Node{Id: "a", Address: "10.0.0.1:8085"},
+		Peers: []*pb.Peer{
+			{
+				Node: &pb.Node{Id: "b", Address: "10.0.0.2:8085"},
+				Peers: []*pb.Peer{
+					{Node: &pb.Node{Id: "c", Address: "10.0.0.3:8085"}},
+					// the root is listed as a peer of its peers
+					{Node: &pb.Node{Id: "a", Address: "10.0.0.1:8085"}},
+				},
+			},
+		},
+	}
+
+	routes := []*pbRtr.Route{
+		{Service: "helloworld", Address: "10.0.0.3:9090", Gateway: "10.0.0.2:8085", Router: "c", Link: "network", Metric: 20},
+		{Service: "helloworld", Address: "10.0.0.1:9090", Router: "a", Link: "local", Metric: 1},
+	}
+
+	return root, routes
+}
+
+func TestGraph(t *testing.T) {
+	g := newGraph(testTopology())
+
+	if len(g.Nodes) != 3 {
+		t.Fatalf("Expected 3 nodes, got %d", len(g.Nodes))
+	}
+	if len(g.Links) != 2 {
+		t.Fatalf("Expected 2 links, got %d", len(g.Links))
+	}
+	if l := g.Links[0]; l.From != "a" || l.To != "b" || l.Metric != 20 || l.Routes != 1 {
+		t.Fatalf("Unexpected root link %+v", l)
+	}
+
+	dot := string(renderDot(g))
+	if !strings.Contains(dot, `"a" -- "b" [label="metric 20, 1 routes"];`) || !strings.Contains(dot, `"b" -- "c";`) {
+		t.Fatalf("Unexpected dot output:\n%s", dot)
+	}
+
+	mermaid := string(renderMermaid(g))
+	if !strings.HasPrefix(mermaid, "graph LR") || !strings.Contains(mermaid, "n1 --- n2") {
+		t.Fatalf("Unexpected mermaid output:\n%s", mermaid)
+	}
+
+	if p := g.path("c"); len(p) != 3 || p[0].Id != "a" || p[2].Id != "c" {
+		t.Fatalf("Unexpected path to c: %v", p)
+	}
+}
+
+func TestTrace(t *testing.T) {
+	root, routes := testTopology()
+	trace := string(renderTrace(newGraph(root, routes), routes))
+
+	expected := `helloworld 10.0.0.1:9090 metric 1 (selected)
+  1. a 10.0.0.1:8085
+  2. helloworld 10.0.0.1:9090
+
+helloworld 10.0.0.3:9090 metric 20
+  1. a 10.0.0.1:8085
+  2. b 10.0.0.2:8085 via network link
+  3. c 10.0.0.3:8085 via network link
+  4. helloworld 10.0.0.3:9090`
+
+	if trace != expected {
+		t.Fatalf("Unexpected trace:\n%s\nexpected:\n%s", trace, expected)
+	}
+}
@@ -21,7 +21,9 @@ import (
 	"github.com/micro/go-micro/v3/server"
 	mucpServer "github.com/micro/go-micro/v3/server/mucp"
 	"github.com/micro/micro/v3/internal/helper"
+	"github.com/micro/micro/v3/internal/mtls"
 	"github.com/micro/micro/v3/internal/muxer"
+	"github.com/micro/micro/v3/internal/namespace"
 	"github.com/micro/micro/v3/service"
 	log "github.com/micro/micro/v3/service/logger"
 	muregistry "github.com/micro/micro/v3/service/registry"
@@ -115,7 +117,14 @@ func Run(ctx *cli.Context) error {
 		tunnel.Token(token),
 	}
 
-	if ctx.Bool("enable_tls") {
+	// the peers of the tunnel verify each other using their mutual TLS certificates, only the
+	// network service is accepted as a peer
+	if m := mtls.DefaultManager; m != nil {
+		peer := namespace.DefaultNamespace + "/" + name
+		tunOpts = append(tunOpts, tunnel.Transport(
+			grpc.NewTransport(transport.TLSConfig(m.PeerConfig(peer))),
+		))
+	} else if ctx.Bool("enable_tls") {
 		config, err := helper.TLSConfig(ctx)
 		if err != nil {
 			fmt.Println(err.Error())
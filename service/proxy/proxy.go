@@ -2,14 +2,10 @@
 package proxy
 
 import (
-	"os"
 	"strings"
+	"time"
 
-	"github.com/go-acme/lego/v3/providers/dns/cloudflare"
 	"github.com/micro/cli/v2"
-	"github.com/micro/go-micro/v3/api/server/acme"
-	"github.com/micro/go-micro/v3/api/server/acme/autocert"
-	"github.com/micro/go-micro/v3/api/server/acme/certmagic"
 	bmem "github.com/micro/go-micro/v3/broker/memory"
 	"github.com/micro/go-micro/v3/proxy"
 	"github.com/micro/go-micro/v3/proxy/grpc"
@@ -18,15 +14,16 @@ import (
 	"github.com/micro/go-micro/v3/registry/noop"
 	"github.com/micro/go-micro/v3/server"
 	sgrpc "github.com/micro/go-micro/v3/server/grpc"
-	"github.com/micro/go-micro/v3/sync/memory"
 	"github.com/micro/micro/v3/client"
+	"github.com/micro/micro/v3/internal/acme"
+	"github.com/micro/micro/v3/internal/breaker"
 	"github.com/micro/micro/v3/internal/helper"
+	"github.com/micro/micro/v3/internal/mtls"
 	"github.com/micro/micro/v3/internal/muxer"
 	"github.com/micro/micro/v3/service"
 	muclient "github.com/micro/micro/v3/service/client"
 	log "github.com/micro/micro/v3/service/logger"
 	murouter "github.com/micro/micro/v3/service/router"
-	"github.com/micro/micro/v3/service/store"
 )
 
 var (
@@ -38,10 +35,6 @@ var (
 	Protocol = "grpc"
 	// The endpoint host to route to
 	Endpoint string
-	// ACME (Cert management)
-	ACMEProvider          = "autocert"
-	ACMEChallengeProvider = "cloudflare"
-	ACMECA                = acme.LetsEncryptProductionCA
 )
 
 func Run(ctx *cli.Context) error {
@@ -57,12 +50,21 @@ func Run(ctx *cli.Context) error {
 	if len(ctx.String("protocol")) > 0 {
 		Protocol = ctx.String("protocol")
 	}
-	if len(ctx.String("acme_provider")) > 0 {
-		ACMEProvider = ctx.String("acme_provider")
+
+	// fail fast for failing services and eject failing nodes from selection
+	srvOpts := []service.Option{service.Name(Name)}
+	if ctx.Bool("enable_breaker") {
+		breaker.DefaultBreakers = breaker.New(
+			breaker.ErrorRate(ctx.Float64("breaker_error_rate")),
+			breaker.MinRequests(ctx.Int("breaker_min_requests")),
+			breaker.Latency(ctx.Duration("breaker_latency")),
+			breaker.Cooldown(ctx.Duration("breaker_cooldown")),
+		)
+		srvOpts = append(srvOpts, service.WrapClient(breaker.DefaultBreakers.Wrapper))
 	}
 
 	// new service
-	service := service.New(service.Name(Name))
+	service := service.New(srvOpts...)
 
 	// set the context
 	popts := []proxy.Option{
@@ -97,44 +99,9 @@ func Run(ctx *cli.Context) error {
 
 	// enable acme will create a net.Listener which
 	if ctx.Bool("enable_acme") {
-		var ap acme.Provider
-
-		switch ACMEProvider {
-		case "autocert":
-			ap = autocert.NewProvider()
-		case "certmagic":
-			if ACMEChallengeProvider != "cloudflare" {
-				log.Fatal("The only implemented DNS challenge provider is cloudflare")
-			}
-
-			apiToken := os.Getenv("CF_API_TOKEN")
-			if len(apiToken) == 0 {
-				log.Fatal("env variables CF_API_TOKEN and CF_ACCOUNT_ID must be set")
-			}
-
-			storage := certmagic.NewStorage(
-				memory.NewSync(),
-				store.DefaultStore,
-			)
-
-			config := cloudflare.NewDefaultConfig()
-			config.AuthToken = apiToken
-			config.ZoneToken = apiToken
-			challengeProvider, err := cloudflare.NewDNSProviderConfig(config)
-			if err != nil {
-				log.Fatal(err.Error())
-			}
-
-			// define the provider
-			ap = certmagic.NewProvider(
-				acme.AcceptToS(true),
-				acme.CA(ACMECA),
-				acme.Cache(storage),
-				acme.ChallengeProvider(challengeProvider),
-				acme.OnDemand(false),
-			)
-		default:
-			log.Fatalf("Unsupported acme provider: %s\n", ACMEProvider)
+		ap, err := acme.NewProvider(ctx)
+		if err != nil {
+			log.Fatal(err)
 		}
 
 		// generate the tls config
@@ -146,6 +113,10 @@ func Run(ctx *cli.Context) error {
 		// set the tls config
 		serverOpts = append(serverOpts, server.TLSConfig(config))
 		// enable tls will leverage tls certs and generate a tls.Config
+	} else if mtls.DefaultManager != nil {
+		// the proxy is the entrypoint of clients such as the CLI which may not have a
+		// certificate, their requests are still authenticated using their tokens
+		serverOpts = append(serverOpts, server.TLSConfig(mtls.DefaultManager.BootstrapConfig()))
 	} else if ctx.Bool("enable_tls") {
 		// get certificates from the context
 		config, err := helper.TLSConfig(ctx)
@@ -228,5 +199,34 @@ var (
 			Usage:   "Set the endpoint to route to e.g greeter or localhost:9090",
 			EnvVars: []string{"MICRO_PROXY_ENDPOINT"},
 		},
+		&cli.BoolFlag{
+			Name:    "enable_breaker",
+			Usage:   "Enable circuit breakers which fail fast for failing services and eject failing nodes",
+			EnvVars: []string{"MICRO_PROXY_ENABLE_BREAKER"},
+			Value:   true,
+		},
+		&cli.Float64Flag{
+			Name:    "breaker_error_rate",
+			Usage:   "Set the rate of failed or slow calls which opens a circuit breaker",
+			EnvVars: []string{"MICRO_PROXY_BREAKER_ERROR_RATE"},
+			Value:   0.5,
+		},
+		&cli.IntFlag{
+			Name:    "breaker_min_requests",
+			Usage:   "Set the number of calls in a window before a circuit breaker can open",
+			EnvVars: []string{"MICRO_PROXY_BREAKER_MIN_REQUESTS"},
+			Value:   20,
+		},
+		&cli.DurationFlag{
+			Name:    "breaker_latency",
+			Usage:   "Set the latency above which calls count against a circuit breaker e.g 5s, latency is ignored by default",
+			EnvVars: []string{"MICRO_PROXY_BREAKER_LATENCY"},
+		},
+		&cli.DurationFlag{
+			Name:    "breaker_cooldown",
+			Usage:   "Set how long an open circuit breaker rejects calls before probing the service or node",
+			EnvVars: []string{"MICRO_PROXY_BREAKER_COOLDOWN"},
+			Value:   time.Second * 30,
+		},
 	)
 )
@@ -0,0 +1,75 @@
+// Package cli implements the `micro registry` subcommands
+// for example:
+//   micro registry peers
+package cli
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/micro/cli/v2"
+	goclient "github.com/micro/go-micro/v3/client"
+	"github.com/micro/micro/v3/cmd"
+	"github.com/micro/micro/v3/internal/helper"
+	"github.com/micro/micro/v3/service/client"
+	"github.com/micro/micro/v3/service/context"
+	"github.com/micro/micro/v3/service/errors"
+	pb "github.com/micro/micro/v3/service/registry/proto"
+)
+
+func init() {
+	cmd.Register(&cli.Command{
+		Name:   "registry",
+		Usage:  "Commands for inspecting the registry",
+		Action: helper.UnexpectedSubcommand,
+		Subcommands: []*cli.Command{
+			{
+				Name:   "peers",
+				Usage:  "List the remote registries services are imported from",
+				Action: listPeers,
+			},
+		},
+	})
+}
+
+func listPeers(ctx *cli.Context) error {
+	cli := pb.NewFederationService("registry", client.DefaultClient)
+
+	rsp, err := cli.Peers(context.DefaultContext, &pb.PeersRequest{}, goclient.WithAuthToken())
+	if verr := errors.Parse(err); verr != nil {
+		return fmt.Errorf("Error listing peers: %v", verr.Detail)
+	} else if err != nil {
+		return fmt.Errorf("Error listing peers: %v", err)
+	}
+
+	if len(rsp.Peers) == 0 {
+		fmt.Println("No peers configured")
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 8, 1, '\t', 0)
+	defer w.Flush()
+
+	fmt.Fprintln(w, strings.Join([]string{"ADDRESS", "DOMAIN", "STATUS", "SERVICES", "NODES", "CONFLICTS", "LAST SYNC", "ERROR"}, "\t"))
+	for _, p := range rsp.Peers {
+		lastSync := "never"
+		if p.LastSync > 0 {
+			lastSync = time.Unix(p.LastSync, 0).Format(time.RFC3339)
+		}
+		fmt.Fprintln(w, strings.Join([]string{
+			p.Address,
+			p.Domain,
+			p.Status,
+			fmt.Sprintf("%d", p.Services),
+			fmt.Sprintf("%d", p.Nodes),
+			fmt.Sprintf("%d", p.Conflicts),
+			lastSync,
+			p.Error,
+		}, "\t"))
+	}
+
+	return nil
+}
@@ -92,9 +92,13 @@ func (s *srv) GetService(name string, opts ...registry.GetOption) ([]*registry.S
 		o(&options)
 	}
 
-	rsp, err := s.client.GetService(context.DefaultContext, &pb.GetRequest{
-		Service: name, Options: &pb.Options{Domain: options.Domain},
-	}, s.callOpts()...)
+	req := &pb.GetRequest{Service: name, Options: &pb.Options{Domain: options.Domain}}
+	if options.Context != nil {
+		req.Selector, _ = options.Context.Value(selectorKey{}).(string)
+		req.All, _ = options.Context.Value(allKey{}).(bool)
+	}
+
+	rsp, err := s.client.GetService(context.DefaultContext, req, s.callOpts()...)
 
 	if verr := errors.Parse(err); verr != nil && verr.Code == 404 {
 		return nil, registry.ErrNotFound
@@ -116,6 +120,10 @@ func (s *srv) ListServices(opts ...registry.ListOption) ([]*registry.Service, er
 	}
 
 	req := &pb.ListRequest{Options: &pb.Options{Domain: options.Domain}}
+	if options.Context != nil {
+		req.Selector, _ = options.Context.Value(selectorKey{}).(string)
+		req.All, _ = options.Context.Value(allKey{}).(bool)
+	}
 	rsp, err := s.client.ListServices(context.DefaultContext, req, s.callOpts()...)
 	if err != nil {
 		return nil, err
@@ -19,3 +19,48 @@ func WithClient(c client.Client) registry.Option {
 		o.Context = context.WithValue(o.Context, clientKey{}, c)
 	}
 }
+
+type selectorKey struct{}
+type allKey struct{}
+
+// GetSelector filters the nodes returned by the registry service on version and metadata
+// e.g. version=~1.2,region=eu
+func GetSelector(s string) registry.GetOption {
+	return func(o *registry.GetOptions) {
+		if o.Context == nil {
+			o.Context = context.Background()
+		}
+		o.Context = context.WithValue(o.Context, selectorKey{}, s)
+	}
+}
+
+// GetAll includes the nodes which failed their health check
+func GetAll() registry.GetOption {
+	return func(o *registry.GetOptions) {
+		if o.Context == nil {
+			o.Context = context.Background()
+		}
+		o.Context = context.WithValue(o.Context, allKey{}, true)
+	}
+}
+
+// ListSelector filters the services listed by the registry service on version and metadata
+// e.g. version=~1.2,region=eu
+func ListSelector(s string) registry.ListOption {
+	return func(o *registry.ListOptions) {
+		if o.Context == nil {
+			o.Context = context.Background()
+		}
+		o.Context = context.WithValue(o.Context, selectorKey{}, s)
+	}
+}
+
+// ListAll includes the nodes which failed their health check
+func ListAll() registry.ListOption {
+	return func(o *registry.ListOptions) {
+		if o.Context == nil {
+			o.Context = context.Background()
+		}
+		o.Context = context.WithValue(o.Context, allKey{}, true)
+	}
+}
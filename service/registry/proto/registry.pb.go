@@ -453,8 +453,12 @@ func (m *EmptyResponse) XXX_DiscardUnknown() {
 var xxx_messageInfo_EmptyResponse proto.InternalMessageInfo
 
 type GetRequest struct {
-	Service              string   `protobuf:"bytes,1,opt,name=service,proto3" json:"service,omitempty"`
-	Options              *Options `protobuf:"bytes,2,opt,name=options,proto3" json:"options,omitempty"`
+	Service string   `protobuf:"bytes,1,opt,name=service,proto3" json:"service,omitempty"`
+	Options *Options `protobuf:"bytes,2,opt,name=options,proto3" json:"options,omitempty"`
+	// selector on version and node metadata e.g. version=~1.2,region=eu
+	Selector string `protobuf:"bytes,3,opt,name=selector,proto3" json:"selector,omitempty"`
+	// include nodes which failed their health check
+	All                  bool     `protobuf:"varint,4,opt,name=all,proto3" json:"all,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -499,6 +503,20 @@ func (m *GetRequest) GetOptions() *Options {
 	return nil
 }
 
+func (m *GetRequest) GetSelector() string {
+	if m != nil {
+		return m.Selector
+	}
+	return ""
+}
+
+func (m *GetRequest) GetAll() bool {
+	if m != nil {
+		return m.All
+	}
+	return false
+}
+
 type GetResponse struct {
 	Services             []*Service `protobuf:"bytes,1,rep,name=services,proto3" json:"services,omitempty"`
 	XXX_NoUnkeyedLiteral struct{}   `json:"-"`
@@ -539,7 +557,11 @@ func (m *GetResponse) GetServices() []*Service {
 }
 
 type ListRequest struct {
-	Options              *Options `protobuf:"bytes,1,opt,name=options,proto3" json:"options,omitempty"`
+	Options *Options `protobuf:"bytes,1,opt,name=options,proto3" json:"options,omitempty"`
+	// selector on version and node metadata e.g. version=~1.2,region=eu
+	Selector string `protobuf:"bytes,2,opt,name=selector,proto3" json:"selector,omitempty"`
+	// include nodes which failed their health check
+	All                  bool     `protobuf:"varint,3,opt,name=all,proto3" json:"all,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -577,6 +599,20 @@ func (m *ListRequest) GetOptions() *Options {
 	return nil
 }
 
+func (m *ListRequest) GetSelector() string {
+	if m != nil {
+		return m.Selector
+	}
+	return ""
+}
+
+func (m *ListRequest) GetAll() bool {
+	if m != nil {
+		return m.All
+	}
+	return false
+}
+
 type ListResponse struct {
 	Services             []*Service `protobuf:"bytes,1,rep,name=services,proto3" json:"services,omitempty"`
 	XXX_NoUnkeyedLiteral struct{}   `json:"-"`
@@ -732,6 +768,180 @@ func (m *Event) GetService() *Service {
 	return nil
 }
 
+type PeersRequest struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *PeersRequest) Reset()         { *m = PeersRequest{} }
+func (m *PeersRequest) String() string { return proto.CompactTextString(m) }
+func (*PeersRequest) ProtoMessage()    {}
+func (*PeersRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_bba65e34813efea5, []int{13}
+}
+
+func (m *PeersRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PeersRequest.Unmarshal(m, b)
+}
+func (m *PeersRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PeersRequest.Marshal(b, m, deterministic)
+}
+func (m *PeersRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PeersRequest.Merge(m, src)
+}
+func (m *PeersRequest) XXX_Size() int {
+	return xxx_messageInfo_PeersRequest.Size(m)
+}
+func (m *PeersRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_PeersRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PeersRequest proto.InternalMessageInfo
+
+type PeersResponse struct {
+	Peers                []*Peer  `protobuf:"bytes,1,rep,name=peers,proto3" json:"peers,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *PeersResponse) Reset()         { *m = PeersResponse{} }
+func (m *PeersResponse) String() string { return proto.CompactTextString(m) }
+func (*PeersResponse) ProtoMessage()    {}
+func (*PeersResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_bba65e34813efea5, []int{14}
+}
+
+func (m *PeersResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PeersResponse.Unmarshal(m, b)
+}
+func (m *PeersResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PeersResponse.Marshal(b, m, deterministic)
+}
+func (m *PeersResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PeersResponse.Merge(m, src)
+}
+func (m *PeersResponse) XXX_Size() int {
+	return xxx_messageInfo_PeersResponse.Size(m)
+}
+func (m *PeersResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_PeersResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PeersResponse proto.InternalMessageInfo
+
+func (m *PeersResponse) GetPeers() []*Peer {
+	if m != nil {
+		return m.Peers
+	}
+	return nil
+}
+
+// Peer is a remote registry whose services are imported
+type Peer struct {
+	// address of the remote registry service
+	Address string `protobuf:"bytes,1,opt,name=address,proto3" json:"address,omitempty"`
+	// domain the services are imported into
+	Domain string `protobuf:"bytes,2,opt,name=domain,proto3" json:"domain,omitempty"`
+	// status of the peering: connecting, connected or error
+	Status string `protobuf:"bytes,3,opt,name=status,proto3" json:"status,omitempty"`
+	// error of the last sync
+	Error string `protobuf:"bytes,4,opt,name=error,proto3" json:"error,omitempty"`
+	// unix timestamp of the last successful sync
+	LastSync int64 `protobuf:"varint,5,opt,name=last_sync,json=lastSync,proto3" json:"last_sync,omitempty"`
+	// number of services imported
+	Services int64 `protobuf:"varint,6,opt,name=services,proto3" json:"services,omitempty"`
+	// number of nodes imported
+	Nodes int64 `protobuf:"varint,7,opt,name=nodes,proto3" json:"nodes,omitempty"`
+	// number of nodes skipped because another peer owns them
+	Conflicts            int64    `protobuf:"varint,8,opt,name=conflicts,proto3" json:"conflicts,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Peer) Reset()         { *m = Peer{} }
+func (m *Peer) String() string { return proto.CompactTextString(m) }
+func (*Peer) ProtoMessage()    {}
+func (*Peer) Descriptor() ([]byte, []int) {
+	return fileDescriptor_bba65e34813efea5, []int{15}
+}
+
+func (m *Peer) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Peer.Unmarshal(m, b)
+}
+func (m *Peer) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Peer.Marshal(b, m, deterministic)
+}
+func (m *Peer) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Peer.Merge(m, src)
+}
+func (m *Peer) XXX_Size() int {
+	return xxx_messageInfo_Peer.Size(m)
+}
+func (m *Peer) XXX_DiscardUnknown() {
+	xxx_messageInfo_Peer.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Peer proto.InternalMessageInfo
+
+func (m *Peer) GetAddress() string {
+	if m != nil {
+		return m.Address
+	}
+	return ""
+}
+
+func (m *Peer) GetDomain() string {
+	if m != nil {
+		return m.Domain
+	}
+	return ""
+}
+
+func (m *Peer) GetStatus() string {
+	if m != nil {
+		return m.Status
+	}
+	return ""
+}
+
+func (m *Peer) GetError() string {
+	if m != nil {
+		return m.Error
+	}
+	return ""
+}
+
+func (m *Peer) GetLastSync() int64 {
+	if m != nil {
+		return m.LastSync
+	}
+	return 0
+}
+
+func (m *Peer) GetServices() int64 {
+	if m != nil {
+		return m.Services
+	}
+	return 0
+}
+
+func (m *Peer) GetNodes() int64 {
+	if m != nil {
+		return m.Nodes
+	}
+	return 0
+}
+
+func (m *Peer) GetConflicts() int64 {
+	if m != nil {
+		return m.Conflicts
+	}
+	return 0
+}
+
 func init() {
 	proto.RegisterEnum("registry.EventType", EventType_name, EventType_value)
 	proto.RegisterType((*Service)(nil), "registry.Service")
@@ -750,6 +960,9 @@ func init() {
 	proto.RegisterType((*ListResponse)(nil), "registry.ListResponse")
 	proto.RegisterType((*WatchRequest)(nil), "registry.WatchRequest")
 	proto.RegisterType((*Event)(nil), "registry.Event")
+	proto.RegisterType((*PeersRequest)(nil), "registry.PeersRequest")
+	proto.RegisterType((*PeersResponse)(nil), "registry.PeersResponse")
+	proto.RegisterType((*Peer)(nil), "registry.Peer")
 }
 
 func init() {
@@ -757,52 +970,63 @@ func init() {
 }
 
 var fileDescriptor_bba65e34813efea5 = []byte{
-	// 710 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xb4, 0x55, 0xdb, 0x6e, 0xd3, 0x4c,
-	0x10, 0x8e, 0x9d, 0xf3, 0xa4, 0x87, 0x74, 0xff, 0xfe, 0xc5, 0x8a, 0x2a, 0x11, 0x59, 0xa0, 0x06,
-	0x2a, 0x92, 0x2a, 0x15, 0x52, 0x69, 0x8a, 0x90, 0xa0, 0x11, 0x37, 0x1c, 0x24, 0x97, 0x02, 0xe2,
-	0xce, 0x8d, 0x47, 0xad, 0xd5, 0xd8, 0x6b, 0x76, 0x37, 0x91, 0xf2, 0x0c, 0x3c, 0x0f, 0xdc, 0xf0,
-	0x54, 0xbc, 0x01, 0xda, 0xf5, 0xfa, 0xd0, 0x3a, 0x50, 0xa9, 0xc0, 0x4d, 0x34, 0x33, 0xfb, 0xcd,
-	0xf9, 0x9b, 0x18, 0xee, 0x73, 0x64, 0x73, 0x7f, 0x82, 0x03, 0x86, 0xe7, 0x3e, 0x17, 0x6c, 0x31,
-	0x88, 0x18, 0x15, 0x34, 0x55, 0xfb, 0x4a, 0x25, 0x8d, 0x44, 0xb7, 0xbf, 0x99, 0x50, 0x3f, 0x89,
-	0x7d, 0x08, 0x81, 0x4a, 0xe8, 0x06, 0x68, 0x19, 0x5d, 0xa3, 0xd7, 0x74, 0x94, 0x4c, 0x2c, 0xa8,
-	0xcf, 0x91, 0x71, 0x9f, 0x86, 0x96, 0xa9, 0xcc, 0x89, 0x4a, 0x46, 0xd0, 0x08, 0x50, 0xb8, 0x9e,
-	0x2b, 0x5c, 0xab, 0xdc, 0x2d, 0xf7, 0x5a, 0xc3, 0xbb, 0xfd, 0x34, 0x8d, 0x0e, 0xd9, 0x7f, 0xad,
-	0x11, 0xe3, 0x50, 0xb0, 0x85, 0x93, 0x3a, 0x90, 0x3d, 0x68, 0x62, 0xe8, 0x45, 0xd4, 0x0f, 0x05,
-	0xb7, 0x2a, 0xca, 0x9b, 0x64, 0xde, 0x63, 0xfd, 0xe4, 0x64, 0x20, 0x72, 0x0f, 0xaa, 0x21, 0xf5,
-	0x90, 0x5b, 0x55, 0x85, 0x5e, 0xcb, 0xd0, 0x6f, 0xa8, 0x87, 0x4e, 0xfc, 0x48, 0x76, 0xa1, 0x4e,
-	0x23, 0xe1, 0xd3, 0x90, 0x5b, 0xb5, 0xae, 0xd1, 0x6b, 0x0d, 0x37, 0x32, 0xdc, 0xdb, 0xf8, 0xc1,
-	0x49, 0x10, 0x9d, 0x11, 0xac, 0x5e, 0xa9, 0x8f, 0xb4, 0xa1, 0x7c, 0x89, 0x0b, 0xdd, 0xbf, 0x14,
-	0xc9, 0x26, 0x54, 0xe7, 0xee, 0x74, 0x86, 0xba, 0xf9, 0x58, 0x39, 0x34, 0x0f, 0x0c, 0xfb, 0xbb,
-	0x01, 0x15, 0x99, 0x99, 0xac, 0x81, 0xe9, 0x7b, 0xda, 0xc7, 0xf4, 0x3d, 0x39, 0x31, 0xd7, 0xf3,
-	0x18, 0x72, 0x9e, 0x4c, 0x4c, 0xab, 0x72, 0xbe, 0x11, 0x65, 0xc2, 0x2a, 0x77, 0x8d, 0x5e, 0xd9,
-	0x51, 0x32, 0x39, 0xc8, 0x4d, 0x31, 0x9e, 0xc3, 0xf6, 0xd5, 0xce, 0x7e, 0x35, 0xc2, 0x3f, 0xab,
-	0xfe, 0x87, 0x01, 0x8d, 0x64, 0xca, 0x4b, 0xf7, 0xfe, 0x00, 0xea, 0x0c, 0x3f, 0xcf, 0x90, 0x0b,
-	0xe5, 0xdc, 0x1a, 0xae, 0x67, 0x65, 0xbd, 0x97, 0x61, 0x9c, 0xe4, 0x9d, 0xec, 0x42, 0x83, 0x21,
-	0x8f, 0x68, 0xc8, 0x51, 0xb5, 0xb6, 0x04, 0x9b, 0x02, 0xc8, 0x51, 0xa1, 0xdf, 0x6e, 0x71, 0xef,
-	0xff, 0xa6, 0xe7, 0x8f, 0x50, 0x55, 0xd5, 0x2c, 0xed, 0x97, 0x40, 0x45, 0x2c, 0xa2, 0xc4, 0x4b,
-	0xc9, 0x64, 0x07, 0x6a, 0xca, 0x9b, 0x6b, 0x7e, 0x17, 0xda, 0xd2, 0xcf, 0xf6, 0x3e, 0xd4, 0x35,
-	0xb9, 0x64, 0x41, 0x42, 0x4c, 0x55, 0xe8, 0xb2, 0x23, 0x45, 0xb2, 0x05, 0x35, 0x8f, 0x06, 0xae,
-	0x9f, 0x1c, 0x90, 0xd6, 0xec, 0x4b, 0xa8, 0x39, 0xc8, 0x67, 0x53, 0x21, 0x11, 0xee, 0x44, 0xba,
-	0xeb, 0x8a, 0xb4, 0x26, 0xc9, 0xac, 0xcf, 0x59, 0xef, 0x60, 0xa3, 0x70, 0x60, 0x4e, 0x82, 0x20,
-	0xdb, 0xd0, 0x14, 0x7e, 0x80, 0x5c, 0xb8, 0x41, 0xa4, 0x19, 0x96, 0x19, 0xec, 0x75, 0x58, 0x1d,
-	0x07, 0x91, 0x58, 0x38, 0x7a, 0x0f, 0xf6, 0x09, 0xc0, 0x4b, 0x14, 0x8e, 0x5e, 0xa1, 0x95, 0x65,
-	0x8a, 0x4b, 0x48, 0xc3, 0xe6, 0x0e, 0xca, 0xbc, 0xe9, 0xa0, 0xec, 0x23, 0x68, 0xa9, 0xa0, 0x7a,
-	0xd7, 0x8f, 0xa0, 0xa1, 0xc3, 0x70, 0xcb, 0x50, 0x13, 0x5c, 0xd2, 0x40, 0x0a, 0xb1, 0x0f, 0xa1,
-	0xf5, 0xca, 0xe7, 0x69, 0x4d, 0xb9, 0xcc, 0xc6, 0x8d, 0x99, 0x9f, 0xc2, 0x4a, 0xec, 0x7b, 0xbb,
-	0xd4, 0xa7, 0xb0, 0xf2, 0xc1, 0x15, 0x93, 0x8b, 0xbf, 0x3c, 0x8f, 0x2f, 0x06, 0x54, 0xc7, 0x73,
-	0x0c, 0x45, 0xe1, 0x4f, 0x62, 0x27, 0x47, 0xb7, 0xb5, 0xe1, 0x7f, 0xb9, 0x13, 0x90, 0xf0, 0x77,
-	0x8b, 0x08, 0x35, 0x07, 0x7f, 0xbb, 0xd6, 0x3c, 0x43, 0x2a, 0x37, 0x31, 0xe4, 0xe1, 0x00, 0x9a,
-	0x69, 0x74, 0x02, 0x50, 0x7b, 0xc1, 0xd0, 0x15, 0xd8, 0x2e, 0x49, 0xf9, 0x18, 0xa7, 0x28, 0xb0,
-	0x6d, 0x48, 0xf9, 0x34, 0xf2, 0xa4, 0xdd, 0x1c, 0x7e, 0x35, 0xa1, 0xe1, 0xe8, 0x70, 0x64, 0xa4,
-	0x08, 0x93, 0x7c, 0x2a, 0x36, 0xb3, 0x3c, 0x19, 0x8d, 0x3a, 0xff, 0x5f, 0xb3, 0x6a, 0xae, 0x95,
-	0xe4, 0xbf, 0x5c, 0x1c, 0x08, 0x19, 0x29, 0x96, 0xd8, 0xb9, 0x93, 0xeb, 0xff, 0x0a, 0x4b, 0x4b,
-	0xe4, 0x10, 0xe0, 0x18, 0xd9, 0xed, 0x7c, 0x9f, 0xc5, 0xa4, 0xd0, 0x48, 0x4e, 0x72, 0xe5, 0xe5,
-	0x88, 0xd6, 0xd9, 0xba, 0x6e, 0x4e, 0x03, 0x3c, 0x86, 0xaa, 0xa2, 0x05, 0xc9, 0x41, 0xf2, 0x3c,
-	0xe9, 0xb4, 0x33, 0x7b, 0x7c, 0xcb, 0x76, 0x69, 0xcf, 0x78, 0x3e, 0xfa, 0xf4, 0xe4, 0xdc, 0x17,
-	0x17, 0xb3, 0xb3, 0xfe, 0x84, 0x06, 0x83, 0xc0, 0x9f, 0x30, 0xaa, 0x7f, 0xe7, 0xfb, 0x83, 0xe5,
-	0x1f, 0xe8, 0x51, 0xa2, 0x9e, 0xd5, 0x94, 0xbe, 0xff, 0x33, 0x00, 0x00, 0xff, 0xff, 0x6e, 0x77,
-	0xa7, 0xb8, 0xca, 0x07, 0x00, 0x00,
+	// 892 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xb4, 0x56, 0x5f, 0x8f, 0xdb, 0x44,
+	0x10, 0xbf, 0xb5, 0xe3, 0xc4, 0x99, 0xdc, 0x5d, 0x8f, 0xa5, 0xb4, 0x56, 0xa8, 0x44, 0x64, 0x15,
+	0x35, 0x50, 0x71, 0xa9, 0x72, 0xaa, 0x74, 0x5c, 0x8a, 0x90, 0xa0, 0x07, 0x3c, 0xf0, 0x4f, 0x7b,
+	0x14, 0x10, 0x2f, 0xc8, 0xb5, 0x87, 0x9e, 0x55, 0xc7, 0x36, 0xbb, 0x9b, 0x48, 0x7e, 0xe4, 0x99,
+	0xcf, 0x03, 0x2f, 0x7c, 0x0c, 0x3e, 0x09, 0xdf, 0x00, 0xed, 0x7a, 0xd7, 0x76, 0x2e, 0x29, 0x15,
+	0x07, 0x7d, 0x89, 0xf6, 0x37, 0x3b, 0xb3, 0x3b, 0xf3, 0xdb, 0xdf, 0x8c, 0x03, 0x6f, 0x0b, 0xe4,
+	0xeb, 0x34, 0xc6, 0x19, 0xc7, 0x67, 0xa9, 0x90, 0xbc, 0x9a, 0x95, 0xbc, 0x90, 0x45, 0x03, 0x8f,
+	0x35, 0xa4, 0xbe, 0xc5, 0xe1, 0xef, 0x0e, 0x0c, 0x2e, 0xea, 0x18, 0x4a, 0xa1, 0x97, 0x47, 0x4b,
+	0x0c, 0xc8, 0x84, 0x4c, 0x87, 0x4c, 0xaf, 0x69, 0x00, 0x83, 0x35, 0x72, 0x91, 0x16, 0x79, 0xe0,
+	0x68, 0xb3, 0x85, 0x74, 0x01, 0xfe, 0x12, 0x65, 0x94, 0x44, 0x32, 0x0a, 0xdc, 0x89, 0x3b, 0x1d,
+	0xcd, 0xdf, 0x3a, 0x6e, 0xae, 0x31, 0x47, 0x1e, 0x7f, 0x61, 0x3c, 0xce, 0x73, 0xc9, 0x2b, 0xd6,
+	0x04, 0xd0, 0x07, 0x30, 0xc4, 0x3c, 0x29, 0x8b, 0x34, 0x97, 0x22, 0xe8, 0xe9, 0x68, 0xda, 0x46,
+	0x9f, 0x9b, 0x2d, 0xd6, 0x3a, 0xd1, 0xbb, 0xe0, 0xe5, 0x45, 0x82, 0x22, 0xf0, 0xb4, 0xf7, 0x61,
+	0xeb, 0xfd, 0x65, 0x91, 0x20, 0xab, 0x37, 0xe9, 0x7d, 0x18, 0x14, 0xa5, 0x4c, 0x8b, 0x5c, 0x04,
+	0xfd, 0x09, 0x99, 0x8e, 0xe6, 0xaf, 0xb5, 0x7e, 0x5f, 0xd5, 0x1b, 0xcc, 0x7a, 0x8c, 0x17, 0x70,
+	0xb0, 0x91, 0x1f, 0x3d, 0x02, 0xf7, 0x39, 0x56, 0xa6, 0x7e, 0xb5, 0xa4, 0x37, 0xc1, 0x5b, 0x47,
+	0xd9, 0x0a, 0x4d, 0xf1, 0x35, 0x38, 0x73, 0x4e, 0x49, 0xf8, 0x07, 0x81, 0x9e, 0xba, 0x99, 0x1e,
+	0x82, 0x93, 0x26, 0x26, 0xc6, 0x49, 0x13, 0xc5, 0x58, 0x94, 0x24, 0x1c, 0x85, 0xb0, 0x8c, 0x19,
+	0xa8, 0xf8, 0x2d, 0x0b, 0x2e, 0x03, 0x77, 0x42, 0xa6, 0x2e, 0xd3, 0x6b, 0x7a, 0xda, 0x61, 0xb1,
+	0xe6, 0xe1, 0xce, 0x66, 0x65, 0x2f, 0xa2, 0xf0, 0xbf, 0x65, 0xff, 0x17, 0x01, 0xdf, 0xb2, 0xbc,
+	0xf3, 0xdd, 0xdf, 0x81, 0x01, 0xc7, 0x9f, 0x57, 0x28, 0xa4, 0x0e, 0x1e, 0xcd, 0x6f, 0xb4, 0x69,
+	0x7d, 0xab, 0x8e, 0x61, 0x76, 0x9f, 0xde, 0x07, 0x9f, 0xa3, 0x28, 0x8b, 0x5c, 0x60, 0xe0, 0xee,
+	0xf6, 0x6d, 0x1c, 0xe8, 0xa3, 0xad, 0x7a, 0x27, 0xdb, 0xef, 0xfe, 0x6a, 0x6a, 0xfe, 0x1e, 0x3c,
+	0x9d, 0xcd, 0xce, 0x7a, 0x29, 0xf4, 0x64, 0x55, 0xda, 0x28, 0xbd, 0xa6, 0xf7, 0xa0, 0xaf, 0xa3,
+	0x85, 0xd1, 0xf7, 0x56, 0x59, 0x66, 0x3b, 0x3c, 0x81, 0x81, 0x11, 0x97, 0x4a, 0x48, 0xca, 0x4c,
+	0x1f, 0xed, 0x32, 0xb5, 0xa4, 0xb7, 0xa0, 0x9f, 0x14, 0xcb, 0x28, 0xb5, 0x0d, 0x64, 0x50, 0xf8,
+	0x1c, 0xfa, 0x0c, 0xc5, 0x2a, 0x93, 0xca, 0x23, 0x8a, 0x55, 0xb8, 0xc9, 0xc8, 0x20, 0x25, 0x66,
+	0xd3, 0xce, 0x81, 0x73, 0x55, 0xcc, 0xa6, 0xc1, 0x98, 0xf5, 0xa0, 0x77, 0x60, 0x28, 0xd3, 0x25,
+	0x0a, 0x19, 0x2d, 0x4b, 0xa3, 0xb0, 0xd6, 0x10, 0xde, 0x80, 0x83, 0xf3, 0x65, 0x29, 0x2b, 0x66,
+	0xde, 0x21, 0xfc, 0x85, 0x00, 0x7c, 0x8a, 0x92, 0x99, 0x37, 0x0c, 0xda, 0xab, 0xea, 0x1c, 0x9a,
+	0x73, 0x3b, 0x1d, 0xe5, 0xbc, 0xac, 0xa3, 0xe8, 0x18, 0x7c, 0x81, 0x19, 0xc6, 0xb2, 0xe0, 0x3a,
+	0x87, 0x21, 0x6b, 0xb0, 0x62, 0x26, 0xca, 0xb2, 0xa0, 0x37, 0x21, 0x53, 0x9f, 0xa9, 0x65, 0xf8,
+	0x08, 0x46, 0x3a, 0x05, 0x23, 0x8d, 0xf7, 0xc0, 0x37, 0x97, 0x8a, 0x80, 0x4c, 0xdc, 0xcd, 0xab,
+	0x6c, 0xbd, 0x8d, 0x4b, 0x78, 0x09, 0xa3, 0xcf, 0x53, 0xd1, 0x54, 0xd0, 0xc9, 0x93, 0xfc, 0xab,
+	0x3c, 0x9d, 0xdd, 0x79, 0xba, 0x6d, 0x9e, 0x1f, 0xc0, 0x7e, 0x7d, 0xd3, 0xf5, 0x12, 0x7d, 0x02,
+	0xfb, 0xdf, 0x45, 0x32, 0xbe, 0xfc, 0x7f, 0xb9, 0x0e, 0x7f, 0x25, 0xe0, 0x9d, 0xaf, 0x31, 0x97,
+	0x5b, 0x13, 0xe8, 0x5e, 0x47, 0xcb, 0x87, 0xf3, 0xd7, 0x3b, 0xfd, 0xa5, 0xdc, 0xbf, 0xa9, 0x4a,
+	0x34, 0x02, 0xff, 0x47, 0xcd, 0x74, 0xe5, 0xd7, 0x7b, 0x99, 0xfc, 0xc2, 0x43, 0xd8, 0xff, 0x1a,
+	0x91, 0x0b, 0x53, 0x64, 0xf8, 0x10, 0x0e, 0x0c, 0x36, 0xa4, 0xdd, 0x05, 0xaf, 0x54, 0x86, 0x80,
+	0x5c, 0x9d, 0xdf, 0xca, 0x8f, 0xd5, 0x9b, 0xe1, 0x9f, 0x04, 0x7a, 0x0a, 0x77, 0xa7, 0x28, 0xd9,
+	0x9c, 0xa2, 0x2f, 0xe8, 0x27, 0x65, 0x17, 0x32, 0x92, 0x2b, 0x61, 0x94, 0x67, 0x90, 0x1a, 0x08,
+	0xc8, 0x79, 0xc1, 0x75, 0x11, 0x43, 0x56, 0x03, 0xfa, 0x26, 0x0c, 0xb3, 0x48, 0xc8, 0x1f, 0x45,
+	0x95, 0xc7, 0x81, 0xa7, 0x4b, 0xf7, 0x95, 0xe1, 0xa2, 0xca, 0xe3, 0x5a, 0x1e, 0xe6, 0x81, 0xfb,
+	0xf5, 0x9e, 0xc5, 0xea, 0xb8, 0xfa, 0x3b, 0x34, 0xd0, 0x1b, 0x35, 0x50, 0x4c, 0xc6, 0x45, 0xfe,
+	0x53, 0x96, 0xc6, 0x52, 0x04, 0x7e, 0xcd, 0x64, 0x63, 0x78, 0x77, 0x06, 0xc3, 0x86, 0x7a, 0x0a,
+	0xd0, 0xff, 0x98, 0x63, 0x24, 0xf1, 0x68, 0x4f, 0xad, 0x1f, 0x63, 0x86, 0x12, 0x8f, 0x88, 0x5a,
+	0x3f, 0x29, 0x13, 0x65, 0x77, 0xe6, 0xbf, 0x39, 0xe0, 0x33, 0xc3, 0x0f, 0x5d, 0xe8, 0x4e, 0xb5,
+	0x1f, 0xe9, 0x9b, 0x2d, 0x71, 0x6d, 0xff, 0x8e, 0xdf, 0xb8, 0x62, 0x35, 0x5d, 0xbe, 0x47, 0x4f,
+	0xed, 0x41, 0xc8, 0xe9, 0xf6, 0xfb, 0x8d, 0x6f, 0x77, 0xc4, 0xb1, 0x31, 0x1f, 0xf6, 0xe8, 0x19,
+	0xc0, 0x63, 0xe4, 0xd7, 0x8b, 0xfd, 0xb0, 0xee, 0x98, 0x0b, 0x4b, 0x5a, 0x27, 0xbd, 0x4e, 0xcf,
+	0x8e, 0x6f, 0x5d, 0x35, 0x37, 0x07, 0x3c, 0x04, 0x4f, 0xf7, 0x0c, 0xed, 0xb8, 0x74, 0x9b, 0x68,
+	0x7c, 0xd4, 0xda, 0xeb, 0x29, 0x1a, 0xee, 0x3d, 0x20, 0xf3, 0xcf, 0x00, 0x3e, 0xc1, 0x04, 0x79,
+	0xa4, 0xe7, 0xe7, 0x19, 0x78, 0x5a, 0x83, 0xdd, 0x43, 0xba, 0x22, 0x1d, 0xdf, 0xde, 0xb2, 0xdb,
+	0x04, 0x3e, 0x5a, 0xfc, 0xf0, 0xfe, 0xb3, 0x54, 0x5e, 0xae, 0x9e, 0x1e, 0xc7, 0xc5, 0x72, 0xb6,
+	0x4c, 0x63, 0x5e, 0x98, 0xdf, 0xf5, 0xc9, 0x6c, 0xf7, 0x9f, 0xac, 0x85, 0x85, 0x4f, 0xfb, 0x1a,
+	0x9f, 0xfc, 0x3d, 0x00, 0xf2, 0x74, 0x21, 0xb2, 0x8e, 0x09, 0x00, 0x00,
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -1056,3 +1280,75 @@ var _Registry_serviceDesc = grpc.ServiceDesc{
 	},
 	Metadata: "service/registry/proto/registry.proto",
 }
+
+// FederationClient is the client API for Federation service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type FederationClient interface {
+	Peers(ctx context.Context, in *PeersRequest, opts ...grpc.CallOption) (*PeersResponse, error)
+}
+
+type federationClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewFederationClient(cc *grpc.ClientConn) FederationClient {
+	return &federationClient{cc}
+}
+
+func (c *federationClient) Peers(ctx context.Context, in *PeersRequest, opts ...grpc.CallOption) (*PeersResponse, error) {
+	out := new(PeersResponse)
+	err := c.cc.Invoke(ctx, "/registry.Federation/Peers", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// FederationServer is the server API for Federation service.
+type FederationServer interface {
+	Peers(context.Context, *PeersRequest) (*PeersResponse, error)
+}
+
+// UnimplementedFederationServer can be embedded to have forward compatible implementations.
+type UnimplementedFederationServer struct {
+}
+
+func (*UnimplementedFederationServer) Peers(ctx context.Context, req *PeersRequest) (*PeersResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Peers not implemented")
+}
+
+func RegisterFederationServer(s *grpc.Server, srv FederationServer) {
+	s.RegisterService(&_Federation_serviceDesc, srv)
+}
+
+func _Federation_Peers_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PeersRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FederationServer).Peers(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/registry.Federation/Peers",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FederationServer).Peers(ctx, req.(*PeersRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _Federation_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "registry.Federation",
+	HandlerType: (*FederationServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Peers",
+			Handler:    _Federation_Peers_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "service/registry/proto/registry.proto",
+}
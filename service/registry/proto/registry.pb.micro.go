@@ -234,3 +234,62 @@ func (x *registryWatchStream) RecvMsg(m interface{}) error {
 func (x *registryWatchStream) Send(m *Result) error {
 	return x.stream.Send(m)
 }
+
+// Api Endpoints for Federation service
+
+func NewFederationEndpoints() []*api.Endpoint {
+	return []*api.Endpoint{}
+}
+
+// Client API for Federation service
+
+type FederationService interface {
+	Peers(ctx context.Context, in *PeersRequest, opts ...client.CallOption) (*PeersResponse, error)
+}
+
+type federationService struct {
+	c    client.Client
+	name string
+}
+
+func NewFederationService(name string, c client.Client) FederationService {
+	return &federationService{
+		c:    c,
+		name: name,
+	}
+}
+
+func (c *federationService) Peers(ctx context.Context, in *PeersRequest, opts ...client.CallOption) (*PeersResponse, error) {
+	req := c.c.NewRequest(c.name, "Federation.Peers", in)
+	out := new(PeersResponse)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// Server API for Federation service
+
+type FederationHandler interface {
+	Peers(context.Context, *PeersRequest, *PeersResponse) error
+}
+
+func RegisterFederationHandler(s server.Server, hdlr FederationHandler, opts ...server.HandlerOption) error {
+	type federation interface {
+		Peers(ctx context.Context, in *PeersRequest, out *PeersResponse) error
+	}
+	type Federation struct {
+		federation
+	}
+	h := &federationHandler{hdlr}
+	return s.Handle(s.NewHandler(&Federation{h}, opts...))
+}
+
+type federationHandler struct {
+	FederationHandler
+}
+
+func (h *federationHandler) Peers(ctx context.Context, in *PeersRequest, out *PeersResponse) error {
+	return h.FederationHandler.Peers(ctx, in, out)
+}
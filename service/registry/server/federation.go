@@ -0,0 +1,279 @@
+package server
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	goclient "github.com/micro/go-micro/v3/client"
+	"github.com/micro/go-micro/v3/registry"
+	"github.com/micro/micro/v3/internal/namespace"
+	"github.com/micro/micro/v3/service/errors"
+	log "github.com/micro/micro/v3/service/logger"
+	muregistry "github.com/micro/micro/v3/service/registry"
+	pb "github.com/micro/micro/v3/service/registry/proto"
+	"github.com/micro/micro/v3/service/registry/util"
+)
+
+const (
+	// PeerConnecting is the status of a peer which hasn't been synced yet
+	PeerConnecting = "connecting"
+	// PeerConnected is the status of a peer whose last sync succeeded
+	PeerConnected = "connected"
+	// PeerError is the status of a peer whose last sync failed
+	PeerError = "error"
+)
+
+// peerMetadata is the metadata key of the address of the peer a node was imported from
+const peerMetadata = "peer"
+
+// isImported returns true if the node was imported from a peer
+func isImported(node *registry.Node) bool {
+	_, ok := node.Metadata[peerMetadata]
+	return ok
+}
+
+// importedNode identifies a node imported from a peer
+type importedNode struct {
+	service string
+	version string
+	node    string
+}
+
+// peer is a remote registry service whose services are imported into the local registry
+type peer struct {
+	address string
+	client  pb.RegistryService
+
+	// status of the peering
+	status    string
+	err       error
+	lastSync  time.Time
+	services  int
+	conflicts int
+
+	// nodes imported on the last sync
+	imported map[importedNode]*registry.Service
+}
+
+// federation periodically lists the services of remote registries and imports them into a
+// separate domain of the local registry. Imported nodes are registered with a TTL of a few sync
+// intervals so they expire if the peer becomes unreachable, and are deregistered as soon as
+// they disappear from the peer. A node is only ever imported from the first peer it is seen on.
+// Only the default domain of the peers is imported: other domains are the namespaces of the
+// peer and merging them into a single domain would expose their services to each other.
+type federation struct {
+	// domain the services are imported into
+	domain string
+	// interval between syncs
+	interval time.Duration
+	// filter on the services to import
+	filter selector
+
+	sync.RWMutex
+	peers []*peer
+	// the peer which owns each node id
+	owners map[string]*peer
+}
+
+func newFederation(addrs []string, domain string, interval time.Duration, filter selector) *federation {
+	f := &federation{
+		domain:   domain,
+		interval: interval,
+		filter:   filter,
+		owners:   make(map[string]*peer),
+	}
+
+	for _, addr := range addrs {
+		f.peers = append(f.peers, &peer{
+			address:  addr,
+			client:   pb.NewRegistryService(name, directClient),
+			status:   PeerConnecting,
+			imported: make(map[importedNode]*registry.Service),
+		})
+	}
+
+	return f
+}
+
+// ttl of the imported nodes
+func (f *federation) ttl() time.Duration {
+	return f.interval * 3
+}
+
+// list the services of the peer which pass the filter
+func (f *federation) list(p *peer) ([]*registry.Service, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), f.interval)
+	defer cancel()
+
+	rsp, err := p.client.ListServices(ctx, &pb.ListRequest{
+		Options: &pb.Options{Domain: registry.DefaultDomain},
+	}, goclient.WithAddress(p.address))
+	if err != nil {
+		return nil, err
+	}
+
+	var services []*registry.Service
+	for _, srv := range rsp.Services {
+		services = append(services, util.ToService(srv))
+	}
+
+	// the nodes have already been health filtered by the peer
+	return filter(services, f.filter, nil, true), nil
+}
+
+// sync imports the services of a peer and removes those which are no longer registered
+func (f *federation) sync(p *peer) {
+	services, err := f.list(p)
+
+	f.Lock()
+	defer f.Unlock()
+
+	if err != nil {
+		if p.status != PeerError {
+			log.Errorf("Error syncing registry peer %s: %v", p.address, err)
+		}
+		p.status = PeerError
+		p.err = err
+
+		// release the nodes of the peer so they can be imported from another peer, the nodes
+		// already imported expire once their ttl passes
+		f.release(p)
+		return
+	}
+
+	imported := make(map[importedNode]*registry.Service)
+	conflicts := 0
+	names := make(map[string]bool)
+
+	for _, srv := range services {
+		for _, node := range srv.Nodes {
+			// a node owned by another peer is a conflict, the first peer wins
+			if owner, ok := f.owners[node.Id]; ok && owner != p {
+				conflicts++
+				continue
+			}
+
+			md := make(map[string]string, len(node.Metadata)+1)
+			for k, v := range node.Metadata {
+				md[k] = v
+			}
+			md[peerMetadata] = p.address
+
+			svc := &registry.Service{
+				Name:      srv.Name,
+				Version:   srv.Version,
+				Metadata:  srv.Metadata,
+				Endpoints: srv.Endpoints,
+				Nodes: []*registry.Node{{
+					Id:       node.Id,
+					Address:  node.Address,
+					Metadata: md,
+				}},
+			}
+
+			// registering again only refreshes the ttl, so a node which is new to the peer or
+			// has moved is removed first in case a stale record was left by another peer
+			key := importedNode{srv.Name, srv.Version, node.Id}
+			if prev, ok := p.imported[key]; !ok || prev.Nodes[0].Address != node.Address {
+				muregistry.Deregister(svc, registry.DeregisterDomain(f.domain))
+			}
+			if err := muregistry.Register(svc, registry.RegisterDomain(f.domain), registry.RegisterTTL(f.ttl())); err != nil {
+				log.Errorf("Error importing service %s from registry peer %s: %v", srv.Name, p.address, err)
+				continue
+			}
+
+			f.owners[node.Id] = p
+			imported[key] = svc
+			names[srv.Name] = true
+		}
+	}
+
+	// deregister the nodes which are no longer registered with the peer
+	for key, svc := range p.imported {
+		if _, ok := imported[key]; ok {
+			continue
+		}
+		// the node may have been imported from another peer since
+		if owner, ok := f.owners[key.node]; ok && owner != p {
+			continue
+		}
+		if err := muregistry.Deregister(svc, registry.DeregisterDomain(f.domain)); err != nil {
+			log.Errorf("Error removing service %s imported from registry peer %s: %v", key.service, p.address, err)
+		}
+		delete(f.owners, key.node)
+	}
+
+	if p.status != PeerConnected {
+		log.Infof("Connected to registry peer %s", p.address)
+	}
+	p.status = PeerConnected
+	p.err = nil
+	p.lastSync = time.Now()
+	p.services = len(names)
+	p.conflicts = conflicts
+	p.imported = imported
+}
+
+// release the ownership of the nodes imported from the peer
+func (f *federation) release(p *peer) {
+	for key := range p.imported {
+		if f.owners[key.node] == p {
+			delete(f.owners, key.node)
+		}
+	}
+	p.imported = make(map[importedNode]*registry.Service)
+}
+
+// run syncs every peer each interval until exit is closed
+func (f *federation) run(exit chan bool) {
+	t := time.NewTicker(f.interval)
+	defer t.Stop()
+
+	for {
+		for _, p := range f.peers {
+			f.sync(p)
+		}
+
+		select {
+		case <-exit:
+			return
+		case <-t.C:
+		}
+	}
+}
+
+// Peers returns the status of each registry peer
+func (f *federation) Peers(ctx context.Context, req *pb.PeersRequest, rsp *pb.PeersResponse) error {
+	// authorize the request
+	if err := namespace.Authorize(ctx, registry.DefaultDomain); err == namespace.ErrForbidden {
+		return errors.Forbidden("registry.Federation.Peers", err.Error())
+	} else if err == namespace.ErrUnauthorized {
+		return errors.Unauthorized("registry.Federation.Peers", err.Error())
+	} else if err != nil {
+		return errors.InternalServerError("registry.Federation.Peers", err.Error())
+	}
+
+	f.RLock()
+	defer f.RUnlock()
+
+	for _, p := range f.peers {
+		pr := &pb.Peer{
+			Address:   p.address,
+			Domain:    f.domain,
+			Status:    p.status,
+			Services:  int64(p.services),
+			Nodes:     int64(len(p.imported)),
+			Conflicts: int64(p.conflicts),
+		}
+		if p.err != nil {
+			pr.Error = p.err.Error()
+		}
+		if !p.lastSync.IsZero() {
+			pr.LastSync = p.lastSync.Unix()
+		}
+		rsp.Peers = append(rsp.Peers, pr)
+	}
+
+	return nil
+}
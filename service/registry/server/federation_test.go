@@ -0,0 +1,105 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	goclient "github.com/micro/go-micro/v3/client"
+	"github.com/micro/go-micro/v3/registry"
+	"github.com/micro/go-micro/v3/registry/memory"
+	muregistry "github.com/micro/micro/v3/service/registry"
+	pb "github.com/micro/micro/v3/service/registry/proto"
+	"github.com/micro/micro/v3/service/registry/util"
+)
+
+type testPeer struct {
+	services []*registry.Service
+	err      error
+	pb.RegistryService
+}
+
+func (t *testPeer) ListServices(ctx context.Context, req *pb.ListRequest, opts ...goclient.CallOption) (*pb.ListResponse, error) {
+	if t.err != nil {
+		return nil, t.err
+	}
+	rsp := &pb.ListResponse{}
+	for _, srv := range t.services {
+		rsp.Services = append(rsp.Services, util.ToProto(srv))
+	}
+	return rsp, nil
+}
+
+func TestFederationSync(t *testing.T) {
+	reg := muregistry.DefaultRegistry
+	muregistry.DefaultRegistry = memory.NewRegistry()
+	defer func() { muregistry.DefaultRegistry = reg }()
+
+	filter, err := parseSelector("name!=excluded")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	f := newFederation([]string{"peer-a", "peer-b"}, "federation", time.Minute, filter)
+	a := &testPeer{services: []*registry.Service{
+		{Name: "foo", Version: "latest", Nodes: []*registry.Node{{Id: "foo-1", Address: "10.0.0.1:1234"}}},
+		{Name: "excluded", Version: "latest", Nodes: []*registry.Node{{Id: "excluded-1", Address: "10.0.0.1:1235"}}},
+	}}
+	b := &testPeer{services: []*registry.Service{
+		{Name: "foo", Version: "latest", Nodes: []*registry.Node{{Id: "foo-1", Address: "10.0.0.2:1234"}}},
+		{Name: "bar", Version: "latest", Nodes: []*registry.Node{{Id: "bar-1", Address: "10.0.0.2:1235"}}},
+	}}
+	f.peers[0].client = a
+	f.peers[1].client = b
+
+	f.sync(f.peers[0])
+	f.sync(f.peers[1])
+
+	srvs, err := muregistry.GetService("foo", registry.GetDomain("federation"))
+	if err != nil {
+		t.Fatalf("Expected foo to be imported: %v", err)
+	}
+	if len(srvs[0].Nodes) != 1 || srvs[0].Nodes[0].Metadata["peer"] != "peer-a" {
+		t.Errorf("Expected foo-1 to be owned by the first peer, got %v", srvs[0].Nodes)
+	}
+	if _, err := muregistry.GetService("excluded", registry.GetDomain("federation")); err != registry.ErrNotFound {
+		t.Errorf("Expected excluded not to be imported, got %v", err)
+	}
+	if _, err := muregistry.GetService("bar", registry.GetDomain(registry.DefaultDomain)); err != registry.ErrNotFound {
+		t.Errorf("Expected bar not to be imported into the default domain, got %v", err)
+	}
+
+	if p := f.peers[1]; p.conflicts != 1 || p.status != PeerConnected {
+		t.Errorf("Expected a conflict on the second peer, got %v conflicts with status %v", p.conflicts, p.status)
+	}
+
+	// removing bar from the peer deregisters it
+	b.services = b.services[:1]
+	f.sync(f.peers[1])
+	if _, err := muregistry.GetService("bar", registry.GetDomain("federation")); err != registry.ErrNotFound {
+		t.Errorf("Expected bar to be deregistered, got %v", err)
+	}
+
+	// a peer which errors releases its nodes to the other peers
+	a.err = errors.New("unreachable")
+	f.sync(f.peers[0])
+	if p := f.peers[0]; p.status != PeerError || len(p.imported) != 0 {
+		t.Errorf("Expected the first peer to have errored and released its nodes, got %v with %v nodes", p.status, len(p.imported))
+	}
+	f.sync(f.peers[1])
+	if owner := f.owners["foo-1"]; owner != f.peers[1] {
+		t.Errorf("Expected foo-1 to be owned by the second peer, got %v", owner)
+	}
+	srvs, err = muregistry.GetService("foo", registry.GetDomain("federation"))
+	if err != nil || srvs[0].Nodes[0].Metadata["peer"] != "peer-b" {
+		t.Errorf("Expected foo-1 to be imported from the second peer, got %v %v", srvs, err)
+	}
+
+	// the first peer recovering doesn't remove the node from the second peer
+	a.err = nil
+	f.sync(f.peers[0])
+	if _, err := muregistry.GetService("foo", registry.GetDomain("federation")); err != nil {
+		t.Errorf("Expected foo to still be imported, got %v", err)
+	}
+}
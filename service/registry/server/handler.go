@@ -19,6 +19,8 @@ type Registry struct {
 	ID string
 	// the event
 	Event *service.Event
+	// health of the registered nodes
+	Health *health
 }
 
 func ActionToEventType(action string) goregistry.EventType {
@@ -70,14 +72,24 @@ func (r *Registry) GetService(ctx context.Context, req *pb.GetRequest, rsp *pb.G
 		return errors.InternalServerError("registry.Registry.GetService", err.Error())
 	}
 
+	// parse the selector
+	sel, err := parseSelector(req.Selector)
+	if err != nil {
+		return errors.BadRequest("registry.Registry.GetService", err.Error())
+	}
+
 	// get the services in the namespace
 	services, err := registry.GetService(req.Service, goregistry.GetDomain(options.Domain))
-	if err == goregistry.ErrNotFound || len(services) == 0 {
-		return errors.NotFound("registry.Registry.GetService", goregistry.ErrNotFound.Error())
-	} else if err != nil {
+	if err != nil && err != goregistry.ErrNotFound {
 		return errors.InternalServerError("registry.Registry.GetService", err.Error())
 	}
 
+	// filter out unhealthy nodes and those not matching the selector
+	services = filter(services, sel, r.Health.status, req.All)
+	if len(services) == 0 {
+		return errors.NotFound("registry.Registry.GetService", goregistry.ErrNotFound.Error())
+	}
+
 	// serialize the response
 	rsp.Services = make([]*pb.Service, len(services))
 	for i, srv := range services {
@@ -174,12 +186,21 @@ func (r *Registry) ListServices(ctx context.Context, req *pb.ListRequest, rsp *p
 		return errors.InternalServerError("registry.Registry.ListServices", err.Error())
 	}
 
+	// parse the selector
+	sel, err := parseSelector(req.Selector)
+	if err != nil {
+		return errors.BadRequest("registry.Registry.ListServices", err.Error())
+	}
+
 	// list the services from the registry
 	services, err := registry.ListServices(goregistry.ListDomain(domain))
 	if err != nil {
 		return errors.InternalServerError("registry.Registry.ListServices", err.Error())
 	}
 
+	// filter out unhealthy nodes and those not matching the selector
+	services = filter(services, sel, r.Health.status, req.All)
+
 	// serialize the response
 	rsp.Services = make([]*pb.Service, len(services))
 	for i, srv := range services {
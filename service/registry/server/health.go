@@ -0,0 +1,233 @@
+package server
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	goclient "github.com/micro/go-micro/v3/client"
+	"github.com/micro/go-micro/v3/registry"
+	debug "github.com/micro/micro/v3/service/debug/proto"
+	"github.com/micro/micro/v3/service/errors"
+	log "github.com/micro/micro/v3/service/logger"
+	muregistry "github.com/micro/micro/v3/service/registry"
+)
+
+const (
+	// HealthUnknown is the status of a node which has not been checked or doesn't serve Debug.Health
+	HealthUnknown = "unknown"
+	// HealthHealthy is the status of a node which passed its last health check
+	HealthHealthy = "healthy"
+	// HealthUnhealthy is the status of a node which failed too many health checks in a row
+	HealthUnhealthy = "unhealthy"
+)
+
+// nodeHealth is the result of the health checks of a single node
+type nodeHealth struct {
+	status   string
+	failures int
+	checked  time.Time
+}
+
+// health periodically calls Debug.Health on every registered node and records the results
+type health struct {
+	// interval between checks
+	interval time.Duration
+	// consecutive failures before a node is considered unhealthy
+	threshold int
+
+	sync.RWMutex
+	nodes map[string]*nodeHealth
+}
+
+func newHealth(interval time.Duration, threshold int) *health {
+	if threshold <= 0 {
+		threshold = 1
+	}
+	return &health{
+		interval:  interval,
+		threshold: threshold,
+		nodes:     make(map[string]*nodeHealth),
+	}
+}
+
+// status returns the health status of a node
+func (h *health) status(id string) string {
+	h.RLock()
+	defer h.RUnlock()
+	if n, ok := h.nodes[id]; ok {
+		return n.status
+	}
+	return HealthUnknown
+}
+
+// filter removes unhealthy nodes and those which don't match the selector. Services left
+// without any nodes are dropped. If status is set, the health status of each node is set in
+// its metadata, otherwise the metadata is copied as is.
+func filter(services []*registry.Service, sel selector, status func(id string) string, all bool) []*registry.Service {
+	var result []*registry.Service
+
+	for _, srv := range services {
+		if !sel.matchService(srv) {
+			continue
+		}
+
+		nodes := make([]*registry.Node, 0, len(srv.Nodes))
+		for _, node := range srv.Nodes {
+			if !sel.matchNode(srv, node) {
+				continue
+			}
+
+			// copy the metadata so the record in the registry isn't modified
+			md := make(map[string]string, len(node.Metadata)+1)
+			for k, v := range node.Metadata {
+				md[k] = v
+			}
+
+			// the health of nodes imported from peers is set by the peer
+			if status != nil && !isImported(node) {
+				md["health"] = status(node.Id)
+			}
+			if md["health"] == HealthUnhealthy && !all {
+				continue
+			}
+
+			nodes = append(nodes, &registry.Node{
+				Id:       node.Id,
+				Address:  node.Address,
+				Metadata: md,
+			})
+		}
+
+		// a service which had nodes but none remain is not returned
+		if len(srv.Nodes) > 0 && len(nodes) == 0 {
+			continue
+		}
+
+		result = append(result, &registry.Service{
+			Name:      srv.Name,
+			Version:   srv.Version,
+			Metadata:  srv.Metadata,
+			Endpoints: srv.Endpoints,
+			Nodes:     nodes,
+		})
+	}
+
+	return result
+}
+
+// check calls Debug.Health on the node and records the result
+func (h *health) check(srv *registry.Service, node *registry.Node) {
+	req := directClient.NewRequest(srv.Name, "Debug.Health", &debug.HealthRequest{})
+	rsp := &debug.HealthResponse{}
+
+	err := directClient.Call(
+		context.Background(),
+		req,
+		rsp,
+		goclient.WithAddress(node.Address),
+		goclient.WithRequestTimeout(h.interval/2),
+		goclient.WithRetries(0),
+	)
+
+	h.Lock()
+	defer h.Unlock()
+
+	n, ok := h.nodes[node.Id]
+	if !ok {
+		n = &nodeHealth{status: HealthUnknown}
+		h.nodes[node.Id] = n
+	}
+	n.checked = time.Now()
+
+	// services which don't serve the debug handler, e.g. those run behind
+	// a proxy, can't be checked so their health remains unknown
+	if unimplemented(err) {
+		n.status = HealthUnknown
+		n.failures = 0
+		return
+	}
+
+	if err == nil && rsp.Status == "ok" {
+		n.status = HealthHealthy
+		n.failures = 0
+		return
+	}
+
+	n.failures++
+	if n.failures >= h.threshold {
+		if n.status != HealthUnhealthy {
+			log.Infof("Node %s of service %s at %s is unhealthy: %v", node.Id, srv.Name, node.Address, err)
+		}
+		n.status = HealthUnhealthy
+	}
+}
+
+// unimplemented returns true if the error is returned by a service which doesn't serve the
+// endpoint. The grpc client returns the unimplemented status of the server as an internal
+// server error so it's matched by its detail.
+func unimplemented(err error) bool {
+	verr := errors.Parse(err)
+	if verr == nil {
+		return false
+	}
+	if verr.Code == 404 || verr.Code == 501 {
+		return true
+	}
+	return verr.Code == 500 && strings.HasPrefix(verr.Detail, "unknown service")
+}
+
+// checkAll checks every node in every domain, other than those imported from peers, and forgets
+// nodes which have been deregistered
+func (h *health) checkAll() {
+	services, err := muregistry.ListServices(registry.ListDomain(registry.WildcardDomain))
+	if err != nil {
+		log.Errorf("Error listing services for health checks: %v", err)
+		return
+	}
+
+	seen := make(map[string]bool)
+	var wg sync.WaitGroup
+
+	for _, srv := range services {
+		for _, node := range srv.Nodes {
+			// nodes imported from peers are checked by the registry they're imported from
+			if isImported(node) {
+				continue
+			}
+			seen[node.Id] = true
+
+			wg.Add(1)
+			go func(srv *registry.Service, node *registry.Node) {
+				defer wg.Done()
+				h.check(srv, node)
+			}(srv, node)
+		}
+	}
+
+	wg.Wait()
+
+	h.Lock()
+	for id := range h.nodes {
+		if !seen[id] {
+			delete(h.nodes, id)
+		}
+	}
+	h.Unlock()
+}
+
+// run checks the nodes every interval until exit is closed
+func (h *health) run(exit chan bool) {
+	t := time.NewTicker(h.interval)
+	defer t.Stop()
+
+	for {
+		select {
+		case <-exit:
+			return
+		case <-t.C:
+			h.checkAll()
+		}
+	}
+}
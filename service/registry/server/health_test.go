@@ -0,0 +1,60 @@
+package server
+
+import (
+	"testing"
+	"time"
+
+	"github.com/micro/go-micro/v3/registry"
+	"github.com/micro/go-micro/v3/registry/memory"
+	"github.com/micro/go-micro/v3/server"
+	"github.com/micro/go-micro/v3/server/grpc"
+	muregistry "github.com/micro/micro/v3/service/registry"
+)
+
+func TestHealthUnimplemented(t *testing.T) {
+	reg := muregistry.DefaultRegistry
+	muregistry.DefaultRegistry = memory.NewRegistry()
+	defer func() { muregistry.DefaultRegistry = reg }()
+
+	// a service which doesn't serve the debug handler
+	srv := grpc.NewServer(
+		server.Name("foo"),
+		server.Address("127.0.0.1:0"),
+		server.Registry(memory.NewRegistry()),
+	)
+	if err := srv.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer srv.Stop()
+
+	muregistry.Register(&registry.Service{
+		Name:    "foo",
+		Version: "latest",
+		Nodes:   []*registry.Node{{Id: "foo-1", Address: srv.Options().Address}},
+	})
+	// a node imported from a peer which isn't reachable from this registry
+	muregistry.Register(&registry.Service{
+		Name:    "bar",
+		Version: "latest",
+		Nodes: []*registry.Node{{
+			Id:       "bar-1",
+			Address:  "127.0.0.1:1",
+			Metadata: map[string]string{peerMetadata: "peer-a", "health": HealthHealthy},
+		}},
+	}, registry.RegisterDomain("federation"))
+
+	h := newHealth(time.Second, 1)
+	h.checkAll()
+
+	if status := h.status("foo-1"); status != HealthUnknown {
+		t.Errorf("Expected a node without a debug handler to have an unknown health, got %v", status)
+	}
+	if _, ok := h.nodes["bar-1"]; ok {
+		t.Errorf("Expected the imported node not to be checked")
+	}
+
+	services, _ := muregistry.GetService("bar", registry.GetDomain("federation"))
+	if rsp := filter(services, nil, h.status, false); len(rsp) != 1 || rsp[0].Nodes[0].Metadata["health"] != HealthHealthy {
+		t.Errorf("Expected the imported node to keep the health set by the peer, got %v", rsp)
+	}
+}
@@ -0,0 +1,112 @@
+package server
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/micro/go-micro/v3/registry"
+)
+
+// match is a single term of a selector e.g. region=eu
+type match struct {
+	key    string
+	op     string
+	value  string
+	regexp *regexp.Regexp
+}
+
+// selector filters services by name and version and nodes by metadata. It is a comma separated
+// list of terms of the form key=value, key!=value, key=~regexp or key!~regexp. The name and version
+// keys match the service, any other key matches the node metadata falling back to the service metadata.
+type selector []*match
+
+// parseSelector parses a selector such as "version=~1.2, region=eu"
+func parseSelector(s string) (selector, error) {
+	var sel selector
+
+	for _, term := range strings.Split(s, ",") {
+		term = strings.TrimSpace(term)
+		if len(term) == 0 {
+			continue
+		}
+
+		// the longest operators must be checked first
+		var m *match
+		for _, op := range []string{"!=", "=~", "!~", "="} {
+			idx := strings.Index(term, op)
+			if idx <= 0 {
+				continue
+			}
+			m = &match{
+				key:   strings.TrimSpace(term[:idx]),
+				op:    op,
+				value: strings.TrimSpace(term[idx+len(op):]),
+			}
+			break
+		}
+		if m == nil {
+			return nil, fmt.Errorf("invalid selector term %q", term)
+		}
+
+		if m.op == "=~" || m.op == "!~" {
+			re, err := regexp.Compile(m.value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid selector term %q: %v", term, err)
+			}
+			m.regexp = re
+		}
+
+		sel = append(sel, m)
+	}
+
+	return sel, nil
+}
+
+func (m *match) matches(v string, ok bool) bool {
+	switch m.op {
+	case "=":
+		return ok && v == m.value
+	case "!=":
+		return !ok || v != m.value
+	case "=~":
+		return ok && m.regexp.MatchString(v)
+	case "!~":
+		return !ok || !m.regexp.MatchString(v)
+	}
+	return false
+}
+
+// matchService returns true if the service level terms of the selector match
+func (s selector) matchService(srv *registry.Service) bool {
+	for _, m := range s {
+		switch m.key {
+		case "name":
+			if !m.matches(srv.Name, true) {
+				return false
+			}
+		case "version":
+			if !m.matches(srv.Version, true) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// matchNode returns true if the metadata terms of the selector match the node
+func (s selector) matchNode(srv *registry.Service, node *registry.Node) bool {
+	for _, m := range s {
+		if m.key == "name" || m.key == "version" {
+			continue
+		}
+		v, ok := node.Metadata[m.key]
+		if !ok {
+			v, ok = srv.Metadata[m.key]
+		}
+		if !m.matches(v, ok) {
+			return false
+		}
+	}
+	return true
+}
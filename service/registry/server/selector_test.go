@@ -0,0 +1,99 @@
+package server
+
+import (
+	"testing"
+
+	"github.com/micro/go-micro/v3/registry"
+)
+
+func TestSelector(t *testing.T) {
+	services := []*registry.Service{
+		{
+			Name:    "foo",
+			Version: "1.2.1",
+			Nodes: []*registry.Node{
+				{Id: "foo-1", Metadata: map[string]string{"region": "eu"}},
+				{Id: "foo-2", Metadata: map[string]string{"region": "us"}},
+			},
+		},
+		{
+			Name:    "foo",
+			Version: "2.0.0",
+			Nodes: []*registry.Node{
+				{Id: "foo-3", Metadata: map[string]string{"region": "eu"}},
+			},
+		},
+	}
+
+	tt := []struct {
+		selector string
+		nodes    []string
+	}{
+		{"", []string{"foo-1", "foo-2", "foo-3"}},
+		{"version=~^1\\.2", []string{"foo-1", "foo-2"}},
+		{"version=~1.2, region=eu", []string{"foo-1"}},
+		{"region!=eu", []string{"foo-2"}},
+		{"version!~^1, region=eu", []string{"foo-3"}},
+		{"zone=a", nil},
+	}
+
+	h := newHealth(0, 1)
+	for _, tc := range tt {
+		t.Run(tc.selector, func(t *testing.T) {
+			sel, err := parseSelector(tc.selector)
+			if err != nil {
+				t.Fatalf("Unexpected error parsing selector: %v", err)
+			}
+
+			var nodes []string
+			for _, srv := range filter(services, sel, h.status, false) {
+				for _, n := range srv.Nodes {
+					nodes = append(nodes, n.Id)
+				}
+			}
+			if len(nodes) != len(tc.nodes) {
+				t.Fatalf("Expected nodes %v, got %v", tc.nodes, nodes)
+			}
+			for i := range nodes {
+				if nodes[i] != tc.nodes[i] {
+					t.Fatalf("Expected nodes %v, got %v", tc.nodes, nodes)
+				}
+			}
+		})
+	}
+
+	if _, err := parseSelector("region"); err == nil {
+		t.Error("Expected an error for a term without an operator")
+	}
+}
+
+func TestHealthFilter(t *testing.T) {
+	services := []*registry.Service{
+		{
+			Name: "foo",
+			Nodes: []*registry.Node{
+				{Id: "foo-1"},
+				{Id: "foo-2"},
+			},
+		},
+	}
+
+	h := newHealth(0, 1)
+	h.nodes["foo-1"] = &nodeHealth{status: HealthUnhealthy}
+
+	rsp := filter(services, nil, h.status, false)
+	if len(rsp) != 1 || len(rsp[0].Nodes) != 1 || rsp[0].Nodes[0].Id != "foo-2" {
+		t.Fatalf("Expected only the healthy node to be returned, got %v", rsp)
+	}
+	if status := rsp[0].Nodes[0].Metadata["health"]; status != HealthUnknown {
+		t.Errorf("Expected health metadata %v, got %v", HealthUnknown, status)
+	}
+
+	rsp = filter(services, nil, h.status, true)
+	if len(rsp) != 1 || len(rsp[0].Nodes) != 2 {
+		t.Fatalf("Expected unhealthy nodes to be included, got %v", rsp)
+	}
+	if services[0].Nodes[0].Metadata != nil {
+		t.Errorf("Expected the registry record not to be modified")
+	}
+}
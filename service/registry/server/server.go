@@ -2,9 +2,11 @@ package server
 
 import (
 	"context"
+	"fmt"
 	"time"
 
 	"github.com/micro/cli/v2"
+	"github.com/micro/go-micro/v3/client/grpc"
 	"github.com/micro/go-micro/v3/registry"
 	"github.com/micro/micro/v3/service"
 	log "github.com/micro/micro/v3/service/logger"
@@ -19,6 +21,46 @@ var (
 	address = ":8000"
 	// topic to publish registry events to
 	topic = "registry.events"
+	// directClient calls nodes and peers at their address rather than through the proxy
+	directClient = grpc.NewClient()
+
+	// Flags specific to the registry
+	Flags = []cli.Flag{
+		&cli.IntFlag{
+			Name:    "health_check_interval",
+			Usage:   "Interval in seconds between health checks of registered nodes, 0 to disable",
+			EnvVars: []string{"MICRO_REGISTRY_HEALTH_CHECK_INTERVAL"},
+			Value:   30,
+		},
+		&cli.IntFlag{
+			Name:    "health_check_threshold",
+			Usage:   "Number of consecutive failed health checks before a node is filtered from lookups",
+			EnvVars: []string{"MICRO_REGISTRY_HEALTH_CHECK_THRESHOLD"},
+			Value:   3,
+		},
+		&cli.StringSliceFlag{
+			Name:    "peers",
+			Usage:   "Addresses of remote registry services to import services from e.g. 10.0.0.2:8000",
+			EnvVars: []string{"MICRO_REGISTRY_PEERS"},
+		},
+		&cli.StringFlag{
+			Name:    "peer_domain",
+			Usage:   "Domain the services of the peers are imported into, only the default domain of the peers is imported",
+			EnvVars: []string{"MICRO_REGISTRY_PEER_DOMAIN"},
+			Value:   "federation",
+		},
+		&cli.StringFlag{
+			Name:    "peer_selector",
+			Usage:   "Only import the services of the peers matching the selector e.g. name=~^foo,region=eu",
+			EnvVars: []string{"MICRO_REGISTRY_PEER_SELECTOR"},
+		},
+		&cli.IntFlag{
+			Name:    "peer_interval",
+			Usage:   "Interval in seconds between syncs with the peers",
+			EnvVars: []string{"MICRO_REGISTRY_PEER_INTERVAL"},
+			Value:   30,
+		},
+	}
 )
 
 // Sub processes registry events
@@ -101,10 +143,41 @@ func Run(ctx *cli.Context) error {
 	// get server id
 	id := srv.Server().Options().Id
 
+	// check the health of registered nodes
+	interval := time.Duration(ctx.Int("health_check_interval")) * time.Second
+	health := newHealth(interval, ctx.Int("health_check_threshold"))
+	if interval > 0 {
+		exit := make(chan bool)
+		defer close(exit)
+		go health.run(exit)
+	}
+
+	// import the services of the peers
+	filter, err := parseSelector(ctx.String("peer_selector"))
+	if err != nil {
+		return err
+	}
+	fed := newFederation(
+		ctx.StringSlice("peers"),
+		ctx.String("peer_domain"),
+		time.Duration(ctx.Int("peer_interval"))*time.Second,
+		filter,
+	)
+	if len(fed.peers) > 0 {
+		if fed.interval <= 0 || len(fed.domain) == 0 || fed.domain == registry.DefaultDomain {
+			return fmt.Errorf("invalid peer interval or domain")
+		}
+		exit := make(chan bool)
+		defer close(exit)
+		go fed.run(exit)
+	}
+	pb.RegisterFederationHandler(srv.Server(), fed)
+
 	// register the handler
 	pb.RegisterRegistryHandler(srv.Server(), &Registry{
-		ID:    id,
-		Event: service.NewEvent(topic),
+		ID:     id,
+		Event:  service.NewEvent(topic),
+		Health: health,
 	})
 
 	// run the service
@@ -0,0 +1,221 @@
+// Package cli implements the `micro router` subcommands
+// for example:
+//   micro router policy create --service helloworld --weight v1=90 --weight v2=10
+//   micro router policy list
+//   micro router policy delete --service helloworld
+package cli
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/micro/cli/v2"
+	goclient "github.com/micro/go-micro/v3/client"
+	"github.com/micro/micro/v3/client/cli/namespace"
+	"github.com/micro/micro/v3/client/cli/util"
+	"github.com/micro/micro/v3/cmd"
+	"github.com/micro/micro/v3/internal/helper"
+	"github.com/micro/micro/v3/service/client"
+	"github.com/micro/micro/v3/service/context"
+	"github.com/micro/micro/v3/service/errors"
+	pb "github.com/micro/micro/v3/service/router/proto"
+)
+
+func init() {
+	cmd.Register(&cli.Command{
+		Name:   "router",
+		Usage:  "Commands for managing the router",
+		Action: helper.UnexpectedSubcommand,
+		Subcommands: []*cli.Command{
+			{
+				Name:   "policy",
+				Usage:  "Manage the traffic policies of services, they are served by the router service which is run with \"micro service router\"",
+				Action: helper.UnexpectedSubcommand,
+				Subcommands: []*cli.Command{
+					{
+						Name:   "create",
+						Usage:  `Create or replace a policy, e.g. "micro router policy create --service helloworld --weight v1=90 --weight v2=10"`,
+						Action: createPolicy,
+						Flags: []cli.Flag{
+							&cli.StringFlag{
+								Name:     "service",
+								Usage:    "The service the policy applies to",
+								Required: true,
+							},
+							&cli.StringSliceFlag{
+								Name:  "weight",
+								Usage: "Percentage of requests routed to a version, in the format version=percentage",
+							},
+							&cli.StringSliceFlag{
+								Name:  "match",
+								Usage: "Route requests with a header value to a version, in the format header=value:version",
+							},
+						},
+					},
+					{
+						Name:   "list",
+						Usage:  "List the policies",
+						Action: listPolicies,
+						Flags: []cli.Flag{
+							&cli.StringFlag{
+								Name:  "service",
+								Usage: "Only list the policy of this service",
+							},
+						},
+					},
+					{
+						Name:   "delete",
+						Usage:  "Delete the policy of a service",
+						Action: deletePolicy,
+						Flags: []cli.Flag{
+							&cli.StringFlag{
+								Name:     "service",
+								Usage:    "The service the policy applies to",
+								Required: true,
+							},
+						},
+					},
+				},
+			},
+		},
+	})
+}
+
+func createPolicy(ctx *cli.Context) error {
+	ns, err := namespace.Get(util.GetEnv(ctx).Name)
+	if err != nil {
+		return fmt.Errorf("Error getting namespace: %v", err)
+	}
+
+	policy, err := constructPolicy(ctx)
+	if err != nil {
+		return err
+	}
+
+	cli := pb.NewPolicyService("router", client.DefaultClient)
+	_, err = cli.Create(context.DefaultContext, &pb.CreatePolicyRequest{
+		Policy: policy, Namespace: ns,
+	}, goclient.WithAuthToken())
+	if verr := errors.Parse(err); verr != nil {
+		return fmt.Errorf("Error: %v", verr.Detail)
+	} else if err != nil {
+		return err
+	}
+
+	fmt.Println("Policy created")
+	return nil
+}
+
+func listPolicies(ctx *cli.Context) error {
+	ns, err := namespace.Get(util.GetEnv(ctx).Name)
+	if err != nil {
+		return fmt.Errorf("Error getting namespace: %v", err)
+	}
+
+	cli := pb.NewPolicyService("router", client.DefaultClient)
+	rsp, err := cli.List(context.DefaultContext, &pb.ListPoliciesRequest{
+		Service: ctx.String("service"), Namespace: ns,
+	}, goclient.WithAuthToken())
+	if verr := errors.Parse(err); verr != nil {
+		return fmt.Errorf("Error listing policies: %v", verr.Detail)
+	} else if err != nil {
+		return fmt.Errorf("Error listing policies: %v", err)
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 8, 1, '\t', 0)
+	defer w.Flush()
+
+	sort.Slice(rsp.Policies, func(i, j int) bool {
+		return rsp.Policies[i].Service < rsp.Policies[j].Service
+	})
+
+	fmt.Fprintln(w, strings.Join([]string{"SERVICE", "WEIGHTS", "MATCHES"}, "\t"))
+	for _, p := range rsp.Policies {
+		fmt.Fprintln(w, strings.Join([]string{p.Service, formatWeights(p), formatMatches(p)}, "\t"))
+	}
+
+	return nil
+}
+
+func deletePolicy(ctx *cli.Context) error {
+	ns, err := namespace.Get(util.GetEnv(ctx).Name)
+	if err != nil {
+		return fmt.Errorf("Error getting namespace: %v", err)
+	}
+
+	cli := pb.NewPolicyService("router", client.DefaultClient)
+	_, err = cli.Delete(context.DefaultContext, &pb.DeletePolicyRequest{
+		Service: ctx.String("service"), Namespace: ns,
+	}, goclient.WithAuthToken())
+	if verr := errors.Parse(err); verr != nil {
+		return fmt.Errorf("Error: %v", verr.Detail)
+	} else if err != nil {
+		return err
+	}
+
+	fmt.Println("Policy deleted")
+	return nil
+}
+
+func constructPolicy(ctx *cli.Context) (*pb.TrafficPolicy, error) {
+	policy := &pb.TrafficPolicy{
+		Service: ctx.String("service"),
+		Weights: make(map[string]int64),
+	}
+
+	for _, w := range ctx.StringSlice("weight") {
+		parts := strings.SplitN(w, "=", 2)
+		if len(parts) != 2 || len(parts[0]) == 0 {
+			return nil, fmt.Errorf("Invalid weight %q, must be in the format version=percentage", w)
+		}
+		weight, err := strconv.ParseInt(parts[1], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid weight %q, must be in the format version=percentage", w)
+		}
+		policy.Weights[parts[0]] = weight
+	}
+
+	for _, m := range ctx.StringSlice("match") {
+		idx := strings.LastIndex(m, ":")
+		if idx <= 0 {
+			return nil, fmt.Errorf("Invalid match %q, must be in the format header=value:version", m)
+		}
+		parts := strings.SplitN(m[:idx], "=", 2)
+		if len(parts) != 2 || len(parts[0]) == 0 {
+			return nil, fmt.Errorf("Invalid match %q, must be in the format header=value:version", m)
+		}
+		policy.Matches = append(policy.Matches, &pb.PolicyMatch{
+			Header:  parts[0],
+			Value:   parts[1],
+			Version: m[idx+1:],
+		})
+	}
+
+	return policy, nil
+}
+
+func formatWeights(p *pb.TrafficPolicy) string {
+	versions := make([]string, 0, len(p.Weights))
+	for v := range p.Weights {
+		versions = append(versions, v)
+	}
+	sort.Strings(versions)
+
+	weights := make([]string, 0, len(versions))
+	for _, v := range versions {
+		weights = append(weights, fmt.Sprintf("%s=%d", v, p.Weights[v]))
+	}
+	return strings.Join(weights, ",")
+}
+
+func formatMatches(p *pb.TrafficPolicy) string {
+	matches := make([]string, 0, len(p.Matches))
+	for _, m := range p.Matches {
+		matches = append(matches, fmt.Sprintf("%s=%s:%s", m.Header, m.Value, m.Version))
+	}
+	return strings.Join(matches, ",")
+}
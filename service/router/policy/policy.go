@@ -0,0 +1,280 @@
+// Package policy evaluates the traffic policies managed by the router service
+package policy
+
+import (
+	"context"
+	"math/rand"
+	"sort"
+	"sync"
+	"time"
+
+	goclient "github.com/micro/go-micro/v3/client"
+	"github.com/micro/go-micro/v3/metadata"
+	"github.com/micro/go-micro/v3/registry"
+	"github.com/micro/go-micro/v3/selector"
+	"github.com/micro/micro/v3/internal/namespace"
+	"github.com/micro/micro/v3/service/client"
+	muregistry "github.com/micro/micro/v3/service/registry"
+	pb "github.com/micro/micro/v3/service/router/proto"
+)
+
+var (
+	// DefaultTTL is how long the policies and versions of a service are cached for
+	DefaultTTL = time.Second * 30
+
+	// core services are never subject to policies, looking up their policies
+	// would otherwise recurse into the router and registry
+	core = map[string]bool{
+		"api":      true,
+		"auth":     true,
+		"broker":   true,
+		"config":   true,
+		"events":   true,
+		"network":  true,
+		"proxy":    true,
+		"registry": true,
+		"router":   true,
+		"runtime":  true,
+		"store":    true,
+	}
+
+	cache = newCache()
+	// router is whether the router service is registered, the policies aren't looked up when
+	// it isn't e.g. since it isn't run by default
+	router = &routerCheck{}
+)
+
+// routerCheck caches whether the router service is registered for the DefaultTTL
+type routerCheck struct {
+	sync.Mutex
+	registered bool
+	checked    time.Time
+}
+
+func (r *routerCheck) isRegistered() bool {
+	r.Lock()
+	defer r.Unlock()
+
+	if time.Since(r.checked) < DefaultTTL {
+		return r.registered
+	}
+	srvs, err := muregistry.GetService("router")
+	r.registered = err == nil && len(srvs) > 0
+	r.checked = time.Now()
+	return r.registered
+}
+
+// entry is the cached policy and node versions of a service in a namespace
+type entry struct {
+	policy   *pb.TrafficPolicy
+	versions map[string]string
+	expires  time.Time
+}
+
+type policyCache struct {
+	sync.RWMutex
+	entries map[string]*entry
+	// loading are the keys of the entries being loaded
+	loading map[string]bool
+}
+
+func newCache() *policyCache {
+	return &policyCache{
+		entries: make(map[string]*entry),
+		loading: make(map[string]bool),
+	}
+}
+
+// get returns the cached entry for a service, nil if it hasn't been loaded. Entries which are
+// missing or expired are loaded in the background so requests aren't delayed by the rpc calls,
+// until then the expired entry is returned. Each entry is only loaded once at a time.
+func (c *policyCache) get(ns, service string) *entry {
+	key := ns + "/" + service
+
+	c.RLock()
+	e, ok := c.entries[key]
+	c.RUnlock()
+	if ok && time.Now().Before(e.expires) {
+		return e
+	}
+
+	c.Lock()
+	if !c.loading[key] {
+		c.loading[key] = true
+		go c.load(key, ns, service)
+	}
+	c.Unlock()
+
+	return e
+}
+
+// load the entry and replace the cached one
+func (c *policyCache) load(key, ns, service string) {
+	e := load(ns, service)
+
+	c.Lock()
+	c.entries[key] = e
+	delete(c.loading, key)
+	c.Unlock()
+}
+
+// load the policy of a service and the version of each of its nodes
+func load(ns, service string) *entry {
+	e := &entry{expires: time.Now().Add(DefaultTTL)}
+	if !router.isRegistered() {
+		return e
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	rsp, err := pb.NewPolicyService("router", client.DefaultClient).List(ctx, &pb.ListPoliciesRequest{
+		Service: service, Namespace: ns,
+	}, goclient.WithAuthToken())
+	if err != nil || len(rsp.Policies) == 0 {
+		return e
+	}
+	e.policy = rsp.Policies[0]
+
+	srvs, err := muregistry.GetService(service, registry.GetDomain(ns))
+	if err != nil {
+		return e
+	}
+	e.versions = make(map[string]string)
+	for _, srv := range srvs {
+		for _, node := range srv.Nodes {
+			e.versions[node.Address] = srv.Version
+		}
+	}
+
+	return e
+}
+
+// Version returns the version of the service a request should be routed to,
+// or a blank string if the policy doesn't apply to the request
+func Version(ctx context.Context, p *pb.TrafficPolicy) string {
+	if p == nil {
+		return ""
+	}
+
+	// matches take precedence over the weights
+	for _, m := range p.Matches {
+		if val, ok := metadata.Get(ctx, m.Header); ok && val == m.Value {
+			return m.Version
+		}
+	}
+
+	if len(p.Weights) == 0 {
+		return ""
+	}
+
+	// sort the versions so the selection is deterministic for a given number
+	versions := make([]string, 0, len(p.Weights))
+	var total int64
+	for v, w := range p.Weights {
+		versions = append(versions, v)
+		total += w
+	}
+	if total <= 0 {
+		return ""
+	}
+	sort.Strings(versions)
+
+	n := rand.Int63n(total)
+	for _, v := range versions {
+		if n < p.Weights[v] {
+			return v
+		}
+		n -= p.Weights[v]
+	}
+
+	return ""
+}
+
+// Filter returns the addresses of the service which the policy routes the request to. If the
+// service has no policy or none of the addresses match the chosen version all are returned.
+func Filter(ctx context.Context, ns, service string, addrs []string) []string {
+	if core[service] || len(addrs) == 0 {
+		return addrs
+	}
+
+	e := cache.get(ns, service)
+	if e == nil {
+		return addrs
+	}
+	version := Version(ctx, e.policy)
+	if len(version) == 0 {
+		return addrs
+	}
+
+	var result []string
+	for _, addr := range addrs {
+		if e.versions[addr] == version {
+			result = append(result, addr)
+		}
+	}
+	if len(result) == 0 {
+		return addrs
+	}
+
+	return result
+}
+
+// Wrapper is a client.Wrapper which applies the traffic policies of the services called when the
+// node is selected, so they also apply to calls made to addresses, e.g. by the proxy
+func Wrapper(c goclient.Client) goclient.Client {
+	return &policyClient{Client: c}
+}
+
+type policyClient struct {
+	goclient.Client
+}
+
+func (c *policyClient) Call(ctx context.Context, req goclient.Request, rsp interface{}, opts ...goclient.CallOption) error {
+	if core[req.Service()] {
+		return c.Client.Call(ctx, req, rsp, opts...)
+	}
+	return c.Client.Call(ctx, req, rsp, append(opts, c.withSelector(ctx, req, opts))...)
+}
+
+func (c *policyClient) Stream(ctx context.Context, req goclient.Request, opts ...goclient.CallOption) (goclient.Stream, error) {
+	if core[req.Service()] {
+		return c.Client.Stream(ctx, req, opts...)
+	}
+	return c.Client.Stream(ctx, req, append(opts, c.withSelector(ctx, req, opts))...)
+}
+
+// withSelector returns the option which filters the nodes selected from by the policy of the
+// service in the namespace of the call
+func (c *policyClient) withSelector(ctx context.Context, req goclient.Request, opts []goclient.CallOption) goclient.CallOption {
+	var options goclient.CallOptions
+	for _, o := range opts {
+		o(&options)
+	}
+	sel := options.Selector
+	if sel == nil {
+		sel = c.Client.Options().Selector
+	}
+
+	ns := options.Network
+	if len(ns) == 0 {
+		ns, _ = metadata.Get(ctx, "Micro-Namespace")
+	}
+	if len(ns) == 0 {
+		ns = namespace.DefaultNamespace
+	}
+
+	return goclient.WithSelector(&policySelector{Selector: sel, ctx: ctx, namespace: ns, service: req.Service()})
+}
+
+// policySelector selects from the nodes the policy of the service routes the request to
+type policySelector struct {
+	selector.Selector
+	ctx       context.Context
+	namespace string
+	service   string
+}
+
+func (s *policySelector) Select(routes []string, opts ...selector.SelectOption) (selector.Next, error) {
+	return s.Selector.Select(Filter(s.ctx, s.namespace, s.service, routes), opts...)
+}
@@ -0,0 +1,134 @@
+package policy
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	goclient "github.com/micro/go-micro/v3/client"
+	"github.com/micro/go-micro/v3/metadata"
+	"github.com/micro/go-micro/v3/registry"
+	"github.com/micro/go-micro/v3/registry/memory"
+	"github.com/micro/go-micro/v3/selector/random"
+	"github.com/micro/micro/v3/service/client"
+	muregistry "github.com/micro/micro/v3/service/registry"
+	pb "github.com/micro/micro/v3/service/router/proto"
+)
+
+func TestVersion(t *testing.T) {
+	p := &pb.TrafficPolicy{
+		Service: "helloworld",
+		Weights: map[string]int64{"v1": 100, "v2": 0},
+		Matches: []*pb.PolicyMatch{{Header: "X-Canary", Value: "true", Version: "v2"}},
+	}
+
+	if v := Version(context.TODO(), nil); v != "" {
+		t.Fatalf("Expected no version without a policy, got %q", v)
+	}
+
+	for i := 0; i < 100; i++ {
+		if v := Version(context.TODO(), p); v != "v1" {
+			t.Fatalf("Expected v1, got %q", v)
+		}
+	}
+
+	ctx := metadata.Set(context.TODO(), "X-Canary", "true")
+	if v := Version(ctx, p); v != "v2" {
+		t.Fatalf("Expected the match to route to v2, got %q", v)
+	}
+
+	ctx = metadata.Set(context.TODO(), "X-Canary", "false")
+	if v := Version(ctx, p); v != "v1" {
+		t.Fatalf("Expected a non matching header to use the weights, got %q", v)
+	}
+}
+
+func TestVersionSplit(t *testing.T) {
+	p := &pb.TrafficPolicy{
+		Service: "helloworld",
+		Weights: map[string]int64{"v1": 50, "v2": 50},
+	}
+
+	counts := make(map[string]int)
+	for i := 0; i < 1000; i++ {
+		counts[Version(context.TODO(), p)]++
+	}
+	if counts["v1"] == 0 || counts["v2"] == 0 || counts["v1"]+counts["v2"] != 1000 {
+		t.Fatalf("Expected traffic to be split between v1 and v2, got %v", counts)
+	}
+}
+
+func TestSelect(t *testing.T) {
+	cache.Lock()
+	cache.entries["foo/helloworld"] = &entry{
+		policy: &pb.TrafficPolicy{
+			Service: "helloworld",
+			Matches: []*pb.PolicyMatch{{Header: "X-Canary", Value: "true", Version: "v2"}},
+		},
+		versions: map[string]string{"10.0.0.1:8080": "v1", "10.0.0.2:8080": "v2"},
+		expires:  time.Now().Add(time.Minute),
+	}
+	cache.Unlock()
+
+	// the policy is applied to the routes selected from, e.g. the addresses of a proxied call
+	ctx := metadata.Set(context.TODO(), "X-Canary", "true")
+	sel := &policySelector{Selector: random.NewSelector(), ctx: ctx, namespace: "foo", service: "helloworld"}
+	next, err := sel.Select([]string{"10.0.0.1:8080", "10.0.0.2:8080"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 10; i++ {
+		if n := next(); n != "10.0.0.2:8080" {
+			t.Fatalf("Expected the canary to be selected, got %v", n)
+		}
+	}
+
+	// policies which haven't been loaded don't delay or filter requests
+	cache.Lock()
+	cache.loading["bar/helloworld"] = true
+	cache.Unlock()
+	if addrs := Filter(ctx, "bar", "helloworld", []string{"10.0.0.1:8080"}); len(addrs) != 1 {
+		t.Fatalf("Expected the addresses to be returned while the policy is loaded, got %v", addrs)
+	}
+}
+
+type failClient struct {
+	goclient.Client
+	t *testing.T
+}
+
+func (f *failClient) Call(ctx context.Context, req goclient.Request, rsp interface{}, opts ...goclient.CallOption) error {
+	f.t.Fatalf("Expected the router not to be called, got a call to %v", req.Service())
+	return nil
+}
+
+func TestLoadWithoutRouter(t *testing.T) {
+	defer func(r registry.Registry, c goclient.Client, rc *routerCheck) {
+		muregistry.DefaultRegistry, client.DefaultClient, router = r, c, rc
+	}(muregistry.DefaultRegistry, client.DefaultClient, router)
+
+	// the router isn't registered so the policies aren't looked up
+	muregistry.DefaultRegistry = memory.NewRegistry()
+	client.DefaultClient = &failClient{t: t}
+	router = &routerCheck{}
+
+	if e := load("foo", "helloworld"); e.policy != nil || e.versions != nil {
+		t.Fatalf("Expected an empty entry, got %+v", e)
+	}
+	if !router.checked.After(time.Time{}) || router.registered {
+		t.Fatal("Expected the router to be cached as not registered")
+	}
+
+	// the negative result is cached, the registry isn't checked again until it expires
+	muregistry.DefaultRegistry.Register(&registry.Service{
+		Name:  "router",
+		Nodes: []*registry.Node{{Id: "router-1", Address: "10.0.0.1:8080"}},
+	})
+	if router.isRegistered() {
+		t.Fatal("Expected the cached result to be used")
+	}
+	router.checked = time.Time{}
+	if !router.isRegistered() {
+		t.Fatal("Expected the router to be registered once the cache expires")
+	}
+}
@@ -293,3 +293,96 @@ func (h *tableHandler) Update(ctx context.Context, in *Route, out *UpdateRespons
 func (h *tableHandler) Read(ctx context.Context, in *ReadRequest, out *ReadResponse) error {
 	return h.TableHandler.Read(ctx, in, out)
 }
+
+// Api Endpoints for Policy service
+
+func NewPolicyEndpoints() []*api.Endpoint {
+	return []*api.Endpoint{}
+}
+
+// Client API for Policy service
+
+type PolicyService interface {
+	Create(ctx context.Context, in *CreatePolicyRequest, opts ...client.CallOption) (*CreatePolicyResponse, error)
+	Delete(ctx context.Context, in *DeletePolicyRequest, opts ...client.CallOption) (*DeletePolicyResponse, error)
+	List(ctx context.Context, in *ListPoliciesRequest, opts ...client.CallOption) (*ListPoliciesResponse, error)
+}
+
+type policyService struct {
+	c    client.Client
+	name string
+}
+
+func NewPolicyService(name string, c client.Client) PolicyService {
+	return &policyService{
+		c:    c,
+		name: name,
+	}
+}
+
+func (c *policyService) Create(ctx context.Context, in *CreatePolicyRequest, opts ...client.CallOption) (*CreatePolicyResponse, error) {
+	req := c.c.NewRequest(c.name, "Policy.Create", in)
+	out := new(CreatePolicyResponse)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *policyService) Delete(ctx context.Context, in *DeletePolicyRequest, opts ...client.CallOption) (*DeletePolicyResponse, error) {
+	req := c.c.NewRequest(c.name, "Policy.Delete", in)
+	out := new(DeletePolicyResponse)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *policyService) List(ctx context.Context, in *ListPoliciesRequest, opts ...client.CallOption) (*ListPoliciesResponse, error) {
+	req := c.c.NewRequest(c.name, "Policy.List", in)
+	out := new(ListPoliciesResponse)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// Server API for Policy service
+
+type PolicyHandler interface {
+	Create(context.Context, *CreatePolicyRequest, *CreatePolicyResponse) error
+	Delete(context.Context, *DeletePolicyRequest, *DeletePolicyResponse) error
+	List(context.Context, *ListPoliciesRequest, *ListPoliciesResponse) error
+}
+
+func RegisterPolicyHandler(s server.Server, hdlr PolicyHandler, opts ...server.HandlerOption) error {
+	type policy interface {
+		Create(ctx context.Context, in *CreatePolicyRequest, out *CreatePolicyResponse) error
+		Delete(ctx context.Context, in *DeletePolicyRequest, out *DeletePolicyResponse) error
+		List(ctx context.Context, in *ListPoliciesRequest, out *ListPoliciesResponse) error
+	}
+	type Policy struct {
+		policy
+	}
+	h := &policyHandler{hdlr}
+	return s.Handle(s.NewHandler(&Policy{h}, opts...))
+}
+
+type policyHandler struct {
+	PolicyHandler
+}
+
+func (h *policyHandler) Create(ctx context.Context, in *CreatePolicyRequest, out *CreatePolicyResponse) error {
+	return h.PolicyHandler.Create(ctx, in, out)
+}
+
+func (h *policyHandler) Delete(ctx context.Context, in *DeletePolicyRequest, out *DeletePolicyResponse) error {
+	return h.PolicyHandler.Delete(ctx, in, out)
+}
+
+func (h *policyHandler) List(ctx context.Context, in *ListPoliciesRequest, out *ListPoliciesResponse) error {
+	return h.PolicyHandler.List(ctx, in, out)
+}
@@ -0,0 +1,144 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+
+	gostore "github.com/micro/go-micro/v3/store"
+	"github.com/micro/micro/v3/internal/namespace"
+	"github.com/micro/micro/v3/service/errors"
+	pb "github.com/micro/micro/v3/service/router/proto"
+	"github.com/micro/micro/v3/service/store"
+)
+
+const (
+	storePrefixPolicies = "policies"
+	joinKey             = "/"
+)
+
+// Policy manages the traffic policies stored for each service
+type Policy struct{}
+
+// authorize the request for the namespace, defaulting it if blank
+func authorizePolicy(ctx context.Context, ns *string, method string) error {
+	if len(*ns) == 0 {
+		*ns = namespace.DefaultNamespace
+	}
+	if err := namespace.Authorize(ctx, *ns); err == namespace.ErrForbidden {
+		return errors.Forbidden(method, err.Error())
+	} else if err == namespace.ErrUnauthorized {
+		return errors.Unauthorized(method, err.Error())
+	} else if err != nil {
+		return errors.InternalServerError(method, err.Error())
+	}
+	return nil
+}
+
+// validatePolicy ensures the policy can be evaluated by the clients
+func validatePolicy(p *pb.TrafficPolicy) error {
+	if len(p.Service) == 0 {
+		return errors.BadRequest("router.Policy.Create", "Service missing")
+	}
+	if len(p.Weights) == 0 && len(p.Matches) == 0 {
+		return errors.BadRequest("router.Policy.Create", "Weights or matches required")
+	}
+
+	if len(p.Weights) > 0 {
+		var total int64
+		for version, weight := range p.Weights {
+			if weight < 0 {
+				return errors.BadRequest("router.Policy.Create", "Invalid weight %d for version %s", weight, version)
+			}
+			total += weight
+		}
+		if total != 100 {
+			return errors.BadRequest("router.Policy.Create", "Weights must add up to 100, got %d", total)
+		}
+	}
+
+	for _, m := range p.Matches {
+		if len(m.Header) == 0 || len(m.Version) == 0 {
+			return errors.BadRequest("router.Policy.Create", "Matches require a header and version")
+		}
+	}
+
+	return nil
+}
+
+// Create or replace the policy for a service
+func (p *Policy) Create(ctx context.Context, req *pb.CreatePolicyRequest, rsp *pb.CreatePolicyResponse) error {
+	if req.Policy == nil {
+		return errors.BadRequest("router.Policy.Create", "Policy missing")
+	}
+	if err := validatePolicy(req.Policy); err != nil {
+		return err
+	}
+	if err := authorizePolicy(ctx, &req.Namespace, "router.Policy.Create"); err != nil {
+		return err
+	}
+
+	bytes, err := json.Marshal(req.Policy)
+	if err != nil {
+		return errors.InternalServerError("router.Policy.Create", "Unable to marshal policy: %v", err)
+	}
+
+	key := strings.Join([]string{storePrefixPolicies, req.Namespace, req.Policy.Service}, joinKey)
+	if err := store.DefaultStore.Write(&gostore.Record{Key: key, Value: bytes}); err != nil {
+		return errors.InternalServerError("router.Policy.Create", "Unable to write to store: %v", err)
+	}
+
+	return nil
+}
+
+// Delete the policy for a service
+func (p *Policy) Delete(ctx context.Context, req *pb.DeletePolicyRequest, rsp *pb.DeletePolicyResponse) error {
+	if len(req.Service) == 0 {
+		return errors.BadRequest("router.Policy.Delete", "Service missing")
+	}
+	if err := authorizePolicy(ctx, &req.Namespace, "router.Policy.Delete"); err != nil {
+		return err
+	}
+
+	key := strings.Join([]string{storePrefixPolicies, req.Namespace, req.Service}, joinKey)
+	if err := store.DefaultStore.Delete(key); err == gostore.ErrNotFound {
+		return errors.NotFound("router.Policy.Delete", "Policy not found")
+	} else if err != nil {
+		return errors.InternalServerError("router.Policy.Delete", "Unable to delete from store: %v", err)
+	}
+
+	return nil
+}
+
+// List the policies in a namespace, optionally for a single service
+func (p *Policy) List(ctx context.Context, req *pb.ListPoliciesRequest, rsp *pb.ListPoliciesResponse) error {
+	if err := authorizePolicy(ctx, &req.Namespace, "router.Policy.List"); err != nil {
+		return err
+	}
+
+	var recs []*gostore.Record
+	var err error
+	if len(req.Service) > 0 {
+		key := strings.Join([]string{storePrefixPolicies, req.Namespace, req.Service}, joinKey)
+		recs, err = store.DefaultStore.Read(key)
+	} else {
+		prefix := strings.Join([]string{storePrefixPolicies, req.Namespace, ""}, joinKey)
+		recs, err = store.DefaultStore.Read(prefix, gostore.ReadPrefix())
+	}
+	if err == gostore.ErrNotFound {
+		return nil
+	} else if err != nil {
+		return errors.InternalServerError("router.Policy.List", "Unable to read from store: %v", err)
+	}
+
+	rsp.Policies = make([]*pb.TrafficPolicy, 0, len(recs))
+	for _, rec := range recs {
+		var policy *pb.TrafficPolicy
+		if err := json.Unmarshal(rec.Value, &policy); err != nil {
+			return errors.InternalServerError("router.Policy.List", "Error unmarshaling json: %v", err)
+		}
+		rsp.Policies = append(rsp.Policies, policy)
+	}
+
+	return nil
+}
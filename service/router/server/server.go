@@ -66,6 +66,7 @@ func Run(ctx *cli.Context) error {
 	// register handlers
 	pb.RegisterRouterHandler(srv.Server(), &Router{Router: r})
 	pb.RegisterTableHandler(srv.Server(), &Table{Router: r})
+	pb.RegisterPolicyHandler(srv.Server(), &Policy{})
 
 	return srv.Run()
 }
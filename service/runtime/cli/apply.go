@@ -0,0 +1,232 @@
+package runtime
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/micro/cli/v2"
+	goruntime "github.com/micro/go-micro/v3/runtime"
+	"github.com/micro/go-micro/v3/runtime/local/source/git"
+	"github.com/micro/micro/v3/client/cli/namespace"
+	"github.com/micro/micro/v3/client/cli/util"
+	"github.com/micro/micro/v3/service/runtime"
+	"github.com/micro/micro/v3/service/runtime/client"
+)
+
+const (
+	// ApplyUsage message for the apply command
+	ApplyUsage = "Converge the running services with a manifest: micro apply -f micro.yaml"
+	// DiffUsage message for the diff command
+	DiffUsage = "Show the changes micro apply would make: micro diff -f micro.yaml"
+)
+
+// applied describes the completed steps
+var applied = map[Action]string{
+	ActionCreate: "Created",
+	ActionUpdate: "Updated",
+	ActionDelete: "Deleted",
+}
+
+var manifestFlags = []cli.Flag{
+	&cli.StringFlag{
+		Name:     "file",
+		Aliases:  []string{"f"},
+		Usage:    "Set the manifest to apply, - reads from stdin",
+		Required: true,
+	},
+}
+
+// loadPlan reads the manifest and the running services and returns the steps to converge them
+func loadPlan(ctx *cli.Context) ([]*Step, string, error) {
+	m, err := loadManifest(ctx.String("file"))
+	if err != nil {
+		return nil, "", err
+	}
+
+	ns, err := namespace.Get(util.GetEnv(ctx).Name)
+	if err != nil {
+		return nil, "", err
+	}
+
+	running, err := runtime.Read(goruntime.ReadNamespace(ns))
+	if err != nil {
+		return nil, "", err
+	}
+
+	steps, err := plan(m, running)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return steps, ns, nil
+}
+
+// printPlan writes the steps in a diff like format
+func printPlan(steps []*Step) {
+	counts := make(map[Action]int)
+	for _, s := range steps {
+		counts[s.Action]++
+
+		switch s.Action {
+		case ActionCreate:
+			fmt.Printf("+ %s@%s\n", s.Name, s.Version)
+		case ActionUpdate:
+			fmt.Printf("~ %s@%s\n", s.Name, s.Version)
+		case ActionDelete:
+			fmt.Printf("- %s@%s\n", s.Name, s.Version)
+		default:
+			fmt.Printf("  %s@%s\n", s.Name, s.Version)
+		}
+	}
+
+	fmt.Printf("\nPlan: %d to create, %d to update, %d to delete, %d unchanged\n",
+		counts[ActionCreate], counts[ActionUpdate], counts[ActionDelete], counts[ActionNone])
+}
+
+func diffManifest(ctx *cli.Context) error {
+	steps, _, err := loadPlan(ctx)
+	if err != nil {
+		return err
+	}
+	printPlan(steps)
+	return nil
+}
+
+func applyManifest(ctx *cli.Context) error {
+	steps, ns, err := loadPlan(ctx)
+	if err != nil {
+		return err
+	}
+
+	// local sources are relative to the manifest
+	dir, err := os.Getwd()
+	if err != nil {
+		return err
+	}
+	if file := ctx.String("file"); file != "-" {
+		if dir, err = filepath.Abs(filepath.Dir(file)); err != nil {
+			return err
+		}
+	}
+
+	var changed bool
+	for _, s := range steps {
+		if s.Action == ActionNone {
+			continue
+		}
+		changed = true
+
+		srv := &goruntime.Service{Name: s.Name, Version: s.Version}
+
+		// updates recreate the service since the env, image etc can't be changed in place
+		if s.Action == ActionDelete || s.Action == ActionUpdate {
+			if err := runtime.Delete(srv, goruntime.DeleteNamespace(ns)); err != nil {
+				return fmt.Errorf("Error deleting %s@%s: %v", s.Name, s.Version, err)
+			}
+		}
+		if s.Action == ActionCreate || s.Action == ActionUpdate {
+			if err := createFromSpec(ctx, dir, ns, s.Spec); err != nil {
+				return fmt.Errorf("Error creating %s@%s: %v", s.Name, s.Version, err)
+			}
+		}
+
+		fmt.Printf("%s %s@%s\n", applied[s.Action], s.Name, s.Version)
+	}
+
+	if !changed {
+		fmt.Println("No changes")
+	}
+
+	return nil
+}
+
+// createFromSpec creates a service in the runtime as micro run would
+func createFromSpec(ctx *cli.Context, dir, ns string, spec *ServiceSpec) error {
+	ref := spec.Source
+	if spec.Version != "latest" {
+		ref += "@" + spec.Version
+	}
+
+	source, err := git.ParseSourceLocal(dir, appendSourceBase(ctx, dir, ref))
+	if err != nil {
+		return err
+	}
+
+	runtimeSource := source.RuntimeSource()
+	if source.Local {
+		if util.IsPlatform(ctx) {
+			return fmt.Errorf("local sources are not yet supported on m3o")
+		}
+		if runtimeSource, err = upload(ctx, source); err != nil {
+			return err
+		}
+	} else if err := sourceExists(source); err != nil {
+		return err
+	}
+
+	retries := DefaultRetries
+	if spec.Retries != nil {
+		retries = *spec.Retries
+	}
+	image := DefaultImage
+	if len(spec.Image) > 0 {
+		image = spec.Image
+	}
+
+	// when using the micro/cells:go image, we pass the source as the argument
+	args := strings.TrimSpace(spec.Args)
+	if len(args) == 0 {
+		args = runtimeSource
+		if len(source.Ref) > 0 {
+			args += "@" + source.Ref
+		}
+	}
+
+	opts := []goruntime.CreateOption{
+		goruntime.WithOutput(os.Stdout),
+		goruntime.WithRetries(retries),
+		goruntime.CreateImage(image),
+		goruntime.CreateType(spec.Type),
+		goruntime.CreateNamespace(ns),
+		goruntime.WithArgs(strings.Split(args, " ")...),
+	}
+	if r, _ := parseResources(spec.CPU, spec.Memory, spec.Disk); r != nil {
+		opts = append(opts, goruntime.ResourceLimits(r))
+	}
+	if spec.Replicas > 1 {
+		opts = append(opts, client.CreateReplicas(spec.Replicas))
+	}
+	if len(spec.DependsOn) > 0 {
+		opts = append(opts, client.CreateDependsOn(spec.DependsOn...))
+	}
+	if command := strings.TrimSpace(spec.Command); len(command) > 0 {
+		opts = append(opts, goruntime.WithCommand(strings.Split(command, " ")...))
+	}
+
+	// sort the env so the order is stable between applies
+	var env []string
+	for k, v := range spec.Env {
+		env = append(env, k+"="+v)
+	}
+	sort.Strings(env)
+	if len(env) > 0 {
+		opts = append(opts, goruntime.WithEnv(env))
+	}
+
+	for k, v := range spec.Secrets {
+		opts = append(opts, goruntime.WithSecret(k, v))
+	}
+	if creds, ok := getGitCredentials(source.Repo); ok {
+		opts = append(opts, goruntime.WithSecret(credentialsKey, creds))
+	}
+
+	return runtime.Create(&goruntime.Service{
+		Name:     spec.Name,
+		Source:   runtimeSource,
+		Version:  spec.Version,
+		Metadata: map[string]string{manifestKey: spec.hash()},
+	}, opts...)
+}
@@ -0,0 +1,83 @@
+package runtime
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/micro/cli/v2"
+	"github.com/micro/micro/v3/client/cli/namespace"
+	"github.com/micro/micro/v3/client/cli/util"
+	"github.com/micro/micro/v3/service/runtime"
+)
+
+const (
+	// BuildsUsage message for the builds command
+	BuildsUsage = "Manage the builds of the sources of services: micro builds [command]"
+)
+
+func listBuilds(ctx *cli.Context) error {
+	ns, err := namespace.Get(util.GetEnv(ctx).Name)
+	if err != nil {
+		return err
+	}
+
+	builds, err := runtime.ReadBuilds(ctx.Args().Get(0), ns)
+	if err != nil {
+		return err
+	}
+	if len(builds) == 0 {
+		fmt.Println("No builds found")
+		return nil
+	}
+
+	writer := tabwriter.NewWriter(os.Stdout, 0, 8, 1, '\t', tabwriter.AlignRight)
+	fmt.Fprintln(writer, "ID\tNAME\tVERSION\tSOURCE\tSIZE\tCREATED\tLAST USED\tIN USE")
+	for _, b := range builds {
+		fmt.Fprintf(writer, "%s\t%s\t%s\t%s\t%s\t%s\t%s\t%v\n",
+			b.ID[:12],
+			b.Name,
+			b.Version,
+			b.Source,
+			formatSize(b.Size),
+			timeAgo(b.Created.Format(time.RFC3339)),
+			timeAgo(b.LastUsed.Format(time.RFC3339)),
+			b.InUse)
+	}
+	writer.Flush()
+	return nil
+}
+
+func pruneBuilds(ctx *cli.Context) error {
+	ns, err := namespace.Get(util.GetEnv(ctx).Name)
+	if err != nil {
+		return err
+	}
+
+	pruned, err := runtime.PruneBuilds(ctx.Duration("unused_for"), ns)
+	if err != nil {
+		return err
+	}
+
+	var size int64
+	for _, b := range pruned {
+		size += b.Size
+	}
+	fmt.Printf("Pruned %d builds, freeing %s\n", len(pruned), formatSize(size))
+	return nil
+}
+
+// formatSize returns the number of bytes in a readable format e.g. 12.5MB
+func formatSize(b int64) string {
+	const unit = 1024
+	if b < unit {
+		return fmt.Sprintf("%dB", b)
+	}
+	div, exp := int64(unit), 0
+	for n := b / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f%cB", float64(b)/float64(div), "KMGTPE"[exp])
+}
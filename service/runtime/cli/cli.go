@@ -32,6 +32,18 @@ var flags = []cli.Flag{
 		Name:  "env_vars",
 		Usage: "Set the environment variables e.g. foo=bar",
 	},
+	&cli.StringFlag{
+		Name:  "cpu",
+		Usage: "Set the maximum cpu the service can use e.g. 0.5 or 500m",
+	},
+	&cli.StringFlag{
+		Name:  "memory",
+		Usage: "Set the maximum memory the service can use e.g. 128Mi or 1Gi",
+	},
+	&cli.StringFlag{
+		Name:  "disk",
+		Usage: "Set the maximum disk space the service can use e.g. 1Gi",
+	},
 }
 
 func init() {
@@ -46,10 +58,36 @@ func init() {
 			micro run ../path/to/folder # deploy local folder to your local micro server
 			micro run helloworld # deploy latest version, translates to micro run github.com/micro/services/helloworld
 			micro run helloworld@9342934e6180 # deploy certain version
-			micro run helloworld@branchname	# deploy certain branch`,
-			Flags:  flags,
+			micro run helloworld@branchname	# deploy certain branch
+			micro run --replicas 3 helloworld # run 3 instances of helloworld
+			micro run --depends_on helloworld greeter # start greeter once helloworld is healthy`,
+			Flags: append([]cli.Flag{
+				&cli.IntFlag{
+					Name:  "replicas",
+					Usage: "Set the number of instances of the service to run",
+				},
+				&cli.StringSliceFlag{
+					Name:  "depends_on",
+					Usage: "Set the services which must be running and healthy before the service is started e.g. store,config",
+				},
+			}, flags...),
 			Action: runService,
 		},
+		&cli.Command{
+			Name:  "apply",
+			Usage: ApplyUsage,
+			Description: `Examples:
+			micro apply -f micro.yaml # create, update or delete services to match the manifest
+			cat micro.yaml | micro apply -f -`,
+			Flags:  manifestFlags,
+			Action: applyManifest,
+		},
+		&cli.Command{
+			Name:   "diff",
+			Usage:  DiffUsage,
+			Flags:  manifestFlags,
+			Action: diffManifest,
+		},
 		&cli.Command{
 			Name:  "update",
 			Usage: UpdateUsage,
@@ -62,6 +100,107 @@ func init() {
 			Flags:  flags,
 			Action: updateService,
 		},
+		&cli.Command{
+			Name:  "scale",
+			Usage: ScaleUsage,
+			Description: `Examples:
+			micro scale helloworld --replicas 3 # run 3 instances of helloworld
+			micro scale helloworld@v2 --min 1 --max 5 --target_rps 100 # scale with the request rate
+			micro scale helloworld --max 0 # disable autoscaling`,
+			Flags:  scaleFlags,
+			Action: scaleService,
+		},
+		&cli.Command{
+			Name:   "history",
+			Usage:  HistoryUsage,
+			Action: getHistory,
+		},
+		&cli.Command{
+			Name:  "rollback",
+			Usage: RollbackUsage,
+			Description: `Examples:
+			micro rollback helloworld # roll back to the previous revision
+			micro rollback helloworld --to 3 # roll back to revision 3 shown by micro history`,
+			Flags: []cli.Flag{
+				&cli.IntFlag{
+					Name:  "to",
+					Usage: "Set the revision to roll back to, defaults to the previous revision",
+				},
+			},
+			Action: rollbackService,
+		},
+		&cli.Command{
+			Name:  "builds",
+			Usage: BuildsUsage,
+			Subcommands: []*cli.Command{
+				{
+					Name:   "list",
+					Usage:  "List the builds of services: micro builds list [service]",
+					Action: listBuilds,
+				},
+				{
+					Name:  "prune",
+					Usage: "Delete the builds which aren't used by a service: micro builds prune",
+					Description: `Examples:
+			micro builds prune # delete every build not in use
+			micro builds prune --unused_for 168h # delete the builds not used in the last week`,
+					Flags: []cli.Flag{
+						&cli.DurationFlag{
+							Name:  "unused_for",
+							Usage: "Set how long a build must not have been used for to be deleted e.g. 24h",
+						},
+					},
+					Action: pruneBuilds,
+				},
+			},
+		},
+		&cli.Command{
+			Name:  "jobs",
+			Usage: JobsUsage,
+			Subcommands: []*cli.Command{
+				{
+					Name:  "create",
+					Usage: CreateJobUsage,
+					Description: `Examples:
+			micro jobs create github.com/micro/services/backup --schedule "0 2 * * *" # run every night at 2am
+			micro jobs create . --schedule "@every 15m" --concurrency forbid --timeout 10m
+			micro jobs create ./report # a job which only runs when triggered`,
+					Flags:  jobFlags,
+					Action: createJob,
+				},
+				{
+					Name:   "list",
+					Usage:  "List the jobs and their last run: micro jobs list",
+					Action: listJobs,
+				},
+				{
+					Name:   "history",
+					Usage:  "Show the runs of a job: micro jobs history [job]",
+					Action: getJobHistory,
+				},
+				{
+					Name:  "logs",
+					Usage: "Show the logs of a run of a job: micro jobs logs [job]",
+					Flags: []cli.Flag{
+						&cli.IntFlag{
+							Name:  "run",
+							Usage: "Set the run to show the logs of, defaults to the latest",
+						},
+					},
+					Action: getJobLogs,
+				},
+				{
+					Name:   "trigger",
+					Usage:  "Start a run of a job now: micro jobs trigger [job]",
+					Action: triggerJob,
+				},
+				{
+					Name:   "delete",
+					Usage:  "Delete a job and its runs: micro jobs delete [job]",
+					Action: deleteJob,
+				},
+			},
+		},
 		&cli.Command{
 			Name:  "kill",
 			Usage: KillUsage,
@@ -75,14 +214,24 @@ func init() {
 			Action: killService,
 		},
 		&cli.Command{
-			Name:   "status",
-			Usage:  GetUsage,
-			Flags:  flags,
+			Name:  "status",
+			Usage: GetUsage,
+			Description: `Examples:
+			micro status # list the status of every service
+			micro status helloworld # get the status of a service
+			micro status --watch # stream the status changes of services as they happen`,
+			Flags: append([]cli.Flag{
+				&cli.BoolFlag{
+					Name:    "watch",
+					Aliases: []string{"w"},
+					Usage:   "Stream the status changes of services as they happen",
+				},
+			}, flags...),
 			Action: getService,
 		},
 		&cli.Command{
 			Name:   "logs",
-			Usage:  "Get logs for a service",
+			Usage:  "Get logs for a service, e.g. micro logs --since 1h --level error helloworld",
 			Action: getLogs,
 			Flags: []cli.Flag{
 				&cli.StringFlag{
@@ -103,6 +252,14 @@ func init() {
 					Name:  "since",
 					Usage: "Set to the relative time from which to show the logs for e.g. 1h",
 				},
+				&cli.StringFlag{
+					Name:  "level",
+					Usage: "Set to only show logs at or above the level e.g. warn",
+				},
+				&cli.StringFlag{
+					Name:  "grep",
+					Usage: "Set to only show logs matching the regular expression e.g. \"timeout|refused\"",
+				},
 				&cli.IntFlag{
 					Name:    "lines",
 					Aliases: []string{"n"},
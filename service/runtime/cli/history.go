@@ -0,0 +1,119 @@
+package runtime
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/micro/cli/v2"
+	goruntime "github.com/micro/go-micro/v3/runtime"
+	"github.com/micro/micro/v3/client/cli/namespace"
+	"github.com/micro/micro/v3/client/cli/util"
+	"github.com/micro/micro/v3/service/runtime"
+	"github.com/micro/micro/v3/service/runtime/client"
+)
+
+const (
+	// HistoryUsage message for the history command
+	HistoryUsage = "Show the revisions of a service: micro history [service]"
+	// RollbackUsage message for the rollback command
+	RollbackUsage = "Roll a service back to a previous revision: micro rollback [service]"
+)
+
+// serviceFromArgs returns the service named by the first argument e.g. helloworld@v2
+func serviceFromArgs(ctx *cli.Context) *goruntime.Service {
+	name := ctx.Args().Get(0)
+	ref := "latest"
+	if parts := strings.Split(name, "@"); len(parts) > 1 {
+		name = parts[0]
+		ref = parts[1]
+	}
+	return &goruntime.Service{Name: name, Version: ref}
+}
+
+func getHistory(ctx *cli.Context) error {
+	if ctx.Args().Len() == 0 {
+		fmt.Println(HistoryUsage)
+		return nil
+	}
+
+	ns, err := namespace.Get(util.GetEnv(ctx).Name)
+	if err != nil {
+		return err
+	}
+
+	revisions, err := runtime.History(serviceFromArgs(ctx), ns)
+	if err != nil {
+		return err
+	}
+	if len(revisions) == 0 {
+		fmt.Println("No revisions found")
+		return nil
+	}
+
+	parse := func(m string) string {
+		if len(m) == 0 {
+			return "n/a"
+		}
+		return m
+	}
+
+	// the source is followed by the short form of the revision it was deployed from
+	source := func(rev *client.Revision) string {
+		if len(rev.Revision) == 0 {
+			return parse(rev.Source)
+		}
+		r := rev.Revision
+		if idx := strings.Index(r, ":"); idx >= 0 {
+			r = r[idx+1:]
+		}
+		if len(r) > 7 {
+			r = r[:7]
+		}
+		return fmt.Sprintf("%v (%v)", parse(rev.Source), r)
+	}
+
+	writer := tabwriter.NewWriter(os.Stdout, 0, 8, 1, '\t', tabwriter.AlignRight)
+	fmt.Fprintln(writer, "REVISION\tSOURCE\tIMAGE\tDEPLOYED BY\tDEPLOYED\tENV")
+	for i, rev := range revisions {
+		number := fmt.Sprintf("%d", rev.Number)
+		if i == len(revisions)-1 {
+			number += " (current)"
+		}
+
+		fmt.Fprintf(writer, "%s\t%s\t%s\t%s\t%s\t%s\n",
+			number,
+			source(rev),
+			parse(rev.Image),
+			parse(rev.DeployedBy),
+			parse(timeAgo(rev.Deployed.Format(time.RFC3339))),
+			parse(strings.Join(rev.Env, ",")))
+	}
+	writer.Flush()
+	return nil
+}
+
+func rollbackService(ctx *cli.Context) error {
+	if ctx.Args().Len() == 0 {
+		fmt.Println(RollbackUsage)
+		return nil
+	}
+
+	ns, err := namespace.Get(util.GetEnv(ctx).Name)
+	if err != nil {
+		return err
+	}
+
+	if err := runtime.Rollback(serviceFromArgs(ctx), ctx.Int("to"), ns); err != nil {
+		return err
+	}
+
+	if ctx.IsSet("to") {
+		fmt.Printf("Rolling back to revision %d, check the progress with micro status\n", ctx.Int("to"))
+	} else {
+		fmt.Println("Rolling back to the previous revision, check the progress with micro status")
+	}
+	return nil
+}
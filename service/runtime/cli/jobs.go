@@ -0,0 +1,238 @@
+package runtime
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/micro/cli/v2"
+	goruntime "github.com/micro/go-micro/v3/runtime"
+	"github.com/micro/micro/v3/client/cli/namespace"
+	"github.com/micro/micro/v3/client/cli/util"
+	"github.com/micro/micro/v3/service/runtime"
+	"github.com/micro/micro/v3/service/runtime/client"
+)
+
+const (
+	// JobsUsage message for the jobs command
+	JobsUsage = "Manage jobs which run on a schedule: micro jobs [command]"
+	// CreateJobUsage message for the jobs create command
+	CreateJobUsage = "Create or update a job: micro jobs create [source] --schedule \"0 2 * * *\""
+	// JobUsage message for the jobs commands which take the name of a job
+	JobUsage = "Required usage: micro jobs [command] [job]"
+)
+
+var jobFlags = append([]cli.Flag{
+	&cli.StringFlag{
+		Name:  "schedule",
+		Usage: "Set the cron schedule of the job e.g. \"0 2 * * *\" or \"@every 1h\", jobs without one only run when triggered",
+	},
+	&cli.StringFlag{
+		Name:  "concurrency",
+		Usage: "Set what to do when a run is due before the previous finished: allow, forbid or replace",
+		Value: client.ConcurrencyAllow,
+	},
+	&cli.DurationFlag{
+		Name:  "timeout",
+		Usage: "Set how long a run can take before it's stopped e.g. 30m, defaults to no limit",
+	},
+}, flags...)
+
+func createJob(ctx *cli.Context) error {
+	if ctx.Args().Len() == 0 {
+		fmt.Println(CreateJobUsage)
+		return nil
+	}
+
+	source, runtimeSource, err := sourceFromArgs(ctx)
+	if err != nil {
+		return err
+	}
+
+	opts, err := createOptions(ctx, source, runtimeSource)
+	if err != nil {
+		return err
+	}
+	var options goruntime.CreateOptions
+	for _, o := range opts {
+		o(&options)
+	}
+
+	job := &client.Job{
+		Name:              source.RuntimeName(),
+		Version:           source.Ref,
+		Source:            runtimeSource,
+		Schedule:          ctx.String("schedule"),
+		ConcurrencyPolicy: ctx.String("concurrency"),
+		Timeout:           ctx.Duration("timeout"),
+		Metadata:          make(map[string]string),
+		Options:           &options,
+	}
+
+	if err := runtime.CreateJob(job, options.Namespace); err != nil {
+		return err
+	}
+
+	if len(job.Schedule) > 0 {
+		fmt.Printf("Job %v will run on the schedule %q\n", job.Name, job.Schedule)
+	} else {
+		fmt.Printf("Job %v created, start a run with micro jobs trigger %v\n", job.Name, job.Name)
+	}
+	return nil
+}
+
+func listJobs(ctx *cli.Context) error {
+	ns, err := namespace.Get(util.GetEnv(ctx).Name)
+	if err != nil {
+		return err
+	}
+
+	jobs, err := runtime.ReadJobs(ctx.Args().Get(0), ns)
+	if err != nil {
+		return err
+	}
+	if len(jobs) == 0 {
+		fmt.Println("No jobs found")
+		return nil
+	}
+
+	parse := func(m string) string {
+		if len(m) == 0 {
+			return "n/a"
+		}
+		return m
+	}
+
+	writer := tabwriter.NewWriter(os.Stdout, 0, 8, 1, '\t', tabwriter.AlignRight)
+	fmt.Fprintln(writer, "NAME\tVERSION\tSCHEDULE\tCONCURRENCY\tLAST RUN\tSTATUS\tNEXT RUN")
+	for _, job := range jobs {
+		lastRun, status := "n/a", "n/a"
+		if r := job.LastRun; r != nil {
+			lastRun = timeAgo(r.Started.Format(time.RFC3339))
+			status = r.Status
+		}
+		next := "n/a"
+		if !job.Next.IsZero() {
+			next = job.Next.Local().Format(time.RFC3339)
+		}
+
+		fmt.Fprintf(writer, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+			job.Name,
+			parse(job.Version),
+			parse(job.Schedule),
+			job.ConcurrencyPolicy,
+			lastRun,
+			status,
+			next)
+	}
+	writer.Flush()
+	return nil
+}
+
+func getJobHistory(ctx *cli.Context) error {
+	if ctx.Args().Len() == 0 {
+		fmt.Println(JobUsage)
+		return nil
+	}
+
+	ns, err := namespace.Get(util.GetEnv(ctx).Name)
+	if err != nil {
+		return err
+	}
+
+	runs, err := runtime.JobRuns(ctx.Args().Get(0), ns)
+	if err != nil {
+		return err
+	}
+	if len(runs) == 0 {
+		fmt.Println("No runs found")
+		return nil
+	}
+
+	writer := tabwriter.NewWriter(os.Stdout, 0, 8, 1, '\t', tabwriter.AlignRight)
+	fmt.Fprintln(writer, "RUN\tSTATUS\tEXIT CODE\tTRIGGER\tSTARTED\tDURATION\tERROR")
+	for _, r := range runs {
+		exitCode, duration := "n/a", "n/a"
+		if r.Status != client.JobRunning {
+			if r.ExitCode >= 0 {
+				exitCode = fmt.Sprintf("%d", r.ExitCode)
+			}
+			duration = r.Finished.Sub(r.Started).Truncate(time.Second).String()
+		}
+		errMsg := r.Error
+		if len(errMsg) == 0 {
+			errMsg = "n/a"
+		}
+
+		fmt.Fprintf(writer, "%d\t%s\t%s\t%s\t%s\t%s\t%s\n",
+			r.Number,
+			r.Status,
+			exitCode,
+			r.Trigger,
+			timeAgo(r.Started.Format(time.RFC3339)),
+			duration,
+			errMsg)
+	}
+	writer.Flush()
+	return nil
+}
+
+func getJobLogs(ctx *cli.Context) error {
+	if ctx.Args().Len() == 0 {
+		fmt.Println(JobUsage)
+		return nil
+	}
+
+	ns, err := namespace.Get(util.GetEnv(ctx).Name)
+	if err != nil {
+		return err
+	}
+
+	logs, err := runtime.JobLogs(ctx.Args().Get(0), ctx.Int("run"), ns)
+	if err != nil {
+		return err
+	}
+	for _, l := range logs {
+		fmt.Println(l.Message)
+	}
+	return nil
+}
+
+func triggerJob(ctx *cli.Context) error {
+	if ctx.Args().Len() == 0 {
+		fmt.Println(JobUsage)
+		return nil
+	}
+
+	ns, err := namespace.Get(util.GetEnv(ctx).Name)
+	if err != nil {
+		return err
+	}
+
+	name := ctx.Args().Get(0)
+	run, err := runtime.TriggerJob(name, ns)
+	if err != nil {
+		return err
+	}
+
+	if run.Status == client.JobFailed {
+		return fmt.Errorf("run %d of job %v failed to start: %v", run.Number, name, run.Error)
+	}
+	fmt.Printf("Started run %d of job %v, view the logs with micro jobs logs %v\n", run.Number, name, name)
+	return nil
+}
+
+func deleteJob(ctx *cli.Context) error {
+	if ctx.Args().Len() == 0 {
+		fmt.Println(JobUsage)
+		return nil
+	}
+
+	ns, err := namespace.Get(util.GetEnv(ctx).Name)
+	if err != nil {
+		return err
+	}
+
+	return runtime.DeleteJob(ctx.Args().Get(0), ns)
+}
@@ -0,0 +1,269 @@
+package runtime
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/ghodss/yaml"
+	goruntime "github.com/micro/go-micro/v3/runtime"
+)
+
+const (
+	// manifestKey is the metadata key of services deployed from a manifest, its value
+	// is the hash of the spec so changes to the manifest can be detected
+	manifestKey = "manifest"
+)
+
+// Manifest declares the services which should be running in a namespace, e.g.
+//
+//	services:
+//	  helloworld:
+//	    source: github.com/micro/services/helloworld
+//	    version: latest
+//	    env:
+//	      GREETING: hello
+//	    secrets:
+//	      API_KEY: ${HELLOWORLD_API_KEY}
+//	    depends_on:
+//	      - store
+type Manifest struct {
+	Services map[string]*ServiceSpec `json:"services"`
+}
+
+// ServiceSpec is the desired state of a service
+type ServiceSpec struct {
+	// Name of the service, defaults to its key in the manifest
+	Name      string            `json:"name,omitempty"`
+	Source    string            `json:"source"`
+	Version   string            `json:"version,omitempty"`
+	Image     string            `json:"image,omitempty"`
+	Command   string            `json:"command,omitempty"`
+	Args      string            `json:"args,omitempty"`
+	Type      string            `json:"type,omitempty"`
+	Retries   *int              `json:"retries,omitempty"`
+	Env       map[string]string `json:"env,omitempty"`
+	Secrets   map[string]string `json:"secrets,omitempty"`
+	DependsOn []string          `json:"depends_on,omitempty"`
+	// CPU, Memory and Disk limit the resources of the service e.g. 500m, 128Mi and 1Gi
+	CPU    string `json:"cpu,omitempty"`
+	Memory string `json:"memory,omitempty"`
+	Disk   string `json:"disk,omitempty"`
+	// Replicas is the number of instances of the service to run
+	Replicas int `json:"replicas,omitempty"`
+}
+
+// hash of the spec, set in the service metadata when it's deployed
+func (s *ServiceSpec) hash() string {
+	b, _ := json.Marshal(s)
+	return fmt.Sprintf("%x", sha256.Sum256(b))[:16]
+}
+
+// loadManifest reads and validates the manifest at the path, "-" reads from stdin.
+// Environment variables such as ${API_KEY} are expanded so secrets don't need
+// to be checked in.
+func loadManifest(path string) (*Manifest, error) {
+	var b []byte
+	var err error
+	if path == "-" {
+		b, err = ioutil.ReadAll(os.Stdin)
+	} else {
+		b, err = ioutil.ReadFile(path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("Error reading manifest: %v", err)
+	}
+
+	return parseManifest([]byte(os.ExpandEnv(string(b))))
+}
+
+// parseManifest parses and validates a manifest
+func parseManifest(b []byte) (*Manifest, error) {
+	var m *Manifest
+	if err := yaml.Unmarshal(b, &m); err != nil {
+		return nil, fmt.Errorf("Error parsing manifest: %v", err)
+	}
+	if m == nil || len(m.Services) == 0 {
+		return nil, fmt.Errorf("Manifest doesn't declare any services")
+	}
+
+	for key, spec := range m.Services {
+		if spec == nil {
+			return nil, fmt.Errorf("Service %s has no spec", key)
+		}
+		if len(spec.Name) == 0 {
+			spec.Name = key
+		}
+		if len(spec.Source) == 0 {
+			return nil, fmt.Errorf("Service %s has no source", key)
+		}
+		// the version can be set on the source as with micro run
+		if parts := strings.Split(spec.Source, "@"); len(parts) == 2 {
+			if len(spec.Version) > 0 && spec.Version != parts[1] {
+				return nil, fmt.Errorf("Service %s has version %s and source %s", key, spec.Version, spec.Source)
+			}
+			spec.Source = parts[0]
+			spec.Version = parts[1]
+		}
+		if len(spec.Version) == 0 {
+			spec.Version = "latest"
+		}
+		if _, err := parseResources(spec.CPU, spec.Memory, spec.Disk); err != nil {
+			return nil, fmt.Errorf("Service %s: %v", key, err)
+		}
+		if spec.Replicas < 0 {
+			return nil, fmt.Errorf("Service %s has %d replicas", key, spec.Replicas)
+		}
+	}
+
+	return m, nil
+}
+
+// Action is a change to converge the runtime with a manifest
+type Action string
+
+const (
+	// ActionCreate creates a service declared in the manifest
+	ActionCreate Action = "create"
+	// ActionUpdate recreates a service whose spec has changed
+	ActionUpdate Action = "update"
+	// ActionDelete deletes a service deployed from a manifest which is no longer declared
+	ActionDelete Action = "delete"
+	// ActionNone leaves a service which is up to date
+	ActionNone Action = "unchanged"
+)
+
+// Step of a plan
+type Step struct {
+	Action  Action
+	Name    string
+	Version string
+	// Spec is nil for deletes
+	Spec *ServiceSpec
+}
+
+// plan returns the steps to converge the running services with the manifest. Deletes come
+// first, then the services are created or updated so they start after their dependencies.
+// Services which weren't deployed from a manifest are never deleted.
+func plan(m *Manifest, running []*goruntime.Service) ([]*Step, error) {
+	current := make(map[string]*goruntime.Service, len(running))
+	for _, srv := range running {
+		current[srv.Name+"@"+srv.Version] = srv
+	}
+
+	// index the specs by name, a service can only be declared once
+	specs := make(map[string]*ServiceSpec, len(m.Services))
+	for _, spec := range m.Services {
+		if _, ok := specs[spec.Name]; ok {
+			return nil, fmt.Errorf("Service %s is declared more than once", spec.Name)
+		}
+		specs[spec.Name] = spec
+	}
+
+	order, err := dependencyOrder(specs, current)
+	if err != nil {
+		return nil, err
+	}
+
+	var steps []*Step
+
+	// delete services which were deployed from a manifest but no longer are declared,
+	// including the previous versions of services whose version changed
+	var deletes []*Step
+	for key, srv := range current {
+		if _, ok := srv.Metadata[manifestKey]; !ok {
+			continue
+		}
+		if spec, ok := specs[srv.Name]; ok && spec.Version == srv.Version {
+			continue
+		}
+		deletes = append(deletes, &Step{Action: ActionDelete, Name: srv.Name, Version: srv.Version})
+		delete(current, key)
+	}
+	sort.Slice(deletes, func(i, j int) bool {
+		return deletes[i].Name+"@"+deletes[i].Version < deletes[j].Name+"@"+deletes[j].Version
+	})
+	steps = append(steps, deletes...)
+
+	for _, name := range order {
+		spec := specs[name]
+		step := &Step{Name: spec.Name, Version: spec.Version, Spec: spec}
+
+		srv, ok := current[spec.Name+"@"+spec.Version]
+		switch {
+		case !ok:
+			step.Action = ActionCreate
+		case srv.Metadata[manifestKey] != spec.hash():
+			step.Action = ActionUpdate
+		default:
+			step.Action = ActionNone
+		}
+
+		steps = append(steps, step)
+	}
+
+	return steps, nil
+}
+
+// dependencyOrder sorts the services so each comes after the services it depends on. Dependencies
+// must be declared in the manifest or already be running.
+func dependencyOrder(specs map[string]*ServiceSpec, current map[string]*goruntime.Service) ([]string, error) {
+	runningNames := make(map[string]bool, len(current))
+	for _, srv := range current {
+		runningNames[srv.Name] = true
+	}
+
+	names := make([]string, 0, len(specs))
+	for name := range specs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	const (
+		visiting = 1
+		visited  = 2
+	)
+	state := make(map[string]int, len(specs))
+	order := make([]string, 0, len(specs))
+
+	var visit func(name string, path []string) error
+	visit = func(name string, path []string) error {
+		switch state[name] {
+		case visited:
+			return nil
+		case visiting:
+			return fmt.Errorf("Dependency cycle: %s", strings.Join(append(path, name), " -> "))
+		}
+		state[name] = visiting
+
+		deps := append([]string{}, specs[name].DependsOn...)
+		sort.Strings(deps)
+		for _, dep := range deps {
+			if _, ok := specs[dep]; !ok {
+				if runningNames[dep] {
+					continue
+				}
+				return fmt.Errorf("Service %s depends on %s which isn't declared or running", name, dep)
+			}
+			if err := visit(dep, append(path, name)); err != nil {
+				return err
+			}
+		}
+
+		state[name] = visited
+		order = append(order, name)
+		return nil
+	}
+
+	for _, name := range names {
+		if err := visit(name, nil); err != nil {
+			return nil, err
+		}
+	}
+
+	return order, nil
+}
@@ -0,0 +1,131 @@
+package runtime
+
+import (
+	"testing"
+
+	goruntime "github.com/micro/go-micro/v3/runtime"
+)
+
+const testManifest = `
+services:
+  api:
+    source: github.com/micro/services/api
+    depends_on:
+      - users
+      - store
+  users:
+    source: github.com/micro/services/users@v2
+    env:
+      FOO: bar
+  legacy:
+    source: github.com/micro/services/legacy
+`
+
+func TestPlan(t *testing.T) {
+	m, err := parseManifest([]byte(testManifest))
+	if err != nil {
+		t.Fatalf("Unexpected error parsing the manifest: %v", err)
+	}
+	if v := m.Services["users"].Version; v != "v2" {
+		t.Fatalf("Expected the version to be parsed from the source, got %q", v)
+	}
+
+	running := []*goruntime.Service{
+		// not deployed from a manifest so never deleted
+		{Name: "store", Version: "latest", Metadata: map[string]string{}},
+		// the previous version of users
+		{Name: "users", Version: "v1", Metadata: map[string]string{manifestKey: "abc"}},
+		// up to date
+		{Name: "legacy", Version: "latest", Metadata: map[string]string{manifestKey: m.Services["legacy"].hash()}},
+		// no longer declared
+		{Name: "old", Version: "latest", Metadata: map[string]string{manifestKey: "abc"}},
+	}
+
+	steps, err := plan(m, running)
+	if err != nil {
+		t.Fatalf("Unexpected error planning: %v", err)
+	}
+
+	expected := []struct {
+		action Action
+		name   string
+		ver    string
+	}{
+		{ActionDelete, "old", "latest"},
+		{ActionDelete, "users", "v1"},
+		{ActionCreate, "users", "v2"},
+		{ActionCreate, "api", "latest"},
+		{ActionNone, "legacy", "latest"},
+	}
+	if len(steps) != len(expected) {
+		t.Fatalf("Expected %d steps, got %d", len(expected), len(steps))
+	}
+
+	// the services must be created after their dependencies
+	created := make(map[string]int)
+	for i, s := range steps {
+		created[s.Name+"@"+s.Version+string(s.Action)] = i
+	}
+	for _, e := range expected {
+		if _, ok := created[e.name+"@"+e.ver+string(e.action)]; !ok {
+			t.Fatalf("Expected to %s %s@%s", e.action, e.name, e.ver)
+		}
+	}
+	if created["users@v2create"] > created["api@latestcreate"] {
+		t.Fatalf("Expected users to be created before api")
+	}
+
+	// a changed spec is updated
+	m.Services["legacy"].Env = map[string]string{"FOO": "baz"}
+	steps, _ = plan(m, running)
+	for _, s := range steps {
+		if s.Name == "legacy" && s.Action != ActionUpdate {
+			t.Fatalf("Expected legacy to be updated, got %s", s.Action)
+		}
+	}
+}
+
+func TestPlanDependencies(t *testing.T) {
+	m, _ := parseManifest([]byte(`
+services:
+  a:
+    source: github.com/micro/services/a
+    depends_on: [b]
+  b:
+    source: github.com/micro/services/b
+    depends_on: [a]
+`))
+	if _, err := plan(m, nil); err == nil {
+		t.Fatalf("Expected an error for a dependency cycle")
+	}
+
+	m, _ = parseManifest([]byte(`
+services:
+  a:
+    source: github.com/micro/services/a
+    depends_on: [missing]
+`))
+	if _, err := plan(m, nil); err == nil {
+		t.Fatalf("Expected an error for a missing dependency")
+	}
+}
+
+func TestParseResources(t *testing.T) {
+	r, err := parseResources("0.25", "1Gi", "512")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if r.CPU != 250 || r.Mem != 1024 || r.Disk != 512 {
+		t.Fatalf("Unexpected resources %+v", r)
+	}
+
+	if r, _ := parseResources("500m", "", ""); r.CPU != 500 {
+		t.Fatalf("Expected 500 millicpu, got %d", r.CPU)
+	}
+	if r, _ := parseResources("", "", ""); r != nil {
+		t.Fatalf("Expected no resources, got %+v", r)
+	}
+	if _, err := parseResources("", "lots", ""); err == nil {
+		t.Fatalf("Expected an error for an invalid size")
+	}
+}
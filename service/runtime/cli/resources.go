@@ -0,0 +1,76 @@
+package runtime
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/micro/cli/v2"
+	goruntime "github.com/micro/go-micro/v3/runtime"
+)
+
+// parseCPU parses a cpu quantity such as 0.5 or 500m into millicpu
+func parseCPU(v string) (int, error) {
+	if strings.HasSuffix(v, "m") {
+		m, err := strconv.Atoi(strings.TrimSuffix(v, "m"))
+		if err != nil || m < 0 {
+			return 0, fmt.Errorf("Invalid cpu %q, must be e.g. 0.5 or 500m", v)
+		}
+		return m, nil
+	}
+
+	f, err := strconv.ParseFloat(v, 64)
+	if err != nil || f < 0 {
+		return 0, fmt.Errorf("Invalid cpu %q, must be e.g. 0.5 or 500m", v)
+	}
+	return int(f * 1000), nil
+}
+
+// parseSize parses a size such as 128Mi or 1Gi into mebibytes, a size without units is in mebibytes
+func parseSize(v string) (int, error) {
+	num, multiplier := v, 1
+	switch {
+	case strings.HasSuffix(v, "Gi"):
+		num, multiplier = strings.TrimSuffix(v, "Gi"), 1024
+	case strings.HasSuffix(v, "Mi"):
+		num = strings.TrimSuffix(v, "Mi")
+	}
+
+	n, err := strconv.Atoi(num)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("Invalid size %q, must be e.g. 128Mi or 1Gi", v)
+	}
+	return n * multiplier, nil
+}
+
+// parseResources returns the resources set, nil if none are
+func parseResources(cpu, mem, disk string) (*goruntime.Resources, error) {
+	if len(cpu) == 0 && len(mem) == 0 && len(disk) == 0 {
+		return nil, nil
+	}
+
+	var r goruntime.Resources
+	var err error
+	if len(cpu) > 0 {
+		if r.CPU, err = parseCPU(cpu); err != nil {
+			return nil, err
+		}
+	}
+	if len(mem) > 0 {
+		if r.Mem, err = parseSize(mem); err != nil {
+			return nil, err
+		}
+	}
+	if len(disk) > 0 {
+		if r.Disk, err = parseSize(disk); err != nil {
+			return nil, err
+		}
+	}
+
+	return &r, nil
+}
+
+// resourcesFromFlags returns the resources set using the cpu, memory and disk flags
+func resourcesFromFlags(ctx *cli.Context) (*goruntime.Resources, error) {
+	return parseResources(ctx.String("cpu"), ctx.String("memory"), ctx.String("disk"))
+}
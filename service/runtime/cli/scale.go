@@ -0,0 +1,84 @@
+package runtime
+
+import (
+	"fmt"
+
+	"github.com/micro/cli/v2"
+	goruntime "github.com/micro/go-micro/v3/runtime"
+	"github.com/micro/micro/v3/client/cli/namespace"
+	"github.com/micro/micro/v3/client/cli/util"
+	"github.com/micro/micro/v3/service/runtime"
+	"github.com/micro/micro/v3/service/runtime/client"
+)
+
+var scaleFlags = []cli.Flag{
+	&cli.IntFlag{
+		Name:  "replicas",
+		Usage: "Set the number of instances of the service to run, disables autoscaling",
+	},
+	&cli.IntFlag{
+		Name:  "min",
+		Usage: "Set the minimum number of instances when autoscaling",
+		Value: 1,
+	},
+	&cli.IntFlag{
+		Name:  "max",
+		Usage: "Set the maximum number of instances when autoscaling, 0 disables autoscaling",
+	},
+	&cli.Float64Flag{
+		Name:  "target_rps",
+		Usage: "Set the requests per second each instance should serve when autoscaling",
+	},
+}
+
+// scaleOption returns the update option for the scale flags
+func scaleOption(ctx *cli.Context) (goruntime.UpdateOption, error) {
+	switch {
+	case ctx.IsSet("replicas") && ctx.IsSet("max"):
+		return nil, fmt.Errorf("Set either --replicas or --max, not both")
+	case ctx.IsSet("replicas"):
+		if ctx.Int("replicas") < 1 {
+			return nil, fmt.Errorf("The number of replicas must be at least 1")
+		}
+		return client.UpdateReplicas(ctx.Int("replicas")), nil
+	case ctx.IsSet("max"):
+		a := &client.Autoscale{
+			Min:       ctx.Int("min"),
+			Max:       ctx.Int("max"),
+			TargetRPS: ctx.Float64("target_rps"),
+		}
+		// a max of zero disables autoscaling
+		if a.Max > 0 {
+			if a.Min < 1 || a.Max < a.Min {
+				return nil, fmt.Errorf("The replicas must be between --min and --max, and --min must be at least 1")
+			}
+			if a.TargetRPS <= 0 {
+				return nil, fmt.Errorf("--target_rps must be greater than zero when autoscaling")
+			}
+		}
+		return client.UpdateAutoscale(a), nil
+	default:
+		return nil, fmt.Errorf("Set --replicas or --max to scale the service")
+	}
+}
+
+func scaleService(ctx *cli.Context) error {
+	// we need the service to scale
+	if ctx.Args().Len() == 0 {
+		fmt.Println(ScaleUsage)
+		return nil
+	}
+
+	opt, err := scaleOption(ctx)
+	if err != nil {
+		return err
+	}
+
+	// determine the namespace
+	ns, err := namespace.Get(util.GetEnv(ctx).Name)
+	if err != nil {
+		return err
+	}
+
+	return runtime.Update(serviceFromArgs(ctx), goruntime.UpdateNamespace(ns), opt)
+}
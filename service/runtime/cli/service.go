@@ -11,6 +11,7 @@ import (
 	"path"
 	"path/filepath"
 	"sort"
+	"strconv"
 	"strings"
 	"text/tabwriter"
 	"time"
@@ -28,6 +29,7 @@ import (
 	"github.com/micro/micro/v3/service/context"
 	"github.com/micro/micro/v3/service/logger"
 	"github.com/micro/micro/v3/service/runtime"
+	"github.com/micro/micro/v3/service/runtime/client"
 	"github.com/micro/micro/v3/service/runtime/server"
 	"google.golang.org/grpc/status"
 )
@@ -39,6 +41,8 @@ const (
 	KillUsage = "Kill a service: micro kill [source]"
 	// UpdateUsage message for the update command
 	UpdateUsage = "Update a service: micro update [source]"
+	// ScaleUsage message for the scale command
+	ScaleUsage = "Scale a service: micro scale [service] --replicas 3"
 	// GetUsage message for micro get command
 	GetUsage = "Get the status of services"
 	// ServicesUsage message for micro services command
@@ -149,15 +153,60 @@ func runService(ctx *cli.Context) error {
 		return nil
 	}
 
-	wd, err := os.Getwd()
+	source, runtimeSource, err := sourceFromArgs(ctx)
 	if err != nil {
 		return err
 	}
 
-	source, err := git.ParseSourceLocal(wd, appendSourceBase(ctx, wd, ctx.Args().Get(0)))
+	opts, err := createOptions(ctx, source, runtimeSource)
 	if err != nil {
 		return err
 	}
+	opts = append(opts, goruntime.WithOutput(os.Stdout))
+
+	if replicas := ctx.Int("replicas"); replicas > 1 {
+		opts = append(opts, client.CreateReplicas(replicas))
+	}
+	if deps := ctx.StringSlice("depends_on"); len(deps) > 0 {
+		opts = append(opts, client.CreateDependsOn(deps...))
+	}
+
+	// run the service
+	service := &goruntime.Service{
+		Name:     source.RuntimeName(),
+		Source:   runtimeSource,
+		Version:  source.Ref,
+		Metadata: make(map[string]string),
+	}
+
+	if err := runtime.Create(service, opts...); err != nil {
+		return err
+	}
+
+	if runtime.DefaultRuntime.String() == "local" {
+		// we need to wait
+		ch := make(chan os.Signal, 1)
+		signal.Notify(ch, os.Interrupt)
+		<-ch
+		// delete the service
+		return runtime.Delete(service)
+	}
+
+	return nil
+}
+
+// sourceFromArgs parses the source in the first argument, local sources are uploaded to the
+// runtime. The source the runtime should use is returned along with the parsed source.
+func sourceFromArgs(ctx *cli.Context) (*git.Source, string, error) {
+	wd, err := os.Getwd()
+	if err != nil {
+		return nil, "", err
+	}
+
+	source, err := git.ParseSourceLocal(wd, appendSourceBase(ctx, wd, ctx.Args().Get(0)))
+	if err != nil {
+		return nil, "", err
+	}
 	var newSource string
 	if source.Local {
 		if cliutil.IsPlatform(ctx) {
@@ -166,24 +215,29 @@ func runService(ctx *cli.Context) error {
 		}
 		newSource, err = upload(ctx, source)
 		if err != nil {
-			return err
+			return nil, "", err
 		}
 	} else {
 		err := sourceExists(source)
 		if err != nil {
-			return err
+			return nil, "", err
 		}
 	}
 
-	typ := ctx.String("type")
-	command := strings.TrimSpace(ctx.String("command"))
-	args := strings.TrimSpace(ctx.String("args"))
-
 	runtimeSource := source.RuntimeSource()
 	if source.Local {
 		runtimeSource = newSource
 	}
 
+	return source, runtimeSource, nil
+}
+
+// createOptions returns the options to create the source with from the flags
+func createOptions(ctx *cli.Context, source *git.Source, runtimeSource string) ([]goruntime.CreateOption, error) {
+	typ := ctx.String("type")
+	command := strings.TrimSpace(ctx.String("command"))
+	args := strings.TrimSpace(ctx.String("args"))
+
 	var retries = DefaultRetries
 	if ctx.IsSet("retries") {
 		retries = ctx.Int("retries")
@@ -202,7 +256,6 @@ func runService(ctx *cli.Context) error {
 
 	// specify the options
 	opts := []goruntime.CreateOption{
-		goruntime.WithOutput(os.Stdout),
 		goruntime.WithRetries(retries),
 		goruntime.CreateImage(image),
 		goruntime.CreateType(typ),
@@ -230,10 +283,18 @@ func runService(ctx *cli.Context) error {
 		opts = append(opts, goruntime.WithArgs(strings.Split(args, " ")...))
 	}
 
+	resources, err := resourcesFromFlags(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if resources != nil {
+		opts = append(opts, goruntime.ResourceLimits(resources))
+	}
+
 	// determine the namespace
 	ns, err := namespace.Get(util.GetEnv(ctx).Name)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	opts = append(opts, goruntime.CreateNamespace(ns))
 	gitCreds, ok := getGitCredentials(source.Repo)
@@ -241,28 +302,7 @@ func runService(ctx *cli.Context) error {
 		opts = append(opts, goruntime.WithSecret(credentialsKey, gitCreds))
 	}
 
-	// run the service
-	service := &goruntime.Service{
-		Name:     source.RuntimeName(),
-		Source:   runtimeSource,
-		Version:  source.Ref,
-		Metadata: make(map[string]string),
-	}
-
-	if err := runtime.Create(service, opts...); err != nil {
-		return err
-	}
-
-	if runtime.DefaultRuntime.String() == "local" {
-		// we need to wait
-		ch := make(chan os.Signal, 1)
-		signal.Notify(ch, os.Interrupt)
-		<-ch
-		// delete the service
-		return runtime.Delete(service)
-	}
-
-	return nil
+	return opts, nil
 }
 
 func getGitCredentials(repo string) (string, bool) {
@@ -429,7 +469,16 @@ func updateService(ctx *cli.Context) error {
 	if ok {
 		opts = append(opts, goruntime.UpdateSecret(credentialsKey, gitCreds))
 	}
-	return runtime.Update(service, goruntime.UpdateNamespace(ns))
+
+	resources, err := resourcesFromFlags(ctx)
+	if err != nil {
+		return err
+	}
+	if resources != nil {
+		opts = append(opts, client.UpdateResources(resources))
+	}
+
+	return runtime.Update(service, opts...)
 }
 
 func getService(ctx *cli.Context) error {
@@ -513,7 +562,8 @@ func getService(ctx *cli.Context) error {
 	}
 
 	// don't do anything if there's no services
-	if len(services) == 0 {
+	watch := ctx.Bool("watch")
+	if len(services) == 0 && !watch {
 		return nil
 	}
 
@@ -532,8 +582,26 @@ func getService(ctx *cli.Context) error {
 
 		// if there is an error, display this in metadata (there is no error field)
 		metadata := fmt.Sprintf("owner=%s, group=%s", parse(service.Metadata["owner"]), parse(service.Metadata["group"]))
+		if res := service.Metadata["resources"]; len(res) > 0 {
+			metadata = fmt.Sprintf("%v, %v", metadata, res)
+		}
+		replicas, _ := strconv.Atoi(service.Metadata["replicas"])
+		if replicas > 1 {
+			metadata = fmt.Sprintf("%v, replicas=%d", metadata, replicas)
+		}
+		if as := service.Metadata["autoscale"]; len(as) > 0 {
+			metadata = fmt.Sprintf("%v, autoscale=(%v)", metadata, as)
+		}
+		if r := service.Metadata["rollout"]; len(r) > 0 {
+			metadata = fmt.Sprintf("%v, rollout=%v", metadata, r)
+		}
+		if deps := service.Metadata["depends_on"]; len(deps) > 0 {
+			metadata = fmt.Sprintf("%v, depends_on=(%v)", metadata, deps)
+		}
 		if status == "error" {
 			metadata = fmt.Sprintf("%v, error=%v", metadata, parse(service.Metadata["error"]))
+		} else if status == "waiting" {
+			metadata = fmt.Sprintf("%v, %v", metadata, parse(service.Metadata["error"]))
 		}
 
 		// parse when the service was started
@@ -547,8 +615,50 @@ func getService(ctx *cli.Context) error {
 			build,
 			updated,
 			metadata)
+
+		// the additional replicas of the service, e.g. helloworld[1]
+		for i := 1; i < replicas; i++ {
+			fmt.Fprintf(writer, "%s[%d]\t%s\t%s\t%s\t%s\t%s\t%s\n",
+				service.Name,
+				i,
+				parse(service.Version),
+				parse(service.Source),
+				strings.ToLower(parse(service.Metadata[fmt.Sprintf("replica.%d", i)])),
+				"", "", "")
+		}
 	}
 	writer.Flush()
+
+	if watch {
+		return watchStatus(name, version, ns)
+	}
+	return nil
+}
+
+// watchStatus prints the status changes of the services until the stream ends, if a name is
+// provided only the changes of that version of the service are printed
+func watchStatus(name, version, ns string) error {
+	evChan, err := runtime.Watch(name, ns)
+	if err != nil {
+		return err
+	}
+
+	for ev := range evChan {
+		if len(name) > 0 && ev.Service.Version != version {
+			continue
+		}
+
+		srv := ev.Service.Name
+		if ev.Replica > 0 {
+			srv = fmt.Sprintf("%s[%d]", srv, ev.Replica)
+		}
+		line := fmt.Sprintf("%s %s %s %s", ev.Updated.Format(time.RFC3339), srv, ev.Service.Version, strings.ToLower(ev.Status))
+		if len(ev.Error) > 0 {
+			line = fmt.Sprintf("%s error=%s", line, ev.Error)
+		}
+		fmt.Println(line)
+	}
+
 	return nil
 }
 
@@ -588,13 +698,23 @@ func getLogs(ctx *cli.Context) error {
 		options = append(options, goruntime.LogsStream(follow))
 	}
 
-	// @todo reintroduce since
-	//since := ctx.String("since")
-	//var readSince time.Time
-	//d, err := time.ParseDuration(since)
-	//if err == nil {
-	//	readSince = time.Now().Add(-d)
-	//}
+	// filter the logs, these are read from the logs the runtime persisted
+	if since := ctx.String("since"); len(since) > 0 {
+		d, err := time.ParseDuration(since)
+		if err != nil {
+			return fmt.Errorf("invalid since %v: %v", since, err)
+		}
+		options = append(options, client.LogsSince(time.Now().Add(-d)))
+	}
+	if level := ctx.String("level"); len(level) > 0 {
+		options = append(options, client.LogsLevel(level))
+	}
+	if grep := ctx.String("grep"); len(grep) > 0 {
+		options = append(options, client.LogsGrep(grep))
+	}
+	if version := ctx.String("version"); len(version) > 0 {
+		options = append(options, client.LogsVersion(version))
+	}
 
 	// determine the namespace
 	ns, err := namespace.Get(util.GetEnv(ctx).Name)
@@ -0,0 +1,94 @@
+package client
+
+import (
+	"errors"
+	"time"
+
+	goclient "github.com/micro/go-micro/v3/client"
+	"github.com/micro/micro/v3/service/context"
+	pb "github.com/micro/micro/v3/service/runtime/proto"
+)
+
+// ErrBuildsNotSupported is returned by runtimes which don't build the sources of services
+var ErrBuildsNotSupported = errors.New("runtime doesn't support builds")
+
+// Build is an artifact built from the source of a service, it's reused by the replicas of the
+// service and when the service is restarted or updated to the same source
+type Build struct {
+	// ID of the build, derived from the source, ref and hash of the code
+	ID      string `json:"id"`
+	Name    string `json:"name"`
+	Version string `json:"version"`
+	Source  string `json:"source"`
+	// SumHash is the sha256 hash of the go.sum of the source
+	SumHash string `json:"sum_hash"`
+	// Size of the artifact in bytes
+	Size     int64     `json:"size"`
+	Created  time.Time `json:"created"`
+	LastUsed time.Time `json:"last_used"`
+	// InUse is true if the build is the latest of a service which exists, set when read
+	InUse bool `json:"-"`
+}
+
+// Builder is implemented by runtimes which build the sources of services
+type Builder interface {
+	// ReadBuilds returns the builds in the namespace, or the builds of the service provided
+	ReadBuilds(name, namespace string) ([]*Build, error)
+	// PruneBuilds deletes the builds which aren't in use and haven't been used for the duration,
+	// the builds deleted are returned
+	PruneBuilds(unusedFor time.Duration, namespace string) ([]*Build, error)
+}
+
+// ReadBuilds returns the builds in the namespace, or the builds of the service provided
+func (s *svc) ReadBuilds(name, namespace string) ([]*Build, error) {
+	req := &pb.ReadBuildsRequest{
+		Name:    name,
+		Options: &pb.BuildOptions{Namespace: namespace},
+	}
+
+	rsp, err := s.runtime.ReadBuilds(context.DefaultContext, req, goclient.WithAuthToken())
+	if err != nil {
+		return nil, err
+	}
+
+	builds := make([]*Build, 0, len(rsp.Builds))
+	for _, b := range rsp.Builds {
+		builds = append(builds, toBuild(b))
+	}
+
+	return builds, nil
+}
+
+// PruneBuilds deletes the builds which aren't in use and haven't been used for the duration
+func (s *svc) PruneBuilds(unusedFor time.Duration, namespace string) ([]*Build, error) {
+	req := &pb.PruneBuildsRequest{
+		UnusedFor: int64(unusedFor.Seconds()),
+		Options:   &pb.BuildOptions{Namespace: namespace},
+	}
+
+	rsp, err := s.runtime.PruneBuilds(context.DefaultContext, req, goclient.WithAuthToken())
+	if err != nil {
+		return nil, err
+	}
+
+	builds := make([]*Build, 0, len(rsp.Builds))
+	for _, b := range rsp.Builds {
+		builds = append(builds, toBuild(b))
+	}
+
+	return builds, nil
+}
+
+func toBuild(b *pb.Build) *Build {
+	return &Build{
+		ID:       b.Id,
+		Name:     b.Name,
+		Version:  b.Version,
+		Source:   b.Source,
+		SumHash:  b.SumHash,
+		Size:     b.Size,
+		Created:  time.Unix(b.Created, 0),
+		LastUsed: time.Unix(b.LastUsed, 0),
+		InUse:    b.InUse,
+	}
+}
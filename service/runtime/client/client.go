@@ -3,6 +3,7 @@ package client
 import (
 	"io"
 	"sync"
+	"time"
 
 	goclient "github.com/micro/go-micro/v3/client"
 	"github.com/micro/go-micro/v3/runtime"
@@ -50,6 +51,7 @@ func (s *svc) Create(svc *runtime.Service, opts ...runtime.CreateOption) error {
 			Version:  svc.Version,
 			Source:   svc.Source,
 			Metadata: svc.Metadata,
+			Replicas: int64(ReplicasFromCreateOptions(options)),
 		},
 		Options: &pb.CreateOptions{
 			Command:   options.Command,
@@ -59,6 +61,8 @@ func (s *svc) Create(svc *runtime.Service, opts ...runtime.CreateOption) error {
 			Image:     options.Image,
 			Namespace: options.Namespace,
 			Secrets:   options.Secrets,
+			Resources: toProtoResources(options.Resources),
+			DependsOn: DependsOnFromCreateOptions(options),
 		},
 	}
 
@@ -75,14 +79,24 @@ func (s *svc) Logs(service *runtime.Service, opts ...runtime.LogsOption) (runtim
 		o(&options)
 	}
 
-	ls, err := s.runtime.Logs(context.DefaultContext, &pb.LogsRequest{
+	req := &pb.LogsRequest{
 		Service: service.Name,
 		Stream:  options.Stream,
 		Count:   options.Count,
 		Options: &pb.LogsOptions{
 			Namespace: options.Namespace,
 		},
-	}, goclient.WithAuthToken())
+	}
+	if q := LogsQueryFromOptions(options); q != nil {
+		if !q.Since.IsZero() {
+			req.Since = int64(time.Since(q.Since).Seconds())
+		}
+		req.Options.Level = q.Level
+		req.Options.Grep = q.Grep
+		req.Options.Version = q.Version
+	}
+
+	ls, err := s.runtime.Logs(context.DefaultContext, req, goclient.WithAuthToken())
 	if err != nil {
 		return nil, err
 	}
@@ -210,11 +224,20 @@ func (s *svc) Update(svc *runtime.Service, opts ...runtime.UpdateOption) error {
 			Version:  svc.Version,
 			Source:   svc.Source,
 			Metadata: svc.Metadata,
+			Replicas: int64(ReplicasFromUpdateOptions(options)),
 		},
 		Options: &pb.UpdateOptions{
 			Namespace: options.Namespace,
+			Resources: toProtoResources(ResourcesFromUpdateOptions(options)),
 		},
 	}
+	if a := AutoscaleFromUpdateOptions(options); a != nil {
+		req.Options.Autoscale = &pb.Autoscale{
+			Min:       int64(a.Min),
+			Max:       int64(a.Max),
+			TargetRps: a.TargetRPS,
+		}
+	}
 
 	if _, err := s.runtime.Update(context.DefaultContext, req, goclient.WithAuthToken()); err != nil {
 		return err
@@ -301,3 +324,14 @@ func NewRuntime(opts ...runtime.Option) runtime.Runtime {
 		runtime: pb.NewRuntimeService("runtime", client.DefaultClient),
 	}
 }
+
+func toProtoResources(r *runtime.Resources) *pb.Resources {
+	if r == nil {
+		return nil
+	}
+	return &pb.Resources{
+		Cpu:  int64(r.CPU),
+		Mem:  int64(r.Mem),
+		Disk: int64(r.Disk),
+	}
+}
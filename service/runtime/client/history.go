@@ -0,0 +1,102 @@
+package client
+
+import (
+	"errors"
+	"time"
+
+	goclient "github.com/micro/go-micro/v3/client"
+	"github.com/micro/go-micro/v3/runtime"
+	"github.com/micro/micro/v3/service/context"
+	pb "github.com/micro/micro/v3/service/runtime/proto"
+)
+
+var (
+	// ErrHistoryNotSupported is returned by runtimes which don't record the revisions of services
+	ErrHistoryNotSupported = errors.New("runtime doesn't record the history of services")
+	// ErrRevisionNotFound is returned when rolling back to a revision which doesn't exist
+	ErrRevisionNotFound = errors.New("revision not found")
+)
+
+// Revision is a deployment of a service
+type Revision struct {
+	// Number of the revision, starting from 1
+	Number int `json:"number"`
+	// Source the service was deployed from
+	Source string `json:"source"`
+	// Revision of the source the service was deployed from, the commit of git sources or the
+	// hash of uploaded and local sources
+	Revision string `json:"revision,omitempty"`
+	// Build of the source the service was run from, if it was built by the runtime
+	Build string `json:"build,omitempty"`
+	// Image the service was run with
+	Image string `json:"image"`
+	// Env the service was run with
+	Env []string `json:"env"`
+	// DeployedBy is the account which deployed the revision
+	DeployedBy string `json:"deployed_by"`
+	// Deployed is the time the revision was deployed
+	Deployed time.Time `json:"deployed"`
+}
+
+// Historian is implemented by runtimes which record the revisions of services
+type Historian interface {
+	// History returns the revisions of the service, oldest first
+	History(srv *runtime.Service, namespace string) ([]*Revision, error)
+	// Rollback redeploys a revision of the service, zero is the previous revision
+	Rollback(srv *runtime.Service, revision int, namespace string) error
+}
+
+// History returns the revisions of the service, oldest first
+func (s *svc) History(srv *runtime.Service, namespace string) ([]*Revision, error) {
+	req := &pb.HistoryRequest{
+		Service: &pb.Service{
+			Name:    srv.Name,
+			Version: srv.Version,
+		},
+		Options: &pb.HistoryOptions{
+			Namespace: namespace,
+		},
+	}
+
+	rsp, err := s.runtime.History(context.DefaultContext, req, goclient.WithAuthToken())
+	if err != nil {
+		return nil, err
+	}
+
+	revisions := make([]*Revision, 0, len(rsp.Revisions))
+	for _, r := range rsp.Revisions {
+		revisions = append(revisions, &Revision{
+			Number:     int(r.Number),
+			Source:     r.Source,
+			Revision:   r.Revision,
+			Build:      r.Build,
+			Image:      r.Image,
+			Env:        r.Env,
+			DeployedBy: r.DeployedBy,
+			Deployed:   time.Unix(r.Deployed, 0),
+		})
+	}
+
+	return revisions, nil
+}
+
+// Rollback redeploys a revision of the service, zero is the previous revision
+func (s *svc) Rollback(srv *runtime.Service, revision int, namespace string) error {
+	req := &pb.RollbackRequest{
+		Service: &pb.Service{
+			Name:     srv.Name,
+			Version:  srv.Version,
+			Metadata: srv.Metadata,
+		},
+		Options: &pb.RollbackOptions{
+			Namespace: namespace,
+			Revision:  int64(revision),
+		},
+	}
+
+	if _, err := s.runtime.Rollback(context.DefaultContext, req, goclient.WithAuthToken()); err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,258 @@
+package client
+
+import (
+	"errors"
+	"time"
+
+	goclient "github.com/micro/go-micro/v3/client"
+	"github.com/micro/go-micro/v3/runtime"
+	"github.com/micro/micro/v3/service/context"
+	pb "github.com/micro/micro/v3/service/runtime/proto"
+)
+
+var (
+	// ErrJobsNotSupported is returned by runtimes which can't run scheduled jobs
+	ErrJobsNotSupported = errors.New("runtime doesn't support jobs")
+	// ErrJobNotFound is returned when the job doesn't exist
+	ErrJobNotFound = errors.New("job not found")
+	// ErrJobRunning is returned when a job which forbids concurrent runs is triggered while running
+	ErrJobRunning = errors.New("job is already running")
+)
+
+const (
+	// ConcurrencyAllow starts a run when due even if the previous run hasn't finished
+	ConcurrencyAllow = "allow"
+	// ConcurrencyForbid skips a run which is due while the previous run hasn't finished
+	ConcurrencyForbid = "forbid"
+	// ConcurrencyReplace stops the previous run when the next is due
+	ConcurrencyReplace = "replace"
+
+	// JobRunning is the status of a run which hasn't finished
+	JobRunning = "running"
+	// JobSucceeded is the status of a run which exited with code zero
+	JobSucceeded = "succeeded"
+	// JobFailed is the status of a run which errored, timed out or was replaced
+	JobFailed = "failed"
+
+	// TriggerSchedule is the trigger of runs started by the schedule of the job
+	TriggerSchedule = "schedule"
+	// TriggerManual is the trigger of runs started by TriggerJob
+	TriggerManual = "manual"
+)
+
+// Job is a service which runs to completion on a schedule
+type Job struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+	Source  string `json:"source"`
+	// Schedule in cron format e.g. "0 2 * * *", jobs without one only run when triggered
+	Schedule string `json:"schedule"`
+	// ConcurrencyPolicy is one of ConcurrencyAllow, ConcurrencyForbid or ConcurrencyReplace
+	ConcurrencyPolicy string `json:"concurrency_policy"`
+	// Timeout is how long a run can take before it's stopped, zero for no limit
+	Timeout  time.Duration     `json:"timeout"`
+	Metadata map[string]string `json:"metadata"`
+	// Options the runs are created with
+	Options *runtime.CreateOptions `json:"options"`
+	// LastRun is the latest run of the job, set when the job is read
+	LastRun *JobRun `json:"-"`
+	// Next is when the job is next due, set when the job is read
+	Next time.Time `json:"-"`
+}
+
+// JobRun is a single run of a job
+type JobRun struct {
+	// Number of the run, starting from 1
+	Number int `json:"number"`
+	// Status is one of JobRunning, JobSucceeded or JobFailed
+	Status   string `json:"status"`
+	ExitCode int    `json:"exit_code"`
+	Error    string `json:"error"`
+	// Trigger is what started the run, TriggerSchedule or TriggerManual
+	Trigger  string    `json:"trigger"`
+	Started  time.Time `json:"started"`
+	Finished time.Time `json:"finished"`
+}
+
+// JobScheduler is implemented by runtimes which run scheduled jobs
+type JobScheduler interface {
+	// CreateJob creates the job or replaces it if it exists
+	CreateJob(job *Job, namespace string) error
+	// ReadJobs returns the jobs in the namespace, or the job with the name provided
+	ReadJobs(name, namespace string) ([]*Job, error)
+	// DeleteJob deletes the job and its runs, stopping any which are running
+	DeleteJob(name, namespace string) error
+	// TriggerJob starts a run of the job now
+	TriggerJob(name, namespace string) (*JobRun, error)
+	// JobRuns returns the runs of the job, oldest first
+	JobRuns(name, namespace string) ([]*JobRun, error)
+	// JobLogs returns the logs of a run of the job, zero is the latest run
+	JobLogs(name string, run int, namespace string) ([]runtime.Log, error)
+}
+
+// CreateJob creates the job or replaces it if it exists
+func (s *svc) CreateJob(job *Job, namespace string) error {
+	opts := job.Options
+	if opts == nil {
+		opts = &runtime.CreateOptions{}
+	}
+
+	req := &pb.CreateJobRequest{
+		Job: &pb.Job{
+			Name:              job.Name,
+			Version:           job.Version,
+			Source:            job.Source,
+			Schedule:          job.Schedule,
+			ConcurrencyPolicy: job.ConcurrencyPolicy,
+			Timeout:           int64(job.Timeout.Seconds()),
+			Metadata:          job.Metadata,
+			Options: &pb.CreateOptions{
+				Command:   opts.Command,
+				Args:      opts.Args,
+				Env:       opts.Env,
+				Type:      opts.Type,
+				Image:     opts.Image,
+				Secrets:   opts.Secrets,
+				Resources: toProtoResources(opts.Resources),
+			},
+		},
+		Options: &pb.JobOptions{
+			Namespace: namespace,
+		},
+	}
+
+	if _, err := s.runtime.CreateJob(context.DefaultContext, req, goclient.WithAuthToken()); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// ReadJobs returns the jobs in the namespace, or the job with the name provided
+func (s *svc) ReadJobs(name, namespace string) ([]*Job, error) {
+	req := &pb.ReadJobsRequest{
+		Name:    name,
+		Options: &pb.JobOptions{Namespace: namespace},
+	}
+
+	rsp, err := s.runtime.ReadJobs(context.DefaultContext, req, goclient.WithAuthToken())
+	if err != nil {
+		return nil, err
+	}
+
+	jobs := make([]*Job, 0, len(rsp.Jobs))
+	for _, j := range rsp.Jobs {
+		job := &Job{
+			Name:              j.Name,
+			Version:           j.Version,
+			Source:            j.Source,
+			Schedule:          j.Schedule,
+			ConcurrencyPolicy: j.ConcurrencyPolicy,
+			Timeout:           time.Duration(j.Timeout) * time.Second,
+			Metadata:          j.Metadata,
+		}
+		if j.LastRun != nil {
+			job.LastRun = toJobRun(j.LastRun)
+		}
+		if j.Next > 0 {
+			job.Next = time.Unix(j.Next, 0)
+		}
+		if o := j.Options; o != nil {
+			job.Options = &runtime.CreateOptions{
+				Command: o.Command,
+				Args:    o.Args,
+				Env:     o.Env,
+				Type:    o.Type,
+				Image:   o.Image,
+			}
+		}
+		jobs = append(jobs, job)
+	}
+
+	return jobs, nil
+}
+
+// DeleteJob deletes the job and its runs, stopping any which are running
+func (s *svc) DeleteJob(name, namespace string) error {
+	req := &pb.DeleteJobRequest{
+		Name:    name,
+		Options: &pb.JobOptions{Namespace: namespace},
+	}
+
+	if _, err := s.runtime.DeleteJob(context.DefaultContext, req, goclient.WithAuthToken()); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// TriggerJob starts a run of the job now
+func (s *svc) TriggerJob(name, namespace string) (*JobRun, error) {
+	req := &pb.TriggerJobRequest{
+		Name:    name,
+		Options: &pb.JobOptions{Namespace: namespace},
+	}
+
+	rsp, err := s.runtime.TriggerJob(context.DefaultContext, req, goclient.WithAuthToken())
+	if err != nil {
+		return nil, err
+	}
+
+	return toJobRun(rsp.Run), nil
+}
+
+// JobRuns returns the runs of the job, oldest first
+func (s *svc) JobRuns(name, namespace string) ([]*JobRun, error) {
+	req := &pb.JobRunsRequest{
+		Name:    name,
+		Options: &pb.JobOptions{Namespace: namespace},
+	}
+
+	rsp, err := s.runtime.JobRuns(context.DefaultContext, req, goclient.WithAuthToken())
+	if err != nil {
+		return nil, err
+	}
+
+	runs := make([]*JobRun, 0, len(rsp.Runs))
+	for _, r := range rsp.Runs {
+		runs = append(runs, toJobRun(r))
+	}
+
+	return runs, nil
+}
+
+// JobLogs returns the logs of a run of the job, zero is the latest run
+func (s *svc) JobLogs(name string, run int, namespace string) ([]runtime.Log, error) {
+	req := &pb.JobLogsRequest{
+		Name:    name,
+		Run:     int64(run),
+		Options: &pb.JobOptions{Namespace: namespace},
+	}
+
+	rsp, err := s.runtime.JobLogs(context.DefaultContext, req, goclient.WithAuthToken())
+	if err != nil {
+		return nil, err
+	}
+
+	logs := make([]runtime.Log, 0, len(rsp.Records))
+	for _, r := range rsp.Records {
+		logs = append(logs, runtime.Log{Message: r.Message, Metadata: r.Metadata})
+	}
+
+	return logs, nil
+}
+
+func toJobRun(r *pb.JobRun) *JobRun {
+	run := &JobRun{
+		Number:   int(r.Number),
+		Status:   r.Status,
+		ExitCode: int(r.ExitCode),
+		Error:    r.Error,
+		Trigger:  r.Trigger,
+		Started:  time.Unix(r.Started, 0),
+	}
+	if r.Finished > 0 {
+		run.Finished = time.Unix(r.Finished, 0)
+	}
+	return run
+}
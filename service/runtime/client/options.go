@@ -0,0 +1,185 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/micro/go-micro/v3/runtime"
+)
+
+type resourcesKey struct{}
+type replicasKey struct{}
+type autoscaleKey struct{}
+type logsQueryKey struct{}
+type dependsOnKey struct{}
+
+// Autoscale configures the number of replicas of a service to follow its request rate
+type Autoscale struct {
+	// Min is the minimum number of replicas
+	Min int `json:"min"`
+	// Max is the maximum number of replicas, autoscaling is disabled when zero
+	Max int `json:"max"`
+	// TargetRPS is the number of requests per second each replica should serve
+	TargetRPS float64 `json:"target_rps"`
+}
+
+// CreateReplicas sets the number of instances of the service to run
+func CreateReplicas(n int) runtime.CreateOption {
+	return func(o *runtime.CreateOptions) {
+		if o.Context == nil {
+			o.Context = context.Background()
+		}
+		o.Context = context.WithValue(o.Context, replicasKey{}, n)
+	}
+}
+
+// ReplicasFromCreateOptions returns the replicas set with CreateReplicas
+func ReplicasFromCreateOptions(o runtime.CreateOptions) int {
+	if o.Context == nil {
+		return 0
+	}
+	n, _ := o.Context.Value(replicasKey{}).(int)
+	return n
+}
+
+// CreateDependsOn sets the services which must be registered and healthy before the service is
+// started, e.g. CreateDependsOn("store", "config")
+func CreateDependsOn(services ...string) runtime.CreateOption {
+	return func(o *runtime.CreateOptions) {
+		if o.Context == nil {
+			o.Context = context.Background()
+		}
+		o.Context = context.WithValue(o.Context, dependsOnKey{}, services)
+	}
+}
+
+// DependsOnFromCreateOptions returns the services set with CreateDependsOn
+func DependsOnFromCreateOptions(o runtime.CreateOptions) []string {
+	if o.Context == nil {
+		return nil
+	}
+	s, _ := o.Context.Value(dependsOnKey{}).([]string)
+	return s
+}
+
+// DependencyCycleError is returned when creating a service would make it depend on itself
+type DependencyCycleError struct {
+	// Cycle is the services in the cycle, starting and ending with the service created
+	Cycle []string
+}
+
+func (e *DependencyCycleError) Error() string {
+	return fmt.Sprintf("dependency cycle: %s", strings.Join(e.Cycle, " -> "))
+}
+
+// UpdateResources changes the resources allocated to the service, the service
+// is restarted for the new limits to take effect
+func UpdateResources(r *runtime.Resources) runtime.UpdateOption {
+	return setUpdateOption(resourcesKey{}, r)
+}
+
+// UpdateReplicas scales the service to the number of instances, disabling autoscaling. The
+// running instances are left as they are rather than being updated.
+func UpdateReplicas(n int) runtime.UpdateOption {
+	return setUpdateOption(replicasKey{}, n)
+}
+
+// UpdateAutoscale scales the service based on its request rate, a max of zero disables it
+func UpdateAutoscale(a *Autoscale) runtime.UpdateOption {
+	return setUpdateOption(autoscaleKey{}, a)
+}
+
+// ResourcesFromUpdateOptions returns the resources set with UpdateResources
+func ResourcesFromUpdateOptions(o runtime.UpdateOptions) *runtime.Resources {
+	if o.Context == nil {
+		return nil
+	}
+	r, _ := o.Context.Value(resourcesKey{}).(*runtime.Resources)
+	return r
+}
+
+// ReplicasFromUpdateOptions returns the replicas set with UpdateReplicas
+func ReplicasFromUpdateOptions(o runtime.UpdateOptions) int {
+	if o.Context == nil {
+		return 0
+	}
+	n, _ := o.Context.Value(replicasKey{}).(int)
+	return n
+}
+
+// AutoscaleFromUpdateOptions returns the autoscaling set with UpdateAutoscale
+func AutoscaleFromUpdateOptions(o runtime.UpdateOptions) *Autoscale {
+	if o.Context == nil {
+		return nil
+	}
+	a, _ := o.Context.Value(autoscaleKey{}).(*Autoscale)
+	return a
+}
+
+func setUpdateOption(k, v interface{}) runtime.UpdateOption {
+	return func(o *runtime.UpdateOptions) {
+		if o.Context == nil {
+			o.Context = context.Background()
+		}
+		o.Context = context.WithValue(o.Context, k, v)
+	}
+}
+
+// LogsQuery filters the logs of a service persisted by the runtime
+type LogsQuery struct {
+	// Since is the time from which to return logs
+	Since time.Time
+	// Level is the minimum level of the logs e.g. warn returns warnings and errors
+	Level string
+	// Grep is a regular expression the logs must match
+	Grep string
+	// Version of the service the logs were written by
+	Version string
+}
+
+// LogsSince returns the logs written since the time
+func LogsSince(t time.Time) runtime.LogsOption {
+	return setLogsQuery(func(q *LogsQuery) { q.Since = t })
+}
+
+// LogsLevel returns the logs of the level or above e.g. warn returns warnings and errors
+func LogsLevel(level string) runtime.LogsOption {
+	return setLogsQuery(func(q *LogsQuery) { q.Level = level })
+}
+
+// LogsGrep returns the logs which match the regular expression
+func LogsGrep(expr string) runtime.LogsOption {
+	return setLogsQuery(func(q *LogsQuery) { q.Grep = expr })
+}
+
+// LogsVersion returns the logs written by the version of the service
+func LogsVersion(version string) runtime.LogsOption {
+	return setLogsQuery(func(q *LogsQuery) { q.Version = version })
+}
+
+// LogsQueryFromOptions returns the query set with LogsSince, LogsLevel, LogsGrep and LogsVersion,
+// nil is returned if none were set
+func LogsQueryFromOptions(o runtime.LogsOptions) *LogsQuery {
+	if o.Context == nil {
+		return nil
+	}
+	q, _ := o.Context.Value(logsQueryKey{}).(*LogsQuery)
+	return q
+}
+
+func setLogsQuery(fn func(q *LogsQuery)) runtime.LogsOption {
+	return func(o *runtime.LogsOptions) {
+		if o.Context == nil {
+			o.Context = context.Background()
+		}
+		// copy the query so options don't modify each other's
+		q := &LogsQuery{}
+		if prev, ok := o.Context.Value(logsQueryKey{}).(*LogsQuery); ok {
+			*q = *prev
+		}
+		fn(q)
+		o.Context = context.WithValue(o.Context, logsQueryKey{}, q)
+	}
+}
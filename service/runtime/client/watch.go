@@ -0,0 +1,73 @@
+package client
+
+import (
+	"errors"
+	"time"
+
+	goclient "github.com/micro/go-micro/v3/client"
+	"github.com/micro/go-micro/v3/runtime"
+	"github.com/micro/micro/v3/service/context"
+	pb "github.com/micro/micro/v3/service/runtime/proto"
+)
+
+// ErrWatchNotSupported is returned by runtimes which can't stream status changes
+var ErrWatchNotSupported = errors.New("runtime doesn't support watching the status of services")
+
+// StatusEvent is a change in the status of a replica of a service
+type StatusEvent struct {
+	Service *runtime.Service
+	// Replica is the index of the replica whose status changed
+	Replica int
+	Status  string
+	Error   string
+	Updated time.Time
+}
+
+// StatusWatcher is implemented by runtimes which stream the status changes of services
+type StatusWatcher interface {
+	// Watch streams the status changes of the services in the namespace, the changes of a single
+	// service are streamed if a name is provided. The channel is closed when the stream ends.
+	Watch(service, namespace string) (<-chan *StatusEvent, error)
+}
+
+// Watch streams the status changes of the services in the namespace
+func (s *svc) Watch(service, namespace string) (<-chan *StatusEvent, error) {
+	req := &pb.WatchRequest{
+		Service: service,
+		Options: &pb.WatchOptions{
+			Namespace: namespace,
+		},
+	}
+
+	stream, err := s.runtime.Watch(context.DefaultContext, req, goclient.WithAuthToken())
+	if err != nil {
+		return nil, err
+	}
+
+	evChan := make(chan *StatusEvent)
+	go func() {
+		defer close(evChan)
+		defer stream.Close()
+
+		for {
+			ev, err := stream.Recv()
+			if err != nil {
+				return
+			}
+			evChan <- &StatusEvent{
+				Service: &runtime.Service{
+					Name:     ev.Service.GetName(),
+					Version:  ev.Service.GetVersion(),
+					Source:   ev.Service.GetSource(),
+					Metadata: ev.Service.GetMetadata(),
+				},
+				Replica: int(ev.Replica),
+				Status:  ev.Status,
+				Error:   ev.Error,
+				Updated: time.Unix(ev.Timestamp, 0),
+			}
+		}
+	}()
+
+	return evChan, nil
+}
@@ -1,10 +1,16 @@
 package runtime
 
-import "github.com/micro/go-micro/v3/runtime"
+import (
+	"time"
+
+	"github.com/micro/go-micro/v3/runtime"
+)
 
 const (
 	// EventTopic the events are published to
 	EventTopic = "runtime"
+	// EventStatusTopic the status changes of services are published to
+	EventStatusTopic = "runtime.status"
 
 	// EventServiceCreated is the topic events are published to when a service is created
 	EventServiceCreated = "service.created"
@@ -14,6 +20,14 @@ const (
 	EventServiceDeleted   = "service.deleted"
 	EventNamespaceCreated = "namespace.created"
 	EventNamespaceDeleted = "namespace.deleted"
+
+	// EventDeploymentStarted is the type of events published when a rolling update starts
+	EventDeploymentStarted = "deployment.started"
+	// EventDeploymentSucceeded is the type of events published when every replica was updated
+	EventDeploymentSucceeded = "deployment.succeeded"
+	// EventDeploymentRolledBack is the type of events published when an update failed its
+	// health checks and the previous version was restored
+	EventDeploymentRolledBack = "deployment.rolledback"
 )
 
 // EventPayload which is published with runtime events
@@ -28,3 +42,23 @@ type EventNamespacePayload struct {
 	Type      string
 	Namespace string
 }
+
+// EventDeploymentPayload which is published with runtime deployment events
+type EventDeploymentPayload struct {
+	Type      string
+	Service   *runtime.Service
+	Namespace string
+	// Error is the reason the deployment was rolled back
+	Error string
+}
+
+// EventStatusPayload which is published when the status of a replica of a service changes
+type EventStatusPayload struct {
+	Service   *runtime.Service
+	Namespace string
+	// Replica is the index of the replica whose status changed
+	Replica int
+	Status  string
+	Error   string
+	Updated time.Time
+}
@@ -14,6 +14,8 @@ func (m *manager) generateAccount(srv *runtime.Service, ns string) (*goauth.Acco
 		goauth.WithIssuer(ns),
 		goauth.WithScopes("service"),
 		goauth.WithType("service"),
+		// the name of the service is checked when issuing certificates to the account
+		goauth.WithMetadata(map[string]string{"service": srv.Name}),
 	}
 
 	acc, err := auth.Generate(accName, opts...)
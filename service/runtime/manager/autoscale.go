@@ -0,0 +1,222 @@
+package manager
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	goclient "github.com/micro/go-micro/v3/client"
+	"github.com/micro/go-micro/v3/client/grpc"
+	"github.com/micro/go-micro/v3/registry"
+	gorun "github.com/micro/go-micro/v3/runtime"
+	debug "github.com/micro/micro/v3/service/debug/proto"
+	"github.com/micro/micro/v3/service/logger"
+	muregistry "github.com/micro/micro/v3/service/registry"
+	"github.com/micro/micro/v3/service/runtime/client"
+)
+
+var (
+	// autoscalePollFrequency is how often the request rate of autoscaled services is sampled
+	autoscalePollFrequency = time.Second * 30
+	// directClient calls the nodes of services at their address rather than through the proxy
+	directClient = grpc.NewClient()
+)
+
+// sample is the total number of requests served by a node at a point in time
+type sample struct {
+	requests uint64
+	time     time.Time
+}
+
+// samples of the nodes of autoscaled services, keyed by node id
+type samples struct {
+	sync.Mutex
+	nodes map[string]*sample
+}
+
+func newSamples() *samples {
+	return &samples{nodes: make(map[string]*sample)}
+}
+
+// rate records the sample for the node and returns the requests per second since the
+// previous sample. False is returned if the node hasn't been sampled before.
+func (s *samples) rate(id string, cur *sample) (float64, bool) {
+	s.Lock()
+	defer s.Unlock()
+
+	prev, ok := s.nodes[id]
+	s.nodes[id] = cur
+	if !ok || !cur.time.After(prev.time) || cur.requests < prev.requests {
+		return 0, false
+	}
+
+	return float64(cur.requests-prev.requests) / cur.time.Sub(prev.time).Seconds(), true
+}
+
+// prune removes the samples of nodes which haven't been sampled since the time
+func (s *samples) prune(before time.Time) {
+	s.Lock()
+	defer s.Unlock()
+
+	for id, smp := range s.nodes {
+		if smp.time.Before(before) {
+			delete(s.nodes, id)
+		}
+	}
+}
+
+// setAutoscale validates the autoscaling and sets it on the service, clamping the
+// replicas to the range allowed. A max of zero disables autoscaling.
+func setAutoscale(s *service, a *client.Autoscale) error {
+	if a.Max == 0 {
+		s.Autoscale = nil
+		return nil
+	}
+	if a.Min < 1 {
+		a.Min = 1
+	}
+	if a.Max < a.Min {
+		return fmt.Errorf("the max replicas %v is less than the min replicas %v", a.Max, a.Min)
+	}
+	if a.TargetRPS <= 0 {
+		return fmt.Errorf("the target requests per second must be greater than zero")
+	}
+
+	s.Autoscale = a
+	s.Replicas = desiredReplicas(s.replicaCount(), float64(s.replicaCount())*a.TargetRPS, a)
+	return nil
+}
+
+// desiredReplicas returns the number of replicas needed to serve the requests per second at the
+// target rate of each replica. Services are scaled up immediately but scaled down one replica at a
+// time so a brief drop in traffic doesn't remove most of the replicas.
+func desiredReplicas(current int, rps float64, a *client.Autoscale) int {
+	desired := int(math.Ceil(rps / a.TargetRPS))
+	if desired < current-1 {
+		desired = current - 1
+	}
+	if desired < a.Min {
+		desired = a.Min
+	}
+	if desired > a.Max {
+		desired = a.Max
+	}
+	return desired
+}
+
+// formatAutoscale describes the autoscaling e.g. "min=1, max=5, target_rps=100"
+func formatAutoscale(a *client.Autoscale) string {
+	if a == nil {
+		return ""
+	}
+	return fmt.Sprintf("min=%d, max=%d, target_rps=%g", a.Min, a.Max, a.TargetRPS)
+}
+
+// watchAutoscale calls syncAutoscale periodically and should be run in a seperate go routine
+func (m *manager) watchAutoscale() {
+	ticker := time.NewTicker(autoscalePollFrequency)
+
+	for {
+		<-ticker.C
+		m.syncAutoscale()
+	}
+}
+
+// syncAutoscale samples the request rate of the services with autoscaling enabled and scales them
+func (m *manager) syncAutoscale() {
+	namespaces, err := m.listNamespaces()
+	if err != nil {
+		logger.Warnf("Error listing namespaces: %v", err)
+		return
+	}
+
+	for _, ns := range namespaces {
+		srvs, err := m.readServices(ns, &gorun.Service{})
+		if err != nil {
+			logger.Warnf("Error reading services from the %v namespace: %v", ns, err)
+			continue
+		}
+
+		for _, s := range srvs {
+			if s.Autoscale == nil {
+				continue
+			}
+			if err := m.autoscale(ns, s); err != nil {
+				logger.Warnf("Error autoscaling service %v:%v in namespace %v: %v", s.Service.Name, s.Service.Version, ns, err)
+			}
+		}
+	}
+
+	// forget the nodes which have stopped
+	m.samples.prune(time.Now().Add(-autoscalePollFrequency * 3))
+}
+
+// autoscale sets the replicas of the service based on its request rate
+func (m *manager) autoscale(ns string, s *service) error {
+	rps, ok := m.requestRate(ns, s)
+	if !ok {
+		return nil
+	}
+
+	desired := desiredReplicas(s.replicaCount(), rps, s.Autoscale)
+	if desired == s.replicaCount() {
+		return nil
+	}
+
+	logger.Infof("Scaling service %v:%v in namespace %v from %v to %v replicas at %.2f requests per second",
+		s.Service.Name, s.Service.Version, ns, s.replicaCount(), desired, rps)
+
+	s.Replicas = desired
+	if err := m.writeService(s); err != nil {
+		return err
+	}
+	return m.scaleService(ns, s.Service)
+}
+
+// requestRate returns the total requests per second served by the replicas of the service. The
+// rate is only known once every node has been sampled twice.
+func (m *manager) requestRate(ns string, s *service) (float64, bool) {
+	srvs, err := muregistry.GetService(nameFromService(s.Service.Name), registry.GetDomain(ns))
+	if err != nil {
+		return 0, false
+	}
+
+	var total float64
+	var sampled, missing bool
+	for _, srv := range srvs {
+		if srv.Version != s.Service.Version {
+			continue
+		}
+
+		for _, node := range srv.Nodes {
+			req := directClient.NewRequest(srv.Name, "Debug.Stats", &debug.StatsRequest{})
+			rsp := &debug.StatsResponse{}
+
+			err := directClient.Call(
+				context.Background(),
+				req,
+				rsp,
+				goclient.WithAddress(node.Address),
+				goclient.WithRequestTimeout(time.Second*5),
+				goclient.WithRetries(0),
+			)
+			if err != nil {
+				logger.Debugf("Error reading the stats of node %v: %v", node.Id, err)
+				continue
+			}
+
+			rate, ok := m.samples.rate(node.Id, &sample{requests: rsp.Requests, time: time.Now()})
+			if !ok {
+				// a new replica which hasn't served requests for a full interval yet
+				missing = true
+				continue
+			}
+			total += rate
+			sampled = true
+		}
+	}
+
+	return total, sampled && !missing
+}
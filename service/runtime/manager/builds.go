@@ -0,0 +1,686 @@
+package manager
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	goauth "github.com/micro/go-micro/v3/auth"
+	gorun "github.com/micro/go-micro/v3/runtime"
+	"github.com/micro/go-micro/v3/runtime/local"
+	"github.com/micro/go-micro/v3/runtime/local/source/git"
+	gostore "github.com/micro/go-micro/v3/store"
+	"github.com/micro/micro/v3/service/logger"
+	"github.com/micro/micro/v3/service/runtime"
+	"github.com/micro/micro/v3/service/runtime/client"
+	"github.com/micro/micro/v3/service/store"
+)
+
+const (
+	// buildPrefix is prefixed to the key for the records of builds e.g. "build:micro:3f2a..."
+	buildPrefix = "build:"
+	// buildSumPrefix is prefixed to the key for the records of the hash of the go.sum of each
+	// revision of a service e.g. "buildsum:micro:9c1e...", so builds are found by their id
+	// without checking out the source
+	buildSumPrefix = "buildsum:"
+	// artifactPrefix is prefixed to the keys of the chunks of the artifacts of builds e.g.
+	// "artifact:micro:3f2a...:0", they're written to the store so they're shared by runtimes
+	artifactPrefix = "artifact:"
+	// artifactChunkSize is the max number of bytes of an artifact written to each record
+	artifactChunkSize = 1024 * 1024
+	// buildOutputLimit is the max number of bytes of the output of a failed build which are
+	// included in the error
+	buildOutputLimit = 2048
+	// gitCredentialsKey is the secret git sources are checked out using
+	gitCredentialsKey = "GIT_CREDENTIALS"
+
+	// statusBuilding is the status of services while their source is built
+	statusBuilding = "building"
+)
+
+var (
+	// buildDir is where sources are checked out to and the artifacts are cached
+	buildDir = filepath.Join(os.TempDir(), "micro", "builds")
+)
+
+// buildKey returns the key of the build in the store
+func buildKey(ns, id string) string {
+	return buildPrefix + ns + ":" + id
+}
+
+// artifactKey returns the key of the chunk of the artifact of the build in the store
+func artifactKey(ns, id string, chunk int) string {
+	return fmt.Sprintf("%v%v:%v:%d", artifactPrefix, ns, id, chunk)
+}
+
+// artifactPath returns the path the artifact of the build is cached at
+func artifactPath(ns, id string) string {
+	return filepath.Join(buildDir, "bin", ns, id)
+}
+
+// shouldBuild returns true if the service should be started from a build of its source. The
+// local runtime otherwise compiles the source with go run every time a process is started,
+// other runtimes build the source in the image the service runs in.
+func shouldBuild(srv *gorun.Service, opts *gorun.CreateOptions) bool {
+	return runtime.DefaultRuntime.String() == "local" && len(srv.Source) > 0 && len(opts.Command) == 0
+}
+
+// buildVersion returns the version of the service or job the replica or run was created from,
+// which is the ref of the source
+func buildVersion(version string) string {
+	version, _ = parseReplica(version)
+	if idx := strings.LastIndex(version, runSeparator); idx > 0 {
+		version = version[:idx]
+	}
+	return version
+}
+
+// revisionID returns the id of the revision of the source of the service
+func revisionID(ns string, srv *gorun.Service, version, revision string) string {
+	h := sha256.New()
+	for _, v := range []string{ns, srv.Name, srv.Source, version, revision} {
+		fmt.Fprintf(h, "%v\n", v)
+	}
+	return hex.EncodeToString(h.Sum(nil))[:32]
+}
+
+// buildID returns the id of the build of the revision of the source with the hash of its go.sum,
+// so the revision is built again if its dependencies change
+func buildID(ns string, srv *gorun.Service, version, revision, sum string) string {
+	h := sha256.New()
+	for _, v := range []string{revisionID(ns, srv, version, revision), sum} {
+		fmt.Fprintf(h, "%v\n", v)
+	}
+	return hex.EncodeToString(h.Sum(nil))[:32]
+}
+
+// buildService returns the path of the artifact built from the source of the service. The
+// build is looked up by the revision of the source before it's checked out, so the artifact
+// is reused without checking out the source if the revision was already built. Builds of the
+// same revision are made once, replicas started at the same time wait for it.
+func (m *manager) buildService(ns string, srv *gorun.Service, opts *gorun.CreateOptions) (string, error) {
+	version := buildVersion(srv.Version)
+
+	// services are pinned to a revision of their source when they're created or updated
+	revision := srv.Metadata[revisionMetadata]
+	pinned := len(revision) > 0
+	if !pinned {
+		var err error
+		if revision, err = sourceRevision(srv, version, opts.Secrets); err != nil {
+			return "", fmt.Errorf("error resolving source: %v", err)
+		}
+	}
+	revID := revisionID(ns, srv, version, revision)
+
+	lock := m.buildLock(ns, revID)
+	lock.Lock()
+	defer lock.Unlock()
+
+	// reuse the artifact if it was already built, by this runtime or another using the store.
+	// The hash of the go.sum of the revision is recorded when it's first built.
+	if sum, err := readBuildSum(ns, revID); err == nil {
+		if path, ok := m.reuseBuild(ns, buildID(ns, srv, version, revision, sum)); ok {
+			return path, nil
+		}
+	} else if err != gostore.ErrNotFound {
+		return "", err
+	}
+
+	// uploaded and local sources can only be built at their current revision, the builds of
+	// previous revisions are only available while they're in the store
+	if pinned && (strings.HasPrefix(revision, "upload:") || strings.HasPrefix(revision, "local:")) {
+		current, err := sourceRevision(srv, version, opts.Secrets)
+		if err != nil {
+			return "", fmt.Errorf("error resolving source: %v", err)
+		} else if current != revision {
+			return "", fmt.Errorf("the source of revision %v is no longer available", revision)
+		}
+	}
+
+	// git sources are checked out at the commit which was resolved
+	root, dir, err := checkoutBuildSource(ns, srv, revision, opts.Secrets)
+	if err != nil {
+		return "", fmt.Errorf("error checking out source: %v", err)
+	}
+
+	// builds are identified by the revision and its go.sum, which is hashed once it's checked out
+	sum := hashSum(root, dir)
+	id := buildID(ns, srv, version, revision, sum)
+	path := artifactPath(ns, id)
+	if err := writeBuildSum(ns, revID, sum); err != nil {
+		return "", err
+	}
+	if path, ok := m.reuseBuild(ns, id); ok {
+		return path, nil
+	}
+
+	logger.Infof("Building service %v:%v in namespace %v", srv.Name, version, ns)
+	ep, err := local.Entrypoint(dir)
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return "", err
+	}
+
+	// build to a temporary file so a failed build doesn't leave a partial artifact
+	tmp := path + ".tmp"
+	cmd := exec.Command("go", "build", "-o", tmp, "./"+filepath.Dir(ep))
+	cmd.Dir = dir
+	cmd.Env = os.Environ()
+	if out, err := cmd.CombinedOutput(); err != nil {
+		os.Remove(tmp)
+		if len(out) > buildOutputLimit {
+			out = out[len(out)-buildOutputLimit:]
+		}
+		return "", fmt.Errorf("error building service: %v: %s", err, strings.TrimSpace(string(out)))
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return "", err
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return "", err
+	}
+	b := &client.Build{
+		ID:       id,
+		Name:     srv.Name,
+		Version:  version,
+		Source:   srv.Source,
+		SumHash:  sum,
+		Size:     info.Size(),
+		Created:  time.Now(),
+		LastUsed: time.Now(),
+	}
+
+	// the build is written once its artifact has been, so builds in the store always have one
+	if err := writeArtifact(ns, id, path); err != nil {
+		return "", fmt.Errorf("error writing artifact: %v", err)
+	}
+	if err := m.writeBuild(ns, b); err != nil {
+		return "", err
+	}
+
+	return path, nil
+}
+
+// reuseBuild returns the path of the artifact of the build if it exists, fetching the artifact
+// from the store if it isn't cached
+func (m *manager) reuseBuild(ns, id string) (string, bool) {
+	lock := m.buildLock(ns, id)
+	lock.Lock()
+	defer lock.Unlock()
+
+	b, err := m.readBuild(ns, id)
+	if err != nil {
+		if err != gostore.ErrNotFound {
+			logger.Warnf("Error reading build %v: %v", id, err)
+		}
+		return "", false
+	}
+
+	path := artifactPath(ns, id)
+	if err := fetchArtifact(ns, b, path); err != nil {
+		logger.Warnf("Error fetching the artifact of build %v, building it again: %v", id, err)
+		return "", false
+	}
+	b.LastUsed = time.Now()
+	if err := m.writeBuild(ns, b); err != nil {
+		logger.Warnf("Error updating build %v: %v", id, err)
+	}
+	return path, true
+}
+
+// readBuildSum returns the hash of the go.sum of the revision
+func readBuildSum(ns, revID string) (string, error) {
+	recs, err := store.Read(buildSumPrefix + ns + ":" + revID)
+	if err != nil {
+		return "", err
+	}
+	return string(recs[0].Value), nil
+}
+
+// writeBuildSum records the hash of the go.sum of the revision
+func writeBuildSum(ns, revID, sum string) error {
+	return store.Write(&gostore.Record{Key: buildSumPrefix + ns + ":" + revID, Value: []byte(sum)})
+}
+
+// buildAndStart builds the source of the service and then starts it, until then its status is
+// building. Builds can take minutes so they're made in the background rather than blocking
+// other events.
+func (m *manager) buildAndStart(ns string, srv *gorun.Service, opts *gorun.CreateOptions, acc *goauth.Account, cancel <-chan struct{}) {
+	m.cacheStatus(ns, &gorun.Service{
+		Name:     srv.Name,
+		Version:  srv.Version,
+		Source:   srv.Source,
+		Metadata: map[string]string{"status": statusBuilding},
+	})
+
+	// the build can't be interrupted, the service isn't started if it was deleted or updated
+	// while it was being built
+	_, err := m.buildService(ns, srv, opts)
+	if !m.doneWaiting(ns, srv, cancel) {
+		logger.Infof("Service %v:%v in namespace %v was changed while it was built, not starting it", srv.Name, srv.Version, ns)
+		return
+	}
+	if err == nil {
+		err = m.startService(ns, srv, opts, acc)
+	}
+	if err == nil {
+		err = m.scaleService(ns, srv)
+	}
+	if err != nil {
+		logger.Warnf("Error starting service %v:%v in namespace %v: %v", srv.Name, srv.Version, ns, err)
+		srv.Metadata = map[string]string{"status": "error", "error": err.Error()}
+		m.cacheStatus(ns, srv)
+		return
+	}
+	m.refreshStatus(ns)
+}
+
+// buildLock returns the lock held while the build is made, fetched or deleted
+func (m *manager) buildLock(ns, id string) *sync.Mutex {
+	m.building.Lock()
+	defer m.building.Unlock()
+
+	key := ns + ":" + id
+	lock, ok := m.buildLocks[key]
+	if !ok {
+		lock = &sync.Mutex{}
+		m.buildLocks[key] = lock
+	}
+	return lock
+}
+
+// sourceRevision returns the revision of the source builds are looked up by, so they can be
+// found without checking out the source. It's the commit the ref of git sources points to, the
+// hash of uploaded sources or the hash of the code of local folders.
+func sourceRevision(srv *gorun.Service, ref string, secrets map[string]string) (string, error) {
+	// uploaded sources have the format lastfolder.tar.gz or lastfolder.tar.gz/relative/path
+	parts := strings.Split(srv.Source, "/")
+	if strings.HasSuffix(parts[0], ".tar.gz") {
+		if f, err := os.Open(filepath.Join(local.SourceDir, parts[0])); err == nil {
+			defer f.Close()
+			h := sha256.New()
+			if _, err := io.Copy(h, f); err != nil {
+				return "", err
+			}
+			return "upload:" + hex.EncodeToString(h.Sum(nil)), nil
+		}
+	}
+
+	source, err := git.ParseSourceLocal("", srv.Source)
+	if err != nil {
+		return "", err
+	}
+	if source.Local {
+		root := source.LocalRepoRoot
+		if len(root) == 0 {
+			root = source.FullPath
+		}
+		code, err := hashCode(root)
+		if err != nil {
+			return "", err
+		}
+		return "local:" + code, nil
+	}
+
+	return remoteCommit(source.Repo, ref, secrets)
+}
+
+// remoteCommit returns the commit the ref of the git repo points to, refs which aren't branches
+// or tags are assumed to be commits
+func remoteCommit(repo, ref string, secrets map[string]string) (string, error) {
+	if ref == "latest" {
+		ref = "master"
+	}
+
+	cmd := exec.Command("git", "ls-remote", "https://"+repo, ref)
+	// git mustn't prompt for credentials since there's no one to enter them
+	cmd.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0")
+	creds := secrets[gitCredentialsKey]
+	if len(creds) > 0 {
+		cmd.Env = append(cmd.Env, gitAuthEnv(creds)...)
+	}
+	out, err := cmd.Output()
+	if err != nil {
+		// the output isn't included since it can contain the credentials
+		msg := err.Error()
+		if len(creds) > 0 {
+			msg = strings.Replace(msg, creds, "***", -1)
+		}
+		return "", fmt.Errorf("error resolving ref %v of %v: %v", ref, repo, msg)
+	}
+
+	if fields := strings.Fields(string(out)); len(fields) > 0 {
+		return fields[0], nil
+	}
+	return ref, nil
+}
+
+// gitAuthEnv returns the environment which configures git to authenticate using the credentials,
+// e.g. "user:token" or "token". They're passed as a header in the environment rather than in the
+// url so they aren't in the arguments of the process, which any user can list.
+func gitAuthEnv(creds string) []string {
+	if !strings.Contains(creds, ":") {
+		creds += ":"
+	}
+	header := "Authorization: Basic " + base64.StdEncoding.EncodeToString([]byte(creds))
+	return []string{
+		"GIT_CONFIG_COUNT=1",
+		"GIT_CONFIG_KEY_0=http.extraHeader",
+		"GIT_CONFIG_VALUE_0=" + header,
+	}
+}
+
+// writeArtifact writes the artifact to the store in chunks
+func writeArtifact(ns, id, path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	buf := make([]byte, artifactChunkSize)
+	for i := 0; ; i++ {
+		n, err := io.ReadFull(f, buf)
+		if err == io.EOF {
+			return nil
+		} else if err != nil && err != io.ErrUnexpectedEOF {
+			return err
+		}
+		if werr := store.Write(&gostore.Record{Key: artifactKey(ns, id, i), Value: buf[:n]}); werr != nil {
+			return werr
+		}
+		if err == io.ErrUnexpectedEOF {
+			return nil
+		}
+	}
+}
+
+// fetchArtifact writes the artifact of the build in the store to the path, unless it's already
+// there
+func fetchArtifact(ns string, b *client.Build, path string) error {
+	if info, err := os.Stat(path); err == nil && info.Size() == b.Size {
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+
+	tmp := path + ".tmp"
+	f, err := os.OpenFile(tmp, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0755)
+	if err != nil {
+		return err
+	}
+	for i := 0; i < artifactChunks(b); i++ {
+		recs, err := store.Read(artifactKey(ns, b.ID, i))
+		if err == nil {
+			_, err = f.Write(recs[0].Value)
+		}
+		if err != nil {
+			f.Close()
+			os.Remove(tmp)
+			return err
+		}
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// deleteArtifact deletes the artifact of the build from the store and the cache
+func deleteArtifact(ns string, b *client.Build) error {
+	for i := 0; i < artifactChunks(b); i++ {
+		if err := store.Delete(artifactKey(ns, b.ID, i)); err != nil && err != gostore.ErrNotFound {
+			return err
+		}
+	}
+	if err := os.Remove(artifactPath(ns, b.ID)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// artifactChunks returns the number of chunks the artifact of the build is written in
+func artifactChunks(b *client.Build) int {
+	return int((b.Size + artifactChunkSize - 1) / artifactChunkSize)
+}
+
+// checkoutBuildSource checks out the source of the service, returning the root of the repo and
+// the directory of the service. Uploaded sources are uncompressed and git sources are checked
+// out at the ref, the same as the local runtime does.
+func checkoutBuildSource(ns string, srv *gorun.Service, ref string, secrets map[string]string) (string, string, error) {
+	// uploaded sources have the format lastfolder.tar.gz or lastfolder.tar.gz/relative/path
+	parts := strings.Split(srv.Source, "/")
+	compressed := filepath.Join(local.SourceDir, parts[0])
+	if strings.HasSuffix(parts[0], ".tar.gz") {
+		if _, err := os.Stat(compressed); err == nil {
+			tarName := strings.TrimSuffix(parts[0], ".tar.gz")
+			root := filepath.Join(buildDir, "src", ns, tarName)
+			if err := os.RemoveAll(root); err != nil {
+				return "", "", err
+			}
+			if err := os.MkdirAll(root, 0755); err != nil {
+				return "", "", err
+			}
+			if err := git.Uncompress(compressed, root); err != nil {
+				return "", "", err
+			}
+
+			dir := filepath.Join(root, tarName)
+			if len(parts) > 1 {
+				dir = filepath.Join(append(append([]string{root}, parts[1:]...), srv.Name)...)
+			}
+			if _, err := os.Stat(filepath.Join(dir, srv.Name)); err == nil {
+				dir = filepath.Join(dir, srv.Name)
+			}
+			return root, dir, nil
+		}
+	}
+
+	source, err := git.ParseSourceLocal("", srv.Source)
+	if err != nil {
+		return "", "", err
+	}
+	source.Ref = ref
+	if err := git.CheckoutSource(filepath.Join(buildDir, "src", ns), source, secrets); err != nil {
+		return "", "", err
+	}
+
+	root := strings.TrimSuffix(source.FullPath, source.Folder)
+	if source.Local {
+		// local folders which aren't in a repo are the root themselves
+		root = source.LocalRepoRoot
+		if len(root) == 0 {
+			root = source.FullPath
+		}
+	}
+	return root, source.FullPath, nil
+}
+
+// hashSum returns the sha256 hash of the go.sum of the service, which is in the directory of
+// the service or one of its parents
+func hashSum(root, dir string) string {
+	h := sha256.New()
+	for d := dir; strings.HasPrefix(d, filepath.Clean(root)); d = filepath.Dir(d) {
+		if b, err := ioutil.ReadFile(filepath.Join(d, "go.sum")); err == nil {
+			h.Write(b)
+			break
+		}
+		if d == filepath.Dir(d) {
+			break
+		}
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// hashCode returns the sha256 hash of the code in the repo, which includes the packages the
+// service may replace with local copies
+func hashCode(root string) (string, error) {
+	h := sha256.New()
+	err := filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			if info.Name() == ".git" {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !strings.HasSuffix(path, ".go") && info.Name() != "go.mod" && info.Name() != "go.sum" {
+			return nil
+		}
+
+		f, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+
+		rel, _ := filepath.Rel(root, path)
+		fmt.Fprintf(h, "%v\n", rel)
+		_, err = io.Copy(h, f)
+		return err
+	})
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// ReadBuilds returns the builds in the namespace, or the builds of the service provided
+func (m *manager) ReadBuilds(name, ns string) ([]*client.Build, error) {
+	builds, err := m.readBuilds(ns)
+	if err != nil {
+		return nil, err
+	}
+
+	var result []*client.Build
+	for _, b := range builds {
+		if len(name) == 0 || b.Name == name {
+			result = append(result, b)
+		}
+	}
+	return result, nil
+}
+
+// PruneBuilds deletes the builds which aren't in use and haven't been used for the duration,
+// including their artifacts
+func (m *manager) PruneBuilds(unusedFor time.Duration, ns string) ([]*client.Build, error) {
+	builds, err := m.readBuilds(ns)
+	if err != nil {
+		return nil, err
+	}
+
+	var pruned []*client.Build
+	for _, b := range builds {
+		if b.InUse || time.Since(b.LastUsed) < unusedFor {
+			continue
+		}
+		if err := m.deleteBuild(ns, b); err != nil {
+			return pruned, err
+		}
+		pruned = append(pruned, b)
+	}
+
+	return pruned, nil
+}
+
+// readBuilds returns the builds in the namespace, oldest first. The latest build of each service
+// or job which exists is marked as in use.
+func (m *manager) readBuilds(ns string) ([]*client.Build, error) {
+	recs, err := store.Read(buildKey(ns, ""), gostore.ReadPrefix())
+	if err != nil {
+		return nil, err
+	}
+
+	builds := make([]*client.Build, 0, len(recs))
+	for _, r := range recs {
+		var b *client.Build
+		if err := json.Unmarshal(r.Value, &b); err != nil {
+			return nil, err
+		}
+		builds = append(builds, b)
+	}
+	sort.Slice(builds, func(i, j int) bool { return builds[i].Created.Before(builds[j].Created) })
+
+	// the services and jobs which can be started from a build
+	exists := make(map[string]bool)
+	srvs, err := m.readServices(ns, &gorun.Service{})
+	if err != nil {
+		return nil, err
+	}
+	for _, s := range srvs {
+		exists[s.Service.Name+":"+s.Service.Version] = true
+	}
+	jobs, err := m.readJobs(ns)
+	if err != nil {
+		return nil, err
+	}
+	for _, j := range jobs {
+		exists[j.Job.Name+":"+j.Job.Version] = true
+	}
+
+	latest := make(map[string]*client.Build)
+	for _, b := range builds {
+		key := b.Name + ":" + b.Version
+		if l, ok := latest[key]; !exists[key] || (ok && l.LastUsed.After(b.LastUsed)) {
+			continue
+		}
+		latest[key] = b
+	}
+	for _, b := range latest {
+		b.InUse = true
+	}
+
+	return builds, nil
+}
+
+// deleteBuild deletes the build and its artifact, the build is deleted first so it isn't found
+// without its artifact
+func (m *manager) deleteBuild(ns string, b *client.Build) error {
+	lock := m.buildLock(ns, b.ID)
+	lock.Lock()
+	defer lock.Unlock()
+
+	if err := store.Delete(buildKey(ns, b.ID)); err != nil {
+		return err
+	}
+	return deleteArtifact(ns, b)
+}
+
+func (m *manager) readBuild(ns, id string) (*client.Build, error) {
+	recs, err := store.Read(buildKey(ns, id))
+	if err != nil {
+		return nil, err
+	}
+	var b *client.Build
+	if err := json.Unmarshal(recs[0].Value, &b); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+func (m *manager) writeBuild(ns string, b *client.Build) error {
+	bytes, err := json.Marshal(b)
+	if err != nil {
+		return err
+	}
+	return store.Write(&gostore.Record{Key: buildKey(ns, b.ID), Value: bytes})
+}
@@ -0,0 +1,127 @@
+package manager
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/micro/go-micro/v3/runtime"
+	gostore "github.com/micro/go-micro/v3/store"
+	"github.com/micro/micro/v3/profile"
+	muruntime "github.com/micro/micro/v3/service/runtime"
+	"github.com/micro/micro/v3/service/store"
+)
+
+func TestBuildService(t *testing.T) {
+	if testing.Short() {
+		t.Skip("builds the source with go build")
+	}
+
+	profile.Test.Setup(nil)
+	muruntime.DefaultRuntime = &testRuntime{}
+	m := New().(*manager)
+
+	dir, err := ioutil.TempDir("", "build-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	buildDir = filepath.Join(dir, "builds")
+
+	src := filepath.Join(dir, "hello")
+	os.MkdirAll(src, 0755)
+	ioutil.WriteFile(filepath.Join(src, "go.mod"), []byte("module hello\n\ngo 1.13\n"), 0644)
+	ioutil.WriteFile(filepath.Join(src, "main.go"), []byte("package main\n\nfunc main() {}\n"), 0644)
+
+	srv := &runtime.Service{Name: "hello", Version: "latest", Source: src}
+	path, err := m.buildService("micro", srv, &runtime.CreateOptions{})
+	if err != nil {
+		t.Fatalf("Unexpected error building service: %v", err)
+	}
+	if _, err := os.Stat(path); err != nil {
+		t.Fatalf("Expected the artifact to exist: %v", err)
+	}
+
+	// the replicas reuse the build
+	replicaPath, err := m.buildService("micro", replica(srv, 1), &runtime.CreateOptions{})
+	if err != nil || replicaPath != path {
+		t.Errorf("Expected the replica to use the build %v, got %v: %v", path, replicaPath, err)
+	}
+
+	// the artifact is fetched from the store if it isn't cached, e.g. by another runtime
+	if err := os.Remove(path); err != nil {
+		t.Fatal(err)
+	}
+	if p, err := m.buildService("micro", srv, &runtime.CreateOptions{}); err != nil || p != path {
+		t.Fatalf("Expected the build %v to be reused, got %v: %v", path, p, err)
+	}
+	if _, err := os.Stat(path); err != nil {
+		t.Fatalf("Expected the artifact to be fetched: %v", err)
+	}
+	if builds, _ := m.ReadBuilds("hello", "micro"); len(builds) != 1 {
+		t.Fatalf("Expected 1 build, got %v", len(builds))
+	}
+
+	// changing the code builds it again
+	ioutil.WriteFile(filepath.Join(src, "main.go"), []byte("package main\n\nfunc main() { println() }\n"), 0644)
+	if p, err := m.buildService("micro", srv, &runtime.CreateOptions{}); err != nil || p == path {
+		t.Errorf("Expected a new build, got %v: %v", p, err)
+	}
+
+	builds, err := m.ReadBuilds("hello", "micro")
+	if err != nil || len(builds) != 2 {
+		t.Fatalf("Expected 2 builds, got %v: %v", len(builds), err)
+	}
+
+	// the service doesn't exist so neither build is in use
+	pruned, err := m.PruneBuilds(0, "micro")
+	if err != nil || len(pruned) != 2 {
+		t.Fatalf("Expected 2 builds to be pruned, got %v: %v", len(pruned), err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("Expected the artifact to be deleted")
+	}
+	if recs, _ := store.Read(artifactPrefix, gostore.ReadPrefix()); len(recs) != 0 {
+		t.Errorf("Expected the artifacts to be deleted from the store, got %v records", len(recs))
+	}
+}
+
+func TestBuildVersion(t *testing.T) {
+	tt := map[string]string{
+		"latest":               "latest",
+		"v1.2" + "-replica-3":  "v1.2",
+		runVersion("main", 12): "main",
+	}
+	for v, exp := range tt {
+		if bv := buildVersion(v); bv != exp {
+			t.Errorf("Expected %v for %v, got %v", exp, v, bv)
+		}
+	}
+}
+
+func TestBuildID(t *testing.T) {
+	srv := &runtime.Service{Name: "hello", Version: "latest", Source: "github.com/micro/services/hello"}
+
+	// a change to the dependencies of the same revision is built again
+	id := buildID("micro", srv, "latest", "3f2a", "sum-1")
+	if id == buildID("micro", srv, "latest", "3f2a", "sum-2") {
+		t.Errorf("Expected the id to change with the go.sum")
+	}
+	if id != buildID("micro", srv, "latest", "3f2a", "sum-1") {
+		t.Errorf("Expected the id to be the same for the same revision and go.sum")
+	}
+}
+
+func TestGitAuthEnv(t *testing.T) {
+	tt := map[string]string{
+		"user:token": "Authorization: Basic dXNlcjp0b2tlbg==",
+		"token":      "Authorization: Basic dG9rZW46",
+	}
+	for creds, header := range tt {
+		env := gitAuthEnv(creds)
+		if len(env) != 3 || env[2] != "GIT_CONFIG_VALUE_0="+header {
+			t.Errorf("Expected the header %v for %v, got %v", header, creds, env)
+		}
+	}
+}
@@ -0,0 +1,208 @@
+package manager
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	goauth "github.com/micro/go-micro/v3/auth"
+	"github.com/micro/go-micro/v3/registry"
+	gorun "github.com/micro/go-micro/v3/runtime"
+	"github.com/micro/micro/v3/service/logger"
+	muregistry "github.com/micro/micro/v3/service/registry"
+	"github.com/micro/micro/v3/service/runtime/client"
+)
+
+const (
+	// statusWaiting is the status of a service which is waiting for its dependencies to become
+	// healthy before it's started
+	statusWaiting = "waiting"
+)
+
+var (
+	// dependencyPollFrequency is how often the dependencies of a waiting service are checked
+	dependencyPollFrequency = time.Second * 2
+)
+
+// checkDependencies returns a DependencyCycleError if the service depending on the services
+// would create a cycle with the dependencies of the services in the namespace
+func (m *manager) checkDependencies(ns, name string, deps []string) error {
+	srvs, err := m.readServices(ns, &gorun.Service{})
+	if err != nil {
+		return err
+	}
+
+	graph := make(map[string][]string)
+	for _, s := range srvs {
+		graph[s.Service.Name] = append(graph[s.Service.Name], s.DependsOn...)
+	}
+	graph[name] = deps
+
+	visited := make(map[string]bool)
+	var visit func(n string, path []string) []string
+	visit = func(n string, path []string) []string {
+		for _, d := range graph[n] {
+			cycle := append(append([]string{}, path...), d)
+			if d == name {
+				return cycle
+			}
+			if visited[d] {
+				continue
+			}
+			visited[d] = true
+			if c := visit(d, cycle); c != nil {
+				return c
+			}
+		}
+		return nil
+	}
+
+	if cycle := visit(name, []string{name}); cycle != nil {
+		return &client.DependencyCycleError{Cycle: cycle}
+	}
+	return nil
+}
+
+// dependencies returns the services the service depends on
+func (m *manager) dependencies(ns string, srv *gorun.Service) []string {
+	version, _ := parseReplica(srv.Version)
+	srvs, err := m.readServices(ns, &gorun.Service{Name: srv.Name, Version: version})
+	if err != nil || len(srvs) == 0 {
+		return nil
+	}
+	return srvs[0].DependsOn
+}
+
+// pendingDependencies returns the dependencies which don't have a healthy node registered
+func pendingDependencies(ns string, deps []string) []string {
+	var pending []string
+
+	for _, dep := range deps {
+		srvs, err := muregistry.GetService(dep, registry.GetDomain(ns))
+		if err != nil {
+			pending = append(pending, dep)
+			continue
+		}
+
+		var ready bool
+		for _, s := range srvs {
+			for _, n := range s.Nodes {
+				if healthy(s, n) {
+					ready = true
+					break
+				}
+			}
+			if ready {
+				break
+			}
+		}
+		if !ready {
+			pending = append(pending, dep)
+		}
+	}
+
+	sort.Strings(pending)
+	return pending
+}
+
+// waitKey returns the key of the waiter of the service
+func waitKey(ns string, srv *gorun.Service) string {
+	version, _ := parseReplica(srv.Version)
+	return ns + ":" + srv.Name + ":" + version
+}
+
+// wait returns the channel which is closed when the service should stop waiting to be started,
+// e.g. because it was deleted. Only one waiter is kept for each service, it replaces any
+// previous waiter which is cancelled.
+func (m *manager) wait(ns string, srv *gorun.Service) <-chan struct{} {
+	m.waiting.Lock()
+	defer m.waiting.Unlock()
+
+	key := waitKey(ns, srv)
+	if ch, ok := m.waiters[key]; ok {
+		close(ch)
+	}
+	ch := make(chan struct{})
+	m.waiters[key] = ch
+	return ch
+}
+
+// cancelWait cancels the waiter of the service if it's waiting to be started
+func (m *manager) cancelWait(ns string, srv *gorun.Service) {
+	m.waiting.Lock()
+	defer m.waiting.Unlock()
+
+	key := waitKey(ns, srv)
+	if ch, ok := m.waiters[key]; ok {
+		close(ch)
+		delete(m.waiters, key)
+	}
+}
+
+// doneWaiting removes the waiter of the service once it stopped waiting, unless it was replaced.
+// It returns false if the waiter was cancelled.
+func (m *manager) doneWaiting(ns string, srv *gorun.Service, cancel <-chan struct{}) bool {
+	m.waiting.Lock()
+	defer m.waiting.Unlock()
+
+	select {
+	case <-cancel:
+		return false
+	default:
+	}
+
+	key := waitKey(ns, srv)
+	if ch, ok := m.waiters[key]; ok && (<-chan struct{})(ch) == cancel {
+		delete(m.waiters, key)
+	}
+	return true
+}
+
+// startWhenReady waits for the dependencies of the service to become healthy and then starts it,
+// until then its status is waiting. It stops waiting if the service is deleted or updated.
+func (m *manager) startWhenReady(ns string, srv *gorun.Service, opts *gorun.CreateOptions, acc *goauth.Account, deps []string, cancel <-chan struct{}) {
+	ticker := time.NewTicker(dependencyPollFrequency)
+	defer ticker.Stop()
+
+	for {
+		pending := pendingDependencies(ns, deps)
+		if len(pending) == 0 {
+			break
+		}
+
+		m.cacheStatus(ns, &gorun.Service{
+			Name:    srv.Name,
+			Version: srv.Version,
+			Source:  srv.Source,
+			Metadata: map[string]string{
+				"status": statusWaiting,
+				"error":  "waiting for " + strings.Join(pending, ", "),
+			},
+		})
+
+		select {
+		case <-ticker.C:
+		case <-cancel:
+			logger.Infof("Stopped waiting to start service %v:%v in namespace %v", srv.Name, srv.Version, ns)
+			return
+		}
+	}
+
+	// the service is started by the update which cancelled the waiter
+	if !m.doneWaiting(ns, srv, cancel) {
+		return
+	}
+
+	logger.Infof("Dependencies of service %v:%v in namespace %v are ready, starting it", srv.Name, srv.Version, ns)
+	err := m.startService(ns, srv, opts, acc)
+	if err == nil {
+		err = m.scaleService(ns, srv)
+	}
+	if err != nil {
+		logger.Warnf("Error starting service %v:%v in namespace %v: %v", srv.Name, srv.Version, ns, err)
+		srv.Metadata = map[string]string{"status": "error", "error": err.Error()}
+		m.cacheStatus(ns, srv)
+		return
+	}
+	m.refreshStatus(ns)
+}
@@ -0,0 +1,82 @@
+package manager
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/micro/go-micro/v3/runtime"
+	"github.com/micro/micro/v3/profile"
+	"github.com/micro/micro/v3/service/runtime/client"
+)
+
+func TestCheckDependencies(t *testing.T) {
+	profile.Test.Setup(nil)
+	m := New().(*manager)
+
+	write := func(name string, deps ...string) {
+		err := m.writeService(&service{
+			Service:   &runtime.Service{Name: name, Version: "latest"},
+			Options:   &runtime.CreateOptions{Namespace: "deps"},
+			DependsOn: deps,
+		})
+		if err != nil {
+			t.Fatalf("Unexpected error writing service: %v", err)
+		}
+	}
+	write("store")
+	write("api", "users")
+	write("users", "store")
+
+	if err := m.checkDependencies("deps", "orders", []string{"users", "store"}); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if err := m.checkDependencies("deps", "store", []string{"store"}); err == nil {
+		t.Errorf("Expected an error for a service depending on itself")
+	}
+
+	err := m.checkDependencies("deps", "store", []string{"api"})
+	cerr, ok := err.(*client.DependencyCycleError)
+	if !ok {
+		t.Fatalf("Expected a dependency cycle error, got %v", err)
+	}
+	if exp := []string{"store", "api", "users", "store"}; !reflect.DeepEqual(cerr.Cycle, exp) {
+		t.Errorf("Expected the cycle %v, got %v", exp, cerr.Cycle)
+	}
+}
+
+func TestWait(t *testing.T) {
+	profile.Test.Setup(nil)
+	m := New().(*manager)
+	srv := &runtime.Service{Name: "api", Version: "latest"}
+
+	// a service waiting for dependencies which never become healthy stops when it's cancelled
+	done := make(chan bool)
+	go func() {
+		m.startWhenReady("deps", srv, &runtime.CreateOptions{}, nil, []string{"users"}, m.wait("deps", srv))
+		close(done)
+	}()
+	time.Sleep(time.Millisecond * 50)
+	m.cancelWait("deps", srv)
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatalf("Expected the waiter to be cancelled")
+	}
+
+	// only one waiter is kept for each service
+	first := m.wait("deps", srv)
+	second := m.wait("deps", &runtime.Service{Name: "api", Version: replicaVersion("latest", 1)})
+	select {
+	case <-first:
+	default:
+		t.Errorf("Expected the first waiter to be cancelled when it's replaced")
+	}
+	if !m.doneWaiting("deps", srv, second) {
+		t.Errorf("Expected the second waiter not to be cancelled")
+	}
+	if len(m.waiters) != 0 {
+		t.Errorf("Expected the waiter to be removed, got %v", len(m.waiters))
+	}
+}
@@ -2,6 +2,8 @@ package manager
 
 import (
 	"encoding/json"
+	"fmt"
+	"strconv"
 	"strings"
 	"time"
 
@@ -9,6 +11,7 @@ import (
 	goauth "github.com/micro/go-micro/v3/auth"
 	gorun "github.com/micro/go-micro/v3/runtime"
 	gostore "github.com/micro/go-micro/v3/store"
+	"github.com/micro/micro/v3/internal/cgroup"
 	"github.com/micro/micro/v3/internal/namespace"
 	"github.com/micro/micro/v3/service/client"
 	"github.com/micro/micro/v3/service/logger"
@@ -108,14 +111,42 @@ func (m *manager) processEvent(key string) {
 	}
 
 	// log the event
-	logger.Infof("Processing %v event for service %v:%v in namespace %v", ev.Type, ev.Service.Name, ev.Service.Version, ns)
+	logger.Infof("Processing %v event for service %v:%v in namespace %v", eventName(ev.Type), ev.Service.Name, ev.Service.Version, ns)
 
 	// apply the event to the managed runtime
+	var waiting bool
 	switch ev.Type {
 	case gorun.Delete:
+		m.cancelWait(ns, ev.Service)
 		err = runtime.Delete(ev.Service, gorun.DeleteNamespace(ns))
+		m.deleteCgroup(ns, ev.Service)
+		if err == nil {
+			err = m.deleteReplicas(ns, ev.Service)
+		}
 	case gorun.Update:
-		err = runtime.Update(ev.Service, gorun.UpdateNamespace(ns))
+		m.cancelWait(ns, ev.Service)
+
+		// changing the resources requires the service to be recreated
+		if ev.Options != nil && ev.Options.Resources != nil {
+			err = m.restartService(ns, ev.Service)
+		} else {
+			// rolling updates wait for the new replicas to become healthy so they're
+			// applied in the background rather than blocking other events
+			go func(srv *gorun.Service) {
+				if err := m.rollingUpdate(ns, srv, nil); err != nil {
+					logger.Warnf("Error updating service %v:%v in namespace %v: %v", srv.Name, srv.Version, ns, err)
+				}
+				m.refreshStatus(ns)
+			}(ev.Service)
+		}
+	case eventRollback:
+		m.cancelWait(ns, ev.Service)
+		go func(srv *gorun.Service, opts *gorun.CreateOptions) {
+			if err := m.rollingUpdate(ns, srv, opts); err != nil {
+				logger.Warnf("Error rolling back service %v:%v in namespace %v: %v", srv.Name, srv.Version, ns, err)
+			}
+			m.refreshStatus(ns)
+		}(ev.Service, ev.Options)
 	case gorun.Create:
 		// generate an auth account for the service to use
 		var acc *goauth.Account
@@ -124,38 +155,37 @@ func (m *manager) processEvent(key string) {
 			return
 		}
 
-		// construct the options
-		options := []gorun.CreateOption{
-			gorun.CreateImage(ev.Options.Image),
-			gorun.CreateType(ev.Options.Type),
-			gorun.CreateNamespace(ns),
-			gorun.WithArgs(ev.Options.Args...),
-			gorun.WithCommand(ev.Options.Command...),
-			gorun.WithEnv(m.runtimeEnv(ev.Service, ev.Options)),
+		// services which depend on others are started once they're healthy rather than
+		// crashing until they are
+		if deps := m.dependencies(ns, ev.Service); len(deps) > 0 {
+			waiting = true
+			go m.startWhenReady(ns, ev.Service, ev.Options, acc, deps, m.wait(ns, ev.Service))
+			break
 		}
 
-		// inject the credentials into the service if present
-		if len(acc.ID) > 0 && len(acc.Secret) > 0 {
-			options = append(options, gorun.WithSecret("MICRO_AUTH_ID", acc.ID))
-			options = append(options, gorun.WithSecret("MICRO_AUTH_SECRET", acc.Secret))
+		// services started from a build of their source are started once it's built
+		if shouldBuild(ev.Service, ev.Options) {
+			waiting = true
+			go m.buildAndStart(ns, ev.Service, ev.Options, acc, m.wait(ns, ev.Service))
+			break
 		}
 
-		// add the secrets
-		for key, value := range ev.Options.Secrets {
-			options = append(options, gorun.WithSecret(key, value))
+		err = m.startService(ns, ev.Service, ev.Options, acc)
+		if err == nil {
+			err = m.scaleService(ns, ev.Service)
 		}
-
-		// create the service
-		err = runtime.Create(ev.Service, options...)
+	case eventScale:
+		err = m.scaleService(ns, ev.Service)
 	}
 
-	// if there was an error update the status in the cache
+	// if there was an error update the status in the cache, otherwise cache the status the
+	// runtime reports once the event has been applied so the change is published
 	if err != nil {
-		logger.Warnf("Error processing %v event for service %v:%v in namespace %v: %v", ev.Type, ev.Service.Name, ev.Service.Version, ns, err)
+		logger.Warnf("Error processing %v event for service %v:%v in namespace %v: %v", eventName(ev.Type), ev.Service.Name, ev.Service.Version, ns, err)
 		ev.Service.Metadata = map[string]string{"status": "error", "error": err.Error()}
 		m.cacheStatus(ns, ev.Service)
-	} else if ev.Type != gorun.Delete {
-		m.cacheStatus(ns, ev.Service)
+	} else if !waiting {
+		m.refreshStatus(ns)
 	}
 
 	// write to the store indicating the event has been consumed. We double the ttl to safely know the
@@ -164,6 +194,96 @@ func (m *manager) processEvent(key string) {
 
 }
 
+// startService creates the service in the managed runtime using the account provided
+func (m *manager) startService(ns string, srv *gorun.Service, opts *gorun.CreateOptions, acc *goauth.Account) error {
+	// construct the options
+	options := []gorun.CreateOption{
+		gorun.CreateImage(opts.Image),
+		gorun.CreateType(opts.Type),
+		gorun.CreateNamespace(ns),
+		gorun.WithEnv(m.runtimeEnv(srv, opts)),
+	}
+
+	// services are started from a build of their source which is shared by the replicas
+	command, args := opts.Command, opts.Args
+	if shouldBuild(srv, opts) {
+		path, err := m.buildService(ns, srv, opts)
+		if err != nil {
+			return err
+		}
+		command, args = []string{path}, nil
+	}
+
+	// the local runtime starts processes again when they exit, runs of jobs are wrapped so the
+	// command is only run once
+	if runtime.DefaultRuntime.String() == "local" && isJobRun(opts) && len(command) > 0 {
+		var err error
+		if command, args, err = runOnce(ns, srv, command, args); err != nil {
+			return err
+		}
+	}
+
+	// processes of the local runtime are moved into the cgroup which enforces the resource
+	// limits before the command is run, so the command and its children can't run outside it.
+	// Services with a source are always built so there's a command unless there's no source.
+	if len(command) > 0 {
+		if path := m.createCgroup(srv, opts); len(path) > 0 {
+			command, args = cgroup.Command(path, command, args)
+		}
+	} else if opts.Resources != nil && runtime.DefaultRuntime.String() == "local" {
+		logger.Warnf("Unable to enforce the resource limits of service %v:%v without a source or command", srv.Name, srv.Version)
+	}
+	options = append(options, gorun.WithCommand(command...), gorun.WithArgs(args...))
+
+	// limit the resources of the service
+	if opts.Resources != nil {
+		options = append(options, gorun.ResourceLimits(opts.Resources))
+	}
+
+	// inject the credentials into the service if present
+	if len(acc.ID) > 0 && len(acc.Secret) > 0 {
+		options = append(options, gorun.WithSecret("MICRO_AUTH_ID", acc.ID))
+		options = append(options, gorun.WithSecret("MICRO_AUTH_SECRET", acc.Secret))
+	}
+
+	// add the secrets provided by the client
+	for key, value := range opts.Secrets {
+		options = append(options, gorun.WithSecret(key, value))
+	}
+
+	// create the service
+	return runtime.Create(srv, options...)
+}
+
+// restartService deletes the service and its replicas from the managed runtime and creates them
+// again using the options in the store
+func (m *manager) restartService(ns string, srv *gorun.Service) error {
+	srvs, err := m.readServices(ns, srv)
+	if err != nil {
+		return err
+	} else if len(srvs) == 0 {
+		return fmt.Errorf("service %v:%v not found", srv.Name, srv.Version)
+	}
+
+	if err := runtime.Delete(srv, gorun.DeleteNamespace(ns)); err != nil {
+		return err
+	}
+	m.deleteCgroup(ns, srv)
+	if err := m.deleteReplicas(ns, srv); err != nil {
+		return err
+	}
+
+	acc, err := m.generateAccount(srv, ns)
+	if err != nil {
+		return err
+	}
+	if err := m.startService(ns, srvs[0].Service, srvs[0].Options, acc); err != nil {
+		return err
+	}
+
+	return m.scaleService(ns, srv)
+}
+
 // runtimeEnv returns the environment variables which should  be used when creating a service.
 func (m *manager) runtimeEnv(srv *gorun.Service, options *gorun.CreateOptions) []string {
 	setEnv := func(p []string, env map[string]string) {
@@ -176,6 +296,9 @@ func (m *manager) runtimeEnv(srv *gorun.Service, options *gorun.CreateOptions) [
 		}
 	}
 
+	// replicas share the version of the service
+	version, replica := parseReplica(srv.Version)
+
 	// overwrite any values
 	env := map[string]string{
 		// ensure a profile for the services isn't set, they
@@ -183,7 +306,8 @@ func (m *manager) runtimeEnv(srv *gorun.Service, options *gorun.CreateOptions) [
 		"MICRO_PROFILE": "",
 		// pass the service's name and version
 		"MICRO_SERVICE_NAME":    nameFromService(srv.Name),
-		"MICRO_SERVICE_VERSION": srv.Version,
+		"MICRO_SERVICE_VERSION": version,
+		replicaEnvVar:           strconv.Itoa(replica),
 		// set the proxy for the service to use (e.g. micro network)
 		// using the proxy which has been configured for the runtime
 		"MICRO_PROXY": client.DefaultClient.Options().Proxy,
@@ -0,0 +1,194 @@
+package manager
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	gorun "github.com/micro/go-micro/v3/runtime"
+	gostore "github.com/micro/go-micro/v3/store"
+	"github.com/micro/micro/v3/internal/namespace"
+	"github.com/micro/micro/v3/service/logger"
+	"github.com/micro/micro/v3/service/runtime/client"
+	"github.com/micro/micro/v3/service/store"
+)
+
+const (
+	// revisionPrefix is prefixed to the key for revision records
+	revisionPrefix = "revision:"
+	// revisionMetadata is the metadata key of the revision of the source the service is pinned
+	// to, its replicas are started from it rather than the latest revision of the source
+	revisionMetadata = "revision"
+	// eventRollback is published when a service is rolled back to a previous revision
+	eventRollback gorun.EventType = 101
+)
+
+// revisionLimit is the number of revisions kept for each service
+var revisionLimit = 20
+
+// revisionKey returns the key of the revision in the store, the number is padded
+// so the revisions are listed in order e.g. "revision:micro:foo:latest:00000001"
+func revisionKey(ns string, srv *gorun.Service, number int) string {
+	return fmt.Sprintf("%v%v:%v:%v:%08d", revisionPrefix, ns, srv.Name, srv.Version, number)
+}
+
+// pinRevision resolves the revision of the source of the service, e.g. the commit its ref points
+// to, and pins the service to it so the replicas and the recorded revision use the same source
+func pinRevision(srv *gorun.Service, opts *gorun.CreateOptions) {
+	if srv.Metadata == nil {
+		srv.Metadata = make(map[string]string)
+	}
+	delete(srv.Metadata, revisionMetadata)
+	if len(srv.Source) == 0 {
+		return
+	}
+
+	rev, err := sourceRevision(srv, buildVersion(srv.Version), opts.Secrets)
+	if err != nil {
+		logger.Warnf("Error resolving the revision of service %v:%v: %v", srv.Name, srv.Version, err)
+		return
+	}
+	srv.Metadata[revisionMetadata] = rev
+}
+
+// recordRevision writes a revision of the service to the store, removing the oldest
+// revisions once there are more than the revisionLimit
+func (m *manager) recordRevision(s *service, deployedBy string) error {
+	revisions, err := m.readRevisions(s.Options.Namespace, s.Service)
+	if err != nil {
+		return err
+	}
+
+	rev := &client.Revision{
+		Number:     1,
+		Source:     s.Service.Source,
+		Revision:   s.Service.Metadata[revisionMetadata],
+		Image:      s.Options.Image,
+		Env:        s.Options.Env,
+		DeployedBy: deployedBy,
+		Deployed:   time.Now(),
+	}
+	// the build is only known if the revision has been built before
+	if len(rev.Revision) > 0 && shouldBuild(s.Service, s.Options) {
+		ns, version := s.Options.Namespace, s.Service.Version
+		if sum, err := readBuildSum(ns, revisionID(ns, s.Service, version, rev.Revision)); err == nil {
+			rev.Build = buildID(ns, s.Service, version, rev.Revision, sum)
+		}
+	}
+	if len(revisions) > 0 {
+		rev.Number = revisions[len(revisions)-1].Number + 1
+	}
+
+	bytes, err := json.Marshal(rev)
+	if err != nil {
+		return err
+	}
+	key := revisionKey(s.Options.Namespace, s.Service, rev.Number)
+	if err := store.Write(&gostore.Record{Key: key, Value: bytes}); err != nil {
+		return err
+	}
+
+	for i := 0; i < len(revisions)+1-revisionLimit; i++ {
+		store.Delete(revisionKey(s.Options.Namespace, s.Service, revisions[i].Number))
+	}
+
+	return nil
+}
+
+// readRevisions returns the revisions of the service in the store, oldest first
+func (m *manager) readRevisions(ns string, srv *gorun.Service) ([]*client.Revision, error) {
+	prefix := fmt.Sprintf("%v%v:%v:%v:", revisionPrefix, ns, srv.Name, srv.Version)
+	recs, err := store.Read(prefix, gostore.ReadPrefix())
+	if err != nil {
+		return nil, err
+	}
+
+	revisions := make([]*client.Revision, 0, len(recs))
+	for _, r := range recs {
+		var rev *client.Revision
+		if err := json.Unmarshal(r.Value, &rev); err != nil {
+			return nil, err
+		}
+		revisions = append(revisions, rev)
+	}
+
+	return revisions, nil
+}
+
+// History returns the revisions of the service, oldest first
+func (m *manager) History(srv *gorun.Service, ns string) ([]*client.Revision, error) {
+	if len(ns) == 0 {
+		ns = namespace.DefaultNamespace
+	}
+	if len(srv.Version) == 0 {
+		srv.Version = "latest"
+	}
+
+	return m.readRevisions(ns, srv)
+}
+
+// Rollback redeploys a revision of the service using a rolling update, zero is the revision before
+// the current one. The rollback is recorded as a new revision once it completes.
+func (m *manager) Rollback(srv *gorun.Service, revision int, ns string) error {
+	if len(ns) == 0 {
+		ns = namespace.DefaultNamespace
+	}
+	if len(srv.Version) == 0 {
+		srv.Version = "latest"
+	}
+
+	srvs, err := m.readServices(ns, srv)
+	if err != nil {
+		return err
+	} else if len(srvs) == 0 {
+		return fmt.Errorf("service %v:%v not found", srv.Name, srv.Version)
+	}
+
+	revisions, err := m.readRevisions(ns, srv)
+	if err != nil {
+		return err
+	}
+
+	target, err := findRevision(revisions, revision)
+	if err != nil {
+		return err
+	}
+
+	// the event carries the spec of the revision to deploy, which is pinned to the revision of
+	// the source it was deployed from
+	md := make(map[string]string, len(srv.Metadata)+1)
+	for k, v := range srv.Metadata {
+		md[k] = v
+	}
+	if len(target.Revision) > 0 {
+		md[revisionMetadata] = target.Revision
+	}
+
+	return m.publishEvent(eventRollback, &gorun.Service{
+		Name:     srv.Name,
+		Version:  srv.Version,
+		Source:   target.Source,
+		Metadata: md,
+	}, &gorun.CreateOptions{
+		Namespace: ns,
+		Image:     target.Image,
+		Env:       target.Env,
+	})
+}
+
+// findRevision returns the revision with the number, zero returns the revision before the latest
+func findRevision(revisions []*client.Revision, number int) (*client.Revision, error) {
+	if number == 0 {
+		if len(revisions) < 2 {
+			return nil, client.ErrRevisionNotFound
+		}
+		return revisions[len(revisions)-2], nil
+	}
+
+	for _, rev := range revisions {
+		if rev.Number == number {
+			return rev, nil
+		}
+	}
+	return nil, client.ErrRevisionNotFound
+}
@@ -0,0 +1,77 @@
+package manager
+
+import (
+	"testing"
+
+	"github.com/micro/go-micro/v3/runtime"
+	"github.com/micro/micro/v3/profile"
+	muruntime "github.com/micro/micro/v3/service/runtime"
+	"github.com/micro/micro/v3/service/runtime/client"
+)
+
+func TestHistory(t *testing.T) {
+	profile.Test.Setup(nil)
+	muruntime.DefaultRuntime = &testRuntime{}
+	m := New().(*manager)
+
+	revisionLimit = 3
+	defer func() { revisionLimit = 20 }()
+
+	srv := &service{
+		Service: &runtime.Service{Name: "foo", Version: "latest", Source: "foo.tar.gz"},
+		Options: &runtime.CreateOptions{Namespace: "history", Image: "micro/cells:v1"},
+	}
+
+	for _, image := range []string{"micro/cells:v1", "micro/cells:v2", "micro/cells:v3", "micro/cells:v4"} {
+		srv.Options.Image = image
+		if err := m.recordRevision(srv, "john"); err != nil {
+			t.Fatalf("Unexpected error recording revision: %v", err)
+		}
+	}
+
+	revisions, err := m.History(srv.Service, "history")
+	if err != nil {
+		t.Fatalf("Unexpected error reading the history: %v", err)
+	}
+	if len(revisions) != 3 {
+		t.Fatalf("Expected the history to be limited to 3 revisions, got %v", len(revisions))
+	}
+	if revisions[0].Number != 2 || revisions[2].Number != 4 {
+		t.Errorf("Expected revisions 2 to 4, got %v to %v", revisions[0].Number, revisions[2].Number)
+	}
+	if revisions[2].Image != "micro/cells:v4" || revisions[2].DeployedBy != "john" {
+		t.Errorf("Unexpected latest revision %+v", revisions[2])
+	}
+
+	// zero is the revision before the latest
+	if rev, err := findRevision(revisions, 0); err != nil || rev.Number != 3 {
+		t.Errorf("Expected the previous revision to be 3, got %+v: %v", rev, err)
+	}
+	if rev, err := findRevision(revisions, 2); err != nil || rev.Image != "micro/cells:v2" {
+		t.Errorf("Expected revision 2, got %+v: %v", rev, err)
+	}
+	if _, err := findRevision(revisions, 1); err != client.ErrRevisionNotFound {
+		t.Errorf("Expected revision 1 to have been removed, got %v", err)
+	}
+	if _, err := findRevision(revisions[:1], 0); err != client.ErrRevisionNotFound {
+		t.Errorf("Expected no previous revision, got %v", err)
+	}
+
+	// revisions record the revision of the source the service is pinned to
+	srv.Service.Metadata = map[string]string{revisionMetadata: "3f2a1b"}
+	if err := m.recordRevision(srv, "john"); err != nil {
+		t.Fatalf("Unexpected error recording revision: %v", err)
+	}
+
+	// the history is kept when the service is deleted so it continues if it's created again
+	if err := m.Delete(srv.Service, runtime.DeleteNamespace("history")); err != nil {
+		t.Fatalf("Unexpected error deleting the service: %v", err)
+	}
+	revisions, err = m.History(srv.Service, "history")
+	if err != nil || len(revisions) != 3 {
+		t.Fatalf("Expected the history to be kept, got %v revisions: %v", len(revisions), err)
+	}
+	if rev := revisions[2]; rev.Number != 5 || rev.Revision != "3f2a1b" {
+		t.Errorf("Expected revision 5 of 3f2a1b, got %+v", rev)
+	}
+}
@@ -0,0 +1,666 @@
+package manager
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	gorun "github.com/micro/go-micro/v3/runtime"
+	gostore "github.com/micro/go-micro/v3/store"
+	"github.com/micro/micro/v3/internal/cron"
+	"github.com/micro/micro/v3/internal/namespace"
+	"github.com/micro/micro/v3/service/logger"
+	"github.com/micro/micro/v3/service/runtime"
+	"github.com/micro/micro/v3/service/runtime/client"
+	"github.com/micro/micro/v3/service/store"
+)
+
+const (
+	// jobPrefix is prefixed to the key for job records
+	jobPrefix = "job:"
+	// jobRunPrefix is prefixed to the key for the records of the runs of jobs
+	jobRunPrefix = "jobrun:"
+	// runSeparator separates the version of a job from the number of the run, each run is
+	// created in the runtime with a version unique to it e.g. "latest-run-3"
+	runSeparator = "-run-"
+	// jobRunEnvVar is set to the number of the run the process is running
+	jobRunEnvVar = "MICRO_JOB_RUN"
+)
+
+var (
+	// jobPollFrequency is how often the schedules of jobs and the status of their runs are checked
+	jobPollFrequency = time.Second
+	// jobRunLimit is the number of finished runs kept for each job
+	jobRunLimit = 20
+	// jobLogLines is the number of lines of logs kept for each run
+	jobLogLines = 200
+	// jobLogTimeout is how long to wait for the logs of a run to be read
+	jobLogTimeout = time.Second * 2
+
+	// runOnceDir is the directory the markers of the runs started by the local runtime are kept in
+	runOnceDir = filepath.Join(os.TempDir(), "micro", "jobs")
+	// runOnceScript runs the command unless the marker passed as $0 exists. The marker is created
+	// before the command so it isn't run again when the local runtime restarts the process after
+	// it exits successfully, processes which fail aren't restarted since runs have no retries.
+	runOnceScript = `[ -e "$0" ] && exit 0; touch "$0" && exec "$@"`
+
+	// exitCodeRe extracts the exit code from the error of a process which exited
+	exitCodeRe = regexp.MustCompile(`exit status (\d+)`)
+)
+
+// job is the object persisted in the store
+type job struct {
+	Job       *client.Job `json:"job"`
+	Namespace string      `json:"namespace"`
+	// Scheduled is when the job was last due, the next run is calculated from it
+	Scheduled time.Time `json:"scheduled"`
+}
+
+// jobRun is a run of a job persisted in the store along with its logs
+type jobRun struct {
+	Run  *client.JobRun `json:"run"`
+	Logs []gorun.Log    `json:"logs"`
+}
+
+// jobKey returns the key of the job in the store e.g. "job:micro:backup"
+func jobKey(ns, name string) string {
+	return jobPrefix + ns + ":" + name
+}
+
+// jobRunKey returns the key of the run in the store, the number is padded so the runs are listed
+// in order e.g. "jobrun:micro:backup:00000003"
+func jobRunKey(ns, name string, number int) string {
+	return fmt.Sprintf("%v%v:%v:%08d", jobRunPrefix, ns, name, number)
+}
+
+// runVersion returns the version of the run of a job with the version
+func runVersion(version string, number int) string {
+	return version + runSeparator + strconv.Itoa(number)
+}
+
+// runService returns the service the run of the job is created as in the runtime
+func runService(j *job, number int) *gorun.Service {
+	md := make(map[string]string, len(j.Job.Metadata))
+	for k, v := range j.Job.Metadata {
+		md[k] = v
+	}
+
+	return &gorun.Service{
+		Name:     j.Job.Name,
+		Version:  runVersion(j.Job.Version, number),
+		Source:   j.Job.Source,
+		Metadata: md,
+	}
+}
+
+// isJobRun returns true if the options are those of a run of a job
+func isJobRun(opts *gorun.CreateOptions) bool {
+	for _, e := range opts.Env {
+		if strings.HasPrefix(e, jobRunEnvVar+"=") {
+			return true
+		}
+	}
+	return false
+}
+
+// runMarker returns the path of the marker of the run created as the service
+func runMarker(ns string, srv *gorun.Service) string {
+	name := strings.Replace(srv.Name, "/", "-", -1)
+	return filepath.Join(runOnceDir, ns, name+"-"+srv.Version)
+}
+
+// runOnce returns the command and args which run the command of a run of a job once, however
+// many times the process is started
+func runOnce(ns string, srv *gorun.Service, command, args []string) ([]string, []string, error) {
+	marker := runMarker(ns, srv)
+	if err := os.MkdirAll(filepath.Dir(marker), 0755); err != nil {
+		return nil, nil, err
+	}
+	// a run which was created again, e.g. after a failed start, is run
+	os.Remove(marker)
+
+	args = append(append([]string{"-c", runOnceScript, marker}, command...), args...)
+	return []string{"/bin/sh"}, args, nil
+}
+
+// CreateJob creates the job or replaces it if it exists, the runs of a replaced job are kept
+func (m *manager) CreateJob(j *client.Job, ns string) error {
+	if len(ns) == 0 {
+		ns = namespace.DefaultNamespace
+	}
+	if len(j.Name) == 0 {
+		return fmt.Errorf("missing job name")
+	}
+	if len(j.Version) == 0 {
+		j.Version = "latest"
+	}
+	if len(j.Schedule) > 0 {
+		if _, err := cron.Parse(j.Schedule); err != nil {
+			return err
+		}
+	}
+	switch j.ConcurrencyPolicy {
+	case "":
+		j.ConcurrencyPolicy = client.ConcurrencyAllow
+	case client.ConcurrencyAllow, client.ConcurrencyForbid, client.ConcurrencyReplace:
+	default:
+		return fmt.Errorf("invalid concurrency policy %v", j.ConcurrencyPolicy)
+	}
+	if j.Options == nil {
+		j.Options = &gorun.CreateOptions{}
+	}
+	j.Options.Namespace = ns
+
+	m.jobs.Lock()
+	defer m.jobs.Unlock()
+
+	// the schedule starts from when the job was created
+	obj := &job{Job: j, Namespace: ns, Scheduled: time.Now()}
+	if prev, err := m.readJob(ns, j.Name); err == nil {
+		obj.Scheduled = prev.Scheduled
+	} else if err != client.ErrJobNotFound {
+		return err
+	}
+
+	return m.writeJob(obj)
+}
+
+// ReadJobs returns the jobs in the namespace, or the job with the name provided
+func (m *manager) ReadJobs(name, ns string) ([]*client.Job, error) {
+	if len(ns) == 0 {
+		ns = namespace.DefaultNamespace
+	}
+
+	var jobs []*job
+	if len(name) > 0 {
+		j, err := m.readJob(ns, name)
+		if err == client.ErrJobNotFound {
+			return []*client.Job{}, nil
+		} else if err != nil {
+			return nil, err
+		}
+		jobs = []*job{j}
+	} else {
+		var err error
+		if jobs, err = m.readJobs(ns); err != nil {
+			return nil, err
+		}
+	}
+
+	ret := make([]*client.Job, 0, len(jobs))
+	for _, j := range jobs {
+		runs, err := m.readJobRuns(ns, j.Job.Name)
+		if err != nil {
+			return nil, err
+		}
+		if len(runs) > 0 {
+			j.Job.LastRun = runs[len(runs)-1].Run
+		}
+		j.Job.Next = nextRun(j)
+		ret = append(ret, j.Job)
+	}
+
+	return ret, nil
+}
+
+// DeleteJob deletes the job and its runs, stopping any which are running
+func (m *manager) DeleteJob(name, ns string) error {
+	if len(ns) == 0 {
+		ns = namespace.DefaultNamespace
+	}
+
+	m.jobs.Lock()
+	defer m.jobs.Unlock()
+
+	j, err := m.readJob(ns, name)
+	if err != nil {
+		return err
+	}
+
+	runs, err := m.readJobRuns(ns, name)
+	if err != nil {
+		return err
+	}
+	for _, r := range runs {
+		if r.Run.Status == client.JobRunning {
+			m.stopRun(j, r.Run)
+		}
+		if err := store.Delete(jobRunKey(ns, name, r.Run.Number)); err != nil {
+			return err
+		}
+	}
+
+	return store.Delete(jobKey(ns, name))
+}
+
+// TriggerJob starts a run of the job now
+func (m *manager) TriggerJob(name, ns string) (*client.JobRun, error) {
+	if len(ns) == 0 {
+		ns = namespace.DefaultNamespace
+	}
+
+	m.jobs.Lock()
+	defer m.jobs.Unlock()
+
+	j, err := m.readJob(ns, name)
+	if err != nil {
+		return nil, err
+	}
+	return m.startRun(j, client.TriggerManual)
+}
+
+// JobRuns returns the runs of the job, oldest first
+func (m *manager) JobRuns(name, ns string) ([]*client.JobRun, error) {
+	if len(ns) == 0 {
+		ns = namespace.DefaultNamespace
+	}
+
+	if _, err := m.readJob(ns, name); err != nil {
+		return nil, err
+	}
+
+	runs, err := m.readJobRuns(ns, name)
+	if err != nil {
+		return nil, err
+	}
+
+	ret := make([]*client.JobRun, 0, len(runs))
+	for _, r := range runs {
+		ret = append(ret, r.Run)
+	}
+	return ret, nil
+}
+
+// JobLogs returns the logs of a run of the job, zero is the latest run. The logs of runs which
+// haven't finished are read from the runtime.
+func (m *manager) JobLogs(name string, number int, ns string) ([]gorun.Log, error) {
+	if len(ns) == 0 {
+		ns = namespace.DefaultNamespace
+	}
+
+	j, err := m.readJob(ns, name)
+	if err != nil {
+		return nil, err
+	}
+
+	runs, err := m.readJobRuns(ns, name)
+	if err != nil {
+		return nil, err
+	} else if len(runs) == 0 {
+		return nil, fmt.Errorf("job %v hasn't run", name)
+	}
+
+	r := runs[len(runs)-1]
+	if number > 0 {
+		r = nil
+		for _, run := range runs {
+			if run.Run.Number == number {
+				r = run
+				break
+			}
+		}
+		if r == nil {
+			return nil, fmt.Errorf("run %d of job %v not found", number, name)
+		}
+	}
+
+	if r.Run.Status == client.JobRunning {
+		return m.runLogs(ns, runService(j, r.Run.Number)), nil
+	}
+	return r.Logs, nil
+}
+
+// watchJobs starts the runs of jobs when they're due and records the result of the runs when they
+// finish, it should be run in a seperate go routine
+func (m *manager) watchJobs() {
+	ticker := time.NewTicker(jobPollFrequency)
+	defer ticker.Stop()
+
+	for {
+		<-ticker.C
+		m.syncJobs()
+	}
+}
+
+// syncJobs checks the runs and schedules of the jobs in every namespace
+func (m *manager) syncJobs() {
+	recs, err := store.Read(jobPrefix, gostore.ReadPrefix())
+	if err != nil {
+		logger.Warnf("Error listing jobs: %v", err)
+		return
+	}
+
+	m.jobs.Lock()
+	defer m.jobs.Unlock()
+
+	for _, rec := range recs {
+		var j *job
+		if err := json.Unmarshal(rec.Value, &j); err != nil {
+			logger.Warnf("Error unmarshaling job %v: %v", rec.Key, err)
+			continue
+		}
+
+		if err := m.syncJobRuns(j); err != nil {
+			logger.Warnf("Error checking the runs of job %v in namespace %v: %v", j.Job.Name, j.Namespace, err)
+		}
+		if err := m.scheduleJob(j); err != nil {
+			logger.Warnf("Error scheduling job %v in namespace %v: %v", j.Job.Name, j.Namespace, err)
+		}
+	}
+}
+
+// scheduleJob starts a run of the job if it's due. Runs which were missed, e.g. while the
+// runtime was down, result in a single run.
+func (m *manager) scheduleJob(j *job) error {
+	next := nextRun(j)
+	if next.IsZero() || time.Now().Before(next) {
+		return nil
+	}
+
+	j.Scheduled = time.Now()
+	if err := m.writeJob(j); err != nil {
+		return err
+	}
+
+	if _, err := m.startRun(j, client.TriggerSchedule); err == client.ErrJobRunning {
+		logger.Infof("Skipping run of job %v in namespace %v, the previous run hasn't finished", j.Job.Name, j.Namespace)
+	} else if err != nil {
+		return err
+	}
+	return nil
+}
+
+// nextRun returns when the job is next due, the zero time is returned for jobs without a schedule
+func nextRun(j *job) time.Time {
+	if len(j.Job.Schedule) == 0 {
+		return time.Time{}
+	}
+	sched, err := cron.Parse(j.Job.Schedule)
+	if err != nil {
+		return time.Time{}
+	}
+	return sched.Next(j.Scheduled)
+}
+
+// startRun creates a run of the job in the managed runtime according to the concurrency policy
+// of the job, the jobs lock should be held by the caller
+func (m *manager) startRun(j *job, trigger string) (*client.JobRun, error) {
+	runs, err := m.readJobRuns(j.Namespace, j.Job.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	var running []*client.JobRun
+	for _, r := range runs {
+		if r.Run.Status == client.JobRunning {
+			running = append(running, r.Run)
+		}
+	}
+
+	number := 1
+	if len(runs) > 0 {
+		number = runs[len(runs)-1].Run.Number + 1
+	}
+
+	if len(running) > 0 {
+		switch j.Job.ConcurrencyPolicy {
+		case client.ConcurrencyForbid:
+			return nil, client.ErrJobRunning
+		case client.ConcurrencyReplace:
+			for _, r := range running {
+				r.Status = client.JobFailed
+				r.ExitCode = -1
+				r.Error = fmt.Sprintf("replaced by run %d", number)
+				m.stopRun(j, r)
+			}
+		}
+	}
+
+	run := &client.JobRun{
+		Number:  number,
+		Status:  client.JobRunning,
+		Trigger: trigger,
+		Started: time.Now(),
+	}
+	if err := m.writeJobRun(j, &jobRun{Run: run}); err != nil {
+		return nil, err
+	}
+	m.pruneJobRuns(j, runs)
+
+	// the process is told which run it is and shares the version of the job
+	srv := runService(j, number)
+	opts := *j.Job.Options
+	opts.Namespace = j.Namespace
+	opts.Env = append(append([]string{}, j.Job.Options.Env...),
+		"MICRO_SERVICE_VERSION="+j.Job.Version,
+		jobRunEnvVar+"="+strconv.Itoa(number),
+	)
+
+	acc, err := m.generateAccount(srv, j.Namespace)
+	if err == nil {
+		err = m.startService(j.Namespace, srv, &opts, acc)
+	}
+	if err != nil {
+		run.Status = client.JobFailed
+		run.ExitCode = -1
+		run.Error = err.Error()
+		run.Finished = time.Now()
+		if werr := m.writeJobRun(j, &jobRun{Run: run}); werr != nil {
+			return nil, werr
+		}
+	}
+
+	return run, nil
+}
+
+// syncJobRuns records the result of the runs of the job which finished and stops any which
+// exceeded the timeout of the job
+func (m *manager) syncJobRuns(j *job) error {
+	runs, err := m.readJobRuns(j.Namespace, j.Job.Name)
+	if err != nil {
+		return err
+	}
+
+	for _, r := range runs {
+		if r.Run.Status != client.JobRunning {
+			continue
+		}
+		run := r.Run
+		srv := runService(j, run.Number)
+
+		srvs, err := runtime.Read(
+			gorun.ReadNamespace(j.Namespace),
+			gorun.ReadService(srv.Name),
+			gorun.ReadVersion(srv.Version),
+		)
+		if err != nil {
+			return err
+		}
+
+		var md map[string]string
+		for _, s := range srvs {
+			if s.Version == srv.Version {
+				md = s.Metadata
+			}
+		}
+
+		switch {
+		case md == nil:
+			// e.g. the runtime was restarted while the run was in progress
+			run.Status = client.JobFailed
+			run.ExitCode = -1
+			run.Error = "run is no longer in the runtime"
+		case md["status"] == "done":
+			run.Status = client.JobSucceeded
+			run.ExitCode = 0
+		case md["status"] == "error":
+			run.Status = client.JobFailed
+			run.ExitCode = exitCode(md["error"])
+			run.Error = md["error"]
+		case j.Job.Timeout > 0 && time.Since(run.Started) > j.Job.Timeout:
+			run.Status = client.JobFailed
+			run.ExitCode = -1
+			run.Error = fmt.Sprintf("timed out after %v", j.Job.Timeout)
+		default:
+			continue
+		}
+
+		m.stopRun(j, run)
+	}
+
+	return nil
+}
+
+// stopRun records the logs and result of a run which finished, or is being stopped, and deletes
+// it from the managed runtime. The status of the run should be set by the caller.
+func (m *manager) stopRun(j *job, run *client.JobRun) {
+	srv := runService(j, run.Number)
+
+	if run.Status == client.JobRunning {
+		run.Status = client.JobFailed
+		run.ExitCode = -1
+		run.Error = "stopped"
+	}
+	run.Finished = time.Now()
+
+	// the logs are read first since they're removed with the pod by kubernetes
+	logs := m.runLogs(j.Namespace, srv)
+
+	if err := runtime.Delete(srv, gorun.DeleteNamespace(j.Namespace)); err != nil {
+		logger.Warnf("Error deleting run %d of job %v: %v", run.Number, j.Job.Name, err)
+	}
+	m.deleteCgroup(j.Namespace, srv)
+	m.deleteStatus(j.Namespace, srv)
+	os.Remove(runMarker(j.Namespace, srv))
+
+	if err := m.writeJobRun(j, &jobRun{Run: run, Logs: logs}); err != nil {
+		logger.Warnf("Error writing run %d of job %v: %v", run.Number, j.Job.Name, err)
+	}
+
+	logger.Infof("Run %d of job %v in namespace %v %v", run.Number, j.Job.Name, j.Namespace, run.Status)
+}
+
+// runLogs returns the latest logs of the run from the managed runtime
+func (m *manager) runLogs(ns string, srv *gorun.Service) []gorun.Log {
+	stream, err := runtime.Logs(srv, gorun.LogsCount(int64(jobLogLines)), gorun.LogsNamespace(ns))
+	if err != nil {
+		logger.Debugf("Error reading the logs of %v:%v: %v", srv.Name, srv.Version, err)
+		return nil
+	}
+	defer stream.Stop()
+
+	var logs []gorun.Log
+	timeout := time.After(jobLogTimeout)
+	for {
+		select {
+		case l, ok := <-stream.Chan():
+			if !ok {
+				return logs
+			}
+			logs = append(logs, l)
+		case <-timeout:
+			return logs
+		}
+	}
+}
+
+// exitCode returns the exit code of a process from its error, -1 if the code isn't known
+func exitCode(err string) int {
+	match := exitCodeRe.FindStringSubmatch(err)
+	if len(match) < 2 {
+		return -1
+	}
+	code, _ := strconv.Atoi(match[1])
+	return code
+}
+
+// writeJob writes the job to the store
+func (m *manager) writeJob(j *job) error {
+	bytes, err := json.Marshal(j)
+	if err != nil {
+		return err
+	}
+	return store.Write(&gostore.Record{Key: jobKey(j.Namespace, j.Job.Name), Value: bytes})
+}
+
+// readJob returns the job from the store
+func (m *manager) readJob(ns, name string) (*job, error) {
+	recs, err := store.Read(jobKey(ns, name))
+	if err == gostore.ErrNotFound || (err == nil && len(recs) == 0) {
+		return nil, client.ErrJobNotFound
+	} else if err != nil {
+		return nil, err
+	}
+
+	var j *job
+	if err := json.Unmarshal(recs[0].Value, &j); err != nil {
+		return nil, err
+	}
+	return j, nil
+}
+
+// readJobs returns the jobs in the namespace
+func (m *manager) readJobs(ns string) ([]*job, error) {
+	recs, err := store.Read(jobPrefix+ns+":", gostore.ReadPrefix())
+	if err != nil {
+		return nil, err
+	}
+
+	jobs := make([]*job, 0, len(recs))
+	for _, r := range recs {
+		var j *job
+		if err := json.Unmarshal(r.Value, &j); err != nil {
+			return nil, err
+		}
+		jobs = append(jobs, j)
+	}
+	return jobs, nil
+}
+
+// writeJobRun writes the run of the job to the store
+func (m *manager) writeJobRun(j *job, r *jobRun) error {
+	bytes, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+	return store.Write(&gostore.Record{Key: jobRunKey(j.Namespace, j.Job.Name, r.Run.Number), Value: bytes})
+}
+
+// readJobRuns returns the runs of the job in the store, oldest first
+func (m *manager) readJobRuns(ns, name string) ([]*jobRun, error) {
+	recs, err := store.Read(fmt.Sprintf("%v%v:%v:", jobRunPrefix, ns, name), gostore.ReadPrefix())
+	if err != nil {
+		return nil, err
+	}
+
+	runs := make([]*jobRun, 0, len(recs))
+	for _, r := range recs {
+		var run *jobRun
+		if err := json.Unmarshal(r.Value, &run); err != nil {
+			return nil, err
+		}
+		runs = append(runs, run)
+	}
+	return runs, nil
+}
+
+// pruneJobRuns removes the oldest finished runs once there are more than the jobRunLimit
+func (m *manager) pruneJobRuns(j *job, runs []*jobRun) {
+	// the run which was just started counts towards the limit
+	excess := len(runs) + 1 - jobRunLimit
+	for _, r := range runs {
+		if excess <= 0 {
+			return
+		}
+		if r.Run.Status == client.JobRunning {
+			continue
+		}
+		store.Delete(jobRunKey(j.Namespace, j.Job.Name, r.Run.Number))
+		excess--
+	}
+}
@@ -0,0 +1,134 @@
+package manager
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/micro/go-micro/v3/runtime"
+	"github.com/micro/micro/v3/profile"
+	muruntime "github.com/micro/micro/v3/service/runtime"
+	"github.com/micro/micro/v3/service/runtime/client"
+)
+
+func (r *testRuntime) Logs(srv *runtime.Service, opts ...runtime.LogsOption) (runtime.Logs, error) {
+	return nil, errors.New("logs not supported")
+}
+
+func TestJobs(t *testing.T) {
+	profile.Test.Setup(nil)
+	rt := &testRuntime{}
+	muruntime.DefaultRuntime = rt
+	m := New().(*manager)
+
+	job := &client.Job{
+		Name:              "backup",
+		Source:            "backup.tar.gz",
+		Schedule:          "0 2 * * *",
+		ConcurrencyPolicy: client.ConcurrencyForbid,
+	}
+	if err := m.CreateJob(job, "jobs"); err != nil {
+		t.Fatalf("Unexpected error creating job: %v", err)
+	}
+	if err := m.CreateJob(&client.Job{Name: "bad", Schedule: "* * *"}, "jobs"); err == nil {
+		t.Errorf("Expected an error creating a job with an invalid schedule")
+	}
+
+	jobs, err := m.ReadJobs("", "jobs")
+	if err != nil {
+		t.Fatalf("Unexpected error reading jobs: %v", err)
+	}
+	if len(jobs) != 1 || jobs[0].Version != "latest" || jobs[0].Next.IsZero() {
+		t.Fatalf("Expected the job to be scheduled, got %+v", jobs)
+	}
+
+	// start a run
+	run, err := m.TriggerJob("backup", "jobs")
+	if err != nil {
+		t.Fatalf("Unexpected error triggering job: %v", err)
+	}
+	if run.Number != 1 || run.Status != client.JobRunning || run.Trigger != client.TriggerManual {
+		t.Errorf("Unexpected run %+v", run)
+	}
+	if rt.createCount != 1 {
+		t.Errorf("Expected the run to be created in the runtime, got %v creates", rt.createCount)
+	}
+
+	// the previous run hasn't finished
+	if _, err := m.TriggerJob("backup", "jobs"); err != client.ErrJobRunning {
+		t.Errorf("Expected %v, got %v", client.ErrJobRunning, err)
+	}
+
+	// the run exits with an error
+	rt.readServices = []*runtime.Service{{
+		Name:     "backup",
+		Version:  runVersion("latest", 1),
+		Metadata: map[string]string{"status": "error", "error": "exit status 3"},
+	}}
+	m.syncJobs()
+
+	runs, err := m.JobRuns("backup", "jobs")
+	if err != nil {
+		t.Fatalf("Unexpected error reading runs: %v", err)
+	}
+	if len(runs) != 1 || runs[0].Status != client.JobFailed || runs[0].ExitCode != 3 || runs[0].Finished.IsZero() {
+		t.Fatalf("Expected the run to have failed with exit code 3, got %+v", runs[0])
+	}
+	if rt.deleteCount != 1 {
+		t.Errorf("Expected the run to be deleted from the runtime, got %v deletes", rt.deleteCount)
+	}
+
+	if run, err := m.TriggerJob("backup", "jobs"); err != nil || run.Number != 2 {
+		t.Errorf("Expected the second run to start, got %+v: %v", run, err)
+	}
+
+	if err := m.DeleteJob("backup", "jobs"); err != nil {
+		t.Fatalf("Unexpected error deleting job: %v", err)
+	}
+	if _, err := m.JobRuns("backup", "jobs"); err != client.ErrJobNotFound {
+		t.Errorf("Expected %v, got %v", client.ErrJobNotFound, err)
+	}
+}
+
+func TestExitCode(t *testing.T) {
+	tt := map[string]int{
+		"exit status 1":   1,
+		"exit status 127": 127,
+		"signal: killed":  -1,
+		"":                -1,
+	}
+	for err, code := range tt {
+		if c := exitCode(err); c != code {
+			t.Errorf("Expected exit code %v for %q, got %v", code, err, c)
+		}
+	}
+}
+
+func TestRunOnce(t *testing.T) {
+	dir, err := ioutil.TempDir("", "runonce")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	runOnceDir = dir
+
+	out := filepath.Join(dir, "out")
+	srv := &runtime.Service{Name: "backup", Version: runVersion("latest", 1)}
+	cmd, args, err := runOnce("jobs", srv, []string{"/bin/sh"}, []string{"-c", "echo run >> " + out})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	// the local runtime starts the process again after it exits
+	for i := 0; i < 3; i++ {
+		if err := exec.Command(cmd[0], args...).Run(); err != nil {
+			t.Fatalf("Unexpected error running the process: %v", err)
+		}
+	}
+	if b, _ := ioutil.ReadFile(out); string(b) != "run\n" {
+		t.Errorf("Expected the command to run once, got %q", b)
+	}
+}
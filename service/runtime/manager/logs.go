@@ -0,0 +1,481 @@
+package manager
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	golog "github.com/micro/go-micro/v3/logger"
+	gorun "github.com/micro/go-micro/v3/runtime"
+	gostore "github.com/micro/go-micro/v3/store"
+	"github.com/micro/micro/v3/internal/namespace"
+	"github.com/micro/micro/v3/service/logger"
+	"github.com/micro/micro/v3/service/runtime"
+	"github.com/micro/micro/v3/service/runtime/client"
+	"github.com/micro/micro/v3/service/store"
+)
+
+const (
+	// logPrefix is prefixed to the key for the records of the logs of services, each record holds
+	// the lines collected in one flush e.g. "log:micro:foo:00000001599047243000000000"
+	logPrefix = "log:"
+)
+
+var (
+	// logPollFrequency is how often the services are checked for logs to collect
+	logPollFrequency = time.Second * 10
+	// logFlushFrequency is how often the collected logs are written to the store
+	logFlushFrequency = time.Second
+	// logChunkSize is the max number of lines written to a single record
+	logChunkSize = 500
+
+	// logLineRe matches the format of the default logger e.g.
+	// "2020-09-02 12:00:00  level=info service=foo Starting server"
+	logLineRe = regexp.MustCompile(`^(\d{4}-\d{2}-\d{2} \d{2}:\d{2}:\d{2})\s+(.*)$`)
+	// logFieldRe matches a field of the default logger e.g. "level=info"
+	logFieldRe = regexp.MustCompile(`^[\w.-]+=\S*$`)
+
+	// logLevels maps the names loggers use for levels to the names of the micro levels
+	logLevels = map[string]string{
+		"warning":  "warn",
+		"err":      "error",
+		"critical": "fatal",
+		"panic":    "fatal",
+	}
+)
+
+// logKey returns the key of the logs collected at the time
+func logKey(ns, name string, t time.Time) string {
+	return fmt.Sprintf("%v%v:%v:%020d", logPrefix, ns, name, t.UnixNano())
+}
+
+// logRetention returns how long the logs of the services in the namespace are kept
+func (m *manager) logRetention(ns string) time.Duration {
+	if d, ok := m.options.NamespaceLogRetention[ns]; ok {
+		return d
+	}
+	return m.options.LogRetention
+}
+
+// Logs for a service. Logs which are filtered using the options in the client package are read
+// from the store, as are the logs of services the managed runtime no longer has logs for.
+func (m *manager) Logs(srv *gorun.Service, opts ...gorun.LogsOption) (gorun.Logs, error) {
+	var options gorun.LogsOptions
+	for _, o := range opts {
+		o(&options)
+	}
+	if len(options.Namespace) == 0 {
+		options.Namespace = namespace.DefaultNamespace
+	}
+
+	query := client.LogsQueryFromOptions(options)
+	if query == nil {
+		stream, err := runtime.Logs(srv, opts...)
+		if err == nil {
+			return stream, nil
+		}
+
+		// e.g. the service was deleted, fall back to the logs which were persisted
+		if recs, rerr := store.Read(logPrefix+options.Namespace+":"+srv.Name+":", gostore.ReadPrefix(), gostore.ReadLimit(1)); rerr != nil || len(recs) == 0 {
+			return nil, err
+		}
+		query = &client.LogsQuery{}
+	}
+
+	filter, err := newLogFilter(query)
+	if err != nil {
+		return nil, err
+	}
+
+	records, err := m.readLogs(options.Namespace, srv.Name, filter, options.Count)
+	if err != nil {
+		return nil, err
+	}
+
+	// follow the logs from the runtime once the persisted records have been sent
+	var live gorun.Logs
+	if options.Stream {
+		live, err = runtime.Logs(srv, gorun.LogsStream(true), gorun.LogsNamespace(options.Namespace))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return newQueryLogs(srv.Name, records, live, filter), nil
+}
+
+// watchLogs collects the logs of the services in the managed runtime and writes them to the store
+// so they're kept once the process or pod is gone, it should be run in a seperate go routine
+func (m *manager) watchLogs() {
+	ticker := time.NewTicker(logPollFrequency)
+	defer ticker.Stop()
+
+	for {
+		m.syncLogTails()
+		<-ticker.C
+	}
+}
+
+// syncLogTails starts collecting the logs of services which started and stops collecting the
+// logs of services which were deleted
+func (m *manager) syncLogTails() {
+	namespaces, err := m.listNamespaces()
+	if err != nil {
+		logger.Warnf("Error listing namespaces: %v", err)
+		return
+	}
+
+	// the services are collected by name since the runtimes return the logs of every version
+	running := make(map[string]bool)
+	unknown := make(map[string]bool)
+	for _, ns := range namespaces {
+		srvs, err := runtime.Read(gorun.ReadNamespace(ns))
+		if err != nil {
+			logger.Warnf("Error reading namespace %v: %v", ns, err)
+			unknown[ns] = true
+			continue
+		}
+
+		for _, srv := range srvs {
+			key := ns + ":" + srv.Name
+			if running[key] {
+				continue
+			}
+			running[key] = true
+			m.tailLogs(ns, srv.Name)
+		}
+	}
+
+	m.tailing.Lock()
+	defer m.tailing.Unlock()
+
+	for key, stream := range m.logTails {
+		ns := strings.SplitN(key, ":", 2)[0]
+		if running[key] || unknown[ns] {
+			continue
+		}
+		stream.Stop()
+		delete(m.logTails, key)
+	}
+}
+
+// tailLogs starts collecting the logs of the service if they're not already being collected
+func (m *manager) tailLogs(ns, name string) {
+	key := ns + ":" + name
+
+	m.tailing.Lock()
+	defer m.tailing.Unlock()
+
+	if _, ok := m.logTails[key]; ok {
+		return
+	}
+
+	stream, err := runtime.Logs(&gorun.Service{Name: name}, gorun.LogsStream(true), gorun.LogsNamespace(ns))
+	if err != nil {
+		logger.Debugf("Error reading the logs of service %v in namespace %v: %v", name, ns, err)
+		return
+	}
+	m.logTails[key] = stream
+
+	go m.collectLogs(ns, name, stream)
+}
+
+// collectLogs writes the logs of the service to the store until the stream ends
+func (m *manager) collectLogs(ns, name string, stream gorun.Logs) {
+	defer func() {
+		m.tailing.Lock()
+		if m.logTails[ns+":"+name] == stream {
+			delete(m.logTails, ns+":"+name)
+		}
+		m.tailing.Unlock()
+	}()
+
+	ticker := time.NewTicker(logFlushFrequency)
+	defer ticker.Stop()
+
+	var records []gorun.Log
+	flush := func() {
+		if len(records) == 0 {
+			return
+		}
+		if err := m.writeLogs(ns, name, records); err != nil {
+			logger.Warnf("Error writing the logs of service %v in namespace %v: %v", name, ns, err)
+		}
+		records = nil
+	}
+
+	for {
+		select {
+		case l, ok := <-stream.Chan():
+			if !ok {
+				flush()
+				return
+			}
+			rec := parseLog(name, l.Message)
+			for k, v := range l.Metadata {
+				if _, ok := rec.Metadata[k]; !ok {
+					rec.Metadata[k] = v
+				}
+			}
+			records = append(records, rec)
+			if len(records) >= logChunkSize {
+				flush()
+			}
+		case <-ticker.C:
+			flush()
+		}
+	}
+}
+
+// writeLogs writes the records to the store, they expire after the retention of the namespace
+func (m *manager) writeLogs(ns, name string, records []gorun.Log) error {
+	bytes, err := json.Marshal(records)
+	if err != nil {
+		return err
+	}
+
+	return store.Write(&gostore.Record{
+		Key:    logKey(ns, name, time.Now()),
+		Value:  bytes,
+		Expiry: m.logRetention(ns),
+	})
+}
+
+// readLogs returns the last count records of the service in the store which match the filter,
+// oldest first. The records are read newest first so only those needed are read, and those
+// written before the filter's since time are skipped by their key.
+func (m *manager) readLogs(ns, name string, filter *logFilter, count int64) ([]gorun.Log, error) {
+	prefix := logPrefix + ns + ":" + name + ":"
+	keys, err := store.List(gostore.ListPrefix(prefix))
+	if err != nil {
+		return nil, err
+	}
+	sort.Sort(sort.Reverse(sort.StringSlice(keys)))
+
+	// the lines of a record were all written before it was flushed
+	var since string
+	if !filter.since.IsZero() {
+		since = logKey(ns, name, filter.since)
+	}
+
+	var logs []gorun.Log
+	for _, key := range keys {
+		if key < since || (count > 0 && int64(len(logs)) >= count) {
+			break
+		}
+
+		recs, err := store.Read(key)
+		if err == gostore.ErrNotFound {
+			// the record expired since the keys were listed
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+
+		var records []gorun.Log
+		if err := json.Unmarshal(recs[0].Value, &records); err != nil {
+			return nil, err
+		}
+		for i := len(records) - 1; i >= 0 && (count <= 0 || int64(len(logs)) < count); i-- {
+			if filter.match(records[i]) {
+				logs = append(logs, records[i])
+			}
+		}
+	}
+
+	// reverse the logs so the oldest is first
+	for i, j := 0, len(logs)-1; i < j; i, j = i+1, j-1 {
+		logs[i], logs[j] = logs[j], logs[i]
+	}
+
+	return logs, nil
+}
+
+// parseLog parses the fields of a line written by the service. JSON and the format of the default
+// logger are parsed, the time the line was written is set in the timestamp field.
+func parseLog(name, line string) gorun.Log {
+	md := make(map[string]string)
+	var timestamp time.Time
+
+	if strings.HasPrefix(strings.TrimSpace(line), "{") {
+		var fields map[string]interface{}
+		if err := json.Unmarshal([]byte(line), &fields); err == nil {
+			for k, v := range fields {
+				md[k] = fmt.Sprintf("%v", v)
+			}
+		}
+		// other common names of the level and time fields
+		for _, k := range []string{"lvl", "severity"} {
+			if v, ok := md[k]; ok && len(md["level"]) == 0 {
+				md["level"] = v
+			}
+		}
+		for _, k := range []string{"time", "ts", "timestamp"} {
+			if t, err := time.Parse(time.RFC3339Nano, md[k]); err == nil {
+				timestamp = t
+				break
+			}
+		}
+	} else if match := logLineRe.FindStringSubmatch(line); match != nil {
+		if t, err := time.ParseInLocation("2006-01-02 15:04:05", match[1], time.Local); err == nil {
+			timestamp = t
+		}
+		for _, f := range strings.Fields(match[2]) {
+			if !logFieldRe.MatchString(f) {
+				break
+			}
+			parts := strings.SplitN(f, "=", 2)
+			md[parts[0]] = parts[1]
+		}
+	}
+
+	if timestamp.IsZero() {
+		timestamp = time.Now()
+	}
+	md["timestamp"] = timestamp.Format(time.RFC3339Nano)
+
+	if level, ok := md["level"]; ok {
+		level = strings.ToLower(level)
+		if l, ok := logLevels[level]; ok {
+			level = l
+		}
+		md["level"] = level
+	}
+	if len(md["service"]) == 0 {
+		md["service"] = nameFromService(name)
+	}
+
+	return gorun.Log{Message: line, Metadata: md}
+}
+
+// logFilter matches the records of a query
+type logFilter struct {
+	since    time.Time
+	level    golog.Level
+	hasLevel bool
+	grep     *regexp.Regexp
+	version  string
+}
+
+func newLogFilter(q *client.LogsQuery) (*logFilter, error) {
+	f := &logFilter{since: q.Since, version: q.Version}
+
+	if len(q.Level) > 0 {
+		level := strings.ToLower(q.Level)
+		if l, ok := logLevels[level]; ok {
+			level = l
+		}
+		lvl, err := golog.GetLevel(level)
+		if err != nil {
+			return nil, fmt.Errorf("invalid level %v", q.Level)
+		}
+		f.level = lvl
+		f.hasLevel = true
+	}
+
+	if len(q.Grep) > 0 {
+		re, err := regexp.Compile(q.Grep)
+		if err != nil {
+			return nil, fmt.Errorf("invalid grep expression: %v", err)
+		}
+		f.grep = re
+	}
+
+	return f, nil
+}
+
+func (f *logFilter) match(l gorun.Log) bool {
+	if !f.since.IsZero() {
+		if t, err := time.Parse(time.RFC3339Nano, l.Metadata["timestamp"]); err == nil && t.Before(f.since) {
+			return false
+		}
+	}
+	if f.hasLevel {
+		// records without a level don't match a level filter
+		lvl, err := golog.GetLevel(l.Metadata["level"])
+		if err != nil || lvl < f.level {
+			return false
+		}
+	}
+	if len(f.version) > 0 && l.Metadata["version"] != f.version {
+		return false
+	}
+	if f.grep != nil && !f.grep.MatchString(l.Message) {
+		return false
+	}
+	return true
+}
+
+// queryLogs sends the records read from the store followed by the matching records from the
+// runtime if the logs are being streamed
+type queryLogs struct {
+	stream chan gorun.Log
+	stop   chan bool
+	once   sync.Once
+	err    error
+}
+
+func newQueryLogs(name string, records []gorun.Log, live gorun.Logs, filter *logFilter) *queryLogs {
+	l := &queryLogs{
+		stream: make(chan gorun.Log),
+		stop:   make(chan bool),
+	}
+
+	go func() {
+		defer close(l.stream)
+		if live != nil {
+			defer live.Stop()
+		}
+
+		for _, rec := range records {
+			select {
+			case l.stream <- rec:
+			case <-l.stop:
+				return
+			}
+		}
+		if live == nil {
+			return
+		}
+
+		for {
+			select {
+			case rec, ok := <-live.Chan():
+				if !ok {
+					l.err = live.Error()
+					return
+				}
+				parsed := parseLog(name, rec.Message)
+				if !filter.match(parsed) {
+					continue
+				}
+				select {
+				case l.stream <- parsed:
+				case <-l.stop:
+					return
+				}
+			case <-l.stop:
+				return
+			}
+		}
+	}()
+
+	return l
+}
+
+func (l *queryLogs) Chan() chan gorun.Log {
+	return l.stream
+}
+
+func (l *queryLogs) Error() error {
+	return l.err
+}
+
+func (l *queryLogs) Stop() error {
+	l.once.Do(func() { close(l.stop) })
+	return nil
+}
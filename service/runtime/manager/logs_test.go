@@ -0,0 +1,117 @@
+package manager
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	gorun "github.com/micro/go-micro/v3/runtime"
+	gostore "github.com/micro/go-micro/v3/store"
+	"github.com/micro/go-micro/v3/store/memory"
+	"github.com/micro/micro/v3/service/runtime/client"
+	"github.com/micro/micro/v3/service/store"
+)
+
+func TestParseLog(t *testing.T) {
+	l := parseLog("github.com/micro/services/helloworld", "2020-09-02 12:00:00  level=warn version=v2 Request timed out")
+	if l.Metadata["level"] != "warn" || l.Metadata["version"] != "v2" || l.Metadata["service"] != "helloworld" {
+		t.Errorf("Unexpected metadata %v", l.Metadata)
+	}
+	if ts := l.Metadata["timestamp"]; ts[:19] != "2020-09-02T12:00:00" {
+		t.Errorf("Unexpected timestamp %v", ts)
+	}
+
+	l = parseLog("foo", `{"severity":"WARNING","time":"2020-09-02T12:00:00Z","msg":"slow"}`)
+	if l.Metadata["level"] != "warn" || l.Metadata["timestamp"] != "2020-09-02T12:00:00Z" {
+		t.Errorf("Unexpected metadata %v", l.Metadata)
+	}
+
+	l = parseLog("foo", "panic: runtime error")
+	if _, ok := l.Metadata["level"]; ok || l.Metadata["service"] != "foo" || len(l.Metadata["timestamp"]) == 0 {
+		t.Errorf("Unexpected metadata %v", l.Metadata)
+	}
+}
+
+func TestLogFilter(t *testing.T) {
+	if _, err := newLogFilter(&client.LogsQuery{Level: "loud"}); err == nil {
+		t.Errorf("Expected an error for an invalid level")
+	}
+	if _, err := newLogFilter(&client.LogsQuery{Grep: "("}); err == nil {
+		t.Errorf("Expected an error for an invalid expression")
+	}
+
+	f, err := newLogFilter(&client.LogsQuery{
+		Since: time.Date(2020, 9, 2, 11, 0, 0, 0, time.UTC),
+		Level: "warning",
+		Grep:  "time(d)? ?out",
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	tt := map[string]bool{
+		`{"level":"error","time":"2020-09-02T12:00:00Z","msg":"timed out"}`: true,
+		`{"level":"info","time":"2020-09-02T12:00:00Z","msg":"timed out"}`:  false,
+		`{"level":"error","time":"2020-09-02T10:00:00Z","msg":"timed out"}`: false,
+		`{"level":"error","time":"2020-09-02T12:00:00Z","msg":"refused"}`:   false,
+		"timeout without a level": false,
+	}
+	for line, match := range tt {
+		if m := f.match(parseLog("foo", line)); m != match {
+			t.Errorf("Expected match %v for %v, got %v", match, line, m)
+		}
+	}
+}
+
+func TestReadLogs(t *testing.T) {
+	defaultStore := store.DefaultStore
+	store.DefaultStore = memory.NewStore()
+	defer func() { store.DefaultStore = defaultStore }()
+
+	// three records flushed an hour apart with two lines each
+	start := time.Date(2020, 9, 2, 10, 0, 0, 0, time.UTC)
+	for i := 0; i < 3; i++ {
+		flushed := start.Add(time.Hour * time.Duration(i))
+		var records []gorun.Log
+		for j := 0; j < 2; j++ {
+			line := fmt.Sprintf(`{"level":"info","time":"%v","msg":"%v"}`, flushed.Add(-time.Minute).Format(time.RFC3339Nano), i*2+j)
+			records = append(records, parseLog("foo", line))
+		}
+		bytes, _ := json.Marshal(records)
+		store.Write(&gostore.Record{Key: logKey("micro", "foo", flushed), Value: bytes})
+	}
+
+	m := &manager{}
+	tt := []struct {
+		name   string
+		since  time.Time
+		count  int64
+		expect []string
+	}{
+		{name: "all", expect: []string{"0", "1", "2", "3", "4", "5"}},
+		{name: "count", count: 3, expect: []string{"3", "4", "5"}},
+		{name: "since", since: start.Add(time.Minute * 30), expect: []string{"2", "3", "4", "5"}},
+		{name: "since and count", since: start.Add(time.Minute * 30), count: 1, expect: []string{"5"}},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			f, err := newLogFilter(&client.LogsQuery{Since: tc.since})
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			logs, err := m.readLogs("micro", "foo", f, tc.count)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			var msgs []string
+			for _, l := range logs {
+				msgs = append(msgs, l.Metadata["msg"])
+			}
+			if strings.Join(msgs, ",") != strings.Join(tc.expect, ",") {
+				t.Errorf("Expected %v, got %v", tc.expect, msgs)
+			}
+		})
+	}
+}
@@ -1,6 +1,10 @@
 package manager
 
 import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
 	"time"
 
 	gorun "github.com/micro/go-micro/v3/runtime"
@@ -11,6 +15,7 @@ import (
 	"github.com/micro/micro/v3/internal/namespace"
 	"github.com/micro/micro/v3/service/logger"
 	"github.com/micro/micro/v3/service/runtime"
+	"github.com/micro/micro/v3/service/runtime/client"
 )
 
 // Init initializes the runtime
@@ -37,8 +42,27 @@ func (m *manager) Create(srv *gorun.Service, opts ...gorun.CreateOption) error {
 		srv.Version = "latest"
 	}
 
-	// write the object to the store
-	if err := m.createService(srv, &options); err != nil {
+	// the service is pinned to the revision of its source so it's recorded in its history
+	pinRevision(srv, &options)
+
+	// services can't depend on themselves, directly or through their dependencies. The
+	// dependencies are checked and the object is written to the store under the lock.
+	m.creating.Lock()
+	if deps := client.DependsOnFromCreateOptions(options); len(deps) > 0 {
+		if err := m.checkDependencies(options.Namespace, srv.Name, deps); err != nil {
+			m.creating.Unlock()
+			return err
+		}
+	}
+	err := m.createService(srv, &options)
+	m.creating.Unlock()
+	if err != nil {
+		return err
+	}
+
+	// the first revision of the service
+	s := &service{Service: srv, Options: &options}
+	if err := m.recordRevision(s, srv.Metadata["owner"]); err != nil {
 		return err
 	}
 
@@ -73,6 +97,29 @@ func (m *manager) Read(opts ...gorun.ReadOption) ([]*gorun.Service, error) {
 	ret := []*gorun.Service{}
 	for _, srv := range srvs {
 		ret = append(ret, srv.Service)
+		if res := formatResources(srv.Options.Resources); len(res) > 0 {
+			srv.Service.Metadata["resources"] = res
+		}
+		if as := formatAutoscale(srv.Autoscale); len(as) > 0 {
+			srv.Service.Metadata["autoscale"] = as
+		}
+		srv.Service.Metadata["replicas"] = strconv.Itoa(srv.replicaCount())
+		if len(srv.DependsOn) > 0 {
+			srv.Service.Metadata["depends_on"] = strings.Join(srv.DependsOn, ",")
+		}
+		if r := m.getRollout(options.Namespace, srv.Service); r != nil {
+			srv.Service.Metadata["rollout"] = r.String()
+		}
+
+		// the status of each additional replica, e.g. "replica.1": "running"
+		for i := 1; i < srv.replicaCount() && replicaServices(); i++ {
+			status := "pending"
+			if md, ok := statuses[srv.Service.Name+":"+replicaVersion(srv.Service.Version, i)]; ok {
+				status = md.Status
+			}
+			srv.Service.Metadata["replica."+strconv.Itoa(i)] = status
+		}
+
 		md, ok := statuses[srv.Service.Name+":"+srv.Service.Version]
 		if !ok {
 			continue
@@ -103,8 +150,46 @@ func (m *manager) Update(srv *gorun.Service, opts ...gorun.UpdateOption) error {
 		srv.Version = "latest"
 	}
 
+	resources := client.ResourcesFromUpdateOptions(options)
+	replicas := client.ReplicasFromUpdateOptions(options)
+	autoscale := client.AutoscaleFromUpdateOptions(options)
+	scale := replicas > 0 || autoscale != nil
+
+	// persist the new resources and replicas so they're used when the service is restarted
+	if resources != nil || scale {
+		srvs, err := m.readServices(options.Namespace, srv)
+		if err != nil {
+			return err
+		} else if len(srvs) == 0 {
+			return fmt.Errorf("service %v:%v not found", srv.Name, srv.Version)
+		}
+		s := srvs[0]
+
+		if resources != nil {
+			s.Options.Resources = resources
+		}
+		if replicas > 0 {
+			s.Replicas = replicas
+			s.Autoscale = nil
+		}
+		if autoscale != nil {
+			if err := setAutoscale(s, autoscale); err != nil {
+				return err
+			}
+		}
+
+		if err := m.writeService(s); err != nil {
+			return err
+		}
+	}
+
+	// scaling doesn't require the running replicas to be updated
+	if resources == nil && scale {
+		return m.publishEvent(eventScale, srv, &gorun.CreateOptions{Namespace: options.Namespace})
+	}
+
 	// publish the update event which will trigger an update in the runtime
-	return m.publishEvent(gorun.Update, srv, &gorun.CreateOptions{Namespace: options.Namespace})
+	return m.publishEvent(gorun.Update, srv, &gorun.CreateOptions{Namespace: options.Namespace, Resources: resources})
 }
 
 // Remove a service
@@ -123,7 +208,7 @@ func (m *manager) Delete(srv *gorun.Service, opts ...gorun.DeleteOption) error {
 		srv.Version = "latest"
 	}
 
-	// delete from the store
+	// delete from the store, the history is kept so it continues if the service is created again
 	if err := m.deleteService(options.Namespace, srv); err != nil {
 		return err
 	}
@@ -159,6 +244,18 @@ func (m *manager) Start() error {
 	// periodically load the status of services from the runtime
 	go m.watchStatus()
 
+	// push the status changes of services as they happen
+	go m.watchRuntime()
+
+	// periodically scale the services with autoscaling enabled
+	go m.watchAutoscale()
+
+	// start the runs of jobs when they're due
+	go m.watchJobs()
+
+	// persist the logs of services so they can be queried
+	go m.watchLogs()
+
 	// todo: compare the store to the runtime incase we missed any events
 
 	// Watch services that were running previously
@@ -167,11 +264,6 @@ func (m *manager) Start() error {
 	return nil
 }
 
-// Logs for a service
-func (m *manager) Logs(srv *gorun.Service, opts ...gorun.LogsOption) (gorun.Logs, error) {
-	return runtime.Logs(srv, opts...)
-}
-
 func (m *manager) watchServices() {
 	nss, err := m.listNamespaces()
 	if err != nil {
@@ -204,29 +296,14 @@ func (m *manager) watchServices() {
 				continue
 			}
 
-			// construct the options
-			options := []gorun.CreateOption{
-				gorun.CreateImage(srv.Options.Image),
-				gorun.CreateType(srv.Options.Type),
-				gorun.CreateNamespace(ns),
-				gorun.WithArgs(srv.Options.Args...),
-				gorun.WithCommand(srv.Options.Command...),
-				gorun.WithEnv(m.runtimeEnv(srv.Service, srv.Options)),
-			}
-
-			// inject the credentials into the service if present
-			if len(acc.ID) > 0 && len(acc.Secret) > 0 {
-				options = append(options, gorun.WithSecret("MICRO_AUTH_ID", acc.ID))
-				options = append(options, gorun.WithSecret("MICRO_AUTH_SECRET", acc.Secret))
-			}
-
-			// add the secrets provided by the client
-			for key, value := range srv.Options.Secrets {
-				options = append(options, gorun.WithSecret(key, value))
+			// wait for the services it depends on to be restarted
+			if len(srv.DependsOn) > 0 {
+				go m.startWhenReady(ns, srv.Service, srv.Options, acc, srv.DependsOn, m.wait(ns, srv.Service))
+				continue
 			}
 
 			// create the service
-			if err := runtime.Create(srv.Service, options...); err != nil {
+			if err := m.startService(ns, srv.Service, srv.Options, acc); err != nil {
 				if logger.V(logger.ErrorLevel, logger.DefaultLogger) {
 					logger.Errorf("Error restarting service: %v", err)
 				}
@@ -251,6 +328,7 @@ func (m *manager) String() string {
 }
 
 type manager struct {
+	options Options
 	// running is true after Start is called
 	running bool
 	// cache is a memory store which is used to store any information we don't want to write to the
@@ -260,12 +338,53 @@ type manager struct {
 	// fileCache is a cache store used to store any information we don't want to write to the
 	// global store but want to persist across restarts, e.g. events consumed
 	fileCache store.Store
+	// scaling are the locks of the services, keyed by namespace, name and version, which are
+	// held while their replicas are created, deleted or updated so they're not changed by more
+	// than one event at a time
+	scaling map[string]*sync.Mutex
+	// scalingMu is locked while the scaling locks are read or added
+	scalingMu sync.Mutex
+	// samples are the request counts of the nodes of autoscaled services
+	samples *samples
+	// jobs is locked while the runs of jobs are started or stopped
+	jobs sync.Mutex
+	// logTails are the streams the logs of services are collected from, keyed by
+	// namespace and service name
+	logTails map[string]gorun.Logs
+	// tailing is locked while the logTails are changed
+	tailing sync.Mutex
+	// buildLocks are the locks of the builds, keyed by namespace and id, which are held while
+	// they're made, fetched or deleted
+	buildLocks map[string]*sync.Mutex
+	// building is locked while the build locks are read or added
+	building sync.Mutex
+	// waiters are closed to cancel the services waiting to be started, e.g. for their
+	// dependencies, keyed by namespace, name and version
+	waiters map[string]chan struct{}
+	// waiting is locked while the waiters are changed
+	waiting sync.Mutex
+	// creating is locked while the dependencies of a service are checked and it's written to
+	// the store, so services created at the same time can't form a cycle
+	creating sync.Mutex
 }
 
 // New returns a manager for the runtime
-func New() gorun.Runtime {
+func New(opts ...Option) gorun.Runtime {
+	options := Options{
+		LogRetention: DefaultLogRetention,
+	}
+	for _, o := range opts {
+		o(&options)
+	}
+
 	return &manager{
-		cache:     memory.NewStore(),
-		fileCache: cachest.NewStore(filest.NewStore()),
+		options:    options,
+		cache:      memory.NewStore(),
+		fileCache:  cachest.NewStore(filest.NewStore()),
+		samples:    newSamples(),
+		logTails:   make(map[string]gorun.Logs),
+		scaling:    make(map[string]*sync.Mutex),
+		buildLocks: make(map[string]*sync.Mutex),
+		waiters:    make(map[string]chan struct{}),
 	}
 }
@@ -0,0 +1,34 @@
+package manager
+
+import "time"
+
+// DefaultLogRetention is how long the logs of services are kept unless configured
+var DefaultLogRetention = time.Hour * 24
+
+// Options for the manager
+type Options struct {
+	// LogRetention is how long the logs of services are kept
+	LogRetention time.Duration
+	// NamespaceLogRetention overrides the LogRetention for namespaces
+	NamespaceLogRetention map[string]time.Duration
+}
+
+// Option sets an option of the manager
+type Option func(o *Options)
+
+// LogRetention sets how long the logs of services are kept
+func LogRetention(d time.Duration) Option {
+	return func(o *Options) {
+		o.LogRetention = d
+	}
+}
+
+// NamespaceLogRetention sets how long the logs of the services in a namespace are kept
+func NamespaceLogRetention(ns string, d time.Duration) Option {
+	return func(o *Options) {
+		if o.NamespaceLogRetention == nil {
+			o.NamespaceLogRetention = make(map[string]time.Duration)
+		}
+		o.NamespaceLogRetention[ns] = d
+	}
+}
@@ -0,0 +1,243 @@
+package manager
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+
+	gorun "github.com/micro/go-micro/v3/runtime"
+	"github.com/micro/go-micro/v3/util/kubernetes/client"
+	"github.com/micro/micro/v3/service/logger"
+	"github.com/micro/micro/v3/service/runtime"
+)
+
+const (
+	// replicaSeparator separates the version of a service from the index of the replica, the
+	// local runtime identifies services by name and version so each replica is created with a
+	// version unique to it, e.g. "latest-replica-1". The first replica uses the version as is.
+	replicaSeparator = "-replica-"
+	// replicaEnvVar is set to the index of the replica the process is running
+	replicaEnvVar = "MICRO_SERVICE_REPLICA"
+	// eventScale is published when the number of replicas of a service changes, it isn't one
+	// of the runtime's event types since the runtime doesn't support replicas
+	eventScale gorun.EventType = 100
+)
+
+// newKubeClient returns the client of the cluster kubernetes runs services in
+var newKubeClient = func() client.Client {
+	return client.NewClusterClient()
+}
+
+// replicaServices is whether the replicas of services are created as separate services in the
+// managed runtime. Kubernetes runs the replicas of a service as the pods of its deployment so
+// they're scaled using the replica count of the deployment instead.
+func replicaServices() bool {
+	return runtime.DefaultRuntime.String() != "kubernetes"
+}
+
+// eventName returns the name of the event type for logging
+func eventName(t gorun.EventType) string {
+	switch t {
+	case eventScale:
+		return "scale"
+	case eventRollback:
+		return "rollback"
+	default:
+		return t.String()
+	}
+}
+
+// replicaVersion returns the version of the replica of a service with the version
+func replicaVersion(version string, replica int) string {
+	if replica == 0 {
+		return version
+	}
+	return version + replicaSeparator + strconv.Itoa(replica)
+}
+
+// parseReplica returns the version of the service and index of the replica from the version
+// of a service in the runtime
+func parseReplica(version string) (string, int) {
+	idx := strings.LastIndex(version, replicaSeparator)
+	if idx < 0 {
+		return version, 0
+	}
+	replica, err := strconv.Atoi(version[idx+len(replicaSeparator):])
+	if err != nil || replica <= 0 {
+		return version, 0
+	}
+	return version[:idx], replica
+}
+
+// replica returns the replica of the service with the index
+func replica(srv *gorun.Service, i int) *gorun.Service {
+	return &gorun.Service{
+		Name:     srv.Name,
+		Version:  replicaVersion(srv.Version, i),
+		Source:   srv.Source,
+		Metadata: srv.Metadata,
+	}
+}
+
+// replicaCount returns the number of replicas of the service, there is always at least one
+func (s *service) replicaCount() int {
+	if s.Replicas < 1 {
+		return 1
+	}
+	return s.Replicas
+}
+
+// runningReplicas returns the replicas of the service which exist in the managed runtime,
+// excluding the first, keyed by their index
+func runningReplicas(ns string, srv *gorun.Service) (map[int]*gorun.Service, error) {
+	srvs, err := runtime.Read(gorun.ReadNamespace(ns), gorun.ReadService(srv.Name))
+	if err != nil {
+		return nil, err
+	}
+
+	replicas := make(map[int]*gorun.Service)
+	for _, s := range srvs {
+		if version, i := parseReplica(s.Version); version == srv.Version && i > 0 {
+			replicas[i] = s
+		}
+	}
+	return replicas, nil
+}
+
+// scaleService creates or deletes replicas of the service in the managed runtime so the
+// number running matches the number in the store
+func (m *manager) scaleService(ns string, srv *gorun.Service) error {
+	lock := m.scalingLock(ns, srv)
+	lock.Lock()
+	defer lock.Unlock()
+
+	srvs, err := m.readServices(ns, srv)
+	if err != nil {
+		return err
+	} else if len(srvs) == 0 {
+		return fmt.Errorf("service %v:%v not found", srv.Name, srv.Version)
+	}
+	s := srvs[0]
+
+	running, err := runningReplicas(ns, s.Service)
+	if err != nil {
+		return err
+	}
+
+	// the deployment is scaled on kubernetes, replicas created as separate services e.g. by an
+	// earlier version of the manager are replaced by it
+	count := s.replicaCount()
+	if !replicaServices() {
+		if err := m.scaleDeployment(ns, s.Service, count); err != nil {
+			return err
+		}
+		count = 1
+	}
+
+	// delete the replicas which are no longer needed
+	for i, r := range running {
+		if i < count {
+			continue
+		}
+		if err := runtime.Delete(r, gorun.DeleteNamespace(ns)); err != nil {
+			return err
+		}
+		m.deleteCgroup(ns, r)
+		m.deleteStatus(ns, r)
+	}
+
+	// create the missing replicas, each using its own account
+	for i := 1; i < count; i++ {
+		if _, ok := running[i]; ok {
+			continue
+		}
+		r := replica(s.Service, i)
+		acc, err := m.generateAccount(r, ns)
+		if err != nil {
+			return err
+		}
+		if err := m.startService(ns, r, s.Options, acc); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// scaleDeployment sets the replica count of the deployment kubernetes runs the service as. The
+// deployment is patched with the one read from kubernetes so none of its spec is lost, services
+// which haven't been deployed yet, e.g. while they're being built, are scaled once they are.
+func (m *manager) scaleDeployment(ns string, srv *gorun.Service, replicas int) error {
+	kclient := newKubeClient()
+
+	// the runtime labels the deployments using the formatted name and version of the service
+	deps := new(client.DeploymentList)
+	labels := map[string]string{"name": client.Format(srv.Name), "version": client.Format(srv.Version)}
+	err := kclient.Get(&client.Resource{Kind: "deployment", Value: deps},
+		client.GetNamespace(client.SerializeResourceName(ns)), client.GetLabels(labels))
+	if err != nil {
+		return err
+	}
+
+	for _, dep := range deps.Items {
+		if dep.Spec == nil || dep.Spec.Replicas == replicas {
+			continue
+		}
+		dep.Spec.Replicas = replicas
+		err := kclient.Update(&client.Resource{Kind: "deployment", Name: dep.Metadata.Name, Value: &dep},
+			client.UpdateNamespace(client.SerializeResourceName(ns)))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// scalingLock returns the lock held while the replicas of the service are changed, services are
+// locked individually so updating one doesn't block scaling the others
+func (m *manager) scalingLock(ns string, srv *gorun.Service) *sync.Mutex {
+	key := fmt.Sprintf("%v:%v:%v", ns, srv.Name, srv.Version)
+
+	m.scalingMu.Lock()
+	defer m.scalingMu.Unlock()
+	lock, ok := m.scaling[key]
+	if !ok {
+		lock = &sync.Mutex{}
+		m.scaling[key] = lock
+	}
+	return lock
+}
+
+// deleteReplicas deletes all the replicas of the service from the managed runtime except the first
+func (m *manager) deleteReplicas(ns string, srv *gorun.Service) error {
+	running, err := runningReplicas(ns, srv)
+	if err != nil {
+		return err
+	}
+
+	for _, r := range running {
+		if err := runtime.Delete(r, gorun.DeleteNamespace(ns)); err != nil {
+			return err
+		}
+		m.deleteCgroup(ns, r)
+		m.deleteStatus(ns, r)
+	}
+	return nil
+}
+
+// syncReplicas scales the services in the namespace, recreating replicas which
+// the managed runtime lost e.g. after a restart
+func (m *manager) syncReplicas(ns string) {
+	srvs, err := m.readServices(ns, &gorun.Service{})
+	if err != nil {
+		logger.Warnf("Error reading services from the %v namespace: %v", ns, err)
+		return
+	}
+
+	for _, s := range srvs {
+		if err := m.scaleService(ns, s.Service); err != nil {
+			logger.Warnf("Error scaling service %v:%v in namespace %v: %v", s.Service.Name, s.Service.Version, ns, err)
+		}
+	}
+}
@@ -0,0 +1,139 @@
+package manager
+
+import (
+	"testing"
+
+	"github.com/micro/go-micro/v3/runtime"
+	kclient "github.com/micro/go-micro/v3/util/kubernetes/client"
+	"github.com/micro/micro/v3/profile"
+	muruntime "github.com/micro/micro/v3/service/runtime"
+	"github.com/micro/micro/v3/service/runtime/client"
+)
+
+func TestParseReplica(t *testing.T) {
+	tt := []struct {
+		Version string
+		Base    string
+		Replica int
+	}{
+		{Version: "latest", Base: "latest", Replica: 0},
+		{Version: "latest-replica-2", Base: "latest", Replica: 2},
+		{Version: "v1.0.0-replica-10", Base: "v1.0.0", Replica: 10},
+		{Version: "v1-replica-x", Base: "v1-replica-x", Replica: 0},
+		{Version: "v1-replica-0", Base: "v1-replica-0", Replica: 0},
+	}
+
+	for _, tc := range tt {
+		base, replica := parseReplica(tc.Version)
+		if base != tc.Base || replica != tc.Replica {
+			t.Errorf("Expected %v to parse as %v and %v, got %v and %v", tc.Version, tc.Base, tc.Replica, base, replica)
+		}
+		if tc.Replica > 0 && replicaVersion(base, replica) != tc.Version {
+			t.Errorf("Expected replica %v of %v to have version %v, got %v", replica, base, tc.Version, replicaVersion(base, replica))
+		}
+	}
+}
+
+func TestDesiredReplicas(t *testing.T) {
+	a := &client.Autoscale{Min: 2, Max: 6, TargetRPS: 100}
+
+	tt := []struct {
+		Name     string
+		Current  int
+		RPS      float64
+		Expected int
+	}{
+		{Name: "Steady", Current: 3, RPS: 250, Expected: 3},
+		{Name: "ScaleUp", Current: 2, RPS: 450, Expected: 5},
+		{Name: "ScaleUpToMax", Current: 2, RPS: 5000, Expected: 6},
+		{Name: "ScaleDownOneAtATime", Current: 6, RPS: 10, Expected: 5},
+		{Name: "ScaleDownToMin", Current: 3, RPS: 0, Expected: 2},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.Name, func(t *testing.T) {
+			if n := desiredReplicas(tc.Current, tc.RPS, a); n != tc.Expected {
+				t.Errorf("Expected %v replicas, got %v", tc.Expected, n)
+			}
+		})
+	}
+}
+
+type testKubeRuntime struct {
+	testRuntime
+}
+
+func (r *testKubeRuntime) String() string {
+	return "kubernetes"
+}
+
+type testKubeClient struct {
+	deployments []kclient.Deployment
+	updates     []*kclient.Deployment
+	kclient.Client
+}
+
+func (c *testKubeClient) Get(r *kclient.Resource, opts ...kclient.GetOption) error {
+	r.Value.(*kclient.DeploymentList).Items = c.deployments
+	return nil
+}
+
+func (c *testKubeClient) Update(r *kclient.Resource, opts ...kclient.UpdateOption) error {
+	c.updates = append(c.updates, r.Value.(*kclient.Deployment))
+	return nil
+}
+
+func TestScaleService(t *testing.T) {
+	profile.Test.Setup(nil)
+	defer func(r runtime.Runtime) { muruntime.DefaultRuntime = r }(muruntime.DefaultRuntime)
+	defer func(f func() kclient.Client) { newKubeClient = f }(newKubeClient)
+	m := New().(*manager)
+
+	srv := &runtime.Service{Name: "foo", Version: "latest"}
+	if err := m.writeService(&service{
+		Service:  srv,
+		Options:  &runtime.CreateOptions{Namespace: "scale"},
+		Replicas: 3,
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	// the local runtime runs each replica as a separate service
+	rt := &testRuntime{readServices: []*runtime.Service{srv}}
+	muruntime.DefaultRuntime = rt
+	if err := m.scaleService("scale", srv); err != nil {
+		t.Fatalf("Unexpected error scaling the service: %v", err)
+	}
+	if rt.createCount != 2 {
+		t.Errorf("Expected 2 replicas to be created, got %v", rt.createCount)
+	}
+
+	// kubernetes scales the deployment instead and replaces any separate replicas
+	krt := &testKubeRuntime{testRuntime{readServices: []*runtime.Service{srv, replica(srv, 1)}}}
+	muruntime.DefaultRuntime = krt
+	kc := &testKubeClient{deployments: []kclient.Deployment{{
+		Metadata: &kclient.Metadata{Name: "foo-latest"},
+		Spec:     &kclient.DeploymentSpec{Replicas: 1},
+	}}}
+	newKubeClient = func() kclient.Client { return kc }
+
+	if err := m.scaleService("scale", srv); err != nil {
+		t.Fatalf("Unexpected error scaling the service: %v", err)
+	}
+	if krt.createCount != 0 || krt.deleteCount != 1 {
+		t.Errorf("Expected the replica to be deleted and none created, got %v created and %v deleted", krt.createCount, krt.deleteCount)
+	}
+	if len(kc.updates) != 1 || kc.updates[0].Spec.Replicas != 3 {
+		t.Fatalf("Expected the deployment to be scaled to 3 replicas, got %v updates", len(kc.updates))
+	}
+
+	// deployments which are already scaled aren't updated
+	kc.deployments[0].Spec.Replicas = 3
+	krt.readServices = []*runtime.Service{srv}
+	if err := m.scaleService("scale", srv); err != nil {
+		t.Fatalf("Unexpected error scaling the service: %v", err)
+	}
+	if len(kc.updates) != 1 {
+		t.Errorf("Expected the scaled deployment not to be updated, got %v updates", len(kc.updates))
+	}
+}
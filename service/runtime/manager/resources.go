@@ -0,0 +1,59 @@
+package manager
+
+import (
+	"fmt"
+	"strings"
+
+	gorun "github.com/micro/go-micro/v3/runtime"
+	"github.com/micro/micro/v3/internal/cgroup"
+	"github.com/micro/micro/v3/service/logger"
+	"github.com/micro/micro/v3/service/runtime"
+)
+
+// createCgroup creates the cgroup which enforces the resource limits of a service run by the
+// local runtime and returns its path. Kubernetes enforces the limits using the pod resources.
+func (m *manager) createCgroup(srv *gorun.Service, options *gorun.CreateOptions) string {
+	if options.Resources == nil || runtime.DefaultRuntime.String() != "local" {
+		return ""
+	}
+
+	path, err := cgroup.Create(options.Namespace, srv.Name, srv.Version, options.Resources)
+	if err != nil {
+		logger.Warnf("Unable to enforce the resource limits of service %v:%v: %v", srv.Name, srv.Version, err)
+		return ""
+	}
+	if options.Resources.Disk > 0 {
+		logger.Warnf("The disk limit of service %v:%v is not enforced by the local runtime", srv.Name, srv.Version)
+	}
+
+	return path
+}
+
+// deleteCgroup removes the cgroup of a service run by the local runtime
+func (m *manager) deleteCgroup(ns string, srv *gorun.Service) {
+	if runtime.DefaultRuntime.String() != "local" {
+		return
+	}
+	if err := cgroup.Delete(ns, srv.Name, srv.Version); err != nil {
+		logger.Debugf("Error deleting the cgroup of service %v:%v: %v", srv.Name, srv.Version, err)
+	}
+}
+
+// formatResources describes the resources e.g. "cpu=250m, mem=128Mi"
+func formatResources(r *gorun.Resources) string {
+	if r == nil {
+		return ""
+	}
+
+	var res []string
+	if r.CPU > 0 {
+		res = append(res, fmt.Sprintf("cpu=%dm", r.CPU))
+	}
+	if r.Mem > 0 {
+		res = append(res, fmt.Sprintf("mem=%dMi", r.Mem))
+	}
+	if r.Disk > 0 {
+		res = append(res, fmt.Sprintf("disk=%dMi", r.Disk))
+	}
+	return strings.Join(res, ", ")
+}
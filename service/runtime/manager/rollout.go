@@ -0,0 +1,350 @@
+package manager
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	goclient "github.com/micro/go-micro/v3/client"
+	goevents "github.com/micro/go-micro/v3/events"
+	"github.com/micro/go-micro/v3/registry"
+	gorun "github.com/micro/go-micro/v3/runtime"
+	"github.com/micro/go-micro/v3/store"
+	debug "github.com/micro/micro/v3/service/debug/proto"
+	"github.com/micro/micro/v3/service/errors"
+	"github.com/micro/micro/v3/service/events"
+	"github.com/micro/micro/v3/service/logger"
+	muregistry "github.com/micro/micro/v3/service/registry"
+	"github.com/micro/micro/v3/service/runtime"
+)
+
+var (
+	// rolloutTimeout is how long a new replica has to register and pass its health check
+	rolloutTimeout = time.Minute * 2
+	// rolloutPollFrequency is how often a new replica is checked while waiting for it
+	rolloutPollFrequency = time.Second
+)
+
+const (
+	// rolloutPrefix is prefixed to the key of the rollout records written to the memory store
+	rolloutPrefix = "rollout:"
+
+	// rolloutInProgress is the status of a rolling update which is replacing replicas
+	rolloutInProgress = "in progress"
+	// rolloutComplete is the status of a rolling update which replaced every replica
+	rolloutComplete = "complete"
+	// rolloutRolledBack is the status of a rolling update which failed and was rolled back
+	rolloutRolledBack = "rolled back"
+)
+
+// rolloutStatus is the progress of the latest rolling update of a service
+type rolloutStatus struct {
+	Status  string
+	Step    int
+	Steps   int
+	Error   string
+	Updated time.Time
+}
+
+// String describes the progress e.g. "in progress 2/4" or "rolled back: ..."
+func (r *rolloutStatus) String() string {
+	switch r.Status {
+	case rolloutInProgress:
+		return fmt.Sprintf("%v %d/%d", r.Status, r.Step, r.Steps)
+	case rolloutRolledBack:
+		return fmt.Sprintf("%v: %v", r.Status, r.Error)
+	default:
+		return r.Status
+	}
+}
+
+// rollingUpdate updates the replicas of a service one at a time. A new replica is started first
+// and every replica is only replaced once the one before it registered and passed its health
+// check. If a replica fails to become healthy before the rolloutTimeout the replicas which were
+// replaced are restored from the previous source and the update is rolled back. When opts are
+// provided, e.g. when rolling back to a revision, the image and env are replaced by theirs.
+func (m *manager) rollingUpdate(ns string, srv *gorun.Service, opts *gorun.CreateOptions) error {
+	srvs, err := m.readServices(ns, srv)
+	if err != nil {
+		return err
+	} else if len(srvs) == 0 {
+		// services which weren't created by the manager are updated in place
+		return runtime.Update(srv, gorun.UpdateNamespace(ns))
+	}
+	prev := srvs[0]
+
+	// the replicas of the service can't be scaled during the update
+	lock := m.scalingLock(ns, prev.Service)
+	lock.Lock()
+	defer lock.Unlock()
+
+	// the new version of the service, rollbacks are pinned to the revision of the source being
+	// rolled back to and updates to the latest revision
+	next := *prev.Service
+	if len(srv.Source) > 0 {
+		next.Source = srv.Source
+	}
+	next.Metadata = make(map[string]string, len(prev.Service.Metadata))
+	for k, v := range prev.Service.Metadata {
+		next.Metadata[k] = v
+	}
+	nextOpts := *prev.Options
+	if opts != nil {
+		nextOpts.Image = opts.Image
+		nextOpts.Env = opts.Env
+	}
+	if rev := srv.Metadata[revisionMetadata]; opts != nil && len(rev) > 0 {
+		next.Metadata[revisionMetadata] = rev
+	} else {
+		pinRevision(&next, &nextOpts)
+	}
+
+	// the extra replica is started first and removed once the others have been replaced. On
+	// kubernetes the replicas are the pods of a single deployment which is replaced as a whole.
+	count := prev.replicaCount()
+	if !replicaServices() {
+		count = 1
+	}
+	surge := replica(&next, count)
+	steps := count + 1
+
+	status := &rolloutStatus{Status: rolloutInProgress, Steps: steps}
+	m.setRollout(ns, prev.Service, status)
+	m.publishDeployment(runtime.EventDeploymentStarted, ns, &next, nil)
+
+	rollback := func(replaced int, err error) error {
+		logger.Warnf("Rolling back the update of service %v:%v in namespace %v: %v", srv.Name, srv.Version, ns, err)
+
+		if derr := runtime.Delete(surge, gorun.DeleteNamespace(ns)); derr != nil {
+			logger.Warnf("Error deleting replica %v:%v: %v", surge.Name, surge.Version, derr)
+		}
+		m.deleteCgroup(ns, surge)
+		m.deleteStatus(ns, surge)
+
+		// restore the replicas which were replaced using the previous source
+		for i := 0; i < replaced; i++ {
+			if rerr := m.recreateReplica(ns, replica(prev.Service, i), prev.Options, prev.replicaCount()); rerr != nil {
+				logger.Warnf("Error restoring replica %v of service %v:%v: %v", i, srv.Name, srv.Version, rerr)
+			}
+		}
+
+		status.Status = rolloutRolledBack
+		status.Error = err.Error()
+		m.setRollout(ns, prev.Service, status)
+		m.publishDeployment(runtime.EventDeploymentRolledBack, ns, &next, err)
+		return nil
+	}
+
+	// start the extra replica with the new version so there's no loss of capacity
+	nodes := m.serviceNodes(ns, prev.Service)
+	acc, err := m.generateAccount(surge, ns)
+	if err != nil {
+		return err
+	}
+	if err := m.startService(ns, surge, &nextOpts, acc); err != nil {
+		return rollback(0, err)
+	}
+	if err := m.waitHealthy(ns, surge, count, nodes); err != nil {
+		return rollback(0, err)
+	}
+	status.Step++
+	m.setRollout(ns, prev.Service, status)
+
+	// replace the existing replicas one at a time
+	for i := 0; i < count; i++ {
+		r := replica(&next, i)
+
+		nodes := m.serviceNodes(ns, prev.Service)
+		if err := m.recreateReplica(ns, r, &nextOpts, prev.replicaCount()); err != nil {
+			return rollback(i+1, err)
+		}
+		if err := m.waitHealthy(ns, r, i, nodes); err != nil {
+			return rollback(i+1, err)
+		}
+
+		status.Step++
+		m.setRollout(ns, prev.Service, status)
+	}
+
+	// the extra replica is no longer needed
+	if err := runtime.Delete(surge, gorun.DeleteNamespace(ns)); err != nil {
+		logger.Warnf("Error deleting replica %v:%v: %v", surge.Name, surge.Version, err)
+	}
+	m.deleteCgroup(ns, surge)
+	m.deleteStatus(ns, surge)
+
+	// persist the new source so the service is restarted with it
+	prev.Service.Source = next.Source
+	prev.Service.Metadata = next.Metadata
+	prev.Options = &nextOpts
+	if err := m.writeService(prev); err != nil {
+		return err
+	}
+	if err := m.recordRevision(prev, srv.Metadata["owner"]); err != nil {
+		logger.Warnf("Error recording the revision of service %v:%v: %v", srv.Name, srv.Version, err)
+	}
+
+	status.Status = rolloutComplete
+	m.setRollout(ns, prev.Service, status)
+	m.publishDeployment(runtime.EventDeploymentSucceeded, ns, &next, nil)
+	return nil
+}
+
+// recreateReplica deletes the replica from the managed runtime and creates it with the options,
+// the deployment is scaled back to the replicas of the service on kubernetes
+func (m *manager) recreateReplica(ns string, r *gorun.Service, opts *gorun.CreateOptions, replicas int) error {
+	if err := runtime.Delete(r, gorun.DeleteNamespace(ns)); err != nil {
+		return err
+	}
+	m.deleteCgroup(ns, r)
+
+	acc, err := m.generateAccount(r, ns)
+	if err != nil {
+		return err
+	}
+	if err := m.startService(ns, r, opts, acc); err != nil {
+		return err
+	}
+	if !replicaServices() {
+		return m.scaleDeployment(ns, r, replicas)
+	}
+	return nil
+}
+
+// serviceNodes returns the ids of the nodes registered for the service
+func (m *manager) serviceNodes(ns string, srv *gorun.Service) map[string]bool {
+	nodes := make(map[string]bool)
+
+	srvs, err := muregistry.GetService(nameFromService(srv.Name), registry.GetDomain(ns))
+	if err != nil {
+		return nodes
+	}
+	for _, s := range srvs {
+		if s.Version != srv.Version {
+			continue
+		}
+		for _, n := range s.Nodes {
+			nodes[n.Id] = true
+		}
+	}
+	return nodes
+}
+
+// waitHealthy waits for a node of the replica which isn't one of the existing nodes to register
+// and pass its health check. An error is returned if the runtime reports the replica errored
+// or it isn't healthy before the rolloutTimeout.
+func (m *manager) waitHealthy(ns string, r *gorun.Service, index int, existing map[string]bool) error {
+	version, _ := parseReplica(r.Version)
+	deadline := time.Now().Add(rolloutTimeout)
+
+	ticker := time.NewTicker(rolloutPollFrequency)
+	defer ticker.Stop()
+
+	for time.Now().Before(deadline) {
+		<-ticker.C
+
+		// the process or pod of the replica crashed
+		if srvs, err := runtime.Read(gorun.ReadNamespace(ns), gorun.ReadService(r.Name), gorun.ReadVersion(r.Version)); err == nil {
+			for _, s := range srvs {
+				if s.Version == r.Version && s.Metadata["status"] == "error" {
+					return fmt.Errorf("replica %v errored: %v", index, s.Metadata["error"])
+				}
+			}
+		}
+
+		srvs, err := muregistry.GetService(nameFromService(r.Name), registry.GetDomain(ns))
+		if err != nil {
+			continue
+		}
+		for _, s := range srvs {
+			if s.Version != version {
+				continue
+			}
+			for _, n := range s.Nodes {
+				if existing[n.Id] {
+					continue
+				}
+				// nodes of services built before replicas were supported aren't labelled
+				if idx, ok := n.Metadata["replica"]; ok && idx != strconv.Itoa(index) {
+					continue
+				}
+				if healthy(s, n) {
+					return nil
+				}
+			}
+		}
+	}
+
+	return fmt.Errorf("replica %v didn't pass its health check within %v", index, rolloutTimeout)
+}
+
+// healthy calls Debug.Health on the node, nodes which don't serve the debug handler
+// are considered healthy once registered
+func healthy(srv *registry.Service, node *registry.Node) bool {
+	req := directClient.NewRequest(srv.Name, "Debug.Health", &debug.HealthRequest{})
+	rsp := &debug.HealthResponse{}
+
+	err := directClient.Call(
+		context.Background(),
+		req,
+		rsp,
+		goclient.WithAddress(node.Address),
+		goclient.WithRequestTimeout(rolloutPollFrequency*5),
+		goclient.WithRetries(0),
+	)
+	if verr := errors.Parse(err); verr != nil && (verr.Code == 404 || verr.Code == 501) {
+		return true
+	}
+
+	return err == nil && rsp.Status == "ok"
+}
+
+// setRollout writes the progress of a rolling update to the memory store
+func (m *manager) setRollout(ns string, srv *gorun.Service, r *rolloutStatus) {
+	r.Updated = time.Now()
+
+	bytes, err := json.Marshal(r)
+	if err != nil {
+		return
+	}
+
+	key := fmt.Sprintf("%v%v:%v:%v", rolloutPrefix, ns, srv.Name, srv.Version)
+	if err := m.cache.Write(&store.Record{Key: key, Value: bytes}); err != nil {
+		logger.Warnf("Error writing the rollout status of service %v:%v: %v", srv.Name, srv.Version, err)
+	}
+}
+
+// getRollout returns the progress of the latest rolling update of the service
+func (m *manager) getRollout(ns string, srv *gorun.Service) *rolloutStatus {
+	recs, err := m.cache.Read(fmt.Sprintf("%v%v:%v:%v", rolloutPrefix, ns, srv.Name, srv.Version))
+	if err != nil || len(recs) == 0 {
+		return nil
+	}
+
+	var r *rolloutStatus
+	if err := json.Unmarshal(recs[0].Value, &r); err != nil {
+		return nil
+	}
+	return r
+}
+
+// publishDeployment publishes the deployment event to the runtime events stream
+func (m *manager) publishDeployment(typ, ns string, srv *gorun.Service, err error) {
+	ev := &runtime.EventDeploymentPayload{
+		Type:      typ,
+		Service:   srv,
+		Namespace: ns,
+	}
+	if err != nil {
+		ev.Error = err.Error()
+	}
+
+	if err := events.Publish(runtime.EventTopic, ev, goevents.WithMetadata(map[string]string{
+		"type":      typ,
+		"namespace": ns,
+	})); err != nil {
+		logger.Warnf("Error publishing %v event for service %v:%v: %v", typ, srv.Name, srv.Version, err)
+	}
+}
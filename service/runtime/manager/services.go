@@ -8,6 +8,7 @@ import (
 	"github.com/micro/go-micro/v3/runtime"
 	gostore "github.com/micro/go-micro/v3/store"
 	"github.com/micro/micro/v3/internal/namespace"
+	"github.com/micro/micro/v3/service/runtime/client"
 	"github.com/micro/micro/v3/service/store"
 )
 
@@ -15,6 +16,12 @@ import (
 type service struct {
 	Service *runtime.Service       `json:"service"`
 	Options *runtime.CreateOptions `json:"options"`
+	// Replicas is the number of instances of the service to run
+	Replicas int `json:"replicas,omitempty"`
+	// Autoscale scales the replicas with the request rate of the service when set
+	Autoscale *client.Autoscale `json:"autoscale,omitempty"`
+	// DependsOn are the services which must be healthy before the service is started
+	DependsOn []string `json:"depends_on,omitempty"`
 }
 
 const (
@@ -30,8 +37,16 @@ func (s *service) Key() string {
 
 // createService writes the service to the store
 func (m *manager) createService(srv *runtime.Service, opts *runtime.CreateOptions) error {
-	s := &service{srv, opts}
+	return m.writeService(&service{
+		Service:   srv,
+		Options:   opts,
+		Replicas:  client.ReplicasFromCreateOptions(*opts),
+		DependsOn: client.DependsOnFromCreateOptions(*opts),
+	})
+}
 
+// writeService writes the service and its replicas to the store
+func (m *manager) writeService(s *service) error {
 	bytes, err := json.Marshal(s)
 	if err != nil {
 		return err
@@ -72,7 +87,7 @@ func (m *manager) readServices(namespace string, srv *runtime.Service) ([]*servi
 
 // deleteSevice from the store
 func (m *manager) deleteService(namespace string, srv *runtime.Service) error {
-	obj := &service{srv, &runtime.CreateOptions{Namespace: namespace}}
+	obj := &service{Service: srv, Options: &runtime.CreateOptions{Namespace: namespace}}
 	return store.Delete(obj.Key())
 }
 
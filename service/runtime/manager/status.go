@@ -7,8 +7,10 @@ import (
 	"strings"
 	"time"
 
+	goevents "github.com/micro/go-micro/v3/events"
 	gorun "github.com/micro/go-micro/v3/runtime"
 	"github.com/micro/go-micro/v3/store"
+	"github.com/micro/micro/v3/service/events"
 	"github.com/micro/micro/v3/service/logger"
 	"github.com/micro/micro/v3/service/runtime"
 )
@@ -37,7 +39,8 @@ func (m *manager) watchStatus() {
 }
 
 // syncStatus calls the managed runtime, gets the serviceStatus for all services listed in the
-// store and writes it to the memory store
+// store and writes it to the memory store. Changes are usually picked up by watchRuntime as
+// they happen, this catches any which were missed.
 func (m *manager) syncStatus() {
 	namespaces, err := m.listNamespaces()
 	if err != nil {
@@ -46,23 +49,35 @@ func (m *manager) syncStatus() {
 	}
 
 	for _, ns := range namespaces {
-		srvs, err := runtime.Read(gorun.ReadNamespace(ns))
-		if err != nil {
+		// one misbehaving namespace shouldn't prevent the others being synced
+		if err := m.refreshStatus(ns); err != nil {
 			logger.Warnf("Error reading namespace %v: %v", ns, err)
-			return
+			continue
 		}
 
-		for _, srv := range srvs {
-			if err := m.cacheStatus(ns, srv); err != nil {
-				logger.Warnf("Error caching status: %v", err)
-				return
-			}
+		// recreate any replicas which have stopped running
+		m.syncReplicas(ns)
+	}
+}
+
+// refreshStatus reads the services in the namespace from the managed runtime and caches their status
+func (m *manager) refreshStatus(ns string) error {
+	srvs, err := runtime.Read(gorun.ReadNamespace(ns))
+	if err != nil {
+		return err
+	}
+
+	for _, srv := range srvs {
+		if err := m.cacheStatus(ns, srv); err != nil {
+			logger.Warnf("Error caching status: %v", err)
 		}
 	}
+
+	return nil
 }
 
 // cacheStatus writes a services status to the memory store which is then later returned in service
-// metadata on gorun.Read
+// metadata on gorun.Read. If the status changed it's published to the runtime.status topic.
 func (m *manager) cacheStatus(ns string, srv *gorun.Service) error {
 	// errors / status is returned from the underlying runtime using srv.Metadata. TODO: Consider
 	// changing this so status / error are attributes on gorun.Service.
@@ -79,12 +94,47 @@ func (m *manager) cacheStatus(ns string, srv *gorun.Service) error {
 		}
 	}
 
+	// lookup the previous status so only changes are published
+	var prev *serviceStatus
+	if recs, err := m.cache.Read(key); err == nil && len(recs) > 0 {
+		json.Unmarshal(recs[0].Value, &prev)
+	}
+
 	bytes, err := json.Marshal(val)
 	if err != nil {
 		return err
 	}
 
-	return m.cache.Write(&store.Record{Key: key, Value: bytes})
+	if err := m.cache.Write(&store.Record{Key: key, Value: bytes}); err != nil {
+		return err
+	}
+
+	if prev == nil || prev.Status != val.Status || prev.Error != val.Error {
+		m.publishStatus(ns, srv, val)
+	}
+
+	return nil
+}
+
+// publishStatus publishes the status of the service to the runtime.status topic
+func (m *manager) publishStatus(ns string, srv *gorun.Service, status *serviceStatus) {
+	version, replica := parseReplica(srv.Version)
+
+	ev := &runtime.EventStatusPayload{
+		Service:   &gorun.Service{Name: srv.Name, Version: version, Source: srv.Source},
+		Namespace: ns,
+		Replica:   replica,
+		Status:    status.Status,
+		Error:     status.Error,
+		Updated:   time.Now(),
+	}
+
+	if err := events.Publish(runtime.EventStatusTopic, ev, goevents.WithMetadata(map[string]string{
+		"namespace": ns,
+		"service":   srv.Name,
+	})); err != nil {
+		logger.Debugf("Error publishing the status of service %v:%v: %v", srv.Name, srv.Version, err)
+	}
 }
 
 // listStatuses returns all the statuses for the services in a given namespace with 'name:version'
@@ -113,3 +163,8 @@ func (m *manager) listStatuses(ns string) (map[string]*serviceStatus, error) {
 
 	return statuses, nil
 }
+
+// deleteStatus removes the status of a service from the memory store
+func (m *manager) deleteStatus(ns string, srv *gorun.Service) {
+	m.cache.Delete(fmt.Sprintf("%v%v:%v:%v", statusPrefix, ns, srv.Name, srv.Version))
+}
@@ -0,0 +1,117 @@
+package manager
+
+import (
+	"strings"
+	"time"
+
+	"github.com/micro/go-micro/v3/store"
+	"github.com/micro/go-micro/v3/util/kubernetes/client"
+	"github.com/micro/micro/v3/service/logger"
+	"github.com/micro/micro/v3/service/runtime"
+)
+
+var (
+	// processWatchFrequency is how often the processes of the local runtime are checked for changes
+	processWatchFrequency = time.Second
+	// podWatchRetry is how long to wait before watching the pods of a namespace again after the
+	// watch ended, kubernetes closes watches periodically
+	podWatchRetry = time.Second * 5
+)
+
+// watchRuntime pushes the status changes of services in the managed runtime to the status cache as
+// they happen and should be run in a seperate go routine. The pods of kubernetes are watched, the
+// local runtime keeps the status of its processes in memory so they're checked frequently.
+func (m *manager) watchRuntime() {
+	if runtime.DefaultRuntime.String() == "kubernetes" {
+		m.watchPods(newKubeClient())
+		return
+	}
+	m.watchProcesses()
+}
+
+// watchProcesses checks the status of the processes run by the local runtime, so a process
+// exiting is published as it happens. Only the namespaces with statuses in the memory cache are
+// checked, the namespaces in the store are picked up by syncStatus.
+func (m *manager) watchProcesses() {
+	ticker := time.NewTicker(processWatchFrequency)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		for _, ns := range m.cachedNamespaces() {
+			if err := m.refreshStatus(ns); err != nil {
+				logger.Debugf("Error reading namespace %v: %v", ns, err)
+			}
+		}
+	}
+}
+
+// cachedNamespaces returns the namespaces with statuses in the memory cache
+func (m *manager) cachedNamespaces() []string {
+	keys, err := m.cache.List(store.ListPrefix(statusPrefix))
+	if err != nil {
+		return nil
+	}
+
+	seen := make(map[string]bool)
+	var namespaces []string
+	for _, key := range keys {
+		// record keys are formatted: 'prefix:namespace:name:version'
+		comps := strings.Split(key, ":")
+		if len(comps) != 4 || seen[comps[1]] {
+			continue
+		}
+		seen[comps[1]] = true
+		namespaces = append(namespaces, comps[1])
+	}
+	return namespaces
+}
+
+// watchPods watches the pods in each namespace, new namespaces are picked up periodically
+func (m *manager) watchPods(kclient client.Client) {
+	ticker := time.NewTicker(statusPollFrequency)
+	defer ticker.Stop()
+
+	watching := make(map[string]bool)
+
+	for {
+		namespaces, err := m.listNamespaces()
+		if err != nil {
+			logger.Warnf("Error listing namespaces: %v", err)
+		}
+
+		for _, ns := range namespaces {
+			if watching[ns] {
+				continue
+			}
+			watching[ns] = true
+			go m.watchNamespacePods(kclient, ns)
+		}
+
+		<-ticker.C
+	}
+}
+
+// watchNamespacePods refreshes the status of the services in the namespace when any of its pods
+// change, e.g. when a pod is scheduled, a container crashes or the pod is deleted
+func (m *manager) watchNamespacePods(kclient client.Client, ns string) {
+	for {
+		w, err := kclient.Watch(&client.Resource{Kind: "pod"}, client.WatchNamespace(ns))
+		if err != nil {
+			logger.Warnf("Error watching the pods in namespace %v: %v", ns, err)
+			time.Sleep(podWatchRetry)
+			continue
+		}
+
+		for ev := range w.Chan() {
+			if ev.Type == client.Error {
+				break
+			}
+			if err := m.refreshStatus(ns); err != nil {
+				logger.Warnf("Error reading namespace %v: %v", ns, err)
+			}
+		}
+
+		w.Stop()
+		time.Sleep(podWatchRetry)
+	}
+}
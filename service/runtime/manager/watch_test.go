@@ -0,0 +1,48 @@
+package manager
+
+import (
+	"testing"
+	"time"
+
+	"github.com/micro/go-micro/v3/runtime"
+	"github.com/micro/micro/v3/internal/namespace"
+	"github.com/micro/micro/v3/profile"
+	muruntime "github.com/micro/micro/v3/service/runtime"
+)
+
+func TestWatchProcesses(t *testing.T) {
+	srv := &runtime.Service{
+		Name:     "foo",
+		Version:  "latest",
+		Metadata: map[string]string{"status": "running"},
+	}
+
+	profile.Test.Setup(nil)
+	muruntime.DefaultRuntime = &testRuntime{readServices: []*runtime.Service{srv}}
+	m := New().(*manager)
+
+	if nss := m.cachedNamespaces(); len(nss) != 0 {
+		t.Fatalf("Expected no namespaces to be cached, got %v", nss)
+	}
+	m.cacheStatus(namespace.DefaultNamespace, srv)
+	if nss := m.cachedNamespaces(); len(nss) != 1 || nss[0] != namespace.DefaultNamespace {
+		t.Fatalf("Expected the default namespace to be cached, got %v", nss)
+	}
+
+	// the process exiting is picked up without waiting for syncStatus
+	srv.Metadata = map[string]string{"status": "error", "error": "exit status 1"}
+
+	freq := processWatchFrequency
+	processWatchFrequency = time.Millisecond * 10
+	defer func() { processWatchFrequency = freq }()
+	go m.watchProcesses()
+
+	for i := 0; i < 100; i++ {
+		statuses, _ := m.listStatuses(namespace.DefaultNamespace)
+		if s, ok := statuses["foo:latest"]; ok && s.Status == "error" {
+			return
+		}
+		time.Sleep(time.Millisecond * 10)
+	}
+	t.Errorf("Expected the status of the exited process to be cached")
+}
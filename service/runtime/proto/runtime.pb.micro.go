@@ -49,6 +49,17 @@ type RuntimeService interface {
 	Logs(ctx context.Context, in *LogsRequest, opts ...client.CallOption) (Runtime_LogsService, error)
 	CreateNamespace(ctx context.Context, in *CreateNamespaceRequest, opts ...client.CallOption) (*CreateNamespaceResponse, error)
 	DeleteNamespace(ctx context.Context, in *DeleteNamespaceRequest, opts ...client.CallOption) (*DeleteNamespaceResponse, error)
+	History(ctx context.Context, in *HistoryRequest, opts ...client.CallOption) (*HistoryResponse, error)
+	Rollback(ctx context.Context, in *RollbackRequest, opts ...client.CallOption) (*RollbackResponse, error)
+	Watch(ctx context.Context, in *WatchRequest, opts ...client.CallOption) (Runtime_WatchService, error)
+	CreateJob(ctx context.Context, in *CreateJobRequest, opts ...client.CallOption) (*CreateJobResponse, error)
+	ReadJobs(ctx context.Context, in *ReadJobsRequest, opts ...client.CallOption) (*ReadJobsResponse, error)
+	DeleteJob(ctx context.Context, in *DeleteJobRequest, opts ...client.CallOption) (*DeleteJobResponse, error)
+	TriggerJob(ctx context.Context, in *TriggerJobRequest, opts ...client.CallOption) (*TriggerJobResponse, error)
+	JobRuns(ctx context.Context, in *JobRunsRequest, opts ...client.CallOption) (*JobRunsResponse, error)
+	JobLogs(ctx context.Context, in *JobLogsRequest, opts ...client.CallOption) (*JobLogsResponse, error)
+	ReadBuilds(ctx context.Context, in *ReadBuildsRequest, opts ...client.CallOption) (*ReadBuildsResponse, error)
+	PruneBuilds(ctx context.Context, in *PruneBuildsRequest, opts ...client.CallOption) (*PruneBuildsResponse, error)
 }
 
 type runtimeService struct {
@@ -172,6 +183,155 @@ func (c *runtimeService) DeleteNamespace(ctx context.Context, in *DeleteNamespac
 	return out, nil
 }
 
+func (c *runtimeService) History(ctx context.Context, in *HistoryRequest, opts ...client.CallOption) (*HistoryResponse, error) {
+	req := c.c.NewRequest(c.name, "Runtime.History", in)
+	out := new(HistoryResponse)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *runtimeService) Rollback(ctx context.Context, in *RollbackRequest, opts ...client.CallOption) (*RollbackResponse, error) {
+	req := c.c.NewRequest(c.name, "Runtime.Rollback", in)
+	out := new(RollbackResponse)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *runtimeService) Watch(ctx context.Context, in *WatchRequest, opts ...client.CallOption) (Runtime_WatchService, error) {
+	req := c.c.NewRequest(c.name, "Runtime.Watch", &WatchRequest{})
+	stream, err := c.c.Stream(ctx, req, opts...)
+	if err != nil {
+		return nil, err
+	}
+	if err := stream.Send(in); err != nil {
+		return nil, err
+	}
+	return &runtimeServiceWatch{stream}, nil
+}
+
+type Runtime_WatchService interface {
+	Context() context.Context
+	SendMsg(interface{}) error
+	RecvMsg(interface{}) error
+	Close() error
+	Recv() (*StatusEvent, error)
+}
+
+type runtimeServiceWatch struct {
+	stream client.Stream
+}
+
+func (x *runtimeServiceWatch) Close() error {
+	return x.stream.Close()
+}
+
+func (x *runtimeServiceWatch) Context() context.Context {
+	return x.stream.Context()
+}
+
+func (x *runtimeServiceWatch) SendMsg(m interface{}) error {
+	return x.stream.Send(m)
+}
+
+func (x *runtimeServiceWatch) RecvMsg(m interface{}) error {
+	return x.stream.Recv(m)
+}
+
+func (x *runtimeServiceWatch) Recv() (*StatusEvent, error) {
+	m := new(StatusEvent)
+	err := x.stream.Recv(m)
+	if err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *runtimeService) CreateJob(ctx context.Context, in *CreateJobRequest, opts ...client.CallOption) (*CreateJobResponse, error) {
+	req := c.c.NewRequest(c.name, "Runtime.CreateJob", in)
+	out := new(CreateJobResponse)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *runtimeService) ReadJobs(ctx context.Context, in *ReadJobsRequest, opts ...client.CallOption) (*ReadJobsResponse, error) {
+	req := c.c.NewRequest(c.name, "Runtime.ReadJobs", in)
+	out := new(ReadJobsResponse)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *runtimeService) DeleteJob(ctx context.Context, in *DeleteJobRequest, opts ...client.CallOption) (*DeleteJobResponse, error) {
+	req := c.c.NewRequest(c.name, "Runtime.DeleteJob", in)
+	out := new(DeleteJobResponse)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *runtimeService) TriggerJob(ctx context.Context, in *TriggerJobRequest, opts ...client.CallOption) (*TriggerJobResponse, error) {
+	req := c.c.NewRequest(c.name, "Runtime.TriggerJob", in)
+	out := new(TriggerJobResponse)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *runtimeService) JobRuns(ctx context.Context, in *JobRunsRequest, opts ...client.CallOption) (*JobRunsResponse, error) {
+	req := c.c.NewRequest(c.name, "Runtime.JobRuns", in)
+	out := new(JobRunsResponse)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *runtimeService) JobLogs(ctx context.Context, in *JobLogsRequest, opts ...client.CallOption) (*JobLogsResponse, error) {
+	req := c.c.NewRequest(c.name, "Runtime.JobLogs", in)
+	out := new(JobLogsResponse)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *runtimeService) ReadBuilds(ctx context.Context, in *ReadBuildsRequest, opts ...client.CallOption) (*ReadBuildsResponse, error) {
+	req := c.c.NewRequest(c.name, "Runtime.ReadBuilds", in)
+	out := new(ReadBuildsResponse)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *runtimeService) PruneBuilds(ctx context.Context, in *PruneBuildsRequest, opts ...client.CallOption) (*PruneBuildsResponse, error) {
+	req := c.c.NewRequest(c.name, "Runtime.PruneBuilds", in)
+	out := new(PruneBuildsResponse)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // Server API for Runtime service
 
 type RuntimeHandler interface {
@@ -182,6 +342,17 @@ type RuntimeHandler interface {
 	Logs(context.Context, *LogsRequest, Runtime_LogsStream) error
 	CreateNamespace(context.Context, *CreateNamespaceRequest, *CreateNamespaceResponse) error
 	DeleteNamespace(context.Context, *DeleteNamespaceRequest, *DeleteNamespaceResponse) error
+	History(context.Context, *HistoryRequest, *HistoryResponse) error
+	Rollback(context.Context, *RollbackRequest, *RollbackResponse) error
+	Watch(context.Context, *WatchRequest, Runtime_WatchStream) error
+	CreateJob(context.Context, *CreateJobRequest, *CreateJobResponse) error
+	ReadJobs(context.Context, *ReadJobsRequest, *ReadJobsResponse) error
+	DeleteJob(context.Context, *DeleteJobRequest, *DeleteJobResponse) error
+	TriggerJob(context.Context, *TriggerJobRequest, *TriggerJobResponse) error
+	JobRuns(context.Context, *JobRunsRequest, *JobRunsResponse) error
+	JobLogs(context.Context, *JobLogsRequest, *JobLogsResponse) error
+	ReadBuilds(context.Context, *ReadBuildsRequest, *ReadBuildsResponse) error
+	PruneBuilds(context.Context, *PruneBuildsRequest, *PruneBuildsResponse) error
 }
 
 func RegisterRuntimeHandler(s server.Server, hdlr RuntimeHandler, opts ...server.HandlerOption) error {
@@ -193,6 +364,17 @@ func RegisterRuntimeHandler(s server.Server, hdlr RuntimeHandler, opts ...server
 		Logs(ctx context.Context, stream server.Stream) error
 		CreateNamespace(ctx context.Context, in *CreateNamespaceRequest, out *CreateNamespaceResponse) error
 		DeleteNamespace(ctx context.Context, in *DeleteNamespaceRequest, out *DeleteNamespaceResponse) error
+		History(ctx context.Context, in *HistoryRequest, out *HistoryResponse) error
+		Rollback(ctx context.Context, in *RollbackRequest, out *RollbackResponse) error
+		Watch(ctx context.Context, stream server.Stream) error
+		CreateJob(ctx context.Context, in *CreateJobRequest, out *CreateJobResponse) error
+		ReadJobs(ctx context.Context, in *ReadJobsRequest, out *ReadJobsResponse) error
+		DeleteJob(ctx context.Context, in *DeleteJobRequest, out *DeleteJobResponse) error
+		TriggerJob(ctx context.Context, in *TriggerJobRequest, out *TriggerJobResponse) error
+		JobRuns(ctx context.Context, in *JobRunsRequest, out *JobRunsResponse) error
+		JobLogs(ctx context.Context, in *JobLogsRequest, out *JobLogsResponse) error
+		ReadBuilds(ctx context.Context, in *ReadBuildsRequest, out *ReadBuildsResponse) error
+		PruneBuilds(ctx context.Context, in *PruneBuildsRequest, out *PruneBuildsResponse) error
 	}
 	type Runtime struct {
 		runtime
@@ -268,3 +450,83 @@ func (h *runtimeHandler) CreateNamespace(ctx context.Context, in *CreateNamespac
 func (h *runtimeHandler) DeleteNamespace(ctx context.Context, in *DeleteNamespaceRequest, out *DeleteNamespaceResponse) error {
 	return h.RuntimeHandler.DeleteNamespace(ctx, in, out)
 }
+
+func (h *runtimeHandler) History(ctx context.Context, in *HistoryRequest, out *HistoryResponse) error {
+	return h.RuntimeHandler.History(ctx, in, out)
+}
+
+func (h *runtimeHandler) Rollback(ctx context.Context, in *RollbackRequest, out *RollbackResponse) error {
+	return h.RuntimeHandler.Rollback(ctx, in, out)
+}
+
+func (h *runtimeHandler) Watch(ctx context.Context, stream server.Stream) error {
+	m := new(WatchRequest)
+	if err := stream.Recv(m); err != nil {
+		return err
+	}
+	return h.RuntimeHandler.Watch(ctx, m, &runtimeWatchStream{stream})
+}
+
+type Runtime_WatchStream interface {
+	Context() context.Context
+	SendMsg(interface{}) error
+	RecvMsg(interface{}) error
+	Close() error
+	Send(*StatusEvent) error
+}
+
+type runtimeWatchStream struct {
+	stream server.Stream
+}
+
+func (x *runtimeWatchStream) Close() error {
+	return x.stream.Close()
+}
+
+func (x *runtimeWatchStream) Context() context.Context {
+	return x.stream.Context()
+}
+
+func (x *runtimeWatchStream) SendMsg(m interface{}) error {
+	return x.stream.Send(m)
+}
+
+func (x *runtimeWatchStream) RecvMsg(m interface{}) error {
+	return x.stream.Recv(m)
+}
+
+func (x *runtimeWatchStream) Send(m *StatusEvent) error {
+	return x.stream.Send(m)
+}
+
+func (h *runtimeHandler) CreateJob(ctx context.Context, in *CreateJobRequest, out *CreateJobResponse) error {
+	return h.RuntimeHandler.CreateJob(ctx, in, out)
+}
+
+func (h *runtimeHandler) ReadJobs(ctx context.Context, in *ReadJobsRequest, out *ReadJobsResponse) error {
+	return h.RuntimeHandler.ReadJobs(ctx, in, out)
+}
+
+func (h *runtimeHandler) DeleteJob(ctx context.Context, in *DeleteJobRequest, out *DeleteJobResponse) error {
+	return h.RuntimeHandler.DeleteJob(ctx, in, out)
+}
+
+func (h *runtimeHandler) TriggerJob(ctx context.Context, in *TriggerJobRequest, out *TriggerJobResponse) error {
+	return h.RuntimeHandler.TriggerJob(ctx, in, out)
+}
+
+func (h *runtimeHandler) JobRuns(ctx context.Context, in *JobRunsRequest, out *JobRunsResponse) error {
+	return h.RuntimeHandler.JobRuns(ctx, in, out)
+}
+
+func (h *runtimeHandler) JobLogs(ctx context.Context, in *JobLogsRequest, out *JobLogsResponse) error {
+	return h.RuntimeHandler.JobLogs(ctx, in, out)
+}
+
+func (h *runtimeHandler) ReadBuilds(ctx context.Context, in *ReadBuildsRequest, out *ReadBuildsResponse) error {
+	return h.RuntimeHandler.ReadBuilds(ctx, in, out)
+}
+
+func (h *runtimeHandler) PruneBuilds(ctx context.Context, in *PruneBuildsRequest, out *PruneBuildsResponse) error {
+	return h.RuntimeHandler.PruneBuilds(ctx, in, out)
+}
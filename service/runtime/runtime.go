@@ -2,6 +2,8 @@
 package runtime
 
 import (
+	"time"
+
 	"github.com/micro/go-micro/v3/runtime"
 	"github.com/micro/micro/v3/service/runtime/client"
 )
@@ -45,3 +47,105 @@ func CreateNamespace(ns string) error {
 func DeleteNamespace(ns string) error {
 	return DefaultRuntime.DeleteNamespace(ns)
 }
+
+// History returns the revisions of a service, oldest first
+func History(srv *runtime.Service, namespace string) ([]*client.Revision, error) {
+	h, ok := DefaultRuntime.(client.Historian)
+	if !ok {
+		return nil, client.ErrHistoryNotSupported
+	}
+	return h.History(srv, namespace)
+}
+
+// Rollback redeploys a revision of a service, zero is the previous revision
+func Rollback(srv *runtime.Service, revision int, namespace string) error {
+	h, ok := DefaultRuntime.(client.Historian)
+	if !ok {
+		return client.ErrHistoryNotSupported
+	}
+	return h.Rollback(srv, revision, namespace)
+}
+
+// Watch streams the status changes of the services in a namespace, the changes of a single
+// service are streamed if a name is provided
+func Watch(service, namespace string) (<-chan *client.StatusEvent, error) {
+	w, ok := DefaultRuntime.(client.StatusWatcher)
+	if !ok {
+		return nil, client.ErrWatchNotSupported
+	}
+	return w.Watch(service, namespace)
+}
+
+// CreateJob creates a job which runs on a schedule, an existing job with the same name is replaced
+func CreateJob(job *client.Job, namespace string) error {
+	s, ok := DefaultRuntime.(client.JobScheduler)
+	if !ok {
+		return client.ErrJobsNotSupported
+	}
+	return s.CreateJob(job, namespace)
+}
+
+// ReadJobs returns the jobs in a namespace, or the job with the name provided
+func ReadJobs(name, namespace string) ([]*client.Job, error) {
+	s, ok := DefaultRuntime.(client.JobScheduler)
+	if !ok {
+		return nil, client.ErrJobsNotSupported
+	}
+	return s.ReadJobs(name, namespace)
+}
+
+// DeleteJob deletes a job and its runs
+func DeleteJob(name, namespace string) error {
+	s, ok := DefaultRuntime.(client.JobScheduler)
+	if !ok {
+		return client.ErrJobsNotSupported
+	}
+	return s.DeleteJob(name, namespace)
+}
+
+// TriggerJob starts a run of a job now
+func TriggerJob(name, namespace string) (*client.JobRun, error) {
+	s, ok := DefaultRuntime.(client.JobScheduler)
+	if !ok {
+		return nil, client.ErrJobsNotSupported
+	}
+	return s.TriggerJob(name, namespace)
+}
+
+// JobRuns returns the runs of a job, oldest first
+func JobRuns(name, namespace string) ([]*client.JobRun, error) {
+	s, ok := DefaultRuntime.(client.JobScheduler)
+	if !ok {
+		return nil, client.ErrJobsNotSupported
+	}
+	return s.JobRuns(name, namespace)
+}
+
+// JobLogs returns the logs of a run of a job, zero is the latest run
+func JobLogs(name string, run int, namespace string) ([]runtime.Log, error) {
+	s, ok := DefaultRuntime.(client.JobScheduler)
+	if !ok {
+		return nil, client.ErrJobsNotSupported
+	}
+	return s.JobLogs(name, run, namespace)
+}
+
+// ReadBuilds returns the builds of the services in a namespace, or the builds of the service
+// with the name provided
+func ReadBuilds(name, namespace string) ([]*client.Build, error) {
+	b, ok := DefaultRuntime.(client.Builder)
+	if !ok {
+		return nil, client.ErrBuildsNotSupported
+	}
+	return b.ReadBuilds(name, namespace)
+}
+
+// PruneBuilds deletes the builds in a namespace which aren't in use and haven't been used for
+// the duration, the builds deleted are returned
+func PruneBuilds(unusedFor time.Duration, namespace string) ([]*client.Build, error) {
+	b, ok := DefaultRuntime.(client.Builder)
+	if !ok {
+		return nil, client.ErrBuildsNotSupported
+	}
+	return b.PruneBuilds(unusedFor, namespace)
+}
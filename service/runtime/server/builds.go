@@ -0,0 +1,92 @@
+package server
+
+import (
+	"context"
+	"time"
+
+	"github.com/micro/micro/v3/internal/namespace"
+	"github.com/micro/micro/v3/service/errors"
+	log "github.com/micro/micro/v3/service/logger"
+	"github.com/micro/micro/v3/service/runtime/client"
+	pb "github.com/micro/micro/v3/service/runtime/proto"
+)
+
+func (r *Runtime) ReadBuilds(ctx context.Context, req *pb.ReadBuildsRequest, rsp *pb.ReadBuildsResponse) error {
+	// set defaults
+	if req.Options == nil {
+		req.Options = &pb.BuildOptions{}
+	}
+	if len(req.Options.Namespace) == 0 {
+		req.Options.Namespace = namespace.DefaultNamespace
+	}
+
+	if err := authorizeNamespace(ctx, "runtime.Runtime.ReadBuilds", req.Options.Namespace); err != nil {
+		return err
+	}
+
+	b, ok := r.Runtime.(client.Builder)
+	if !ok {
+		return errors.InternalServerError("runtime.Runtime.ReadBuilds", client.ErrBuildsNotSupported.Error())
+	}
+
+	builds, err := b.ReadBuilds(req.Name, req.Options.Namespace)
+	if err != nil {
+		return errors.InternalServerError("runtime.Runtime.ReadBuilds", err.Error())
+	}
+
+	for _, build := range builds {
+		rsp.Builds = append(rsp.Builds, toProtoBuild(build))
+	}
+
+	return nil
+}
+
+func (r *Runtime) PruneBuilds(ctx context.Context, req *pb.PruneBuildsRequest, rsp *pb.PruneBuildsResponse) error {
+	// validate the request
+	if req.UnusedFor < 0 {
+		return errors.BadRequest("runtime.Runtime.PruneBuilds", "invalid unused for")
+	}
+
+	// set defaults
+	if req.Options == nil {
+		req.Options = &pb.BuildOptions{}
+	}
+	if len(req.Options.Namespace) == 0 {
+		req.Options.Namespace = namespace.DefaultNamespace
+	}
+
+	if err := authorizeNamespace(ctx, "runtime.Runtime.PruneBuilds", req.Options.Namespace); err != nil {
+		return err
+	}
+
+	b, ok := r.Runtime.(client.Builder)
+	if !ok {
+		return errors.InternalServerError("runtime.Runtime.PruneBuilds", client.ErrBuildsNotSupported.Error())
+	}
+
+	log.Infof("Pruning builds in namespace %s", req.Options.Namespace)
+	builds, err := b.PruneBuilds(time.Duration(req.UnusedFor)*time.Second, req.Options.Namespace)
+	if err != nil {
+		return errors.InternalServerError("runtime.Runtime.PruneBuilds", err.Error())
+	}
+
+	for _, build := range builds {
+		rsp.Builds = append(rsp.Builds, toProtoBuild(build))
+	}
+
+	return nil
+}
+
+func toProtoBuild(b *client.Build) *pb.Build {
+	return &pb.Build{
+		Id:       b.ID,
+		Name:     b.Name,
+		Version:  b.Version,
+		Source:   b.Source,
+		SumHash:  b.SumHash,
+		Size:     b.Size,
+		Created:  b.Created.Unix(),
+		LastUsed: b.LastUsed.Unix(),
+		InUse:    b.InUse,
+	}
+}
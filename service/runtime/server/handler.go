@@ -13,11 +13,15 @@ import (
 	"github.com/micro/micro/v3/service/events"
 	log "github.com/micro/micro/v3/service/logger"
 	"github.com/micro/micro/v3/service/runtime"
+	"github.com/micro/micro/v3/service/runtime/client"
 	pb "github.com/micro/micro/v3/service/runtime/proto"
 )
 
 type Runtime struct {
 	Runtime gorun.Runtime
+
+	// watchers of the status changes of services
+	watchers statusWatchers
 }
 
 func (r *Runtime) Read(ctx context.Context, req *pb.ReadRequest, rsp *pb.ReadResponse) error {
@@ -80,10 +84,13 @@ func (r *Runtime) Create(ctx context.Context, req *pb.CreateRequest, rsp *pb.Cre
 	service := toService(req.Service)
 	setupServiceMeta(ctx, service)
 
-	options := toCreateOptions(ctx, req.Options)
+	options := toCreateOptions(ctx, req.Service, req.Options)
 
 	log.Infof("Creating service %s version %s source %s", service.Name, service.Version, service.Source)
 	if err := r.Runtime.Create(service, options...); err != nil {
+		if _, ok := err.(*client.DependencyCycleError); ok {
+			return errors.BadRequest("runtime.Runtime.Create", err.Error())
+		}
 		return errors.InternalServerError("runtime.Runtime.Create", err.Error())
 	}
 
@@ -126,7 +133,7 @@ func (r *Runtime) Update(ctx context.Context, req *pb.UpdateRequest, rsp *pb.Upd
 	service := toService(req.Service)
 	setupServiceMeta(ctx, service)
 
-	options := toUpdateOptions(ctx, req.Options)
+	options := toUpdateOptions(ctx, req.Service, req.Options)
 
 	log.Infof("Updating service %s version %s source %s", service.Name, service.Version, service.Source)
 
@@ -236,6 +243,9 @@ func (r *Runtime) Logs(ctx context.Context, req *pb.LogsRequest, stream pb.Runti
 	if req.GetStream() {
 		opts = append(opts, gorun.LogsStream(req.GetStream()))
 	}
+	if req.GetSince() > 0 {
+		opts = append(opts, client.LogsSince(time.Now().Add(-time.Duration(req.GetSince())*time.Second)))
+	}
 
 	logStream, err := r.Runtime.Logs(&gorun.Service{
 		Name: req.GetService(),
@@ -254,10 +264,16 @@ func (r *Runtime) Logs(ctx context.Context, req *pb.LogsRequest, stream pb.Runti
 				return logStream.Error()
 			}
 			// send record
+			// the runtime manager sets the time persisted records were written
+			var timestamp int64
+			if t, err := time.Parse(time.RFC3339Nano, record.Metadata["timestamp"]); err == nil {
+				timestamp = t.Unix()
+			}
+
 			if err := stream.Send(&pb.LogRecord{
-				//Timestamp: record.Timestamp.Unix(),
-				Metadata: record.Metadata,
-				Message:  record.Message,
+				Timestamp: timestamp,
+				Metadata:  record.Metadata,
+				Message:   record.Message,
 			}); err != nil {
 				return err
 			}
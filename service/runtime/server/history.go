@@ -0,0 +1,118 @@
+package server
+
+import (
+	"context"
+
+	goevents "github.com/micro/go-micro/v3/events"
+	"github.com/micro/micro/v3/internal/namespace"
+	"github.com/micro/micro/v3/service/errors"
+	"github.com/micro/micro/v3/service/events"
+	log "github.com/micro/micro/v3/service/logger"
+	"github.com/micro/micro/v3/service/runtime"
+	"github.com/micro/micro/v3/service/runtime/client"
+	pb "github.com/micro/micro/v3/service/runtime/proto"
+)
+
+func (r *Runtime) History(ctx context.Context, req *pb.HistoryRequest, rsp *pb.HistoryResponse) error {
+	// validate the request
+	if req.Service == nil || len(req.Service.Name) == 0 {
+		return errors.BadRequest("runtime.Runtime.History", "blank service")
+	}
+
+	// set defaults
+	if req.Options == nil {
+		req.Options = &pb.HistoryOptions{}
+	}
+	if len(req.Options.Namespace) == 0 {
+		req.Options.Namespace = namespace.DefaultNamespace
+	}
+
+	// authorize the request
+	if err := namespace.Authorize(ctx, req.Options.Namespace); err == namespace.ErrForbidden {
+		return errors.Forbidden("runtime.Runtime.History", err.Error())
+	} else if err == namespace.ErrUnauthorized {
+		return errors.Unauthorized("runtime.Runtime.History", err.Error())
+	} else if err != nil {
+		return errors.InternalServerError("runtime.Runtime.History", err.Error())
+	}
+
+	h, ok := r.Runtime.(client.Historian)
+	if !ok {
+		return errors.InternalServerError("runtime.Runtime.History", client.ErrHistoryNotSupported.Error())
+	}
+
+	revisions, err := h.History(toService(req.Service), req.Options.Namespace)
+	if err != nil {
+		return errors.InternalServerError("runtime.Runtime.History", err.Error())
+	}
+
+	for _, rev := range revisions {
+		rsp.Revisions = append(rsp.Revisions, &pb.Revision{
+			Number:     int64(rev.Number),
+			Source:     rev.Source,
+			Revision:   rev.Revision,
+			Build:      rev.Build,
+			Image:      rev.Image,
+			Env:        rev.Env,
+			DeployedBy: rev.DeployedBy,
+			Deployed:   rev.Deployed.Unix(),
+		})
+	}
+
+	return nil
+}
+
+func (r *Runtime) Rollback(ctx context.Context, req *pb.RollbackRequest, rsp *pb.RollbackResponse) error {
+	// validate the request
+	if req.Service == nil || len(req.Service.Name) == 0 {
+		return errors.BadRequest("runtime.Runtime.Rollback", "blank service")
+	}
+	if req.Options != nil && req.Options.Revision < 0 {
+		return errors.BadRequest("runtime.Runtime.Rollback", "invalid revision")
+	}
+
+	// set defaults
+	if req.Options == nil {
+		req.Options = &pb.RollbackOptions{}
+	}
+	if len(req.Options.Namespace) == 0 {
+		req.Options.Namespace = namespace.DefaultNamespace
+	}
+
+	// authorize the request
+	if err := namespace.Authorize(ctx, req.Options.Namespace); err == namespace.ErrForbidden {
+		return errors.Forbidden("runtime.Runtime.Rollback", err.Error())
+	} else if err == namespace.ErrUnauthorized {
+		return errors.Unauthorized("runtime.Runtime.Rollback", err.Error())
+	} else if err != nil {
+		return errors.InternalServerError("runtime.Runtime.Rollback", err.Error())
+	}
+
+	h, ok := r.Runtime.(client.Historian)
+	if !ok {
+		return errors.InternalServerError("runtime.Runtime.Rollback", client.ErrHistoryNotSupported.Error())
+	}
+
+	// record who rolled the service back
+	service := toService(req.Service)
+	setupServiceMeta(ctx, service)
+
+	log.Infof("Rolling back service %s version %s to revision %d", service.Name, service.Version, req.Options.Revision)
+	if err := h.Rollback(service, int(req.Options.Revision), req.Options.Namespace); err == client.ErrRevisionNotFound {
+		return errors.NotFound("runtime.Runtime.Rollback", err.Error())
+	} else if err != nil {
+		return errors.InternalServerError("runtime.Runtime.Rollback", err.Error())
+	}
+
+	// a rollback is published as an update of the service
+	ev := &runtime.EventPayload{
+		Service:   service,
+		Namespace: req.Options.Namespace,
+		Type:      runtime.EventServiceUpdated,
+	}
+
+	return events.Publish(runtime.EventTopic, ev, goevents.WithMetadata(map[string]string{
+		"type":      runtime.EventServiceUpdated,
+		"namespace": req.Options.Namespace,
+	}))
+}
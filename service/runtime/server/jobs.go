@@ -0,0 +1,333 @@
+package server
+
+import (
+	"context"
+	"time"
+
+	gorun "github.com/micro/go-micro/v3/runtime"
+	"github.com/micro/micro/v3/internal/cron"
+	"github.com/micro/micro/v3/internal/namespace"
+	"github.com/micro/micro/v3/service/errors"
+	log "github.com/micro/micro/v3/service/logger"
+	"github.com/micro/micro/v3/service/runtime/client"
+	pb "github.com/micro/micro/v3/service/runtime/proto"
+)
+
+func (r *Runtime) CreateJob(ctx context.Context, req *pb.CreateJobRequest, rsp *pb.CreateJobResponse) error {
+	// validate the request
+	if req.Job == nil || len(req.Job.Name) == 0 {
+		return errors.BadRequest("runtime.Runtime.CreateJob", "blank job")
+	}
+	if len(req.Job.Schedule) > 0 {
+		if _, err := cron.Parse(req.Job.Schedule); err != nil {
+			return errors.BadRequest("runtime.Runtime.CreateJob", err.Error())
+		}
+	}
+	switch req.Job.ConcurrencyPolicy {
+	case "", client.ConcurrencyAllow, client.ConcurrencyForbid, client.ConcurrencyReplace:
+	default:
+		return errors.BadRequest("runtime.Runtime.CreateJob", "invalid concurrency policy %v", req.Job.ConcurrencyPolicy)
+	}
+	if req.Job.Timeout < 0 {
+		return errors.BadRequest("runtime.Runtime.CreateJob", "invalid timeout")
+	}
+
+	// set defaults
+	if req.Options == nil {
+		req.Options = &pb.JobOptions{}
+	}
+	if len(req.Options.Namespace) == 0 {
+		req.Options.Namespace = namespace.DefaultNamespace
+	}
+	if req.Job.Options == nil {
+		req.Job.Options = &pb.CreateOptions{}
+	}
+
+	if err := authorizeNamespace(ctx, "runtime.Runtime.CreateJob", req.Options.Namespace); err != nil {
+		return err
+	}
+
+	s, ok := r.Runtime.(client.JobScheduler)
+	if !ok {
+		return errors.InternalServerError("runtime.Runtime.CreateJob", client.ErrJobsNotSupported.Error())
+	}
+
+	// the owner of the job is the account which created it
+	job := toJob(req.Job)
+	srv := toService(&pb.Service{Name: job.Name, Metadata: job.Metadata})
+	setupServiceMeta(ctx, srv)
+	job.Metadata = srv.Metadata
+
+	log.Infof("Creating job %s version %s with schedule %q", job.Name, job.Version, job.Schedule)
+	if err := s.CreateJob(job, req.Options.Namespace); err != nil {
+		return errors.InternalServerError("runtime.Runtime.CreateJob", err.Error())
+	}
+
+	return nil
+}
+
+func (r *Runtime) ReadJobs(ctx context.Context, req *pb.ReadJobsRequest, rsp *pb.ReadJobsResponse) error {
+	// set defaults
+	if req.Options == nil {
+		req.Options = &pb.JobOptions{}
+	}
+	if len(req.Options.Namespace) == 0 {
+		req.Options.Namespace = namespace.DefaultNamespace
+	}
+
+	if err := authorizeNamespace(ctx, "runtime.Runtime.ReadJobs", req.Options.Namespace); err != nil {
+		return err
+	}
+
+	s, ok := r.Runtime.(client.JobScheduler)
+	if !ok {
+		return errors.InternalServerError("runtime.Runtime.ReadJobs", client.ErrJobsNotSupported.Error())
+	}
+
+	jobs, err := s.ReadJobs(req.Name, req.Options.Namespace)
+	if err != nil {
+		return errors.InternalServerError("runtime.Runtime.ReadJobs", err.Error())
+	}
+
+	for _, job := range jobs {
+		rsp.Jobs = append(rsp.Jobs, toProtoJob(job))
+	}
+
+	return nil
+}
+
+func (r *Runtime) DeleteJob(ctx context.Context, req *pb.DeleteJobRequest, rsp *pb.DeleteJobResponse) error {
+	// validate the request
+	if len(req.Name) == 0 {
+		return errors.BadRequest("runtime.Runtime.DeleteJob", "blank job")
+	}
+
+	// set defaults
+	if req.Options == nil {
+		req.Options = &pb.JobOptions{}
+	}
+	if len(req.Options.Namespace) == 0 {
+		req.Options.Namespace = namespace.DefaultNamespace
+	}
+
+	if err := authorizeNamespace(ctx, "runtime.Runtime.DeleteJob", req.Options.Namespace); err != nil {
+		return err
+	}
+
+	s, ok := r.Runtime.(client.JobScheduler)
+	if !ok {
+		return errors.InternalServerError("runtime.Runtime.DeleteJob", client.ErrJobsNotSupported.Error())
+	}
+
+	log.Infof("Deleting job %s", req.Name)
+	if err := s.DeleteJob(req.Name, req.Options.Namespace); err == client.ErrJobNotFound {
+		return errors.NotFound("runtime.Runtime.DeleteJob", err.Error())
+	} else if err != nil {
+		return errors.InternalServerError("runtime.Runtime.DeleteJob", err.Error())
+	}
+
+	return nil
+}
+
+func (r *Runtime) TriggerJob(ctx context.Context, req *pb.TriggerJobRequest, rsp *pb.TriggerJobResponse) error {
+	// validate the request
+	if len(req.Name) == 0 {
+		return errors.BadRequest("runtime.Runtime.TriggerJob", "blank job")
+	}
+
+	// set defaults
+	if req.Options == nil {
+		req.Options = &pb.JobOptions{}
+	}
+	if len(req.Options.Namespace) == 0 {
+		req.Options.Namespace = namespace.DefaultNamespace
+	}
+
+	if err := authorizeNamespace(ctx, "runtime.Runtime.TriggerJob", req.Options.Namespace); err != nil {
+		return err
+	}
+
+	s, ok := r.Runtime.(client.JobScheduler)
+	if !ok {
+		return errors.InternalServerError("runtime.Runtime.TriggerJob", client.ErrJobsNotSupported.Error())
+	}
+
+	log.Infof("Triggering job %s", req.Name)
+	run, err := s.TriggerJob(req.Name, req.Options.Namespace)
+	if err == client.ErrJobNotFound {
+		return errors.NotFound("runtime.Runtime.TriggerJob", err.Error())
+	} else if err == client.ErrJobRunning {
+		return errors.Conflict("runtime.Runtime.TriggerJob", err.Error())
+	} else if err != nil {
+		return errors.InternalServerError("runtime.Runtime.TriggerJob", err.Error())
+	}
+
+	rsp.Run = toProtoJobRun(run)
+	return nil
+}
+
+func (r *Runtime) JobRuns(ctx context.Context, req *pb.JobRunsRequest, rsp *pb.JobRunsResponse) error {
+	// validate the request
+	if len(req.Name) == 0 {
+		return errors.BadRequest("runtime.Runtime.JobRuns", "blank job")
+	}
+
+	// set defaults
+	if req.Options == nil {
+		req.Options = &pb.JobOptions{}
+	}
+	if len(req.Options.Namespace) == 0 {
+		req.Options.Namespace = namespace.DefaultNamespace
+	}
+
+	if err := authorizeNamespace(ctx, "runtime.Runtime.JobRuns", req.Options.Namespace); err != nil {
+		return err
+	}
+
+	s, ok := r.Runtime.(client.JobScheduler)
+	if !ok {
+		return errors.InternalServerError("runtime.Runtime.JobRuns", client.ErrJobsNotSupported.Error())
+	}
+
+	runs, err := s.JobRuns(req.Name, req.Options.Namespace)
+	if err == client.ErrJobNotFound {
+		return errors.NotFound("runtime.Runtime.JobRuns", err.Error())
+	} else if err != nil {
+		return errors.InternalServerError("runtime.Runtime.JobRuns", err.Error())
+	}
+
+	for _, run := range runs {
+		rsp.Runs = append(rsp.Runs, toProtoJobRun(run))
+	}
+
+	return nil
+}
+
+func (r *Runtime) JobLogs(ctx context.Context, req *pb.JobLogsRequest, rsp *pb.JobLogsResponse) error {
+	// validate the request
+	if len(req.Name) == 0 {
+		return errors.BadRequest("runtime.Runtime.JobLogs", "blank job")
+	}
+	if req.Run < 0 {
+		return errors.BadRequest("runtime.Runtime.JobLogs", "invalid run")
+	}
+
+	// set defaults
+	if req.Options == nil {
+		req.Options = &pb.JobOptions{}
+	}
+	if len(req.Options.Namespace) == 0 {
+		req.Options.Namespace = namespace.DefaultNamespace
+	}
+
+	if err := authorizeNamespace(ctx, "runtime.Runtime.JobLogs", req.Options.Namespace); err != nil {
+		return err
+	}
+
+	s, ok := r.Runtime.(client.JobScheduler)
+	if !ok {
+		return errors.InternalServerError("runtime.Runtime.JobLogs", client.ErrJobsNotSupported.Error())
+	}
+
+	logs, err := s.JobLogs(req.Name, int(req.Run), req.Options.Namespace)
+	if err == client.ErrJobNotFound {
+		return errors.NotFound("runtime.Runtime.JobLogs", err.Error())
+	} else if err != nil {
+		return errors.InternalServerError("runtime.Runtime.JobLogs", err.Error())
+	}
+
+	for _, l := range logs {
+		rsp.Records = append(rsp.Records, &pb.LogRecord{
+			Message:  l.Message,
+			Metadata: l.Metadata,
+		})
+	}
+
+	return nil
+}
+
+// authorizeNamespace checks the account in the context can manage the resources of the namespace
+func authorizeNamespace(ctx context.Context, method, ns string) error {
+	if err := namespace.Authorize(ctx, ns); err == namespace.ErrForbidden {
+		return errors.Forbidden(method, err.Error())
+	} else if err == namespace.ErrUnauthorized {
+		return errors.Unauthorized(method, err.Error())
+	} else if err != nil {
+		return errors.InternalServerError(method, err.Error())
+	}
+	return nil
+}
+
+func toJob(j *pb.Job) *client.Job {
+	version := j.Version
+	if len(version) == 0 {
+		version = "latest"
+	}
+	policy := j.ConcurrencyPolicy
+	if len(policy) == 0 {
+		policy = client.ConcurrencyAllow
+	}
+
+	job := &client.Job{
+		Name:              j.Name,
+		Version:           version,
+		Source:            j.Source,
+		Schedule:          j.Schedule,
+		ConcurrencyPolicy: policy,
+		Timeout:           time.Duration(j.Timeout) * time.Second,
+		Metadata:          j.Metadata,
+	}
+
+	// the runs are created with the same options as services
+	job.Options = &gorun.CreateOptions{}
+	if j.Options != nil {
+		for _, o := range toCreateOptions(context.Background(), &pb.Service{}, j.Options) {
+			o(job.Options)
+		}
+	}
+
+	return job
+}
+
+func toProtoJob(j *client.Job) *pb.Job {
+	job := &pb.Job{
+		Name:              j.Name,
+		Version:           j.Version,
+		Source:            j.Source,
+		Schedule:          j.Schedule,
+		ConcurrencyPolicy: j.ConcurrencyPolicy,
+		Timeout:           int64(j.Timeout.Seconds()),
+		Metadata:          j.Metadata,
+	}
+	if o := j.Options; o != nil {
+		job.Options = &pb.CreateOptions{
+			Command: o.Command,
+			Args:    o.Args,
+			Env:     o.Env,
+			Type:    o.Type,
+			Image:   o.Image,
+		}
+	}
+	if j.LastRun != nil {
+		job.LastRun = toProtoJobRun(j.LastRun)
+	}
+	if !j.Next.IsZero() {
+		job.Next = j.Next.Unix()
+	}
+	return job
+}
+
+func toProtoJobRun(r *client.JobRun) *pb.JobRun {
+	run := &pb.JobRun{
+		Number:   int64(r.Number),
+		Status:   r.Status,
+		ExitCode: int64(r.ExitCode),
+		Error:    r.Error,
+		Trigger:  r.Trigger,
+		Started:  r.Started.Unix(),
+	}
+	if !r.Finished.IsZero() {
+		run.Finished = r.Finished.Unix()
+	}
+	return run
+}
@@ -1,7 +1,10 @@
 package server
 
 import (
+	"fmt"
 	"os"
+	"strings"
+	"time"
 
 	"github.com/micro/cli/v2"
 	goruntime "github.com/micro/go-micro/v3/runtime"
@@ -30,6 +33,17 @@ var (
 			Usage:   "Set the max retries per service",
 			EnvVars: []string{"MICRO_RUNTIME_RETRIES"},
 		},
+		&cli.DurationFlag{
+			Name:    "log_retention",
+			Usage:   "Set how long the logs of services are kept, e.g. 72h",
+			EnvVars: []string{"MICRO_RUNTIME_LOG_RETENTION"},
+			Value:   manager.DefaultLogRetention,
+		},
+		&cli.StringSliceFlag{
+			Name:    "log_retention_namespace",
+			Usage:   "Set how long the logs of services in a namespace are kept, e.g. foo=168h",
+			EnvVars: []string{"MICRO_RUNTIME_LOG_RETENTION_NAMESPACE"},
+		},
 	}
 )
 
@@ -60,7 +74,22 @@ func Run(ctx *cli.Context) error {
 	srv := service.New(srvOpts...)
 
 	// create a new runtime manager
-	manager := manager.New()
+	var mgrOpts []manager.Option
+	if ctx.IsSet("log_retention") {
+		mgrOpts = append(mgrOpts, manager.LogRetention(ctx.Duration("log_retention")))
+	}
+	for _, r := range ctx.StringSlice("log_retention_namespace") {
+		parts := strings.SplitN(r, "=", 2)
+		if len(parts) != 2 {
+			return fmt.Errorf("invalid log retention %v, expected namespace=duration", r)
+		}
+		d, err := time.ParseDuration(parts[1])
+		if err != nil {
+			return fmt.Errorf("invalid log retention for namespace %v: %v", parts[0], err)
+		}
+		mgrOpts = append(mgrOpts, manager.NamespaceLogRetention(parts[0], d))
+	}
+	manager := manager.New(mgrOpts...)
 
 	// start the manager
 	if err := manager.Start(); err != nil {
@@ -8,18 +8,23 @@ import (
 	"io"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
 
 	"github.com/micro/go-micro/v3/runtime"
+	"github.com/micro/micro/v3/service/runtime/client"
 	pb "github.com/micro/micro/v3/service/runtime/proto"
 )
 
 func toProto(s *runtime.Service) *pb.Service {
+	replicas, _ := strconv.ParseInt(s.Metadata["replicas"], 10, 64)
+
 	return &pb.Service{
 		Name:     s.Name,
 		Version:  s.Version,
 		Source:   s.Source,
 		Metadata: s.Metadata,
+		Replicas: replicas,
 	}
 }
 
@@ -32,11 +37,21 @@ func toService(s *pb.Service) *runtime.Service {
 	}
 }
 
-func toCreateOptions(ctx context.Context, opts *pb.CreateOptions) []runtime.CreateOption {
+func toCreateOptions(ctx context.Context, srv *pb.Service, opts *pb.CreateOptions) []runtime.CreateOption {
 	options := []runtime.CreateOption{
 		runtime.CreateNamespace(opts.Namespace),
 	}
 
+	// run multiple instances
+	if srv.Replicas > 0 {
+		options = append(options, client.CreateReplicas(int(srv.Replicas)))
+	}
+
+	// wait for the services it depends on
+	if len(opts.DependsOn) > 0 {
+		options = append(options, client.CreateDependsOn(opts.DependsOn...))
+	}
+
 	// command options
 	if len(opts.Command) > 0 {
 		options = append(options, runtime.WithCommand(opts.Command...))
@@ -67,6 +82,11 @@ func toCreateOptions(ctx context.Context, opts *pb.CreateOptions) []runtime.Crea
 		options = append(options, runtime.WithSecret(k, v))
 	}
 
+	// limit the resources
+	if r := toResources(opts.Resources); r != nil {
+		options = append(options, runtime.ResourceLimits(r))
+	}
+
 	// TODO: output options
 
 	return options
@@ -90,10 +110,40 @@ func toReadOptions(ctx context.Context, opts *pb.ReadOptions) []runtime.ReadOpti
 	return options
 }
 
-func toUpdateOptions(ctx context.Context, opts *pb.UpdateOptions) []runtime.UpdateOption {
-	return []runtime.UpdateOption{
+func toUpdateOptions(ctx context.Context, srv *pb.Service, opts *pb.UpdateOptions) []runtime.UpdateOption {
+	options := []runtime.UpdateOption{
 		runtime.UpdateNamespace(opts.Namespace),
 	}
+
+	// change the resources
+	if r := toResources(opts.Resources); r != nil {
+		options = append(options, client.UpdateResources(r))
+	}
+
+	// scale the service
+	if srv.Replicas > 0 {
+		options = append(options, client.UpdateReplicas(int(srv.Replicas)))
+	}
+	if a := opts.Autoscale; a != nil {
+		options = append(options, client.UpdateAutoscale(&client.Autoscale{
+			Min:       int(a.Min),
+			Max:       int(a.Max),
+			TargetRPS: a.TargetRps,
+		}))
+	}
+
+	return options
+}
+
+func toResources(r *pb.Resources) *runtime.Resources {
+	if r == nil {
+		return nil
+	}
+	return &runtime.Resources{
+		CPU:  int(r.Cpu),
+		Mem:  int(r.Mem),
+		Disk: int(r.Disk),
+	}
 }
 
 func toDeleteOptions(ctx context.Context, opts *pb.DeleteOptions) []runtime.DeleteOption {
@@ -103,9 +153,22 @@ func toDeleteOptions(ctx context.Context, opts *pb.DeleteOptions) []runtime.Dele
 }
 
 func toLogsOptions(ctx context.Context, opts *pb.LogsOptions) []runtime.LogsOption {
-	return []runtime.LogsOption{
+	options := []runtime.LogsOption{
 		runtime.LogsNamespace(opts.Namespace),
 	}
+
+	// filter the persisted logs
+	if len(opts.Level) > 0 {
+		options = append(options, client.LogsLevel(opts.Level))
+	}
+	if len(opts.Grep) > 0 {
+		options = append(options, client.LogsGrep(opts.Grep))
+	}
+	if len(opts.Version) > 0 {
+		options = append(options, client.LogsVersion(opts.Version))
+	}
+
+	return options
 }
 
 // taken from https://gist.github.com/mimoo/25fc9716e0f1353791f5908f94d6e726
@@ -0,0 +1,140 @@
+package server
+
+import (
+	"context"
+	"sync"
+
+	goevents "github.com/micro/go-micro/v3/events"
+	"github.com/micro/micro/v3/internal/namespace"
+	"github.com/micro/micro/v3/service/errors"
+	"github.com/micro/micro/v3/service/events"
+	log "github.com/micro/micro/v3/service/logger"
+	"github.com/micro/micro/v3/service/runtime"
+	pb "github.com/micro/micro/v3/service/runtime/proto"
+)
+
+// statusWatchers fans the status events published by the runtime manager out to the streams of
+// the Watch handler so a single subscription to the events service is shared by every stream
+type statusWatchers struct {
+	sync.Mutex
+	subscribed bool
+	watchers   map[chan *runtime.EventStatusPayload]bool
+}
+
+// watch returns a channel the status events are sent to until stop is called
+func (s *statusWatchers) watch() (chan *runtime.EventStatusPayload, error) {
+	s.Lock()
+	defer s.Unlock()
+
+	if !s.subscribed {
+		evChan, err := events.Subscribe(runtime.EventStatusTopic)
+		if err != nil {
+			return nil, err
+		}
+		s.subscribed = true
+		go s.run(evChan)
+	}
+
+	if s.watchers == nil {
+		s.watchers = make(map[chan *runtime.EventStatusPayload]bool)
+	}
+	ch := make(chan *runtime.EventStatusPayload, 64)
+	s.watchers[ch] = true
+	return ch, nil
+}
+
+// stop removes the watcher and closes its channel
+func (s *statusWatchers) stop(ch chan *runtime.EventStatusPayload) {
+	s.Lock()
+	defer s.Unlock()
+
+	if !s.watchers[ch] {
+		return
+	}
+	delete(s.watchers, ch)
+	close(ch)
+}
+
+func (s *statusWatchers) run(evChan <-chan goevents.Event) {
+	for ev := range evChan {
+		var payload *runtime.EventStatusPayload
+		if err := ev.Unmarshal(&payload); err != nil {
+			log.Errorf("Error unmarshaling status event: %v", err)
+			continue
+		}
+
+		s.Lock()
+		for ch := range s.watchers {
+			// events are dropped for watchers which aren't keeping up rather than blocking the others
+			select {
+			case ch <- payload:
+			default:
+			}
+		}
+		s.Unlock()
+	}
+
+	// the subscription ended, e.g. the events service restarted, so the streams are closed and the
+	// next call to watch subscribes again
+	s.Lock()
+	defer s.Unlock()
+
+	s.subscribed = false
+	for ch := range s.watchers {
+		delete(s.watchers, ch)
+		close(ch)
+	}
+}
+
+func (r *Runtime) Watch(ctx context.Context, req *pb.WatchRequest, stream pb.Runtime_WatchStream) error {
+	// set defaults
+	if req.Options == nil {
+		req.Options = &pb.WatchOptions{}
+	}
+	if len(req.Options.Namespace) == 0 {
+		req.Options.Namespace = namespace.DefaultNamespace
+	}
+
+	// authorize the request
+	if err := namespace.Authorize(ctx, req.Options.Namespace); err == namespace.ErrForbidden {
+		return errors.Forbidden("runtime.Runtime.Watch", err.Error())
+	} else if err == namespace.ErrUnauthorized {
+		return errors.Unauthorized("runtime.Runtime.Watch", err.Error())
+	} else if err != nil {
+		return errors.InternalServerError("runtime.Runtime.Watch", err.Error())
+	}
+
+	ch, err := r.watchers.watch()
+	if err != nil {
+		return errors.InternalServerError("runtime.Runtime.Watch", err.Error())
+	}
+	defer r.watchers.stop(ch)
+	defer stream.Close()
+
+	for {
+		select {
+		case payload, ok := <-ch:
+			if !ok {
+				return nil
+			}
+			if payload.Namespace != req.Options.Namespace || payload.Service == nil {
+				continue
+			}
+			if len(req.Service) > 0 && req.Service != payload.Service.Name {
+				continue
+			}
+
+			if err := stream.Send(&pb.StatusEvent{
+				Service:   toProto(payload.Service),
+				Replica:   int64(payload.Replica),
+				Status:    payload.Status,
+				Error:     payload.Error,
+				Timestamp: payload.Updated.Unix(),
+			}); err != nil {
+				return err
+			}
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
@@ -14,6 +14,7 @@ import (
 	"github.com/micro/go-micro/v3/server"
 	signalutil "github.com/micro/go-micro/v3/util/signal"
 	"github.com/micro/micro/v3/cmd"
+	"github.com/micro/micro/v3/internal/mtls"
 	muclient "github.com/micro/micro/v3/service/client"
 	mudebug "github.com/micro/micro/v3/service/debug"
 	debug "github.com/micro/micro/v3/service/debug/handler"
@@ -63,6 +64,10 @@ func New(opts ...Option) *Service {
 		if a := ctx.String("service_address"); len(a) > 0 {
 			opts = append([]Option{Address(a)}, opts...)
 		}
+		// replicas of a service register with their index so the nodes can be told apart
+		if r := os.Getenv("MICRO_SERVICE_REPLICA"); len(r) > 0 {
+			opts = append([]Option{Metadata(map[string]string{"replica": r})}, opts...)
+		}
 		return nil
 	}
 
@@ -70,8 +75,29 @@ func New(opts ...Option) *Service {
 	// function which parses CLI flags.
 	cmd.New(cmd.SetupOnly(), cmd.Before(before)).Run()
 
+	srv := &Service{opts: newOptions(opts...)}
+
+	// issue the mutual TLS certificate now the account is setup and the name is known, services
+	// can't be called without it
+	if m := mtls.DefaultManager; m != nil && len(srv.Name()) > 0 {
+		m.Init(mtls.Service(srv.Name()))
+		if err := m.Start(); err != nil {
+			logger.Warnf("Error issuing the mutual TLS certificate, it'll be retried: %v", err)
+		}
+	}
+
+	// services run by the runtime include which replica they are in their logs so the records
+	// collected by the runtime can be told apart
+	if r := os.Getenv("MICRO_SERVICE_REPLICA"); len(r) > 0 {
+		logger.SetFields(map[string]interface{}{
+			"service": srv.Name(),
+			"version": srv.Version(),
+			"replica": r,
+		})
+	}
+
 	// return a new service
-	return &Service{opts: newOptions(opts...)}
+	return srv
 }
 
 // Name of the service